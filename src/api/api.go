@@ -22,6 +22,18 @@ import (
 var logger = logging.NewLogger(config.GetConfig())
 
 func InitServer(cfg *config.Config) {
+	r := NewRouter(cfg)
+	logger := logging.NewLogger(cfg)
+	logger.Info(logging.General, logging.Startup, "Started", nil)
+	err := r.Run(fmt.Sprintf(":%s", cfg.Server.InternalPort))
+	if err != nil {
+		logger.Fatal(logging.General, logging.Startup, err.Error(), nil)
+	}
+}
+
+// NewRouter builds the gin engine with all middlewares and routes without listening,
+// repositories are resolved by the dependency package
+func NewRouter(cfg *config.Config) *gin.Engine {
 	gin.SetMode(cfg.Server.RunMode)
 	r := gin.New()
 	RegisterValidators()
@@ -30,16 +42,14 @@ func InitServer(cfg *config.Config) {
 	r.Use(middleware.DefaultStructuredLogger(cfg))
 	r.Use(middleware.Cors(cfg))
 	r.Use(middleware.Prometheus())
+	r.Use(middleware.ReadYourWrites(cfg))
+	r.Use(middleware.PersianQuery())
+	r.Use(middleware.JalaliDates())
 	r.Use(gin.Logger(), gin.CustomRecovery(middleware.ErrorHandler) /*middleware.TestMiddleware()*/, middleware.LimitByRequest())
 
 	RegisterRoutes(r, cfg)
 	RegisterSwagger(r, cfg)
-	logger := logging.NewLogger(cfg)
-	logger.Info(logging.General, logging.Startup, "Started", nil)
-	err := r.Run(fmt.Sprintf(":%s", cfg.Server.InternalPort))
-	if err != nil {
-		logger.Fatal(logging.General, logging.Startup, err.Error(), nil)
-	}
+	return r
 }
 
 func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
@@ -53,29 +63,50 @@ func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
 
 		// User
 		users := v1.Group("/users")
+		tenants := v1.Group("/tenants", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}), middleware.PlatformTenant())
 
 		// Base
-		countries := v1.Group("/countries", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		cities := v1.Group("/cities", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		files := v1.Group("/files", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		companies := v1.Group("/companies", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		colors := v1.Group("/colors", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		years := v1.Group("/years", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
+		countries := v1.Group("/countries", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		cities := v1.Group("/cities", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		files := v1.Group("/files", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		companies := v1.Group("/companies", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		colors := v1.Group("/colors", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		years := v1.Group("/years", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		exchangeRates := v1.Group("/exchange-rates", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
 
 		// Property
-		properties := v1.Group("/properties", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		propertyCategories := v1.Group("/property-categories", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
+		properties := v1.Group("/properties", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		propertyCategories := v1.Group("/property-categories", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
 
 		// Car
-		carTypes := v1.Group("/car-types", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		gearboxes := v1.Group("/gearboxes", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		carModels := v1.Group("/car-models", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		carModelColors := v1.Group("/car-model-colors", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		carModelYears := v1.Group("/car-model-years", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		carModelPriceHistories := v1.Group("/car-model-price-histories", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		carModelImages := v1.Group("/car-model-images", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		carModelProperties := v1.Group("/car-model-properties", middleware.Authentication(cfg), middleware.Authorization([]string{"admin"}))
-		carModelComments := v1.Group("/car-model-comments", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "default"}))
+		carTypes := v1.Group("/car-types", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		gearboxes := v1.Group("/gearboxes", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		carModels := v1.Group("/car-models", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		carModelColors := v1.Group("/car-model-colors", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		carModelYears := v1.Group("/car-model-years", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		carModelPriceHistories := v1.Group("/car-model-price-histories", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		carModelImages := v1.Group("/car-model-images", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		carModelProperties := v1.Group("/car-model-properties", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		carModelComments := v1.Group("/car-model-comments", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin", "default"}))
+		carModelRatings := v1.Group("/car-model-ratings", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin", "default"}))
+
+		// Webhook
+		webhooks := v1.Group("/webhooks", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+		webhookDeliveries := v1.Group("/webhook-deliveries", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin"}))
+
+		// Catalog, read only and anonymous
+		catalog := v1.Group("/catalog", middleware.CacheControl(cfg.Catalog.MaxAge), middleware.CatalogLimiter(cfg), middleware.CatalogTenant(cfg))
+
+		// Notification
+		priceAlerts := v1.Group("/price-alerts", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin", "default"}))
+		notifications := v1.Group("/notifications", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin", "default"}))
+
+		// Listing
+		listings := v1.Group("/listings", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin", "default"}))
+
+		// Watchlist
+		watchlists := v1.Group("/watchlists", middleware.Authentication(cfg), middleware.Authorization([]string{"admin", "tenant-admin", "default"}))
+		sharedWatchlists := v1.Group("/shared-watchlists", middleware.CatalogLimiter(cfg))
 
 		// Test
 		router.Health(health)
@@ -83,6 +114,7 @@ func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
 
 		// User
 		router.User(users, cfg)
+		router.Tenant(tenants, cfg)
 
 		// Base
 		router.Country(countries, cfg)
@@ -91,6 +123,7 @@ func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
 		router.Company(companies, cfg)
 		router.Color(colors, cfg)
 		router.Year(years, cfg)
+		router.ExchangeRate(exchangeRates, cfg)
 
 		// Property
 		router.Property(properties, cfg)
@@ -106,6 +139,25 @@ func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
 		router.CarModelImage(carModelImages, cfg)
 		router.CarModelProperty(carModelProperties, cfg)
 		router.CarModelComment(carModelComments, cfg)
+		router.CarModelRating(carModelRatings, cfg)
+
+		// Webhook
+		router.Webhook(webhooks, cfg)
+		router.WebhookDelivery(webhookDeliveries, cfg)
+
+		// Catalog
+		router.Catalog(catalog, cfg)
+
+		// Notification
+		router.PriceAlert(priceAlerts, cfg)
+		router.Notification(notifications, cfg)
+
+		// Listing
+		router.Listing(listings, cfg)
+
+		// Watchlist
+		router.Watchlist(watchlists, cfg)
+		router.SharedWatchlist(sharedWatchlists, cfg)
 
 		r.Static("/static", "./uploads")
 
@@ -120,6 +172,9 @@ func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
 }
 
 func RegisterValidators() {
+	if _, ok := binding.Validator.(validation.PersianValidator); !ok {
+		binding.Validator = validation.PersianValidator{StructValidator: binding.Validator}
+	}
 	val, ok := binding.Validator.Engine().(*validator.Validate)
 	if ok {
 		err := val.RegisterValidation("mobile", validation.IranianMobileNumberValidator, true)
@@ -130,6 +185,10 @@ func RegisterValidators() {
 		if err != nil {
 			logger.Error(logging.Validation, logging.Startup, err.Error(), nil)
 		}
+		err = val.RegisterValidation("slug", validation.SlugValidator, true)
+		if err != nil {
+			logger.Error(logging.Validation, logging.Startup, err.Error(), nil)
+		}
 	}
 }
 
@@ -154,4 +213,24 @@ func RegisterPrometheus() {
 	if err != nil {
 		logger.Error(logging.Prometheus, logging.Startup, err.Error(), nil)
 	}
+
+	err = prometheus.Register(metrics.CacheCall)
+	if err != nil {
+		logger.Error(logging.Prometheus, logging.Startup, err.Error(), nil)
+	}
+
+	err = prometheus.Register(metrics.OutboxPublish)
+	if err != nil {
+		logger.Error(logging.Prometheus, logging.Startup, err.Error(), nil)
+	}
+
+	err = prometheus.Register(metrics.WebhookDelivery)
+	if err != nil {
+		logger.Error(logging.Prometheus, logging.Startup, err.Error(), nil)
+	}
+
+	err = prometheus.Register(metrics.Notification)
+	if err != nil {
+		logger.Error(logging.Prometheus, logging.Startup, err.Error(), nil)
+	}
 }
@@ -1,9 +1,16 @@
 package dto
 
 import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
@@ -58,6 +65,125 @@ type CarModelResponse struct {
 	CarModelImages     []CarModelImageResponse    `json:"carModelImages,omitempty"`
 	CarModelProperties []CarModelPropertyResponse `json:"carModelProperties,omitempty"`
 	CarModelComments   []CarModelCommentResponse  `json:"carModelComments,omitempty"`
+	Rating             RatingSummaryResponse      `json:"rating"`
+}
+
+type RatingSummaryResponse struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+	// Distribution counts the ratings of every star from 1 to 5
+	Distribution []RatingCountResponse `json:"distribution"`
+}
+
+type RatingCountResponse struct {
+	Stars int `json:"stars"`
+	Count int `json:"count"`
+}
+
+type CarModelSearchRequest struct {
+	Query      string `form:"q" binding:"required,min=2,max=100"`
+	PageNumber int    `form:"pageNumber" binding:"min=0"`
+	PageSize   int    `form:"pageSize" binding:"min=0,max=100"`
+}
+
+type CarModelSearchResponse struct {
+	Id          int     `json:"id"`
+	Name        string  `json:"name"`
+	CompanyName string  `json:"companyName"`
+	CarTypeName string  `json:"carTypeName"`
+	GearboxName string  `json:"gearboxName"`
+	Rank        float64 `json:"rank"`
+	// Highlight is escaped html, the matches are wrapped in <mark>
+	Highlight string `json:"highlight"`
+}
+
+// CompareCarModelsRequest ids are comma separated car model ids
+type CompareCarModelsRequest struct {
+	Ids string `form:"ids" binding:"required"`
+}
+
+type CarModelComparisonResponse struct {
+	CarModels  []ComparedCarModelResponse           `json:"carModels"`
+	Categories []PropertyCategoryComparisonResponse `json:"categories"`
+	Prices     []YearPriceComparisonResponse        `json:"prices"`
+	Currency   string                               `json:"currency"`
+	Colors     []ColorComparisonResponse            `json:"colors"`
+}
+
+type ComparedCarModelResponse struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	CompanyName string `json:"companyName"`
+	CarTypeName string `json:"carTypeName"`
+	GearboxName string `json:"gearboxName"`
+}
+
+type PropertyCategoryComparisonResponse struct {
+	Id         int                          `json:"id"`
+	Name       string                       `json:"name"`
+	Icon       string                       `json:"icon"`
+	Properties []PropertyComparisonResponse `json:"properties"`
+}
+
+type PropertyComparisonResponse struct {
+	Id       int       `json:"id"`
+	Name     string    `json:"name"`
+	Icon     string    `json:"icon"`
+	DataType string    `json:"dataType"`
+	Unit     string    `json:"unit"`
+	Values   []*string `json:"values"`
+	Differs  bool      `json:"differs"`
+}
+
+type YearPriceComparisonResponse struct {
+	PersianYearId int        `json:"persianYearId"`
+	Year          int        `json:"year"`
+	PersianTitle  string     `json:"persianTitle"`
+	Prices        []*float64 `json:"prices"`
+}
+
+type ColorComparisonResponse struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name"`
+	HexCode   string `json:"hexCode"`
+	Available []bool `json:"available"`
+	Differs   bool   `json:"differs"`
+}
+
+type FacetedCarModelsResponse struct {
+	Items       filter.PagedList[CarModelFacetItemResponse] `json:"items"`
+	Facets      []FacetResponse                             `json:"facets"`
+	PriceRanges []PriceRangeFacetResponse                   `json:"priceRanges"`
+	Currency    string                                      `json:"currency"`
+}
+
+type CarModelFacetItemResponse struct {
+	Id          int      `json:"id"`
+	Name        string   `json:"name"`
+	CompanyName string   `json:"companyName"`
+	CountryName string   `json:"countryName"`
+	CarTypeName string   `json:"carTypeName"`
+	GearboxName string   `json:"gearboxName"`
+	MinPrice    *float64 `json:"minPrice"`
+	MaxPrice    *float64 `json:"maxPrice"`
+}
+
+type FacetResponse struct {
+	Field  string               `json:"field"`
+	Values []FacetValueResponse `json:"values"`
+}
+
+type FacetValueResponse struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	Selected bool   `json:"selected"`
+}
+
+type PriceRangeFacetResponse struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
 }
 
 type CreateCarModelColorRequest struct {
@@ -92,22 +218,86 @@ type CarModelYearResponse struct {
 	CarModelPriceHistories []CarModelPriceHistoryResponse `json:"carModelPriceHistories,omitempty"`
 }
 
+// CreateCarModelPriceHistoryRequest currency defaults to IRT, Toman
 type CreateCarModelPriceHistoryRequest struct {
 	CarModelYearId int       `json:"carModelYearId" binding:"required"`
 	PriceAt        time.Time `json:"priceAt" binding:"required"`
 	Price          float64   `json:"price" binding:"required"`
+	Currency       string    `json:"currency" binding:"omitempty,oneof=IRR IRT USD EUR"`
 }
 
 type UpdateCarModelPriceHistoryRequest struct {
-	PriceAt time.Time `json:"priceAt,omitempty"`
-	Price   float64   `json:"price,omitempty"`
+	PriceAt  time.Time `json:"priceAt,omitempty"`
+	Price    float64   `json:"price,omitempty"`
+	Currency string    `json:"currency,omitempty" binding:"omitempty,oneof=IRR IRT USD EUR"`
 }
 
 type CarModelPriceHistoryResponse struct {
-	Id             int       `json:"id"`
-	CarModelYearId int       `json:"carModelYearId"`
-	PriceAt        time.Time `json:"priceAt,omitempty"`
-	Price          float64   `json:"price,omitempty"`
+	Id                int       `json:"id"`
+	CarModelYearId    int       `json:"carModelYearId"`
+	PriceAt           time.Time `json:"priceAt,omitempty"`
+	Price             float64   `json:"price,omitempty"`
+	Currency          string    `json:"currency,omitempty"`
+	ConvertedPrice    *float64  `json:"convertedPrice,omitempty"`
+	ConvertedCurrency string    `json:"convertedCurrency,omitempty"`
+}
+
+// PriceAnalyticsRequest dates are days in Iran time, both are inclusive. The prices are converted to
+// currency, IRT by default
+type PriceAnalyticsRequest struct {
+	Bucket   string     `form:"bucket" binding:"omitempty,oneof=day week month persian_month"`
+	From     *time.Time `form:"from" time_format:"2006-01-02"`
+	To       *time.Time `form:"to" time_format:"2006-01-02"`
+	Currency string     `form:"currency" binding:"omitempty,oneof=IRR IRT USD EUR"`
+}
+
+type PriceAnalyticsResponse struct {
+	Bucket   string                `json:"bucket"`
+	Currency string                `json:"currency"`
+	From     *time.Time            `json:"from,omitempty"`
+	To       *time.Time            `json:"to,omitempty"`
+	Summary  PriceSummaryResponse  `json:"summary"`
+	Buckets  []PriceBucketResponse `json:"buckets"`
+}
+
+type CarModelPriceAnalyticsResponse struct {
+	CarModelId int                                  `json:"carModelId"`
+	Years      []CarModelYearPriceAnalyticsResponse `json:"years"`
+}
+
+type CarModelYearPriceAnalyticsResponse struct {
+	CarModelYearId int `json:"carModelYearId"`
+	PriceAnalyticsResponse
+}
+
+type PriceSummaryResponse struct {
+	Count         int                   `json:"count"`
+	FirstPrice    float64               `json:"firstPrice"`
+	LastPrice     float64               `json:"lastPrice"`
+	LastPriceAt   *time.Time            `json:"lastPriceAt,omitempty"`
+	MinPrice      float64               `json:"minPrice"`
+	MaxPrice      float64               `json:"maxPrice"`
+	AveragePrice  float64               `json:"averagePrice"`
+	ChangePercent *float64              `json:"changePercent"`
+	Changes       []PriceChangeResponse `json:"changes"`
+}
+
+type PriceChangeResponse struct {
+	Days          int      `json:"days"`
+	BasePrice     *float64 `json:"basePrice"`
+	ChangePercent *float64 `json:"changePercent"`
+}
+
+type PriceBucketResponse struct {
+	Label         string    `json:"label"`
+	StartAt       time.Time `json:"startAt"`
+	EndAt         time.Time `json:"endAt"`
+	Count         int       `json:"count"`
+	Min           float64   `json:"min"`
+	Max           float64   `json:"max"`
+	Average       float64   `json:"average"`
+	Last          float64   `json:"last"`
+	ChangePercent *float64  `json:"changePercent"`
 }
 
 type CreateCarModelImageRequest struct {
@@ -120,11 +310,18 @@ type UpdateCarModelImageRequest struct {
 	IsMainImage bool `json:"isMainImage,omitempty"`
 }
 
+// ReorderCarModelImagesRequest lists all images of a car model in the new gallery order
+type ReorderCarModelImagesRequest struct {
+	CarModelId int   `json:"carModelId" binding:"required"`
+	ImageIds   []int `json:"imageIds" binding:"required,min=1,dive,min=1"`
+}
+
 type CarModelImageResponse struct {
 	Id          int          `json:"id"`
 	CarModelId  int          `json:"carModelId,omitempty"`
 	Image       FileResponse `json:"image,omitempty"`
 	IsMainImage bool         `json:"isMainImage"`
+	SortOrder   int          `json:"sortOrder"`
 }
 
 type CreateCarModelPropertyRequest struct {
@@ -137,16 +334,21 @@ type UpdateCarModelPropertyRequest struct {
 	Value string `json:"value" binding:"required,max=100"`
 }
 
+// CarModelPropertyResponse numericValue is the number of int, decimal and bool values and the start of
+// range values, numericMaxValue is the end of range values
 type CarModelPropertyResponse struct {
-	Id         int              `json:"id"`
-	CarModelId int              `json:"carModelId,omitempty"`
-	Property   PropertyResponse `json:"property,omitempty"`
-	Value      string           `json:"value"`
+	Id              int              `json:"id"`
+	CarModelId      int              `json:"carModelId,omitempty"`
+	Property        PropertyResponse `json:"property,omitempty"`
+	Value           string           `json:"value"`
+	NumericValue    *float64         `json:"numericValue,omitempty"`
+	NumericMaxValue *float64         `json:"numericMaxValue,omitempty"`
 }
 
+// the author of a comment is the current user, a reply names the comment it replies to as parentId
 type CreateCarModelCommentRequest struct {
 	CarModelId int    `json:"carModelId" binding:"required"`
-	UserId     int    `json:"userId"`
+	ParentId   *int   `json:"parentId" binding:"omitempty,min=1"`
 	Message    string `json:"message" binding:"required,max=100"`
 }
 
@@ -155,10 +357,43 @@ type UpdateCarModelCommentRequest struct {
 }
 
 type CarModelCommentResponse struct {
-	Id         int          `json:"id"`
-	CarModelId int          `json:"carModelId"`
-	User       UserResponse `json:"user"`
-	Message    string       `json:"message"`
+	Id             int          `json:"id"`
+	CarModelId     int          `json:"carModelId"`
+	User           UserResponse `json:"user"`
+	Message        string       `json:"message"`
+	Status         string       `json:"status"`
+	ModerationNote string       `json:"moderationNote,omitempty"`
+	ModeratedAt    *time.Time   `json:"moderatedAt"`
+	ReportCount    int          `json:"reportCount"`
+	ParentId       *int         `json:"parentId"`
+	HelpfulCount   int          `json:"helpfulCount"`
+	UnhelpfulCount int          `json:"unhelpfulCount"`
+}
+
+type ModerateCarModelCommentRequest struct {
+	Status         string `json:"status" binding:"required,oneof=pending approved rejected hidden"`
+	ModerationNote string `json:"moderationNote" binding:"max=500"`
+}
+
+type ReportCarModelCommentRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type VoteCarModelCommentRequest struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+type CreateCarModelRatingRequest struct {
+	CarModelId int `json:"carModelId" binding:"required"`
+	Stars      int `json:"stars" binding:"required,min=1,max=5"`
+}
+
+type CarModelRatingResponse struct {
+	Id         int        `json:"id"`
+	CarModelId int        `json:"carModelId"`
+	Stars      int        `json:"stars"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ModifiedAt *time.Time `json:"modifiedAt"`
 }
 
 type UserResponse struct {
@@ -239,7 +474,7 @@ func ToCarModelResponse(from dto.CarModel) CarModelResponse {
 	}()
 
 	go func() {
-		for _, item := range from.CarModelImages {
+		for _, item := range sortCarModelImages(from.CarModelImages) {
 			images = append(images, ToCarModelImageResponse(item))
 		}
 		wg.Done()
@@ -265,6 +500,21 @@ func ToCarModelResponse(from dto.CarModel) CarModelResponse {
 		CarModelImages:     images,
 		CarModelProperties: properties,
 		CarModelComments:   comments,
+		Rating:             ToRatingSummaryResponse(from),
+	}
+}
+
+func ToRatingSummaryResponse(from dto.CarModel) RatingSummaryResponse {
+	return RatingSummaryResponse{
+		Average: from.RatingAverage,
+		Count:   from.RatingCount,
+		Distribution: []RatingCountResponse{
+			{Stars: 1, Count: from.OneStarCount},
+			{Stars: 2, Count: from.TwoStarCount},
+			{Stars: 3, Count: from.ThreeStarCount},
+			{Stars: 4, Count: from.FourStarCount},
+			{Stars: 5, Count: from.FiveStarCount},
+		},
 	}
 }
 
@@ -286,6 +536,18 @@ func ToUpdateCarModel(from UpdateCarModelRequest) dto.UpdateCarModel {
 	}
 }
 
+func ToCarModelSearchResponse(from dto.CarModelSearchResult) CarModelSearchResponse {
+	return CarModelSearchResponse{
+		Id:          from.Id,
+		Name:        from.Name,
+		CompanyName: from.CompanyName,
+		CarTypeName: from.CarTypeName,
+		GearboxName: from.GearboxName,
+		Rank:        from.Rank,
+		Highlight:   from.Highlight,
+	}
+}
+
 func ToCarModelColorResponse(from dto.CarModelColor) CarModelColorResponse {
 	return CarModelColorResponse{
 		Id:    from.Id,
@@ -337,10 +599,13 @@ func ToUpdateCarModelYear(from UpdateCarModelYearRequest) dto.UpdateCarModelYear
 
 func ToCarModelPriceHistoryResponse(from dto.CarModelPriceHistory) CarModelPriceHistoryResponse {
 	return CarModelPriceHistoryResponse{
-		Id:             from.Id,
-		CarModelYearId: from.CarModelYearId,
-		PriceAt:        from.PriceAt,
-		Price:          from.Price,
+		Id:                from.Id,
+		CarModelYearId:    from.CarModelYearId,
+		PriceAt:           from.PriceAt,
+		Price:             from.Price,
+		Currency:          from.Currency,
+		ConvertedPrice:    from.ConvertedPrice,
+		ConvertedCurrency: from.ConvertedCurrency,
 	}
 }
 
@@ -349,13 +614,185 @@ func ToCreateCarModelPriceHistory(from CreateCarModelPriceHistoryRequest) dto.Cr
 		CarModelYearId: from.CarModelYearId,
 		PriceAt:        from.PriceAt,
 		Price:          from.Price,
+		Currency:       from.Currency,
 	}
 }
 
 func ToUpdateCarModelPriceHistory(from UpdateCarModelPriceHistoryRequest) dto.UpdateCarModelPriceHistory {
 	return dto.UpdateCarModelPriceHistory{
-		PriceAt: from.PriceAt,
-		Price:   from.Price,
+		PriceAt:  from.PriceAt,
+		Price:    from.Price,
+		Currency: from.Currency,
+	}
+}
+
+func ToPriceAnalyticsQuery(from PriceAnalyticsRequest) dto.PriceAnalyticsQuery {
+	query := dto.PriceAnalyticsQuery{Bucket: from.Bucket, Currency: from.Currency}
+	if from.From != nil {
+		from := time.Date(from.From.Year(), from.From.Month(), from.From.Day(), 0, 0, 0, 0, common.IranLocation)
+		query.From = &from
+	}
+	if from.To != nil {
+		// the query range excludes To
+		to := time.Date(from.To.Year(), from.To.Month(), from.To.Day()+1, 0, 0, 0, 0, common.IranLocation)
+		query.To = &to
+	}
+	return query
+}
+
+func ToPriceAnalyticsResponse(from dto.PriceAnalytics) PriceAnalyticsResponse {
+	changes := []PriceChangeResponse{}
+	for _, item := range from.Summary.Changes {
+		changes = append(changes, PriceChangeResponse{Days: item.Days, BasePrice: item.BasePrice, ChangePercent: item.ChangePercent})
+	}
+	buckets := []PriceBucketResponse{}
+	for _, item := range from.Buckets {
+		buckets = append(buckets, PriceBucketResponse{
+			Label:         item.Label,
+			StartAt:       item.StartAt,
+			EndAt:         item.EndAt,
+			Count:         item.Count,
+			Min:           item.Min,
+			Max:           item.Max,
+			Average:       item.Average,
+			Last:          item.Last,
+			ChangePercent: item.ChangePercent,
+		})
+	}
+	return PriceAnalyticsResponse{
+		Bucket:   from.Bucket,
+		Currency: from.Currency,
+		From:     from.From,
+		To:       from.To,
+		Summary: PriceSummaryResponse{
+			Count:         from.Summary.Count,
+			FirstPrice:    from.Summary.FirstPrice,
+			LastPrice:     from.Summary.LastPrice,
+			LastPriceAt:   from.Summary.LastPriceAt,
+			MinPrice:      from.Summary.MinPrice,
+			MaxPrice:      from.Summary.MaxPrice,
+			AveragePrice:  from.Summary.AveragePrice,
+			ChangePercent: from.Summary.ChangePercent,
+			Changes:       changes,
+		},
+		Buckets: buckets,
+	}
+}
+
+func ToCarModelPriceAnalyticsResponse(from dto.CarModelPriceAnalytics) CarModelPriceAnalyticsResponse {
+	years := []CarModelYearPriceAnalyticsResponse{}
+	for _, item := range from.Years {
+		years = append(years, CarModelYearPriceAnalyticsResponse{CarModelYearId: item.CarModelYearId, PriceAnalyticsResponse: ToPriceAnalyticsResponse(item.PriceAnalytics)})
+	}
+	return CarModelPriceAnalyticsResponse{CarModelId: from.CarModelId, Years: years}
+}
+
+// ToCompareCarModelIds parses the comma separated ids
+func ToCompareCarModelIds(from CompareCarModelsRequest) ([]int, error) {
+	ids := []int{}
+	for _, item := range strings.Split(from.Ids, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || id <= 0 {
+			return nil, &service_errors.ServiceError{EndUserMessage: service_errors.CompareCarModelsInvalid}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func ToCarModelComparisonResponse(from dto.CarModelComparison) CarModelComparisonResponse {
+	carModels := []ComparedCarModelResponse{}
+	for _, item := range from.CarModels {
+		carModels = append(carModels, ComparedCarModelResponse{
+			Id:          item.Id,
+			Name:        item.Name,
+			CompanyName: item.CompanyName,
+			CarTypeName: item.CarTypeName,
+			GearboxName: item.GearboxName,
+		})
+	}
+	categories := []PropertyCategoryComparisonResponse{}
+	for _, category := range from.Categories {
+		properties := []PropertyComparisonResponse{}
+		for _, item := range category.Properties {
+			properties = append(properties, PropertyComparisonResponse{
+				Id:       item.Id,
+				Name:     item.Name,
+				Icon:     item.Icon,
+				DataType: item.DataType,
+				Unit:     item.Unit,
+				Values:   item.Values,
+				Differs:  item.Differs,
+			})
+		}
+		categories = append(categories, PropertyCategoryComparisonResponse{
+			Id:         category.Id,
+			Name:       category.Name,
+			Icon:       category.Icon,
+			Properties: properties,
+		})
+	}
+	prices := []YearPriceComparisonResponse{}
+	for _, item := range from.Prices {
+		prices = append(prices, YearPriceComparisonResponse{
+			PersianYearId: item.PersianYearId,
+			Year:          item.Year,
+			PersianTitle:  item.PersianTitle,
+			Prices:        item.Prices,
+		})
+	}
+	colors := []ColorComparisonResponse{}
+	for _, item := range from.Colors {
+		colors = append(colors, ColorComparisonResponse{
+			Id:        item.Id,
+			Name:      item.Name,
+			HexCode:   item.HexCode,
+			Available: item.Available,
+			Differs:   item.Differs,
+		})
+	}
+	return CarModelComparisonResponse{CarModels: carModels, Categories: categories, Prices: prices, Currency: from.Currency, Colors: colors}
+}
+
+func ToFacetedCarModelsResponse(from dto.FacetedCarModels) FacetedCarModelsResponse {
+	items := []CarModelFacetItemResponse{}
+	for _, item := range *from.Items.Items {
+		items = append(items, CarModelFacetItemResponse{
+			Id:          item.Id,
+			Name:        item.Name,
+			CompanyName: item.CompanyName,
+			CountryName: item.CountryName,
+			CarTypeName: item.CarTypeName,
+			GearboxName: item.GearboxName,
+			MinPrice:    item.MinPrice,
+			MaxPrice:    item.MaxPrice,
+		})
+	}
+	facets := []FacetResponse{}
+	for _, facet := range from.Facets {
+		values := []FacetValueResponse{}
+		for _, item := range facet.Values {
+			values = append(values, FacetValueResponse{Id: item.Id, Name: item.Name, Count: item.Count, Selected: item.Selected})
+		}
+		facets = append(facets, FacetResponse{Field: facet.Field, Values: values})
+	}
+	priceRanges := []PriceRangeFacetResponse{}
+	for _, item := range from.PriceRanges {
+		priceRanges = append(priceRanges, PriceRangeFacetResponse{From: item.From, To: item.To, Count: item.Count})
+	}
+	return FacetedCarModelsResponse{
+		Items: filter.PagedList[CarModelFacetItemResponse]{
+			PageNumber:      from.Items.PageNumber,
+			PageSize:        from.Items.PageSize,
+			TotalRows:       from.Items.TotalRows,
+			TotalPages:      from.Items.TotalPages,
+			HasPreviousPage: from.Items.HasPreviousPage,
+			HasNextPage:     from.Items.HasNextPage,
+			Items:           &items,
+		},
+		Facets:      facets,
+		PriceRanges: priceRanges,
+		Currency:    from.Currency,
 	}
 }
 
@@ -364,10 +801,20 @@ func ToCarModelImageResponse(from dto.CarModelImage) CarModelImageResponse {
 		Id:          from.Id,
 		CarModelId:  from.CarModelId,
 		IsMainImage: from.IsMainImage,
+		SortOrder:   from.SortOrder,
 		Image:       ToFileResponse(from.Image),
 	}
 }
 
+// sortCarModelImages returns the images in gallery order, preloads don't keep it
+func sortCarModelImages(from []dto.CarModelImage) []dto.CarModelImage {
+	images := slices.Clone(from)
+	slices.SortFunc(images, func(a, b dto.CarModelImage) int {
+		return cmp.Or(cmp.Compare(a.SortOrder, b.SortOrder), cmp.Compare(a.Id, b.Id))
+	})
+	return images
+}
+
 func ToCreateCarModelImage(from CreateCarModelImageRequest) dto.CreateCarModelImage {
 	return dto.CreateCarModelImage{
 		CarModelId:  from.CarModelId,
@@ -382,12 +829,21 @@ func ToUpdateCarModelImage(from UpdateCarModelImageRequest) dto.UpdateCarModelIm
 	}
 }
 
+func ToReorderCarModelImages(from ReorderCarModelImagesRequest) dto.ReorderCarModelImages {
+	return dto.ReorderCarModelImages{
+		CarModelId: from.CarModelId,
+		ImageIds:   from.ImageIds,
+	}
+}
+
 func ToCarModelPropertyResponse(from dto.CarModelProperty) CarModelPropertyResponse {
 	return CarModelPropertyResponse{
-		Id:         from.Id,
-		CarModelId: from.CarModelId,
-		Property:   ToPropertyResponse(from.Property),
-		Value:      from.Value,
+		Id:              from.Id,
+		CarModelId:      from.CarModelId,
+		Property:        ToPropertyResponse(from.Property),
+		Value:           from.Value,
+		NumericValue:    from.NumericValue,
+		NumericMaxValue: from.NumericMaxValue,
 	}
 }
 
@@ -406,22 +862,71 @@ func ToUpdateCarModelProperty(from UpdateCarModelPropertyRequest) dto.UpdateCarM
 }
 
 func ToCarModelCommentResponse(from dto.CarModelComment) CarModelCommentResponse {
-	return CarModelCommentResponse{
-		Id:         from.Id,
-		CarModelId: from.CarModelId,
-		Message:    from.Message,
-		User:       ToUserResponse(from.User),
+	response := CarModelCommentResponse{
+		Id:             from.Id,
+		CarModelId:     from.CarModelId,
+		Message:        from.Message,
+		User:           ToUserResponse(from.User),
+		Status:         from.Status,
+		ModerationNote: from.ModerationNote,
+		ReportCount:    from.ReportCount,
+		ParentId:       from.ParentId,
+		HelpfulCount:   from.HelpfulCount,
+		UnhelpfulCount: from.UnhelpfulCount,
+	}
+	if from.ModeratedAt.Valid {
+		response.ModeratedAt = &from.ModeratedAt.Time
 	}
+	return response
 }
 
 func ToCreateCarModelComment(from CreateCarModelCommentRequest) dto.CreateCarModelComment {
 	return dto.CreateCarModelComment{
 		CarModelId: from.CarModelId,
-		UserId:     from.UserId,
+		ParentId:   from.ParentId,
 		Message:    from.Message,
 	}
 }
 
+func ToModerateCarModelComment(from ModerateCarModelCommentRequest) dto.ModerateCarModelComment {
+	return dto.ModerateCarModelComment{
+		Status:         from.Status,
+		ModerationNote: from.ModerationNote,
+	}
+}
+
+func ToCreateCarModelCommentReport(from ReportCarModelCommentRequest) dto.CreateCarModelCommentReport {
+	return dto.CreateCarModelCommentReport{
+		Reason: from.Reason,
+	}
+}
+
+func ToCreateCarModelCommentVote(from VoteCarModelCommentRequest) dto.CreateCarModelCommentVote {
+	return dto.CreateCarModelCommentVote{
+		Helpful: *from.Helpful,
+	}
+}
+
+func ToCreateCarModelRating(from CreateCarModelRatingRequest) dto.CreateCarModelRating {
+	return dto.CreateCarModelRating{
+		CarModelId: from.CarModelId,
+		Stars:      from.Stars,
+	}
+}
+
+func ToCarModelRatingResponse(from dto.CarModelRating) CarModelRatingResponse {
+	response := CarModelRatingResponse{
+		Id:         from.Id,
+		CarModelId: from.CarModelId,
+		Stars:      from.Stars,
+		CreatedAt:  from.CreatedAt,
+	}
+	if from.ModifiedAt.Valid {
+		response.ModifiedAt = &from.ModifiedAt.Time
+	}
+	return response
+}
+
 func ToUpdateCarModelComment(from UpdateCarModelCommentRequest) dto.UpdateCarModelComment {
 	return dto.UpdateCarModelComment{
 		Message: from.Message,
@@ -437,3 +942,57 @@ func ToUserResponse(from dto.User) UserResponse {
 		Email:     from.Email,
 	}
 }
+
+type ImportCarModelsRequest struct {
+	FileFormRequest
+	DryRun bool `json:"dryRun" form:"dryRun"`
+}
+
+type CatalogImportResponse struct {
+	DryRun      bool                       `json:"dryRun"`
+	TotalRows   int                        `json:"totalRows"`
+	ValidRows   int                        `json:"validRows"`
+	InvalidRows int                        `json:"invalidRows"`
+	Created     int                        `json:"created"`
+	Updated     int                        `json:"updated"`
+	Rows        []CatalogImportRowResponse `json:"rows"`
+}
+
+type CatalogImportRowResponse struct {
+	Row        int                          `json:"row"`
+	Name       string                       `json:"name,omitempty"`
+	Action     string                       `json:"action,omitempty"`
+	CarModelId int                          `json:"carModelId,omitempty"`
+	Errors     []CatalogImportErrorResponse `json:"errors,omitempty"`
+}
+
+type CatalogImportErrorResponse struct {
+	Column  string `json:"column"`
+	Message string `json:"message"`
+}
+
+func ToCatalogImportResponse(from dto.CatalogImportResult) CatalogImportResponse {
+	rows := []CatalogImportRowResponse{}
+	for _, row := range from.Rows {
+		errors := []CatalogImportErrorResponse{}
+		for _, err := range row.Errors {
+			errors = append(errors, CatalogImportErrorResponse{Column: err.Column, Message: err.Message})
+		}
+		rows = append(rows, CatalogImportRowResponse{
+			Row:        row.Row,
+			Name:       row.Name,
+			Action:     row.Action,
+			CarModelId: row.CarModelId,
+			Errors:     errors,
+		})
+	}
+	return CatalogImportResponse{
+		DryRun:      from.DryRun,
+		TotalRows:   from.TotalRows,
+		ValidRows:   from.ValidRows,
+		InvalidRows: from.InvalidRows,
+		Created:     from.Created,
+		Updated:     from.Updated,
+		Rows:        rows,
+	}
+}
@@ -0,0 +1,235 @@
+package dto
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+// The catalog responses are safe to serve anonymously, they never carry audit fields,
+// users or file paths of the server
+
+const catalogImagePath = "/static"
+
+type CatalogListRequest struct {
+	PageNumber int `form:"pageNumber" binding:"min=0"`
+	PageSize   int `form:"pageSize" binding:"min=0,max=100"`
+}
+
+type CatalogCarModelListRequest struct {
+	CatalogListRequest
+	CompanyId int `form:"companyId" binding:"min=0"`
+	CarTypeId int `form:"carTypeId" binding:"min=0"`
+	GearboxId int `form:"gearboxId" binding:"min=0"`
+}
+
+type CatalogItemResponse struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CatalogCompanyResponse struct {
+	Id      int                 `json:"id"`
+	Name    string              `json:"name"`
+	Country CatalogItemResponse `json:"country"`
+}
+
+type CatalogColorResponse struct {
+	Id      int    `json:"id"`
+	Name    string `json:"name"`
+	HexCode string `json:"hexCode"`
+}
+
+type CatalogCarModelResponse struct {
+	Id           int                    `json:"id"`
+	Name         string                 `json:"name"`
+	Company      CatalogCompanyResponse `json:"company"`
+	CarType      CatalogItemResponse    `json:"carType"`
+	Gearbox      CatalogItemResponse    `json:"gearbox"`
+	MainImageUrl string                 `json:"mainImageUrl,omitempty"`
+	// the latest price of the newest year that has a price, in the default currency unless it has no
+	// exchange rate at its time
+	LatestPrice         *float64              `json:"latestPrice"`
+	LatestPriceCurrency string                `json:"latestPriceCurrency,omitempty"`
+	Rating              RatingSummaryResponse `json:"rating"`
+}
+
+type CatalogCarModelDetailResponse struct {
+	CatalogCarModelResponse
+	Colors     []CatalogColorResponse    `json:"colors"`
+	Years      []CatalogYearResponse     `json:"years"`
+	Properties []CatalogPropertyResponse `json:"properties"`
+	Images     []CatalogImageResponse    `json:"images"`
+	Comments   []CatalogCommentResponse  `json:"comments"`
+}
+
+type CatalogYearResponse struct {
+	Id           int        `json:"id"`
+	PersianTitle string     `json:"persianTitle"`
+	Year         int        `json:"year"`
+	Price        *float64   `json:"price"`
+	Currency     string     `json:"currency,omitempty"`
+	PriceAt      *time.Time `json:"priceAt"`
+}
+
+type CatalogPropertyResponse struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Unit     string `json:"unit"`
+	Value    string `json:"value"`
+}
+
+// CatalogCommentResponse is an approved comment, the author is named by the first name only
+type CatalogCommentResponse struct {
+	Id             int    `json:"id"`
+	ParentId       *int   `json:"parentId"`
+	Author         string `json:"author"`
+	Message        string `json:"message"`
+	HelpfulCount   int    `json:"helpfulCount"`
+	UnhelpfulCount int    `json:"unhelpfulCount"`
+}
+
+type CatalogImageResponse struct {
+	Url         string `json:"url"`
+	Description string `json:"description"`
+	IsMainImage bool   `json:"isMainImage"`
+}
+
+func ToCatalogFilter(from CatalogListRequest) filter.PaginationInputWithFilter {
+	req := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageNumber: from.PageNumber, PageSize: from.PageSize},
+		DynamicFilter:   filter.DynamicFilter{Sort: &[]filter.Sort{{ColId: "Name", Sort: "asc"}}},
+	}
+	// the paged list is built from the request, so the defaults are set here
+	req.GetPageNumber()
+	req.GetPageSize()
+	return req
+}
+
+func ToCatalogCarModelFilter(from CatalogCarModelListRequest) filter.PaginationInputWithFilter {
+	req := ToCatalogFilter(from.CatalogListRequest)
+	req.Filter = map[string]filter.Filter{}
+	for field, id := range map[string]int{"CompanyId": from.CompanyId, "CarTypeId": from.CarTypeId, "GearboxId": from.GearboxId} {
+		if id > 0 {
+			req.Filter[field] = filter.Filter{Type: "equals", From: fmt.Sprint(id), FilterType: "number"}
+		}
+	}
+	return req
+}
+
+func ToCatalogItemResponse(from dto.IdName) CatalogItemResponse {
+	return CatalogItemResponse{Id: from.Id, Name: from.Name}
+}
+
+func ToCatalogCompanyResponse(from dto.Company) CatalogCompanyResponse {
+	return CatalogCompanyResponse{
+		Id:      from.Id,
+		Name:    from.Name,
+		Country: CatalogItemResponse{Id: from.Country.Id, Name: from.Country.Name},
+	}
+}
+
+func ToCatalogColorResponse(from dto.Color) CatalogColorResponse {
+	return CatalogColorResponse{Id: from.Id, Name: from.Name, HexCode: from.HexCode}
+}
+
+func ToCatalogCarModelResponse(from dto.CarModel) CatalogCarModelResponse {
+	response := CatalogCarModelResponse{
+		Id:      from.Id,
+		Name:    from.Name,
+		Company: ToCatalogCompanyResponse(from.Company),
+		CarType: ToCatalogItemResponse(from.CarType),
+		Gearbox: ToCatalogItemResponse(from.Gearbox),
+		Rating:  ToRatingSummaryResponse(from),
+	}
+	for _, item := range from.CarModelImages {
+		if item.IsMainImage {
+			response.MainImageUrl = catalogImageUrl(item.Image)
+		}
+	}
+	newest := 0
+	for _, year := range from.CarModelYears {
+		if price := latestCatalogPrice(year.CarModelPriceHistories); price != nil && year.PersianYear.Year >= newest {
+			newest = year.PersianYear.Year
+			response.LatestPrice, response.LatestPriceCurrency = catalogPrice(*price)
+		}
+	}
+	return response
+}
+
+func ToCatalogCarModelDetailResponse(from dto.CarModel) CatalogCarModelDetailResponse {
+	response := CatalogCarModelDetailResponse{
+		CatalogCarModelResponse: ToCatalogCarModelResponse(from),
+		Colors:                  []CatalogColorResponse{},
+		Years:                   []CatalogYearResponse{},
+		Properties:              []CatalogPropertyResponse{},
+		Images:                  []CatalogImageResponse{},
+		Comments:                []CatalogCommentResponse{},
+	}
+	for _, item := range from.CarModelColors {
+		response.Colors = append(response.Colors, ToCatalogColorResponse(item.Color))
+	}
+	for _, item := range from.CarModelYears {
+		year := CatalogYearResponse{Id: item.PersianYear.Id, PersianTitle: item.PersianYear.PersianTitle, Year: item.PersianYear.Year}
+		if price := latestCatalogPrice(item.CarModelPriceHistories); price != nil {
+			year.Price, year.Currency = catalogPrice(*price)
+			year.PriceAt = &price.PriceAt
+		}
+		response.Years = append(response.Years, year)
+	}
+	sort.Slice(response.Years, func(i, j int) bool { return response.Years[i].Year > response.Years[j].Year })
+	for _, item := range from.CarModelProperties {
+		response.Properties = append(response.Properties, CatalogPropertyResponse{
+			Id:       item.Property.Id,
+			Name:     item.Property.Name,
+			Category: item.Property.Category.Name,
+			Unit:     item.Property.Unit,
+			Value:    item.Value,
+		})
+	}
+	for _, item := range sortCarModelImages(from.CarModelImages) {
+		response.Images = append(response.Images, CatalogImageResponse{
+			Url:         catalogImageUrl(item.Image),
+			Description: item.Image.Description,
+			IsMainImage: item.IsMainImage,
+		})
+	}
+	for _, item := range from.CarModelComments {
+		response.Comments = append(response.Comments, CatalogCommentResponse{
+			Id:             item.Id,
+			ParentId:       item.ParentId,
+			Author:         item.User.FirstName,
+			Message:        item.Message,
+			HelpfulCount:   item.HelpfulCount,
+			UnhelpfulCount: item.UnhelpfulCount,
+		})
+	}
+	return response
+}
+
+// catalogImageUrl is the url of an uploaded file under the static route
+func catalogImageUrl(from dto.File) string {
+	return fmt.Sprintf("%s/%s", catalogImagePath, from.Name)
+}
+
+// catalogPrice returns the converted price when it was converted, otherwise the price as recorded
+func catalogPrice(price dto.CarModelPriceHistory) (*float64, string) {
+	if price.ConvertedPrice != nil {
+		return price.ConvertedPrice, price.ConvertedCurrency
+	}
+	return &price.Price, price.Currency
+}
+
+func latestCatalogPrice(prices []dto.CarModelPriceHistory) *dto.CarModelPriceHistory {
+	var latest *dto.CarModelPriceHistory
+	for i, price := range prices {
+		if latest == nil || price.PriceAt.After(latest.PriceAt) || price.PriceAt.Equal(latest.PriceAt) && price.Id > latest.Id {
+			latest = &prices[i]
+		}
+	}
+	return latest
+}
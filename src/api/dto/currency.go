@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+// CurrencyRequest converts the prices of a response to Currency, IRT is Toman
+type CurrencyRequest struct {
+	Currency string `form:"currency" binding:"omitempty,oneof=IRR IRT USD EUR"`
+}
+
+// CreateExchangeRateRequest rate is the price of one unit of currency in Rial
+type CreateExchangeRateRequest struct {
+	Currency string    `json:"currency" binding:"required,oneof=USD EUR"`
+	Rate     float64   `json:"rate" binding:"required,gt=0"`
+	RateAt   time.Time `json:"rateAt" binding:"required"`
+}
+
+type UpdateExchangeRateRequest struct {
+	Currency string    `json:"currency" binding:"required,oneof=USD EUR"`
+	Rate     float64   `json:"rate" binding:"required,gt=0"`
+	RateAt   time.Time `json:"rateAt" binding:"required"`
+}
+
+type ExchangeRateResponse struct {
+	Id       int       `json:"id"`
+	Currency string    `json:"currency"`
+	Rate     float64   `json:"rate"`
+	RateAt   time.Time `json:"rateAt"`
+}
+
+func ToCurrency(from CurrencyRequest) string {
+	return from.Currency
+}
+
+func ToExchangeRateResponse(from dto.ExchangeRate) ExchangeRateResponse {
+	return ExchangeRateResponse{
+		Id:       from.Id,
+		Currency: from.Currency,
+		Rate:     from.Rate,
+		RateAt:   from.RateAt,
+	}
+}
+
+func ToCreateExchangeRate(from CreateExchangeRateRequest) dto.CreateExchangeRate {
+	return dto.CreateExchangeRate{
+		Currency: from.Currency,
+		Rate:     from.Rate,
+		RateAt:   from.RateAt,
+	}
+}
+
+func ToUpdateExchangeRate(from UpdateExchangeRateRequest) dto.UpdateExchangeRate {
+	return dto.UpdateExchangeRate{
+		Currency: from.Currency,
+		Rate:     from.Rate,
+		RateAt:   from.RateAt,
+	}
+}
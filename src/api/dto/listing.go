@@ -0,0 +1,164 @@
+package dto
+
+import (
+	"time"
+
+	usecase "github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+type CreateListingRequest struct {
+	CarModelYearId int     `json:"carModelYearId" binding:"required"`
+	CityId         int     `json:"cityId" binding:"required"`
+	ColorId        int     `json:"colorId" binding:"required"`
+	Mileage        int     `json:"mileage" binding:"min=0,max=5000000"`
+	Price          float64 `json:"price" binding:"required,gt=0"`
+	Description    string  `json:"description" binding:"max=2000"`
+	ContactName    string  `json:"contactName" binding:"required,max=50"`
+	ContactPhone   string  `json:"contactPhone" binding:"required,mobile"`
+}
+
+type UpdateListingRequest struct {
+	CarModelYearId int     `json:"carModelYearId" binding:"required"`
+	CityId         int     `json:"cityId" binding:"required"`
+	ColorId        int     `json:"colorId" binding:"required"`
+	Mileage        int     `json:"mileage" binding:"min=0,max=5000000"`
+	Price          float64 `json:"price" binding:"required,gt=0"`
+	Description    string  `json:"description" binding:"max=2000"`
+	ContactName    string  `json:"contactName" binding:"required,max=50"`
+	ContactPhone   string  `json:"contactPhone" binding:"required,mobile"`
+}
+
+// pending_review submits a draft or renews an expired listing, draft withdraws a listing and
+// sold closes a published one
+type ChangeListingStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=pending_review draft sold"`
+}
+
+// published publishes a listing under review, draft sends it or a published listing back to its owner
+type ModerateListingRequest struct {
+	Status         string `json:"status" binding:"required,oneof=published draft"`
+	ModerationNote string `json:"moderationNote" binding:"max=500"`
+}
+
+type UploadListingImageRequest struct {
+	FileFormRequest
+	Description string `json:"description" form:"description" binding:"max=500"`
+}
+
+type ListingResponse struct {
+	Id             int                    `json:"id"`
+	CarModelYearId int                    `json:"carModelYearId"`
+	CarModelId     int                    `json:"carModelId"`
+	CarModelName   string                 `json:"carModelName"`
+	CompanyName    string                 `json:"companyName"`
+	PersianTitle   string                 `json:"persianTitle"`
+	Year           int                    `json:"year"`
+	City           CatalogItemResponse    `json:"city"`
+	Color          CatalogColorResponse   `json:"color"`
+	Mileage        int                    `json:"mileage"`
+	Price          float64                `json:"price"`
+	Description    string                 `json:"description"`
+	ContactName    string                 `json:"contactName"`
+	ContactPhone   string                 `json:"contactPhone"`
+	Status         string                 `json:"status"`
+	ModerationNote string                 `json:"moderationNote,omitempty"`
+	ModeratedAt    *time.Time             `json:"moderatedAt"`
+	PublishedAt    *time.Time             `json:"publishedAt"`
+	ExpiresAt      *time.Time             `json:"expiresAt"`
+	SoldAt         *time.Time             `json:"soldAt"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	Images         []ListingImageResponse `json:"images,omitempty"`
+}
+
+type ListingImageResponse struct {
+	Id          int    `json:"id"`
+	Url         string `json:"url"`
+	Description string `json:"description"`
+	IsMainImage bool   `json:"isMainImage"`
+}
+
+func ToCreateListing(from CreateListingRequest) usecase.CreateListing {
+	return usecase.CreateListing{
+		CarModelYearId: from.CarModelYearId,
+		CityId:         from.CityId,
+		ColorId:        from.ColorId,
+		Mileage:        from.Mileage,
+		Price:          from.Price,
+		Description:    from.Description,
+		ContactName:    from.ContactName,
+		ContactPhone:   from.ContactPhone,
+	}
+}
+
+func ToUpdateListing(from UpdateListingRequest) usecase.UpdateListing {
+	return usecase.UpdateListing{
+		CarModelYearId: from.CarModelYearId,
+		CityId:         from.CityId,
+		ColorId:        from.ColorId,
+		Mileage:        from.Mileage,
+		Price:          from.Price,
+		Description:    from.Description,
+		ContactName:    from.ContactName,
+		ContactPhone:   from.ContactPhone,
+	}
+}
+
+func ToChangeListingStatus(from ChangeListingStatusRequest) usecase.ChangeListingStatus {
+	return usecase.ChangeListingStatus{
+		Status: from.Status,
+	}
+}
+
+func ToModerateListing(from ModerateListingRequest) usecase.ModerateListing {
+	return usecase.ModerateListing{
+		Status:         from.Status,
+		ModerationNote: from.ModerationNote,
+	}
+}
+
+func ToListingResponse(from usecase.Listing) ListingResponse {
+	response := ListingResponse{
+		Id:             from.Id,
+		CarModelYearId: from.CarModelYear.Id,
+		CarModelId:     from.CarModelYear.CarModel.Id,
+		CarModelName:   from.CarModelYear.CarModel.Name,
+		CompanyName:    from.CarModelYear.CarModel.Company.Name,
+		PersianTitle:   from.CarModelYear.PersianYear.PersianTitle,
+		Year:           from.CarModelYear.PersianYear.Year,
+		City:           ToCatalogItemResponse(from.City),
+		Color:          ToCatalogColorResponse(from.Color),
+		Mileage:        from.Mileage,
+		Price:          from.Price,
+		Description:    from.Description,
+		ContactName:    from.ContactName,
+		ContactPhone:   from.ContactPhone,
+		Status:         from.Status,
+		ModerationNote: from.ModerationNote,
+		CreatedAt:      from.CreatedAt,
+	}
+	if from.ModeratedAt.Valid {
+		response.ModeratedAt = &from.ModeratedAt.Time
+	}
+	if from.PublishedAt.Valid {
+		response.PublishedAt = &from.PublishedAt.Time
+	}
+	if from.ExpiresAt.Valid {
+		response.ExpiresAt = &from.ExpiresAt.Time
+	}
+	if from.SoldAt.Valid {
+		response.SoldAt = &from.SoldAt.Time
+	}
+	// only a single listing has its gallery
+	if from.Images != nil {
+		response.Images = []ListingImageResponse{}
+		for i, item := range from.Images {
+			response.Images = append(response.Images, ListingImageResponse{
+				Id:          item.Id,
+				Url:         catalogImageUrl(item.Image),
+				Description: item.Image.Description,
+				IsMainImage: i == 0,
+			})
+		}
+	}
+	return response
+}
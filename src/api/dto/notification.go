@@ -0,0 +1,112 @@
+package dto
+
+import (
+	"time"
+
+	usecase "github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+// Rule above and below need a threshold, change needs a percent. Currency of the threshold defaults to IRT, Toman
+type CreatePriceAlertRequest struct {
+	CarModelYearId int     `json:"carModelYearId" binding:"required"`
+	Rule           string  `json:"rule" binding:"required,oneof=above below change"`
+	Threshold      float64 `json:"threshold" binding:"min=0"`
+	Currency       string  `json:"currency" binding:"omitempty,oneof=IRR IRT USD EUR"`
+	Percent        float64 `json:"percent" binding:"min=0,max=1000"`
+	Channel        string  `json:"channel" binding:"required,oneof=in_app sms email"`
+}
+
+type UpdatePriceAlertRequest struct {
+	Rule      string  `json:"rule" binding:"required,oneof=above below change"`
+	Threshold float64 `json:"threshold" binding:"min=0"`
+	Percent   float64 `json:"percent" binding:"min=0,max=1000"`
+	Channel   string  `json:"channel" binding:"required,oneof=in_app sms email"`
+	Enabled   bool    `json:"enabled"`
+}
+
+type PriceAlertResponse struct {
+	Id              int        `json:"id"`
+	CarModelYearId  int        `json:"carModelYearId"`
+	Rule            string     `json:"rule"`
+	Threshold       float64    `json:"threshold"`
+	Currency        string     `json:"currency"`
+	Percent         float64    `json:"percent"`
+	Channel         string     `json:"channel"`
+	Enabled         bool       `json:"enabled"`
+	BasePrice       *float64   `json:"basePrice"`
+	LastTriggeredAt *time.Time `json:"lastTriggeredAt"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+type NotificationResponse struct {
+	Id        int        `json:"id"`
+	Channel   string     `json:"channel"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Status    string     `json:"status"`
+	SentAt    *time.Time `json:"sentAt"`
+	ReadAt    *time.Time `json:"readAt"`
+	LastError string     `json:"lastError,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func ToCreatePriceAlert(from CreatePriceAlertRequest) usecase.CreatePriceAlert {
+	return usecase.CreatePriceAlert{
+		CarModelYearId: from.CarModelYearId,
+		Rule:           from.Rule,
+		Threshold:      from.Threshold,
+		Currency:       from.Currency,
+		Percent:        from.Percent,
+		Channel:        from.Channel,
+	}
+}
+
+func ToUpdatePriceAlert(from UpdatePriceAlertRequest) usecase.UpdatePriceAlert {
+	return usecase.UpdatePriceAlert{
+		Rule:      from.Rule,
+		Threshold: from.Threshold,
+		Percent:   from.Percent,
+		Channel:   from.Channel,
+		Enabled:   from.Enabled,
+	}
+}
+
+func ToPriceAlertResponse(from usecase.PriceAlert) PriceAlertResponse {
+	response := PriceAlertResponse{
+		Id:             from.Id,
+		CarModelYearId: from.CarModelYearId,
+		Rule:           from.Rule,
+		Threshold:      from.Threshold,
+		Currency:       from.Currency,
+		Percent:        from.Percent,
+		Channel:        from.Channel,
+		Enabled:        from.Enabled,
+		CreatedAt:      from.CreatedAt,
+	}
+	if from.BasePrice.Valid {
+		response.BasePrice = &from.BasePrice.Float64
+	}
+	if from.LastTriggeredAt.Valid {
+		response.LastTriggeredAt = &from.LastTriggeredAt.Time
+	}
+	return response
+}
+
+func ToNotificationResponse(from usecase.Notification) NotificationResponse {
+	response := NotificationResponse{
+		Id:        from.Id,
+		Channel:   from.Channel,
+		Title:     from.Title,
+		Body:      from.Body,
+		Status:    from.Status,
+		LastError: from.LastError,
+		CreatedAt: from.CreatedAt,
+	}
+	if from.SentAt.Valid {
+		response.SentAt = &from.SentAt.Time
+	}
+	if from.ReadAt.Valid {
+		response.ReadAt = &from.ReadAt.Time
+	}
+	return response
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/naeemaei/golang-clean-web-api/usecase/dto"
+import (
+	"strings"
+
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
 
 type CreatePropertyCategoryRequest struct {
 	Name string `json:"name" binding:"required,alpha,min=3,max=50"`
@@ -19,54 +23,76 @@ type PropertyCategoryResponse struct {
 	Properties []PropertyResponse `json:"properties,omitempty"`
 }
 
+// CreatePropertyRequest dataType defaults to string, minValue and maxValue bound int, decimal and range values
+// and allowedValues are the values of an enum
 type CreatePropertyRequest struct {
-	Name        string `json:"name" binding:"required,alpha,min=3,max=50"`
-	CategoryId  int    `json:"categoryId" binding:"required"`
-	Icon        string `json:"icon" binding:"max=1000"`
-	Description string `json:"description" binding:"max=1000"`
-	DataType    string `json:"dataType" binding:"max=15"`
-	Unit        string `json:"unit" binding:"max=15"`
+	Name          string   `json:"name" binding:"required,alpha,min=3,max=50"`
+	CategoryId    int      `json:"categoryId" binding:"required"`
+	Icon          string   `json:"icon" binding:"max=1000"`
+	Description   string   `json:"description" binding:"max=1000"`
+	DataType      string   `json:"dataType" binding:"omitempty,oneof=string int decimal bool enum range"`
+	Unit          string   `json:"unit" binding:"max=15"`
+	MinValue      *float64 `json:"minValue"`
+	MaxValue      *float64 `json:"maxValue"`
+	AllowedValues []string `json:"allowedValues" binding:"max=50,dive,excludesall=0x2C,max=50"`
 }
 
 type UpdatePropertyRequest struct {
-	Name        string `json:"name,omitempty"`
-	CategoryId  int    `json:"categoryId,omitempty"`
-	Icon        string `json:"icon,omitempty" binding:"max=1000"`
-	Description string `json:"description,omitempty" binding:"max=1000"`
-	DataType    string `json:"dataType,omitempty" binding:"max=15"`
-	Unit        string `json:"unit,omitempty" binding:"max=15"`
+	Name          string   `json:"name,omitempty"`
+	CategoryId    int      `json:"categoryId,omitempty"`
+	Icon          string   `json:"icon,omitempty" binding:"max=1000"`
+	Description   string   `json:"description,omitempty" binding:"max=1000"`
+	DataType      string   `json:"dataType,omitempty" binding:"omitempty,oneof=string int decimal bool enum range"`
+	Unit          string   `json:"unit,omitempty" binding:"max=15"`
+	MinValue      *float64 `json:"minValue,omitempty"`
+	MaxValue      *float64 `json:"maxValue,omitempty"`
+	AllowedValues []string `json:"allowedValues,omitempty" binding:"max=50,dive,excludesall=0x2C,max=50"`
 }
 
 type PropertyResponse struct {
-	Id          int                      `json:"id"`
-	Name        string                   `json:"name"`
-	Icon        string                   `json:"icon"`
-	Description string                   `json:"description"`
-	DataType    string                   `json:"dataType"`
-	Unit        string                   `json:"unit"`
-	Category    PropertyCategoryResponse `json:"category,omitempty"`
+	Id            int                      `json:"id"`
+	Name          string                   `json:"name"`
+	Icon          string                   `json:"icon"`
+	Description   string                   `json:"description"`
+	DataType      string                   `json:"dataType"`
+	Unit          string                   `json:"unit"`
+	MinValue      *float64                 `json:"minValue,omitempty"`
+	MaxValue      *float64                 `json:"maxValue,omitempty"`
+	AllowedValues []string                 `json:"allowedValues,omitempty"`
+	Category      PropertyCategoryResponse `json:"category,omitempty"`
 }
 
 func ToPropertyResponse(from dto.Property) PropertyResponse {
-	return PropertyResponse{
+	response := PropertyResponse{
 		Id:          from.Id,
 		Name:        from.Name,
 		Icon:        from.Icon,
 		DataType:    from.DataType,
 		Unit:        from.Unit,
+		MinValue:    from.MinValue,
+		MaxValue:    from.MaxValue,
 		Category:    ToPropertyCategoryResponse(from.Category),
 		Description: from.Description,
 	}
+	if from.AllowedValues != "" {
+		response.AllowedValues = strings.Split(from.AllowedValues, ",")
+	}
+	return response
 }
 
 func ToCreateProperty(from CreatePropertyRequest) dto.CreateProperty {
 	return dto.CreateProperty{
 		Name:        from.Name,
 		Icon:        from.Icon,
-		DataType:    from.DataType,
 		Unit:        from.Unit,
 		CategoryId:  from.CategoryId,
 		Description: from.Description,
+		PropertyType: dto.PropertyType{
+			DataType:      from.DataType,
+			MinValue:      from.MinValue,
+			MaxValue:      from.MaxValue,
+			AllowedValues: strings.Join(from.AllowedValues, ","),
+		},
 	}
 }
 
@@ -74,10 +100,15 @@ func ToUpdateProperty(from UpdatePropertyRequest) dto.UpdateProperty {
 	return dto.UpdateProperty{
 		Name:        from.Name,
 		Icon:        from.Icon,
-		DataType:    from.DataType,
 		Unit:        from.Unit,
 		CategoryId:  from.CategoryId,
 		Description: from.Description,
+		PropertyType: dto.PropertyType{
+			DataType:      from.DataType,
+			MinValue:      from.MinValue,
+			MaxValue:      from.MaxValue,
+			AllowedValues: strings.Join(from.AllowedValues, ","),
+		},
 	}
 }
 
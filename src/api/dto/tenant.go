@@ -0,0 +1,57 @@
+package dto
+
+import usecase "github.com/naeemaei/golang-clean-web-api/usecase/dto"
+
+type CreateTenantRequest struct {
+	Name string `json:"name" binding:"required,min=3,max=50"`
+	Slug string `json:"slug" binding:"required,slug,min=3,max=30"`
+}
+
+type UpdateTenantRequest struct {
+	Name    string `json:"name" binding:"required,min=3,max=50"`
+	Enabled bool   `json:"enabled"`
+}
+
+type TenantResponse struct {
+	Id      int    `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	Enabled bool   `json:"enabled"`
+}
+
+type TenantAdminResponse struct {
+	Id       int    `json:"id"`
+	TenantId int    `json:"tenantId"`
+	Username string `json:"username"`
+}
+
+func ToCreateTenant(from CreateTenantRequest) usecase.CreateTenant {
+	return usecase.CreateTenant{
+		Name: from.Name,
+		Slug: from.Slug,
+	}
+}
+
+func ToUpdateTenant(from UpdateTenantRequest) usecase.UpdateTenant {
+	return usecase.UpdateTenant{
+		Name:    from.Name,
+		Enabled: from.Enabled,
+	}
+}
+
+func ToTenantResponse(from usecase.Tenant) TenantResponse {
+	return TenantResponse{
+		Id:      from.Id,
+		Name:    from.Name,
+		Slug:    from.Slug,
+		Enabled: from.Enabled,
+	}
+}
+
+func ToTenantAdminResponse(from usecase.TenantAdmin) TenantAdminResponse {
+	return TenantAdminResponse{
+		Id:       from.Id,
+		TenantId: from.TenantId,
+		Username: from.Username,
+	}
+}
@@ -18,7 +18,8 @@ type RegisterUserByUsernameRequest struct {
 	LastName  string `json:"lastName" binding:"required,min=6"`
 	Username  string `json:"username" binding:"required,min=5"`
 	Email     string `json:"email" binding:"min=6,email"`
-	Password  string `json:"password" binding:"required,password,min=6"`
+	Password  string `json:"password" binding:"required,password,min=6" normalize:"-"`
+	Language  string `json:"language" binding:"omitempty,oneof=fa en"`
 }
 
 type RegisterLoginByMobileRequest struct {
@@ -26,9 +27,14 @@ type RegisterLoginByMobileRequest struct {
 	Otp          string `json:"otp" binding:"required,min=6,max=6"`
 }
 
+// SetLanguageRequest an empty language removes the preference, Accept-Language is used instead
+type SetLanguageRequest struct {
+	Language string `json:"language" binding:"omitempty,oneof=fa en"`
+}
+
 type LoginByUsernameRequest struct {
 	Username string `json:"username" binding:"required,min=5"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required,min=6" normalize:"-"`
 }
 
 func (from RegisterUserByUsernameRequest) ToRegisterUserByUsername() usecase.RegisterUserByUsername {
@@ -38,5 +44,6 @@ func (from RegisterUserByUsernameRequest) ToRegisterUserByUsername() usecase.Reg
 		LastName:  from.LastName,
 		Email:     from.Email,
 		Password:  from.Password,
+		Language:  from.Language,
 	}
 }
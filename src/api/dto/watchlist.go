@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"time"
+
+	usecase "github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+type CreateWatchlistRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// Shared makes the watchlist readable through its share link, unsharing it breaks the old link
+type UpdateWatchlistRequest struct {
+	Name   string `json:"name" binding:"required,max=100"`
+	Shared bool   `json:"shared"`
+}
+
+// Either carModelId or carModelYearId is required, an entry of a year may leave out the car model
+type CreateWatchlistEntryRequest struct {
+	CarModelId     int  `json:"carModelId" binding:"min=0"`
+	CarModelYearId *int `json:"carModelYearId" binding:"omitempty,min=1"`
+}
+
+type WatchlistResponse struct {
+	Id         int                      `json:"id"`
+	Name       string                   `json:"name"`
+	Shared     bool                     `json:"shared"`
+	ShareToken string                   `json:"shareToken,omitempty"`
+	CreatedAt  time.Time                `json:"createdAt"`
+	Entries    []WatchlistEntryResponse `json:"entries,omitempty"`
+}
+
+// SharedWatchlistResponse is read anonymously, so it has neither the owner nor the share token
+type SharedWatchlistResponse struct {
+	Name    string                   `json:"name"`
+	Entries []WatchlistEntryResponse `json:"entries"`
+}
+
+type WatchlistEntryResponse struct {
+	Id             int                      `json:"id"`
+	CarModelId     int                      `json:"carModelId"`
+	CarModelName   string                   `json:"carModelName"`
+	CompanyName    string                   `json:"companyName"`
+	CarModelYearId *int                     `json:"carModelYearId"`
+	Followers      int                      `json:"followers"`
+	LatestPrices   []WatchlistPriceResponse `json:"latestPrices"`
+}
+
+type WatchlistPriceResponse struct {
+	CarModelYearId int       `json:"carModelYearId"`
+	PersianTitle   string    `json:"persianTitle"`
+	Year           int       `json:"year"`
+	Price          float64   `json:"price"`
+	Currency       string    `json:"currency"`
+	PriceAt        time.Time `json:"priceAt"`
+}
+
+func ToCreateWatchlist(from CreateWatchlistRequest) usecase.CreateWatchlist {
+	return usecase.CreateWatchlist{
+		Name: from.Name,
+	}
+}
+
+func ToUpdateWatchlist(from UpdateWatchlistRequest) usecase.UpdateWatchlist {
+	return usecase.UpdateWatchlist{
+		Name:   from.Name,
+		Shared: from.Shared,
+	}
+}
+
+func ToCreateWatchlistEntry(from CreateWatchlistEntryRequest) usecase.CreateWatchlistEntry {
+	return usecase.CreateWatchlistEntry{
+		CarModelId:     from.CarModelId,
+		CarModelYearId: from.CarModelYearId,
+	}
+}
+
+func ToWatchlistResponse(from usecase.Watchlist) WatchlistResponse {
+	response := WatchlistResponse{
+		Id:        from.Id,
+		Name:      from.Name,
+		Shared:    from.ShareToken != nil,
+		CreatedAt: from.CreatedAt,
+	}
+	if from.ShareToken != nil {
+		response.ShareToken = *from.ShareToken
+	}
+	if from.Entries != nil {
+		response.Entries = ToWatchlistEntryResponses(from.Entries)
+	}
+	return response
+}
+
+func ToSharedWatchlistResponse(from usecase.Watchlist) SharedWatchlistResponse {
+	return SharedWatchlistResponse{
+		Name:    from.Name,
+		Entries: ToWatchlistEntryResponses(from.Entries),
+	}
+}
+
+func ToWatchlistEntryResponses(from []usecase.WatchlistEntry) []WatchlistEntryResponse {
+	entries := []WatchlistEntryResponse{}
+	for _, item := range from {
+		entry := WatchlistEntryResponse{
+			Id:             item.Id,
+			CarModelId:     item.CarModelId,
+			CarModelName:   item.CarModelName,
+			CompanyName:    item.CompanyName,
+			CarModelYearId: item.CarModelYearId,
+			Followers:      item.Followers,
+			LatestPrices:   []WatchlistPriceResponse{},
+		}
+		for _, price := range item.LatestPrices {
+			entry.LatestPrices = append(entry.LatestPrices, WatchlistPriceResponse{
+				CarModelYearId: price.CarModelYearId,
+				PersianTitle:   price.PersianTitle,
+				Year:           price.Year,
+				Price:          price.Price,
+				Currency:       price.Currency,
+				PriceAt:        price.PriceAt,
+			})
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	usecase "github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+type CreateWebhookSubscriptionRequest struct {
+	Url string `json:"url" binding:"required,url,max=500"`
+	// EntityCreated, EntityUpdated, EntityDeleted, PriceChanged or * for all of them
+	EventTypes []string `json:"eventTypes" binding:"required,min=1"`
+	// Entity names like CarModel, empty for all entities
+	Entities []string `json:"entities"`
+	// Generated when empty, it is only returned by create
+	Secret string `json:"secret" binding:"omitempty,min=16,max=100" normalize:"-"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	Url        string   `json:"url" binding:"required,url,max=500"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1"`
+	Entities   []string `json:"entities"`
+	Enabled    bool     `json:"enabled"`
+}
+
+type WebhookSubscriptionResponse struct {
+	Id         int      `json:"id"`
+	Url        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	Entities   []string `json:"entities"`
+	Enabled    bool     `json:"enabled"`
+	Secret     string   `json:"secret,omitempty"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id             int             `json:"id"`
+	SubscriptionId int             `json:"subscriptionId"`
+	EventId        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastAttemptAt  *time.Time      `json:"lastAttemptAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+	ResponseStatus int             `json:"responseStatus"`
+	LastError      string          `json:"lastError"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+func ToCreateWebhookSubscription(from CreateWebhookSubscriptionRequest) usecase.CreateWebhookSubscription {
+	return usecase.CreateWebhookSubscription{
+		Url:        from.Url,
+		EventTypes: strings.Join(from.EventTypes, ","),
+		Entities:   strings.Join(from.Entities, ","),
+		Secret:     from.Secret,
+	}
+}
+
+func ToUpdateWebhookSubscription(from UpdateWebhookSubscriptionRequest) usecase.UpdateWebhookSubscription {
+	return usecase.UpdateWebhookSubscription{
+		Url:        from.Url,
+		EventTypes: strings.Join(from.EventTypes, ","),
+		Entities:   strings.Join(from.Entities, ","),
+		Enabled:    from.Enabled,
+	}
+}
+
+// ToWebhookSubscriptionResponse leaves the secret out
+func ToWebhookSubscriptionResponse(from usecase.WebhookSubscription) WebhookSubscriptionResponse {
+	return WebhookSubscriptionResponse{
+		Id:         from.Id,
+		Url:        from.Url,
+		EventTypes: splitList(from.EventTypes),
+		Entities:   splitList(from.Entities),
+		Enabled:    from.Enabled,
+	}
+}
+
+// ToCreatedWebhookSubscriptionResponse returns the secret once, so the partner can verify signatures
+func ToCreatedWebhookSubscriptionResponse(from usecase.WebhookSubscription) WebhookSubscriptionResponse {
+	response := ToWebhookSubscriptionResponse(from)
+	response.Secret = from.Secret
+	return response
+}
+
+func ToWebhookDeliveryResponse(from usecase.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		Id:             from.Id,
+		SubscriptionId: from.SubscriptionId,
+		EventId:        from.EventId,
+		EventType:      from.EventType,
+		Status:         from.Status,
+		Attempts:       from.Attempts,
+		NextAttemptAt:  from.NextAttemptAt,
+		ResponseStatus: from.ResponseStatus,
+		LastError:      from.LastError,
+		CreatedAt:      from.CreatedAt,
+	}
+	if from.Payload != "" {
+		response.Payload = json.RawMessage(from.Payload)
+	}
+	if from.LastAttemptAt.Valid {
+		response.LastAttemptAt = &from.LastAttemptAt.Time
+	}
+	if from.DeliveredAt.Valid {
+		response.DeliveredAt = &from.DeliveredAt.Time
+	}
+	return response
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return []string{}
+	}
+	return strings.Split(list, ",")
+}
@@ -6,18 +6,15 @@ import (
 	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
+// StartAt and EndAt of a PersianYear are computed from Year
 type CreatePersianYearRequest struct {
-	PersianTitle string    `json:"persianTitle" binding:"min=4,max=4"`
-	Year         int       `json:"year"`
-	StartAt      time.Time `json:"startAt"`
-	EndAt        time.Time `json:"endAt"`
+	PersianTitle string `json:"persianTitle" binding:"min=4,max=4"`
+	Year         int    `json:"year" binding:"required,min=1"`
 }
 
 type UpdatePersianYearRequest struct {
-	PersianTitle string    `json:"persianTitle,omitempty" binding:"min=4,max=4"`
-	Year         int       `json:"year,omitempty"`
-	StartAt      time.Time `json:"startAt,omitempty"`
-	EndAt        time.Time `json:"endAt,omitempty"`
+	PersianTitle string `json:"persianTitle,omitempty" binding:"min=4,max=4"`
+	Year         int    `json:"year,omitempty" binding:"required,min=1"`
 }
 
 type PersianYearResponse struct {
@@ -56,8 +53,6 @@ func ToCreatePersianYear(from CreatePersianYearRequest) dto.CreatePersianYear {
 	return dto.CreatePersianYear{
 		PersianTitle: from.PersianTitle,
 		Year:         from.Year,
-		StartAt:      from.StartAt,
-		EndAt:        from.EndAt,
 	}
 }
 
@@ -65,7 +60,5 @@ func ToUpdatePersianYear(from UpdatePersianYearRequest) dto.UpdatePersianYear {
 	return dto.UpdatePersianYear{
 		PersianTitle: from.PersianTitle,
 		Year:         from.Year,
-		StartAt:      from.StartAt,
-		EndAt:        from.EndAt,
 	}
 }
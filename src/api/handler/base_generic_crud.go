@@ -37,7 +37,7 @@ func Create[TRequest any, TUInput any, TUOutput any, TResponse any](c *gin.Conte
 	err := c.ShouldBindJSON(&request)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 
@@ -48,7 +48,7 @@ func Create[TRequest any, TUInput any, TUOutput any, TResponse any](c *gin.Conte
 	usecaseResult, err := usecaseCreate(c, usecaseInput)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -78,7 +78,7 @@ func Update[TRequest any, TUInput any, TUOutput any, TResponse any](c *gin.Conte
 	err := c.ShouldBindJSON(&request)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 
 	}
@@ -89,7 +89,7 @@ func Update[TRequest any, TUInput any, TUOutput any, TResponse any](c *gin.Conte
 	usecaseResult, err := usecaseUpdate(c, id, usecaseInput)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -110,7 +110,7 @@ func Delete(c *gin.Context, usecaseDelete func(ctx context.Context, id int) erro
 	err := usecaseDelete(c, id)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, 0))
@@ -135,7 +135,47 @@ func GetById[TUOutput any, TResponse any](c *gin.Context,
 	usecaseResult, err := usecaseGet(c, id)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+
+	// map usecase response to http response
+	response := responseMapper(usecaseResult)
+
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(response, true, 0))
+}
+
+// Get a result of an entity by its id and the query string
+// TRequest: Http query string
+// TUInput: Usecase method input that mapped from TRequest with TUInput := mapper(TRequest)
+// TUOutput: Usecase function output
+// TResponse: Http response body that mapped from TUOutput with TResponse := mapper(TUOutput)
+// usecaseGet: usecase method of the entity id
+func GetByIdWithQuery[TRequest any, TUInput any, TUOutput any, TResponse any](c *gin.Context,
+	requestMapper func(req TRequest) (res TUInput),
+	responseMapper func(req TUOutput) (res TResponse),
+	usecaseGet func(c context.Context, id int, req TUInput) (TUOutput, error)) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	if id == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			helper.GenerateBaseResponse(nil, false, helper.ValidationError))
+		return
+	}
+
+	// bind http query string
+	request := new(TRequest)
+	err := c.ShouldBindQuery(request)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+
+	// call use case method
+	usecaseResult, err := usecaseGet(c, id, requestMapper(*request))
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -150,7 +190,8 @@ func GetById[TUOutput any, TResponse any](c *gin.Context,
 // TResponse: Http response body that mapped from TUOutput with TResponse := mapper(TUOutput)
 // responseMapper: this function map usecase output to endpoint output
 // usecaseList: usecase GetByFilter method
-func GetByFilter[TUOutput any, TResponse any](c *gin.Context,
+// The result is exported as csv or xlsx when requested by ?format= or the Accept header, with the limits of cfg.Export
+func GetByFilter[TUOutput any, TResponse any](c *gin.Context, cfg *config.Config,
 	responseMapper func(req TUOutput) (res TResponse),
 	usecaseList func(c context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[TUOutput], error)) {
 
@@ -158,7 +199,13 @@ func GetByFilter[TUOutput any, TResponse any](c *gin.Context,
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+
+	// csv or xlsx export by format query parameter or Accept header
+	if format, ok := exportFormat(c); ok {
+		exportByFilter(c, cfg.Export, format, *req, responseMapper, usecaseList)
 		return
 	}
 
@@ -166,7 +213,7 @@ func GetByFilter[TUOutput any, TResponse any](c *gin.Context,
 	usecaseResult, err := usecaseList(c, *req)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 	response := filter.PagedList[TResponse]{
@@ -188,3 +235,50 @@ func GetByFilter[TUOutput any, TResponse any](c *gin.Context,
 
 	c.JSON(http.StatusOK, helper.GenerateBaseResponse(response, true, 0))
 }
+
+// Get entities by a query string, e.g. for anonymous GET requests that can be cached
+// TRequest: Http query string
+// TUOutput: Usecase function output
+// TResponse: Http response body that mapped from TUOutput with TResponse := mapper(TUOutput)
+// requestMapper: this function map the query string to the usecase filter
+// responseMapper: this function map usecase output to endpoint output
+// usecaseList: usecase GetByFilter method
+func GetByQuery[TRequest any, TUOutput any, TResponse any](c *gin.Context,
+	requestMapper func(req TRequest) (res filter.PaginationInputWithFilter),
+	responseMapper func(req TUOutput) (res TResponse),
+	usecaseList func(c context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[TUOutput], error)) {
+
+	// bind http query string
+	request := new(TRequest)
+	err := c.ShouldBindQuery(request)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+
+	// call use case method
+	usecaseResult, err := usecaseList(c, requestMapper(*request))
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+
+	// map usecase response to http response
+	items := []TResponse{}
+	for _, item := range *usecaseResult.Items {
+		items = append(items, responseMapper(item))
+	}
+	response := filter.PagedList[TResponse]{
+		PageNumber:      usecaseResult.PageNumber,
+		PageSize:        usecaseResult.PageSize,
+		TotalRows:       usecaseResult.TotalRows,
+		TotalPages:      usecaseResult.TotalPages,
+		HasPreviousPage: usecaseResult.HasPreviousPage,
+		HasNextPage:     usecaseResult.HasNextPage,
+		Items:           &items,
+	}
+
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(response, true, 0))
+}
@@ -1,22 +1,32 @@
 package handler
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/naeemaei/golang-clean-web-api/api/dto"
-	_ "github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/dependency"
-	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/pkg/export"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"github.com/naeemaei/golang-clean-web-api/usecase"
 )
 
 type CarModelHandler struct {
-	usecase *usecase.CarModelUsecase
+	cfg           *config.Config
+	usecase       *usecase.CarModelUsecase
+	importUsecase *usecase.CatalogImportUsecase
+	priceUsecase  *usecase.CarModelPriceHistoryUsecase
 }
 
 func NewCarModelHandler(cfg *config.Config) *CarModelHandler {
 	return &CarModelHandler{
-		usecase: usecase.NewCarModelUsecase(cfg, dependency.GetCarModelRepository(cfg)),
+		cfg:           cfg,
+		usecase:       usecase.NewCarModelUsecase(cfg, dependency.GetCarModelRepository(cfg), dependency.GetExchangeRateRepository(cfg)),
+		importUsecase: usecase.NewCatalogImportUsecase(cfg, dependency.GetCatalogImportRepository(cfg)),
+		priceUsecase:  usecase.NewCarModelPriceHistoryUsecase(cfg, dependency.GetCarModelPriceHistoryRepository(cfg), dependency.GetExchangeRateRepository(cfg)),
 	}
 }
 
@@ -96,5 +106,191 @@ func (h *CarModelHandler) GetById(c *gin.Context) {
 // @Router /v1/car-models/get-by-filter [post]
 // @Security AuthBearer
 func (h *CarModelHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCarModelResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCarModelResponse, h.usecase.GetByFilter)
+}
+
+// SearchCarModels godoc
+// @Summary Search CarModels
+// @Description Full text search over company, model, car type, gearbox and property values with Persian normalization
+// @Tags CarModels
+// @Accept json
+// @produces json
+// @Param q query string true "Search query"
+// @Param pageNumber query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CarModelSearchResponse]} "CarModel search response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/car-models/search [get]
+// @Security AuthBearer
+func (h *CarModelHandler) Search(c *gin.Context) {
+	req := new(dto.CarModelSearchRequest)
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	paging := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageNumber: req.PageNumber, PageSize: req.PageSize},
+	}
+	result, err := h.usecase.Search(c, req.Query, paging)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	items := []dto.CarModelSearchResponse{}
+	for _, item := range *result.Items {
+		items = append(items, dto.ToCarModelSearchResponse(item))
+	}
+	response := filter.PagedList[dto.CarModelSearchResponse]{
+		PageNumber:      result.PageNumber,
+		PageSize:        result.PageSize,
+		TotalRows:       result.TotalRows,
+		TotalPages:      result.TotalPages,
+		HasPreviousPage: result.HasPreviousPage,
+		HasNextPage:     result.HasNextPage,
+		Items:           &items,
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(response, true, helper.Success))
+}
+
+// ImportCarModels godoc
+// @Summary Import CarModels
+// @Description Create or update car models with their colors, years and property values from a csv or xlsx file.
+// @Description Columns are company, model, car_type, gearbox, colors and years, every other column is a property name.
+// @Description Names are resolved to ids, valid rows are saved in one transaction and invalid rows are reported.
+// @Tags CarModels
+// @Accept x-www-form-urlencoded
+// @produces json
+// @Param dryRun formData bool false "Only validate the file"
+// @Param file formData file true "CSV or XLSX file"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CatalogImportResponse} "Import report"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/car-models/import [post]
+// @Security AuthBearer
+func (h *CarModelHandler) Import(c *gin.Context) {
+	req := dto.ImportCarModelsRequest{}
+	err := c.ShouldBind(&req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	format, ok := export.FormatOfFile(req.File.Filename)
+	if !ok {
+		err = &service_errors.ServiceError{EndUserMessage: service_errors.ImportFormatNotSupported}
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	file, err := req.File.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importUsecase.Import(c, format, file, req.DryRun)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	resultCode := helper.Success
+	if result.InvalidRows > 0 || result.ValidRows == 0 {
+		resultCode = helper.ValidationError
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToCatalogImportResponse(result), resultCode == helper.Success, resultCode))
+}
+
+// GetCarModelPriceAnalytics godoc
+// @Summary Get the price analytics of a CarModel
+// @Description Price analytics of every year of the car model, one series per year, see the CarModelYear price analytics.
+// @Description Years without prices in the currency are left out
+// @Tags CarModels
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param bucket query string false "day, week, month or persian_month, default month"
+// @Param from query string false "First day, yyyy-mm-dd"
+// @Param to query string false "Last day, yyyy-mm-dd"
+// @Param currency query string false "IRR, IRT, USD or EUR, default IRT"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CarModelPriceAnalyticsResponse} "Price analytics response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-models/{id}/price-analytics [get]
+// @Security AuthBearer
+func (h *CarModelHandler) PriceAnalytics(c *gin.Context) {
+	GetByIdWithQuery(c, dto.ToPriceAnalyticsQuery, dto.ToCarModelPriceAnalyticsResponse, h.priceUsecase.GetCarModelAnalytics)
+}
+
+// CompareCarModels godoc
+// @Summary Compare CarModels
+// @Description Compare 2 to 4 car models side by side. Property values are grouped by property category with a row per property,
+// @Description the values of every row are in the order of the car models and differs is set when they are not all the same.
+// @Description Prices are the latest price of every car model in every persian year.
+// @Tags CarModels
+// @Accept json
+// @produces json
+// @Param ids query string true "Comma separated car model ids"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CarModelComparisonResponse} "CarModel comparison response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-models/compare [get]
+// @Security AuthBearer
+func (h *CarModelHandler) Compare(c *gin.Context) {
+	req := new(dto.CompareCarModelsRequest)
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	ids, err := dto.ToCompareCarModelIds(*req)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	result, err := h.usecase.Compare(c, ids)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToCarModelComparisonResponse(result), true, helper.Success))
+}
+
+// FacetCarModels godoc
+// @Summary Faceted search of CarModels
+// @Description Filters the car models with in filters on CompanyId, CountryId, CarTypeId, GearboxId, ColorId and PersianYearId,
+// @Description from holds comma separated ids, an equals filter takes one id. Price filters the latest price of the years, inRange is [from, to).
+// @Description The other fields of the car model take the usual filters, a filter on an unknown field is rejected.
+// @Description Every facet counts the car models matching all the other selections, price ranges match all but the Price selection.
+// @Description Items sort by Id, Name or Price.
+// @Tags CarModels
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.FacetedCarModelsResponse} "Faceted CarModels response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/car-models/facets [post]
+// @Security AuthBearer
+func (h *CarModelHandler) Facets(c *gin.Context) {
+	req := new(filter.PaginationInputWithFilter)
+	err := c.ShouldBindJSON(req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	result, err := h.usecase.FacetedSearch(c, *req)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToFacetedCarModelsResponse(result), true, helper.Success))
 }
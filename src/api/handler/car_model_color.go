@@ -11,11 +11,13 @@ import (
 )
 
 type CarModelColorHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CarModelColorUsecase
 }
 
 func NewCarModelColorHandler(cfg *config.Config) *CarModelColorHandler {
 	return &CarModelColorHandler{
+		cfg:     cfg,
 		usecase: usecase.NewCarModelColorUsecase(cfg, dependency.GetCarModelColorRepository(cfg)),
 	}
 }
@@ -96,5 +98,5 @@ func (h *CarModelColorHandler) GetById(c *gin.Context) {
 // @Router /v1/car-model-colors/get-by-filter [post]
 // @Security AuthBearer
 func (h *CarModelColorHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCarModelColorResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCarModelColorResponse, h.usecase.GetByFilter)
 }
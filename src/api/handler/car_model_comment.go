@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"github.com/naeemaei/golang-clean-web-api/api/dto"
-	_ "github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/dependency"
 	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
@@ -11,18 +14,20 @@ import (
 )
 
 type CarModelCommentHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CarModelCommentUsecase
 }
 
 func NewCarModelCommentHandler(cfg *config.Config) *CarModelCommentHandler {
 	return &CarModelCommentHandler{
+		cfg:     cfg,
 		usecase: usecase.NewCarModelCommentUsecase(cfg, dependency.GetCarModelCommentRepository(cfg)),
 	}
 }
 
 // CreateCarModelComment godoc
 // @Summary Create a CarModelComment
-// @Description Create a CarModelComment
+// @Description Create a CarModelComment of the current user, it is public once a moderator approves it
 // @Tags CarModelComments
 // @Accept json
 // @produces json
@@ -37,7 +42,7 @@ func (h *CarModelCommentHandler) Create(c *gin.Context) {
 
 // UpdateCarModelComment godoc
 // @Summary Update a CarModelComment
-// @Description Update a CarModelComment
+// @Description Update a CarModelComment of the current user, the edited comment waits for moderation again
 // @Tags CarModelComments
 // @Accept json
 // @produces json
@@ -54,7 +59,7 @@ func (h *CarModelCommentHandler) Update(c *gin.Context) {
 
 // DeleteCarModelComment godoc
 // @Summary Delete a CarModelComment
-// @Description Delete a CarModelComment
+// @Description Delete a CarModelComment of the current user
 // @Tags CarModelComments
 // @Accept json
 // @produces json
@@ -70,7 +75,7 @@ func (h *CarModelCommentHandler) Delete(c *gin.Context) {
 
 // GetCarModelComment godoc
 // @Summary Get a CarModelComment
-// @Description Get a CarModelComment
+// @Description Get an approved CarModelComment or one of the current user
 // @Tags CarModelComments
 // @Accept json
 // @produces json
@@ -86,7 +91,7 @@ func (h *CarModelCommentHandler) GetById(c *gin.Context) {
 
 // GetCarModelComments godoc
 // @Summary Get CarModelComments
-// @Description Get CarModelComments
+// @Description Get CarModelComments, users get the approved ones only
 // @Tags CarModelComments
 // @Accept json
 // @produces json
@@ -96,5 +101,126 @@ func (h *CarModelCommentHandler) GetById(c *gin.Context) {
 // @Router /v1/car-model-comments/get-by-filter [post]
 // @Security AuthBearer
 func (h *CarModelCommentHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCarModelCommentResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCarModelCommentResponse, h.usecase.GetByFilter)
+}
+
+// GetMyCarModelComments godoc
+// @Summary Get my CarModelComments
+// @Description Get the CarModelComments of the current user in any status
+// @Tags CarModelComments
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CarModelCommentResponse]} "CarModelComment response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/car-model-comments/mine/get-by-filter [post]
+// @Security AuthBearer
+func (h *CarModelCommentHandler) GetMine(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToCarModelCommentResponse, h.usecase.GetMine)
+}
+
+// GetCarModelCommentModerationQueue godoc
+// @Summary Get the moderation queue
+// @Description Get the pending CarModelComments, the most reported first, filter by status to get the others
+// @Tags CarModelComments
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CarModelCommentResponse]} "CarModelComment response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/car-model-comments/moderation/get-by-filter [post]
+// @Security AuthBearer
+func (h *CarModelCommentHandler) GetModerationQueue(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToCarModelCommentResponse, h.usecase.GetModerationQueue)
+}
+
+// ModerateCarModelComment godoc
+// @Summary Moderate a CarModelComment
+// @Description Set the status of a CarModelComment to pending, approved, rejected or hidden, its reports are cleared
+// @Tags CarModelComments
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.ModerateCarModelCommentRequest true "Moderate a CarModelComment"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CarModelCommentResponse} "CarModelComment response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-comments/{id}/moderation [put]
+// @Security AuthBearer
+func (h *CarModelCommentHandler) Moderate(c *gin.Context) {
+	Update(c, dto.ToModerateCarModelComment, dto.ToCarModelCommentResponse, h.usecase.Moderate)
+}
+
+// ReportCarModelComment godoc
+// @Summary Report a CarModelComment
+// @Description Report an approved CarModelComment of another user as abusive, a comment reported by enough users waits for moderation again
+// @Tags CarModelComments
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.ReportCarModelCommentRequest true "Report a CarModelComment"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Already reported"
+// @Router /v1/car-model-comments/{id}/report [post]
+// @Security AuthBearer
+func (h *CarModelCommentHandler) Report(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	request := dto.ReportCarModelCommentRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	if err := h.usecase.Report(c, id, dto.ToCreateCarModelCommentReport(request)); err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+}
+
+// VoteCarModelComment godoc
+// @Summary Vote a CarModelComment
+// @Description Vote whether an approved CarModelComment of another user was helpful, voting again changes the vote
+// @Tags CarModelComments
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.VoteCarModelCommentRequest true "Vote a CarModelComment"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-comments/{id}/vote [put]
+// @Security AuthBearer
+func (h *CarModelCommentHandler) Vote(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	request := dto.VoteCarModelCommentRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	if err := h.usecase.Vote(c, id, dto.ToCreateCarModelCommentVote(request)); err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+}
+
+// RemoveCarModelCommentVote godoc
+// @Summary Remove a vote
+// @Description Remove the vote of the current user on a CarModelComment
+// @Tags CarModelComments
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-comments/{id}/vote [delete]
+// @Security AuthBearer
+func (h *CarModelCommentHandler) RemoveVote(c *gin.Context) {
+	Delete(c, h.usecase.RemoveVote)
 }
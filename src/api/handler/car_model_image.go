@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"github.com/naeemaei/golang-clean-web-api/api/dto"
-	_ "github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/dependency"
 	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
@@ -11,11 +14,13 @@ import (
 )
 
 type CarModelImageHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CarModelImageUsecase
 }
 
 func NewCarModelImageHandler(cfg *config.Config) *CarModelImageHandler {
 	return &CarModelImageHandler{
+		cfg:     cfg,
 		usecase: usecase.NewCarModelImageUsecase(cfg, dependency.GetCarModelImageRepository(cfg)),
 	}
 }
@@ -46,12 +51,67 @@ func (h *CarModelImageHandler) Create(c *gin.Context) {
 // @Success 200 {object} helper.BaseHttpResponse{result=dto.CarModelImageResponse} "CarModelImage response"
 // @Failure 400 {object} helper.BaseHttpResponse "Bad request"
 // @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "The main image can't be unset"
 // @Router /v1/car-model-images/{id} [put]
 // @Security AuthBearer
 func (h *CarModelImageHandler) Update(c *gin.Context) {
 	Update(c, dto.ToUpdateCarModelImage, dto.ToCarModelImageResponse, h.usecase.Update)
 }
 
+// SetMainCarModelImage godoc
+// @Summary Set the main CarModelImage
+// @Description Make a CarModelImage the main image of its CarModel, the previous main image is demoted
+// @Tags CarModelImages
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CarModelImageResponse} "CarModelImage response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-images/{id}/main [put]
+// @Security AuthBearer
+func (h *CarModelImageHandler) SetMain(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	if id == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			helper.GenerateBaseResponse(nil, false, helper.ValidationError))
+		return
+	}
+	image, err := h.usecase.SetMain(c, id)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToCarModelImageResponse(image), true, helper.Success))
+}
+
+// ReorderCarModelImages godoc
+// @Summary Reorder CarModelImages
+// @Description Set the gallery order of a CarModel, imageIds must list all of its CarModelImage ids
+// @Tags CarModelImages
+// @Accept json
+// @produces json
+// @Param Request body dto.ReorderCarModelImagesRequest true "Reorder CarModelImages"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-images/reorder [put]
+// @Security AuthBearer
+func (h *CarModelImageHandler) Reorder(c *gin.Context) {
+	request := dto.ReorderCarModelImagesRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	if err := h.usecase.Reorder(c, dto.ToReorderCarModelImages(request)); err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+}
+
 // DeleteCarModelImage godoc
 // @Summary Delete a CarModelImage
 // @Description Delete a CarModelImage
@@ -96,5 +156,5 @@ func (h *CarModelImageHandler) GetById(c *gin.Context) {
 // @Router /v1/car-model-images/get-by-filter [post]
 // @Security AuthBearer
 func (h *CarModelImageHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCarModelImageResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCarModelImageResponse, h.usecase.GetByFilter)
 }
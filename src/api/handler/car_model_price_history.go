@@ -1,22 +1,28 @@
 package handler
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/naeemaei/golang-clean-web-api/api/dto"
-	_ "github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/dependency"
-	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	"github.com/naeemaei/golang-clean-web-api/usecase"
+	usecaseDto "github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
 type CarModelPriceHistoryHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CarModelPriceHistoryUsecase
 }
 
 func NewCarModelPriceHistoryHandler(cfg *config.Config) *CarModelPriceHistoryHandler {
 	return &CarModelPriceHistoryHandler{
-		usecase: usecase.NewCarModelPriceHistoryUsecase(cfg, dependency.GetCarModelPriceHistoryRepository(cfg)),
+		cfg:     cfg,
+		usecase: usecase.NewCarModelPriceHistoryUsecase(cfg, dependency.GetCarModelPriceHistoryRepository(cfg), dependency.GetExchangeRateRepository(cfg)),
 	}
 }
 
@@ -75,13 +81,14 @@ func (h *CarModelPriceHistoryHandler) Delete(c *gin.Context) {
 // @Accept json
 // @produces json
 // @Param id path int true "Id"
+// @Param currency query string false "IRR, IRT, USD or EUR to convert the price to"
 // @Success 200 {object} helper.BaseHttpResponse{result=dto.CarModelPriceHistoryResponse} "CarModelPriceHistory response"
 // @Failure 400 {object} helper.BaseHttpResponse "Bad request"
 // @Failure 404 {object} helper.BaseHttpResponse "Not found"
 // @Router /v1/car-model-price-histories/{id} [get]
 // @Security AuthBearer
 func (h *CarModelPriceHistoryHandler) GetById(c *gin.Context) {
-	GetById(c, dto.ToCarModelPriceHistoryResponse, h.usecase.GetById)
+	GetByIdWithQuery(c, dto.ToCurrency, dto.ToCarModelPriceHistoryResponse, h.usecase.GetById)
 }
 
 // GetCarModelPriceHistories godoc
@@ -91,10 +98,19 @@ func (h *CarModelPriceHistoryHandler) GetById(c *gin.Context) {
 // @Accept json
 // @produces json
 // @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Param currency query string false "IRR, IRT, USD or EUR to convert the prices to"
 // @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CarModelPriceHistoryResponse]} "CarModelPriceHistory response"
 // @Failure 400 {object} helper.BaseHttpResponse "Bad request"
 // @Router /v1/car-model-price-histories/get-by-filter [post]
 // @Security AuthBearer
 func (h *CarModelPriceHistoryHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCarModelPriceHistoryResponse, h.usecase.GetByFilter)
+	req := new(dto.CurrencyRequest)
+	if err := c.ShouldBindQuery(req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	GetByFilter(c, h.cfg, dto.ToCarModelPriceHistoryResponse, func(ctx context.Context, input filter.PaginationInputWithFilter) (*filter.PagedList[usecaseDto.CarModelPriceHistory], error) {
+		return h.usecase.GetByFilter(ctx, input, req.Currency)
+	})
 }
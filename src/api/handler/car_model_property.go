@@ -11,12 +11,14 @@ import (
 )
 
 type CarModelPropertyHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CarModelPropertyUsecase
 }
 
 func NewCarModelPropertyHandler(cfg *config.Config) *CarModelPropertyHandler {
 	return &CarModelPropertyHandler{
-		usecase: usecase.NewCarModelPropertyUsecase(cfg, dependency.GetCarModelPropertyRepository(cfg)),
+		cfg:     cfg,
+		usecase: usecase.NewCarModelPropertyUsecase(cfg, dependency.GetCarModelPropertyRepository(cfg), dependency.GetPropertyRepository(cfg)),
 	}
 }
 
@@ -96,5 +98,5 @@ func (h *CarModelPropertyHandler) GetById(c *gin.Context) {
 // @Router /v1/car-model-properties/get-by-filter [post]
 // @Security AuthBearer
 func (h *CarModelPropertyHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCarModelPropertyResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCarModelPropertyResponse, h.usecase.GetByFilter)
 }
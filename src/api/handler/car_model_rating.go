@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	_ "github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+type CarModelRatingHandler struct {
+	cfg     *config.Config
+	usecase *usecase.CarModelRatingUsecase
+}
+
+func NewCarModelRatingHandler(cfg *config.Config) *CarModelRatingHandler {
+	return &CarModelRatingHandler{
+		cfg:     cfg,
+		usecase: usecase.NewCarModelRatingUsecase(cfg, dependency.GetCarModelRatingRepository(cfg)),
+	}
+}
+
+// RateCarModel godoc
+// @Summary Rate a CarModel
+// @Description Rate a CarModel with 1 to 5 stars, rating it again changes the stars of the current user
+// @Tags CarModelRatings
+// @Accept json
+// @produces json
+// @Param Request body dto.CreateCarModelRatingRequest true "Rate a CarModel"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.CarModelRatingResponse} "CarModelRating response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-ratings/ [post]
+// @Security AuthBearer
+func (h *CarModelRatingHandler) Create(c *gin.Context) {
+	Create(c, dto.ToCreateCarModelRating, dto.ToCarModelRatingResponse, h.usecase.Rate)
+}
+
+// DeleteCarModelRating godoc
+// @Summary Delete a CarModelRating
+// @Description Delete a CarModelRating of the current user
+// @Tags CarModelRatings
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-ratings/{id} [delete]
+// @Security AuthBearer
+func (h *CarModelRatingHandler) Delete(c *gin.Context) {
+	Delete(c, h.usecase.Delete)
+}
+
+// GetCarModelRating godoc
+// @Summary Get a CarModelRating
+// @Description Get a CarModelRating of the current user
+// @Tags CarModelRatings
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CarModelRatingResponse} "CarModelRating response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-ratings/{id} [get]
+// @Security AuthBearer
+func (h *CarModelRatingHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToCarModelRatingResponse, h.usecase.GetById)
+}
+
+// GetCarModelRatings godoc
+// @Summary Get CarModelRatings
+// @Description Get the CarModelRatings of the current user
+// @Tags CarModelRatings
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CarModelRatingResponse]} "CarModelRating response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/car-model-ratings/get-by-filter [post]
+// @Security AuthBearer
+func (h *CarModelRatingHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToCarModelRatingResponse, h.usecase.GetByFilter)
+}
@@ -11,12 +11,16 @@ import (
 )
 
 type CarModelYearHandler struct {
-	usecase *usecase.CarModelYearUsecase
+	cfg          *config.Config
+	usecase      *usecase.CarModelYearUsecase
+	priceUsecase *usecase.CarModelPriceHistoryUsecase
 }
 
 func NewCarModelYearHandler(cfg *config.Config) *CarModelYearHandler {
 	return &CarModelYearHandler{
-		usecase: usecase.NewCarModelYearUsecase(cfg, dependency.GetCarModelYearRepository(cfg)),
+		cfg:          cfg,
+		usecase:      usecase.NewCarModelYearUsecase(cfg, dependency.GetCarModelYearRepository(cfg)),
+		priceUsecase: usecase.NewCarModelPriceHistoryUsecase(cfg, dependency.GetCarModelPriceHistoryRepository(cfg), dependency.GetExchangeRateRepository(cfg)),
 	}
 }
 
@@ -96,5 +100,25 @@ func (h *CarModelYearHandler) GetById(c *gin.Context) {
 // @Router /v1/car-model-years/get-by-filter [post]
 // @Security AuthBearer
 func (h *CarModelYearHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCarModelYearResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCarModelYearResponse, h.usecase.GetByFilter)
+}
+
+// GetCarModelYearPriceAnalytics godoc
+// @Summary Get the price analytics of a CarModelYear
+// @Description Price time series bucketed by day, week (from Saturday), month or persian_month in Iran time, with min, max, average, last price and change of every bucket and 30, 90 and 365 day changes
+// @Tags CarModelYears
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param bucket query string false "day, week, month or persian_month, default month"
+// @Param from query string false "First day, yyyy-mm-dd"
+// @Param to query string false "Last day, yyyy-mm-dd"
+// @Param currency query string false "IRR, IRT, USD or EUR, default IRT"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.PriceAnalyticsResponse} "Price analytics response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/car-model-years/{id}/price-analytics [get]
+// @Security AuthBearer
+func (h *CarModelYearHandler) PriceAnalytics(c *gin.Context) {
+	GetByIdWithQuery(c, dto.ToPriceAnalyticsQuery, dto.ToPriceAnalyticsResponse, h.priceUsecase.GetCarModelYearAnalytics)
 }
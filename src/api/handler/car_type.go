@@ -11,11 +11,13 @@ import (
 )
 
 type CarTypeHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CarTypeUsecase
 }
 
 func NewCarTypeHandler(cfg *config.Config) *CarTypeHandler {
 	return &CarTypeHandler{
+		cfg:     cfg,
 		usecase: usecase.NewCarTypeUsecase(cfg, dependency.GetCarTypeRepository(cfg)),
 	}
 }
@@ -96,5 +98,5 @@ func (h *CarTypeHandler) GetById(c *gin.Context) {
 // @Router /v1/car-types/get-by-filter [post]
 // @Security AuthBearer
 func (h *CarTypeHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCarTypeResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCarTypeResponse, h.usecase.GetByFilter)
 }
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+// CatalogHandler serves the catalog read only to anonymous clients
+type CatalogHandler struct {
+	carModelUsecase *usecase.CarModelUsecase
+	// listUsecase loads the car models of a page without comments, colors and property values
+	listUsecase    *usecase.CarModelUsecase
+	companyUsecase *usecase.CompanyUsecase
+	colorUsecase   *usecase.ColorUsecase
+	carTypeUsecase *usecase.CarTypeUsecase
+	gearboxUsecase *usecase.GearboxUsecase
+}
+
+func NewCatalogHandler(cfg *config.Config) *CatalogHandler {
+	return &CatalogHandler{
+		carModelUsecase: usecase.NewCarModelUsecase(cfg, dependency.GetCarModelRepository(cfg), dependency.GetExchangeRateRepository(cfg)),
+		listUsecase:     usecase.NewCarModelUsecase(cfg, dependency.GetCatalogCarModelRepository(cfg), dependency.GetExchangeRateRepository(cfg)),
+		companyUsecase:  usecase.NewCompanyUsecase(cfg, dependency.GetCompanyRepository(cfg)),
+		colorUsecase:    usecase.NewColorUsecase(cfg, dependency.GetColorRepository(cfg)),
+		carTypeUsecase:  usecase.NewCarTypeUsecase(cfg, dependency.GetCarTypeRepository(cfg)),
+		gearboxUsecase:  usecase.NewGearboxUsecase(cfg, dependency.GetGearboxRepository(cfg)),
+	}
+}
+
+// GetCatalogCarModels godoc
+// @Summary Get catalog CarModels
+// @Description Get the car models of the catalog ordered by name, the tenant is selected by the X-Tenant slug
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param X-Tenant header string false "Tenant slug"
+// @Param pageNumber query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param companyId query int false "Company id"
+// @Param carTypeId query int false "Car type id"
+// @Param gearboxId query int false "Gearbox id"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CatalogCarModelResponse]} "Catalog CarModel response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/catalog/car-models [get]
+func (h *CatalogHandler) GetCarModels(c *gin.Context) {
+	GetByQuery(c, dto.ToCatalogCarModelFilter, dto.ToCatalogCarModelResponse, h.listUsecase.GetPublicByFilter)
+}
+
+// FacetCatalogCarModels godoc
+// @Summary Faceted search of catalog CarModels
+// @Description The faceted search of /car-models/facets for anonymous clients, the tenant is selected by the X-Tenant slug
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param X-Tenant header string false "Tenant slug"
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.FacetedCarModelsResponse} "Faceted CarModels response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/catalog/car-models/facets [post]
+func (h *CatalogHandler) FacetCarModels(c *gin.Context) {
+	req := new(filter.PaginationInputWithFilter)
+	err := c.ShouldBindJSON(req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	result, err := h.carModelUsecase.FacetedSearch(c, *req)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToFacetedCarModelsResponse(result), true, helper.Success))
+}
+
+// GetCatalogCarModel godoc
+// @Summary Get a catalog CarModel
+// @Description Get a car model of the catalog with its colors, the latest price of every year, property values, images and approved comments
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param X-Tenant header string false "Tenant slug"
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CatalogCarModelDetailResponse} "Catalog CarModel response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/catalog/car-models/{id} [get]
+func (h *CatalogHandler) GetCarModel(c *gin.Context) {
+	GetById(c, dto.ToCatalogCarModelDetailResponse, h.carModelUsecase.GetPublicById)
+}
+
+// GetCatalogCompanies godoc
+// @Summary Get catalog Companies
+// @Description Get the companies of the catalog ordered by name
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param pageNumber query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CatalogCompanyResponse]} "Catalog Company response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/catalog/companies [get]
+func (h *CatalogHandler) GetCompanies(c *gin.Context) {
+	GetByQuery(c, dto.ToCatalogFilter, dto.ToCatalogCompanyResponse, h.companyUsecase.GetByFilter)
+}
+
+// GetCatalogCompany godoc
+// @Summary Get a catalog Company
+// @Description Get a company of the catalog
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CatalogCompanyResponse} "Catalog Company response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/catalog/companies/{id} [get]
+func (h *CatalogHandler) GetCompany(c *gin.Context) {
+	GetById(c, dto.ToCatalogCompanyResponse, h.companyUsecase.GetById)
+}
+
+// GetCatalogColors godoc
+// @Summary Get catalog Colors
+// @Description Get the colors of the catalog ordered by name
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param pageNumber query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CatalogColorResponse]} "Catalog Color response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/catalog/colors [get]
+func (h *CatalogHandler) GetColors(c *gin.Context) {
+	GetByQuery(c, dto.ToCatalogFilter, dto.ToCatalogColorResponse, h.colorUsecase.GetByFilter)
+}
+
+// GetCatalogColor godoc
+// @Summary Get a catalog Color
+// @Description Get a color of the catalog
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CatalogColorResponse} "Catalog Color response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/catalog/colors/{id} [get]
+func (h *CatalogHandler) GetColor(c *gin.Context) {
+	GetById(c, dto.ToCatalogColorResponse, h.colorUsecase.GetById)
+}
+
+// GetCatalogCarTypes godoc
+// @Summary Get catalog CarTypes
+// @Description Get the car types of the catalog ordered by name
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param pageNumber query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CatalogItemResponse]} "Catalog CarType response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/catalog/car-types [get]
+func (h *CatalogHandler) GetCarTypes(c *gin.Context) {
+	GetByQuery(c, dto.ToCatalogFilter, dto.ToCatalogItemResponse, h.carTypeUsecase.GetByFilter)
+}
+
+// GetCatalogCarType godoc
+// @Summary Get a catalog CarType
+// @Description Get a car type of the catalog
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CatalogItemResponse} "Catalog CarType response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/catalog/car-types/{id} [get]
+func (h *CatalogHandler) GetCarType(c *gin.Context) {
+	GetById(c, dto.ToCatalogItemResponse, h.carTypeUsecase.GetById)
+}
+
+// GetCatalogGearboxes godoc
+// @Summary Get catalog Gearboxes
+// @Description Get the gearboxes of the catalog ordered by name
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param pageNumber query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.CatalogItemResponse]} "Catalog Gearbox response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/catalog/gearboxes [get]
+func (h *CatalogHandler) GetGearboxes(c *gin.Context) {
+	GetByQuery(c, dto.ToCatalogFilter, dto.ToCatalogItemResponse, h.gearboxUsecase.GetByFilter)
+}
+
+// GetCatalogGearbox godoc
+// @Summary Get a catalog Gearbox
+// @Description Get a gearbox of the catalog
+// @Tags Catalog
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CatalogItemResponse} "Catalog Gearbox response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/catalog/gearboxes/{id} [get]
+func (h *CatalogHandler) GetGearbox(c *gin.Context) {
+	GetById(c, dto.ToCatalogItemResponse, h.gearboxUsecase.GetById)
+}
@@ -11,11 +11,13 @@ import (
 )
 
 type CityHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CityUsecase
 }
 
 func NewCityHandler(cfg *config.Config) *CityHandler {
 	return &CityHandler{
+		cfg:     cfg,
 		usecase: usecase.NewCityUsecase(cfg, dependency.GetCityRepository(cfg)),
 	}
 }
@@ -96,5 +98,5 @@ func (h *CityHandler) GetById(c *gin.Context) {
 // @Router /v1/cities/get-by-filter [post]
 // @Security AuthBearer
 func (h *CityHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCityResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCityResponse, h.usecase.GetByFilter)
 }
@@ -11,11 +11,13 @@ import (
 )
 
 type ColorHandler struct {
+	cfg     *config.Config
 	usecase *usecase.ColorUsecase
 }
 
 func NewColorHandler(cfg *config.Config) *ColorHandler {
 	return &ColorHandler{
+		cfg:     cfg,
 		usecase: usecase.NewColorUsecase(cfg, dependency.GetColorRepository(cfg)),
 	}
 }
@@ -96,5 +98,5 @@ func (h *ColorHandler) GetById(c *gin.Context) {
 // @Router /v1/colors/get-by-filter [post]
 // @Security AuthBearer
 func (h *ColorHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToColorResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToColorResponse, h.usecase.GetByFilter)
 }
@@ -11,11 +11,13 @@ import (
 )
 
 type CompanyHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CompanyUsecase
 }
 
 func NewCompanyHandler(cfg *config.Config) *CompanyHandler {
 	return &CompanyHandler{
+		cfg:     cfg,
 		usecase: usecase.NewCompanyUsecase(cfg, dependency.GetCompanyRepository(cfg)),
 	}
 }
@@ -96,5 +98,5 @@ func (h *CompanyHandler) GetById(c *gin.Context) {
 // @Router /v1/companies/get-by-filter [post]
 // @Security AuthBearer
 func (h *CompanyHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCompanyResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCompanyResponse, h.usecase.GetByFilter)
 }
@@ -11,11 +11,13 @@ import (
 )
 
 type CountryHandler struct {
+	cfg     *config.Config
 	usecase *usecase.CountryUsecase
 }
 
 func NewCountryHandler(cfg *config.Config) *CountryHandler {
 	return &CountryHandler{
+		cfg:     cfg,
 		usecase: usecase.NewCountryUsecase(cfg, dependency.GetCountryRepository(cfg))}
 }
 
@@ -92,5 +94,5 @@ func (h *CountryHandler) GetById(c *gin.Context) {
 // @Router /v1/countries/get-by-filter [post]
 // @Security AuthBearer
 func (h *CountryHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToCountryResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToCountryResponse, h.usecase.GetByFilter)
 }
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	_ "github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+type ExchangeRateHandler struct {
+	cfg     *config.Config
+	usecase *usecase.ExchangeRateUsecase
+}
+
+func NewExchangeRateHandler(cfg *config.Config) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		cfg:     cfg,
+		usecase: usecase.NewExchangeRateUsecase(cfg, dependency.GetExchangeRateRepository(cfg)),
+	}
+}
+
+// CreateExchangeRate godoc
+// @Summary Create an ExchangeRate
+// @Description Create an ExchangeRate
+// @Tags ExchangeRates
+// @Accept json
+// @produces json
+// @Param Request body dto.CreateExchangeRateRequest true "Create an ExchangeRate"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.ExchangeRateResponse} "ExchangeRate response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 409 {object} helper.BaseHttpResponse "Exists"
+// @Router /v1/exchange-rates/ [post]
+// @Security AuthBearer
+func (h *ExchangeRateHandler) Create(c *gin.Context) {
+	Create(c, dto.ToCreateExchangeRate, dto.ToExchangeRateResponse, h.usecase.Create)
+}
+
+// UpdateExchangeRate godoc
+// @Summary Update an ExchangeRate
+// @Description Update an ExchangeRate
+// @Tags ExchangeRates
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.UpdateExchangeRateRequest true "Update an ExchangeRate"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ExchangeRateResponse} "ExchangeRate response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/exchange-rates/{id} [put]
+// @Security AuthBearer
+func (h *ExchangeRateHandler) Update(c *gin.Context) {
+	Update(c, dto.ToUpdateExchangeRate, dto.ToExchangeRateResponse, h.usecase.Update)
+}
+
+// DeleteExchangeRate godoc
+// @Summary Delete an ExchangeRate
+// @Description Delete an ExchangeRate
+// @Tags ExchangeRates
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/exchange-rates/{id} [delete]
+// @Security AuthBearer
+func (h *ExchangeRateHandler) Delete(c *gin.Context) {
+	Delete(c, h.usecase.Delete)
+}
+
+// GetExchangeRate godoc
+// @Summary Get an ExchangeRate
+// @Description Get an ExchangeRate
+// @Tags ExchangeRates
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ExchangeRateResponse} "ExchangeRate response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/exchange-rates/{id} [get]
+// @Security AuthBearer
+func (h *ExchangeRateHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToExchangeRateResponse, h.usecase.GetById)
+}
+
+// GetExchangeRates godoc
+// @Summary Get ExchangeRates
+// @Description Get ExchangeRates
+// @Tags ExchangeRates
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.ExchangeRateResponse]} "ExchangeRate response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/exchange-rates/get-by-filter [post]
+// @Security AuthBearer
+func (h *ExchangeRateHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToExchangeRateResponse, h.usecase.GetByFilter)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/pkg/export"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+)
+
+const (
+	exportFormatQuery  = "format"
+	exportColumnsQuery = "columns"
+)
+
+var kebabCaseExp = regexp.MustCompile("([a-z0-9])([A-Z])")
+
+// exportFormat selects the export format by the format query parameter or the Accept header,
+// ok is false when the client wants the default json response
+func exportFormat(c *gin.Context) (export.Format, bool) {
+	if value := c.Query(exportFormatQuery); value != "" {
+		return export.ParseFormat(value)
+	}
+	return export.ParseFormat(c.GetHeader("Accept"))
+}
+
+// Export entities by filter as a spreadsheet, pages of ExportConfig.BatchSize rows are fetched
+// with the same DynamicFilter and written to the response one by one
+func exportByFilter[TUOutput any, TResponse any](c *gin.Context, exportConfig config.ExportConfig, format export.Format,
+	req filter.PaginationInputWithFilter,
+	responseMapper func(req TUOutput) (res TResponse),
+	usecaseList func(c context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[TUOutput], error)) {
+
+	columns := export.ParseColumns(c.Query(exportColumnsQuery))
+	if len(columns) == 0 {
+		columns = export.Columns(reflect.TypeOf(*new(TResponse)))
+	}
+	// offset paging needs a stable order
+	if req.Sort == nil || len(*req.Sort) == 0 {
+		req.Sort = &[]filter.Sort{{ColId: "Id", Sort: "asc"}}
+	}
+	batchSize := exportConfig.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	req.PageSize = batchSize
+	req.PageNumber = 1
+
+	// the first page is fetched before writing anything so errors are still json
+	page, err := usecaseList(c, req)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	total := page.TotalRows
+	if maxRows := exportMaxRows(exportConfig, format); maxRows > 0 && total > int64(maxRows) {
+		total = int64(maxRows)
+		c.Header("X-Export-Truncated", "true")
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFileName[TResponse](format)))
+	c.Status(http.StatusOK)
+
+	writer, err := export.NewWriter(format, c.Writer)
+	if err == nil {
+		err = writer.WriteHeader(columns)
+	}
+	written := int64(0)
+	for err == nil {
+		for _, item := range *page.Items {
+			if written >= total {
+				break
+			}
+			if err = writer.WriteRow(export.Values(responseMapper(item), columns)); err != nil {
+				break
+			}
+			written++
+		}
+		if err != nil || written >= total || !page.HasNextPage {
+			break
+		}
+		if err = writer.Flush(); err != nil {
+			break
+		}
+		req.PageNumber++
+		page, err = usecaseList(c, req)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// headers are already sent, the client gets a truncated file
+		logger.Error(logging.IO, logging.Api, err.Error(), nil)
+		_ = c.Error(err)
+	}
+}
+
+// exportMaxRows returns the row cap of format, XLSX is buffered until it is complete and
+// has its own cap when it is lower than MaxRows
+func exportMaxRows(exportConfig config.ExportConfig, format export.Format) int {
+	maxRows := exportConfig.MaxRows
+	if format == export.Xlsx && exportConfig.XlsxMaxRows > 0 && (maxRows <= 0 || exportConfig.XlsxMaxRows < maxRows) {
+		maxRows = exportConfig.XlsxMaxRows
+	}
+	return maxRows
+}
+
+// exportFileName builds e.g. car-model-price-history-2024-05-01.csv from CarModelPriceHistoryResponse
+func exportFileName[TResponse any](format export.Format) string {
+	name := strings.TrimSuffix(reflect.TypeOf(*new(TResponse)).Name(), "Response")
+	name = strings.ToLower(kebabCaseExp.ReplaceAllString(name, "${1}-${2}"))
+	return fmt.Sprintf("%s-%s.%s", name, time.Now().Format("2006-01-02"), format)
+}
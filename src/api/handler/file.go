@@ -22,11 +22,13 @@ import (
 )
 
 type FileHandler struct {
+	cfg     *config.Config
 	usecase *usecase.FileUsecase
 }
 
 func NewFileHandler(cfg *config.Config) *FileHandler {
 	return &FileHandler{
+		cfg:     cfg,
 		usecase: usecase.NewFileUsecase(cfg, dependency.GetFileRepository(cfg)),
 	}
 }
@@ -48,7 +50,7 @@ func (h *FileHandler) Create(c *gin.Context) {
 	err := c.ShouldBind(&upload)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 	req := dto.CreateFileRequest{}
@@ -58,14 +60,14 @@ func (h *FileHandler) Create(c *gin.Context) {
 	req.Name, err = saveUploadedFile(upload.File, req.Directory)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
 	res, err := h.usecase.Create(c, dto.ToCreateFile(req))
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 	c.JSON(http.StatusCreated, helper.GenerateBaseResponse(res, true, helper.Success))
@@ -123,7 +125,7 @@ func (h *FileHandler) Delete(c *gin.Context) {
 	err = h.usecase.Delete(c, id)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
@@ -156,7 +158,7 @@ func (h *FileHandler) GetById(c *gin.Context) {
 // @Router /v1/files/get-by-filter [post]
 // @Security AuthBearer
 func (h *FileHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToFileResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToFileResponse, h.usecase.GetByFilter)
 }
 
 func saveUploadedFile(file *multipart.FileHeader, directory string) (string, error) {
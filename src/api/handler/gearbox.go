@@ -11,11 +11,13 @@ import (
 )
 
 type GearboxHandler struct {
+	cfg     *config.Config
 	usecase *usecase.GearboxUsecase
 }
 
 func NewGearboxHandler(cfg *config.Config) *GearboxHandler {
 	return &GearboxHandler{
+		cfg:     cfg,
 		usecase: usecase.NewGearboxUsecase(cfg, dependency.GetGearboxRepository(cfg)),
 	}
 }
@@ -96,5 +98,5 @@ func (h *GearboxHandler) GetById(c *gin.Context) {
 // @Router /v1/gearboxes/get-by-filter [post]
 // @Security AuthBearer
 func (h *GearboxHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToGearboxResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToGearboxResponse, h.usecase.GetByFilter)
 }
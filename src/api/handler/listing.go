@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+type ListingHandler struct {
+	cfg     *config.Config
+	usecase *usecase.ListingUsecase
+}
+
+func NewListingHandler(cfg *config.Config) *ListingHandler {
+	return &ListingHandler{
+		cfg: cfg,
+		usecase: usecase.NewListingUsecase(cfg, dependency.GetListingRepository(cfg), dependency.GetListingImageRepository(cfg),
+			dependency.GetFileRepository(cfg)),
+	}
+}
+
+// CreateListing godoc
+// @Summary Create a Listing
+// @Description Create a draft Listing of the current user, it is public once submitted and published by a moderator
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param Request body dto.CreateListingRequest true "Create a Listing"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.ListingResponse} "Listing response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/listings/ [post]
+// @Security AuthBearer
+func (h *ListingHandler) Create(c *gin.Context) {
+	Create(c, dto.ToCreateListing, dto.ToListingResponse, h.usecase.Create)
+}
+
+// UpdateListing godoc
+// @Summary Update a Listing
+// @Description Update a Listing of the current user that is not sold or expired, an edited published Listing is reviewed again
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.UpdateListingRequest true "Update a Listing"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ListingResponse} "Listing response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/listings/{id} [put]
+// @Security AuthBearer
+func (h *ListingHandler) Update(c *gin.Context) {
+	Update(c, dto.ToUpdateListing, dto.ToListingResponse, h.usecase.Update)
+}
+
+// DeleteListing godoc
+// @Summary Delete a Listing
+// @Description Delete a Listing of the current user
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/listings/{id} [delete]
+// @Security AuthBearer
+func (h *ListingHandler) Delete(c *gin.Context) {
+	Delete(c, h.usecase.Delete)
+}
+
+// GetListing godoc
+// @Summary Get a Listing
+// @Description Get a published Listing, or one of the current user, with its images
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ListingResponse} "Listing response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/listings/{id} [get]
+// @Security AuthBearer
+func (h *ListingHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToListingResponse, h.usecase.GetById)
+}
+
+// GetListings godoc
+// @Summary Get Listings
+// @Description Get the published Listings, admins get Listings in any status
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.ListingResponse]} "Listing response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/listings/get-by-filter [post]
+// @Security AuthBearer
+func (h *ListingHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToListingResponse, h.usecase.GetByFilter)
+}
+
+// GetMyListings godoc
+// @Summary Get my Listings
+// @Description Get the Listings of the current user in any status
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.ListingResponse]} "Listing response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/listings/mine/get-by-filter [post]
+// @Security AuthBearer
+func (h *ListingHandler) GetMine(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToListingResponse, h.usecase.GetMine)
+}
+
+// ChangeListingStatus godoc
+// @Summary Change the status of a Listing
+// @Description Submit a draft or expired Listing for review, withdraw a Listing to draft or mark a published Listing as sold
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.ChangeListingStatusRequest true "Change the status of a Listing"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ListingResponse} "Listing response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/listings/{id}/status [put]
+// @Security AuthBearer
+func (h *ListingHandler) ChangeStatus(c *gin.Context) {
+	Update(c, dto.ToChangeListingStatus, dto.ToListingResponse, h.usecase.ChangeStatus)
+}
+
+// AddListingImage godoc
+// @Summary Add a Listing image
+// @Description Upload an image to the end of the gallery of a Listing of the current user, the first image is the main one
+// @Tags Listings
+// @Accept x-www-form-urlencoded
+// @produces json
+// @Param id path int true "Id"
+// @Param file formData dto.UploadListingImageRequest true "Add a Listing image"
+// @Param file formData file true "Add a Listing image"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ListingResponse} "Listing response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/listings/{id}/images [post]
+// @Security AuthBearer
+func (h *ListingHandler) AddImage(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	upload := dto.UploadListingImageRequest{}
+	if err := c.ShouldBind(&upload); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	req := dto.CreateFileRequest{}
+	req.Description = upload.Description
+	req.MimeType = upload.File.Header.Get("Content-Type")
+	req.Directory = "uploads"
+	var err error
+	req.Name, err = saveUploadedFile(upload.File, req.Directory)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+
+	listing, err := h.usecase.AddImage(c, id, dto.ToCreateFile(req))
+	if err != nil {
+		// the listing did not take the file
+		if removeErr := os.Remove(fmt.Sprintf("%s/%s", req.Directory, req.Name)); removeErr != nil {
+			logger.Error(logging.IO, logging.RemoveFile, removeErr.Error(), nil)
+		}
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToListingResponse(listing), true, helper.Success))
+}
+
+// RemoveListingImage godoc
+// @Summary Remove a Listing image
+// @Description Remove an image from the gallery of a Listing of the current user
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param imageId path int true "Image id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ListingResponse} "Listing response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/listings/{id}/images/{imageId} [delete]
+// @Security AuthBearer
+func (h *ListingHandler) RemoveImage(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	imageId, _ := strconv.Atoi(c.Params.ByName("imageId"))
+	listing, err := h.usecase.RemoveImage(c, id, imageId)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToListingResponse(listing), true, helper.Success))
+}
+
+// GetListingModerationQueue godoc
+// @Summary Get the Listing moderation queue
+// @Description Get the Listings under review, the oldest first, filter by status to get the others
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.ListingResponse]} "Listing response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/listings/moderation/get-by-filter [post]
+// @Security AuthBearer
+func (h *ListingHandler) GetModerationQueue(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToListingResponse, h.usecase.GetModerationQueue)
+}
+
+// ModerateListing godoc
+// @Summary Moderate a Listing
+// @Description Publish a Listing under review, or send it or a published Listing back to draft with a note
+// @Tags Listings
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.ModerateListingRequest true "Moderate a Listing"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ListingResponse} "Listing response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/listings/{id}/moderation [put]
+// @Security AuthBearer
+func (h *ListingHandler) Moderate(c *gin.Context) {
+	Update(c, dto.ToModerateListing, dto.ToListingResponse, h.usecase.Moderate)
+}
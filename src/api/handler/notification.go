@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	_ "github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+type NotificationHandler struct {
+	cfg     *config.Config
+	usecase *usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(cfg *config.Config) *NotificationHandler {
+	return &NotificationHandler{
+		cfg:     cfg,
+		usecase: usecase.NewNotificationUsecase(cfg, dependency.GetNotificationRepository(cfg)),
+	}
+}
+
+// GetNotification godoc
+// @Summary Get a notification
+// @Description Get a notification of the current user
+// @Tags Notifications
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.NotificationResponse} "Notification response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/notifications/{id} [get]
+// @Security AuthBearer
+func (h *NotificationHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToNotificationResponse, h.usecase.GetById)
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Get the notifications of the current user, filter by ReadAt to get the unread ones
+// @Tags Notifications
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.NotificationResponse]} "Notification response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/notifications/get-by-filter [post]
+// @Security AuthBearer
+func (h *NotificationHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToNotificationResponse, h.usecase.GetByFilter)
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification as read
+// @Description Mark a notification of the current user as read
+// @Tags Notifications
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.NotificationResponse} "Notification response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/notifications/{id}/read [post]
+// @Security AuthBearer
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	GetById(c, dto.ToNotificationResponse, h.usecase.MarkRead)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	_ "github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+type PriceAlertHandler struct {
+	cfg     *config.Config
+	usecase *usecase.PriceAlertUsecase
+}
+
+func NewPriceAlertHandler(cfg *config.Config) *PriceAlertHandler {
+	notifications := usecase.NewNotificationUsecase(cfg, dependency.GetNotificationRepository(cfg))
+	return &PriceAlertHandler{
+		cfg:     cfg,
+		usecase: usecase.NewPriceAlertUsecase(cfg, dependency.GetPriceAlertRepository(cfg), dependency.GetCarModelPriceHistoryRepository(cfg), dependency.GetExchangeRateRepository(cfg), notifications),
+	}
+}
+
+// CreatePriceAlert godoc
+// @Summary Create a price alert
+// @Description Watch the price of a car model year, above and below notify when the price crosses threshold, change notifies when the price moves percent percent from the last notified price
+// @Tags PriceAlerts
+// @Accept json
+// @produces json
+// @Param Request body dto.CreatePriceAlertRequest true "Create a price alert"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.PriceAlertResponse} "Price alert response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/price-alerts/ [post]
+// @Security AuthBearer
+func (h *PriceAlertHandler) Create(c *gin.Context) {
+	Create(c, dto.ToCreatePriceAlert, dto.ToPriceAlertResponse, h.usecase.Create)
+}
+
+// UpdatePriceAlert godoc
+// @Summary Update a price alert
+// @Description Update a price alert of the current user
+// @Tags PriceAlerts
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.UpdatePriceAlertRequest true "Update a price alert"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.PriceAlertResponse} "Price alert response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/price-alerts/{id} [put]
+// @Security AuthBearer
+func (h *PriceAlertHandler) Update(c *gin.Context) {
+	Update(c, dto.ToUpdatePriceAlert, dto.ToPriceAlertResponse, h.usecase.Update)
+}
+
+// DeletePriceAlert godoc
+// @Summary Delete a price alert
+// @Description Delete a price alert of the current user
+// @Tags PriceAlerts
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/price-alerts/{id} [delete]
+// @Security AuthBearer
+func (h *PriceAlertHandler) Delete(c *gin.Context) {
+	Delete(c, h.usecase.Delete)
+}
+
+// GetPriceAlert godoc
+// @Summary Get a price alert
+// @Description Get a price alert of the current user
+// @Tags PriceAlerts
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.PriceAlertResponse} "Price alert response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/price-alerts/{id} [get]
+// @Security AuthBearer
+func (h *PriceAlertHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToPriceAlertResponse, h.usecase.GetById)
+}
+
+// GetPriceAlerts godoc
+// @Summary Get price alerts
+// @Description Get the price alerts of the current user
+// @Tags PriceAlerts
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.PriceAlertResponse]} "Price alert response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/price-alerts/get-by-filter [post]
+// @Security AuthBearer
+func (h *PriceAlertHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToPriceAlertResponse, h.usecase.GetByFilter)
+}
@@ -11,12 +11,14 @@ import (
 )
 
 type PropertyHandler struct {
+	cfg     *config.Config
 	usecase *usecase.PropertyUsecase
 }
 
 func NewPropertyHandler(cfg *config.Config) *PropertyHandler {
 	return &PropertyHandler{
-		usecase: usecase.NewPropertyUsecase(cfg, dependency.GetPropertyRepository(cfg)),
+		cfg:     cfg,
+		usecase: usecase.NewPropertyUsecase(cfg, dependency.GetPropertyRepository(cfg), dependency.GetCarModelPropertyRepository(cfg)),
 	}
 }
 
@@ -46,6 +48,7 @@ func (h *PropertyHandler) Create(c *gin.Context) {
 // @Success 200 {object} helper.BaseHttpResponse{result=dto.PropertyResponse} "Property response"
 // @Failure 400 {object} helper.BaseHttpResponse "Bad request"
 // @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "The type would invalidate the values of the property"
 // @Router /v1/properties/{id} [put]
 // @Security AuthBearer
 func (h *PropertyHandler) Update(c *gin.Context) {
@@ -96,5 +99,5 @@ func (h *PropertyHandler) GetById(c *gin.Context) {
 // @Router /v1/properties/get-by-filter [post]
 // @Security AuthBearer
 func (h *PropertyHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToPropertyResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToPropertyResponse, h.usecase.GetByFilter)
 }
@@ -11,11 +11,13 @@ import (
 )
 
 type PropertyCategoryHandler struct {
+	cfg     *config.Config
 	usecase *usecase.PropertyCategoryUsecase
 }
 
 func NewPropertyCategoryHandler(cfg *config.Config) *PropertyCategoryHandler {
 	return &PropertyCategoryHandler{
+		cfg:     cfg,
 		usecase: usecase.NewPropertyCategoryUsecase(cfg, dependency.GetPropertyCategoryRepository(cfg)),
 	}
 }
@@ -96,5 +98,5 @@ func (h *PropertyCategoryHandler) GetById(c *gin.Context) {
 // @Router /v1/property-categories/get-by-filter [post]
 // @Security AuthBearer
 func (h *PropertyCategoryHandler) GetByFilter(c *gin.Context) {
-	GetByFilter(c, dto.ToPropertyCategoryResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToPropertyCategoryResponse, h.usecase.GetByFilter)
 }
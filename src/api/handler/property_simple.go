@@ -19,7 +19,7 @@ type PropertySimpleHandler struct {
 
 func NewPropertySimpleHandler(cfg *config.Config) *PropertySimpleHandler {
 	return &PropertySimpleHandler{
-		usecase: usecase.NewPropertyUsecase(cfg, dependency.GetPropertyRepository(cfg)),
+		usecase: usecase.NewPropertyUsecase(cfg, dependency.GetPropertyRepository(cfg), dependency.GetCarModelPropertyRepository(cfg)),
 	}
 }
 
@@ -39,7 +39,7 @@ func (h *PropertySimpleHandler) Create(c *gin.Context) {
 	err := c.ShouldBindJSON(&request)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 
@@ -48,7 +48,7 @@ func (h *PropertySimpleHandler) Create(c *gin.Context) {
 
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -69,6 +69,7 @@ func (h *PropertySimpleHandler) Create(c *gin.Context) {
 // @Success 200 {object} helper.BaseHttpResponse{result=dto.PropertyResponse} "Property response"
 // @Failure 400 {object} helper.BaseHttpResponse "Bad request"
 // @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "The type would invalidate the values of the property"
 // @Router /v1/properties/{id} [put]
 // @Security AuthBearer
 func (h *PropertySimpleHandler) Update(c *gin.Context) {
@@ -78,7 +79,7 @@ func (h *PropertySimpleHandler) Update(c *gin.Context) {
 	err := c.ShouldBindJSON(&request)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 
 	}
@@ -87,7 +88,7 @@ func (h *PropertySimpleHandler) Update(c *gin.Context) {
 
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -120,7 +121,7 @@ func (h *PropertySimpleHandler) Delete(c *gin.Context) {
 	err := h.usecase.Delete(c, id)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, 0))
@@ -150,7 +151,7 @@ func (h *PropertySimpleHandler) GetById(c *gin.Context) {
 	property, err := h.usecase.GetById(c, id)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -176,7 +177,7 @@ func (h *PropertySimpleHandler) GetByFilter(c *gin.Context) {
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 
@@ -184,7 +185,7 @@ func (h *PropertySimpleHandler) GetByFilter(c *gin.Context) {
 	properties, err := h.usecase.GetByFilter(c, *req)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 	response := filter.PagedList[dto.PropertyResponse]{
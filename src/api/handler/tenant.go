@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+type TenantHandler struct {
+	cfg     *config.Config
+	usecase *usecase.TenantUsecase
+}
+
+func NewTenantHandler(cfg *config.Config) *TenantHandler {
+	return &TenantHandler{
+		cfg:     cfg,
+		usecase: usecase.NewTenantUsecase(cfg, dependency.GetTenantRepository(cfg), dependency.GetUserRepository(cfg)),
+	}
+}
+
+// CreateTenant godoc
+// @Summary Create a tenant
+// @Description Create a dealer organization, only platform admins can provision tenants
+// @Tags Tenants
+// @Accept json
+// @produces json
+// @Param Request body dto.CreateTenantRequest true "Create a tenant"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.TenantResponse} "Tenant response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 409 {object} helper.BaseHttpResponse "Slug exists"
+// @Router /v1/tenants/ [post]
+// @Security AuthBearer
+func (h *TenantHandler) Create(c *gin.Context) {
+	Create(c, dto.ToCreateTenant, dto.ToTenantResponse, h.usecase.Create)
+}
+
+// UpdateTenant godoc
+// @Summary Update a tenant
+// @Description Update a tenant, users of a disabled tenant can not login
+// @Tags Tenants
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.UpdateTenantRequest true "Update a tenant"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.TenantResponse} "Tenant response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/tenants/{id} [put]
+// @Security AuthBearer
+func (h *TenantHandler) Update(c *gin.Context) {
+	Update(c, dto.ToUpdateTenant, dto.ToTenantResponse, h.usecase.Update)
+}
+
+// DeleteTenant godoc
+// @Summary Delete a tenant
+// @Description Delete a tenant
+// @Tags Tenants
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/tenants/{id} [delete]
+// @Security AuthBearer
+func (h *TenantHandler) Delete(c *gin.Context) {
+	Delete(c, h.usecase.Delete)
+}
+
+// GetTenant godoc
+// @Summary Get a tenant
+// @Description Get a tenant
+// @Tags Tenants
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.TenantResponse} "Tenant response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/tenants/{id} [get]
+// @Security AuthBearer
+func (h *TenantHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToTenantResponse, h.usecase.GetById)
+}
+
+// GetTenants godoc
+// @Summary Get tenants
+// @Description Get tenants
+// @Tags Tenants
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.TenantResponse]} "Tenant response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/tenants/get-by-filter [post]
+// @Security AuthBearer
+func (h *TenantHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToTenantResponse, h.usecase.GetByFilter)
+}
+
+// CreateTenantAdmin godoc
+// @Summary Create a tenant admin
+// @Description Create an admin user of the tenant, the user manages the catalog of its tenant
+// @Tags Tenants
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.RegisterUserByUsernameRequest true "Admin user"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.TenantAdminResponse} "Tenant admin response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 409 {object} helper.BaseHttpResponse "Username or email exists"
+// @Router /v1/tenants/{id}/admins [post]
+// @Security AuthBearer
+func (h *TenantHandler) CreateAdmin(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	if id == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			helper.GenerateBaseResponse(nil, false, helper.ValidationError))
+		return
+	}
+	req := new(dto.RegisterUserByUsernameRequest)
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	admin, err := h.usecase.CreateAdmin(c, id, req.ToRegisterUserByUsername())
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusCreated, helper.GenerateBaseResponse(dto.ToTenantAdminResponse(admin), true, helper.Success))
+}
@@ -163,7 +163,7 @@ func (h *TestHandler) BodyBinder(c *gin.Context) {
 	err := c.ShouldBindJSON(&p)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil,
+			helper.GenerateBaseResponseWithValidationError(c, nil,
 				false, helper.ValidationError, err))
 		return
 	}
@@ -187,7 +187,7 @@ func (h *TestHandler) FileBinder(c *gin.Context) {
 	err := c.SaveUploadedFile(file, "file")
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError,
-			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 	c.JSON(http.StatusOK, helper.GenerateBaseResponse(gin.H{
@@ -17,8 +17,10 @@ type UsersHandler struct {
 }
 
 func NewUserHandler(cfg *config.Config) *UsersHandler {
-	usecase := usecase.NewUserUsecase(cfg, dependency.GetUserRepository(cfg))
-	return &UsersHandler{usecase: usecase}
+	return &UsersHandler{
+		usecase:    usecase.NewUserUsecase(cfg, dependency.GetUserRepository(cfg)),
+		otpUsecase: usecase.NewOtpUsecase(cfg),
+	}
 }
 
 // LoginByUsername godoc
@@ -37,13 +39,13 @@ func (h *UsersHandler) LoginByUsername(c *gin.Context) {
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 	token, err := h.usecase.LoginByUsername(c, req.Username, req.Password)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -66,13 +68,13 @@ func (h *UsersHandler) RegisterByUsername(c *gin.Context) {
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 	err = h.usecase.RegisterByUsername(c, req.ToRegisterUserByUsername())
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -95,13 +97,13 @@ func (h *UsersHandler) RegisterLoginByMobileNumber(c *gin.Context) {
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 	token, err := h.usecase.RegisterAndLoginByMobileNumber(c, req.MobileNumber, req.Otp)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 
@@ -124,15 +126,43 @@ func (h *UsersHandler) SendOtp(c *gin.Context) {
 	err := c.ShouldBindJSON(&req)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
 		return
 	}
 	err = h.otpUsecase.SendOtp(req.MobileNumber)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
 		return
 	}
 	// TODO: Call internal SMS service
 	c.JSON(http.StatusCreated, helper.GenerateBaseResponse(nil, true, helper.Success))
 }
+
+// SetLanguage godoc
+// @Summary Set the language of the error messages of the current user
+// @Description Set the language of the error messages of the current user, it is used by the tokens issued after the change
+// @Tags Users
+// @Accept  json
+// @Produce  json
+// @Param Request body dto.SetLanguageRequest true "SetLanguageRequest"
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 400 {object} helper.BaseHttpResponse "Failed"
+// @Router /v1/users/language [put]
+// @Security AuthBearer
+func (h *UsersHandler) SetLanguage(c *gin.Context) {
+	req := new(dto.SetLanguageRequest)
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(c, nil, false, helper.ValidationError, err))
+		return
+	}
+	err = h.usecase.SetLanguage(c, req.Language)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+type WatchlistHandler struct {
+	cfg     *config.Config
+	usecase *usecase.WatchlistUsecase
+}
+
+func NewWatchlistHandler(cfg *config.Config) *WatchlistHandler {
+	return &WatchlistHandler{
+		cfg: cfg,
+		usecase: usecase.NewWatchlistUsecase(cfg, dependency.GetWatchlistRepository(cfg), dependency.GetWatchlistEntryRepository(cfg),
+			dependency.GetCarModelYearRepository(cfg), dependency.GetCarModelPriceHistoryRepository(cfg),
+			dependency.GetExchangeRateRepository(cfg)),
+	}
+}
+
+// CreateWatchlist godoc
+// @Summary Create a Watchlist
+// @Description Create a private Watchlist of the current user
+// @Tags Watchlists
+// @Accept json
+// @produces json
+// @Param Request body dto.CreateWatchlistRequest true "Create a Watchlist"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.WatchlistResponse} "Watchlist response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/watchlists/ [post]
+// @Security AuthBearer
+func (h *WatchlistHandler) Create(c *gin.Context) {
+	Create(c, dto.ToCreateWatchlist, dto.ToWatchlistResponse, h.usecase.Create)
+}
+
+// UpdateWatchlist godoc
+// @Summary Update a Watchlist
+// @Description Rename a Watchlist of the current user or share it through a link
+// @Tags Watchlists
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.UpdateWatchlistRequest true "Update a Watchlist"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WatchlistResponse} "Watchlist response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/watchlists/{id} [put]
+// @Security AuthBearer
+func (h *WatchlistHandler) Update(c *gin.Context) {
+	Update(c, dto.ToUpdateWatchlist, dto.ToWatchlistResponse, h.usecase.Update)
+}
+
+// DeleteWatchlist godoc
+// @Summary Delete a Watchlist
+// @Description Delete a Watchlist of the current user
+// @Tags Watchlists
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/watchlists/{id} [delete]
+// @Security AuthBearer
+func (h *WatchlistHandler) Delete(c *gin.Context) {
+	Delete(c, h.usecase.Delete)
+}
+
+// GetWatchlist godoc
+// @Summary Get a Watchlist
+// @Description Get a Watchlist of the current user with the latest prices and followers of its entries
+// @Tags Watchlists
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WatchlistResponse} "Watchlist response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/watchlists/{id} [get]
+// @Security AuthBearer
+func (h *WatchlistHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToWatchlistResponse, h.usecase.GetById)
+}
+
+// GetWatchlists godoc
+// @Summary Get Watchlists
+// @Description Get the Watchlists of the current user without their entries
+// @Tags Watchlists
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.WatchlistResponse]} "Watchlist response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/watchlists/get-by-filter [post]
+// @Security AuthBearer
+func (h *WatchlistHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToWatchlistResponse, h.usecase.GetByFilter)
+}
+
+// AddWatchlistEntry godoc
+// @Summary Add a Watchlist entry
+// @Description Add a CarModel or a CarModelYear to a Watchlist of the current user
+// @Tags Watchlists
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.CreateWatchlistEntryRequest true "Add a Watchlist entry"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WatchlistResponse} "Watchlist response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/watchlists/{id}/entries [post]
+// @Security AuthBearer
+func (h *WatchlistHandler) AddEntry(c *gin.Context) {
+	Update(c, dto.ToCreateWatchlistEntry, dto.ToWatchlistResponse, h.usecase.AddEntry)
+}
+
+// RemoveWatchlistEntry godoc
+// @Summary Remove a Watchlist entry
+// @Description Remove an entry from a Watchlist of the current user
+// @Tags Watchlists
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param entryId path int true "Entry id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WatchlistResponse} "Watchlist response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/watchlists/{id}/entries/{entryId} [delete]
+// @Security AuthBearer
+func (h *WatchlistHandler) RemoveEntry(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	entryId, _ := strconv.Atoi(c.Params.ByName("entryId"))
+	watchlist, err := h.usecase.RemoveEntry(c, id, entryId)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToWatchlistResponse(watchlist), true, helper.Success))
+}
+
+// GetSharedWatchlist godoc
+// @Summary Get a shared Watchlist
+// @Description Get a shared Watchlist by its share token, it needs no login
+// @Tags Watchlists
+// @Accept json
+// @produces json
+// @Param token path string true "Share token"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.SharedWatchlistResponse} "Shared Watchlist response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/shared-watchlists/{token} [get]
+func (h *WatchlistHandler) GetShared(c *gin.Context) {
+	watchlist, err := h.usecase.GetShared(c, c.Params.ByName("token"))
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToSharedWatchlistResponse(watchlist), true, helper.Success))
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/dto"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	_ "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+type WebhookHandler struct {
+	cfg     *config.Config
+	usecase *usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(cfg *config.Config) *WebhookHandler {
+	return &WebhookHandler{
+		cfg:     cfg,
+		usecase: usecase.NewWebhookUsecase(cfg, dependency.GetWebhookSubscriptionRepository(cfg), dependency.GetWebhookDeliveryRepository(cfg)),
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook subscription
+// @Description Subscribe a url to the events of the current tenant, the response has the signing secret
+// @Tags Webhooks
+// @Accept json
+// @produces json
+// @Param Request body dto.CreateWebhookSubscriptionRequest true "Create a webhook subscription"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.WebhookSubscriptionResponse} "Webhook subscription response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/webhooks/ [post]
+// @Security AuthBearer
+func (h *WebhookHandler) Create(c *gin.Context) {
+	Create(c, dto.ToCreateWebhookSubscription, dto.ToCreatedWebhookSubscriptionResponse, h.usecase.Create)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook subscription
+// @Description Update a webhook subscription, deliveries of a disabled subscription wait until it is enabled again
+// @Tags Webhooks
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Param Request body dto.UpdateWebhookSubscriptionRequest true "Update a webhook subscription"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WebhookSubscriptionResponse} "Webhook subscription response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/webhooks/{id} [put]
+// @Security AuthBearer
+func (h *WebhookHandler) Update(c *gin.Context) {
+	Update(c, dto.ToUpdateWebhookSubscription, dto.ToWebhookSubscriptionResponse, h.usecase.Update)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription
+// @Description Delete a webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse "response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/webhooks/{id} [delete]
+// @Security AuthBearer
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	Delete(c, h.usecase.Delete)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WebhookSubscriptionResponse} "Webhook subscription response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/webhooks/{id} [get]
+// @Security AuthBearer
+func (h *WebhookHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToWebhookSubscriptionResponse, h.usecase.GetById)
+}
+
+// GetWebhooks godoc
+// @Summary Get webhook subscriptions
+// @Description Get webhook subscriptions
+// @Tags Webhooks
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.WebhookSubscriptionResponse]} "Webhook subscription response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/webhooks/get-by-filter [post]
+// @Security AuthBearer
+func (h *WebhookHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToWebhookSubscriptionResponse, h.usecase.GetByFilter)
+}
+
+// GetWebhookDelivery godoc
+// @Summary Get a webhook delivery
+// @Description Get a webhook delivery with its payload and last response
+// @Tags Webhooks
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WebhookDeliveryResponse} "Webhook delivery response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/webhook-deliveries/{id} [get]
+// @Security AuthBearer
+func (h *WebhookHandler) GetDeliveryById(c *gin.Context) {
+	GetById(c, dto.ToWebhookDeliveryResponse, h.usecase.GetDeliveryById)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get webhook deliveries
+// @Description The delivery log, filter by subscriptionId and status (pending, delivered, dead) to find dead letters
+// @Tags Webhooks
+// @Accept json
+// @produces json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.WebhookDeliveryResponse]} "Webhook delivery response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/webhook-deliveries/get-by-filter [post]
+// @Security AuthBearer
+func (h *WebhookHandler) GetDeliveriesByFilter(c *gin.Context) {
+	GetByFilter(c, h.cfg, dto.ToWebhookDeliveryResponse, h.usecase.GetDeliveriesByFilter)
+}
+
+// RedeliverWebhook godoc
+// @Summary Redeliver a webhook delivery
+// @Description Send a delivery again, a dead letter gets all its attempts back
+// @Tags Webhooks
+// @Accept json
+// @produces json
+// @Param id path int true "Id"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WebhookDeliveryResponse} "Webhook delivery response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/webhook-deliveries/{id}/redeliver [post]
+// @Security AuthBearer
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Params.ByName("id"))
+	if id == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound,
+			helper.GenerateBaseResponse(nil, false, helper.ValidationError))
+		return
+	}
+	delivery, err := h.usecase.Redeliver(c, id)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(c, nil, false, helper.InternalError, err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToWebhookDeliveryResponse(delivery), true, helper.Success))
+}
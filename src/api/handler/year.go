@@ -11,11 +11,13 @@ import (
 )
 
 type PersianYearHandler struct {
+	cfg     *config.Config
 	usecase *usecase.PersianYearUsecase
 }
 
 func NewPersianYearHandler(cfg *config.Config) *PersianYearHandler {
 	return &PersianYearHandler{
+		cfg:     cfg,
 		usecase: usecase.NewPersianYearUsecase(cfg, dependency.GetPersianYearRepository(cfg)),
 	}
 }
@@ -98,5 +100,5 @@ func (h *PersianYearHandler) GetById(c *gin.Context) {
 // @Security AuthBearer
 func (h *PersianYearHandler) GetByFilter(c *gin.Context) {
 
-	GetByFilter(c, dto.ToPersianYearResponse, h.usecase.GetByFilter)
+	GetByFilter(c, h.cfg, dto.ToPersianYearResponse, h.usecase.GetByFilter)
 }
@@ -1,6 +1,15 @@
 package helper
 
-import validation "github.com/naeemaei/golang-clean-web-api/api/validation"
+import (
+	"github.com/gin-gonic/gin"
+	validation "github.com/naeemaei/golang-clean-web-api/api/validation"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"golang.org/x/text/language"
+)
+
+// languageMatcher picks a supported language from Accept-Language, the first one is the default
+var languageMatcher = language.NewMatcher([]language.Tag{language.English, language.Persian})
 
 type BaseHttpResponse struct {
 	Result           any                           `json:"result"`
@@ -17,11 +26,12 @@ func GenerateBaseResponse(result any, success bool, resultCode ResultCode) *Base
 	}
 }
 
-func GenerateBaseResponseWithError(result any, success bool, resultCode ResultCode, err error) *BaseHttpResponse {
+// GenerateBaseResponseWithError translates the error to the language of the request
+func GenerateBaseResponseWithError(c *gin.Context, result any, success bool, resultCode ResultCode, err error) *BaseHttpResponse {
 	return &BaseHttpResponse{Result: result,
 		Success:    success,
 		ResultCode: resultCode,
-		Error:      err.Error(),
+		Error:      service_errors.Message(err.Error(), RequestLanguage(c)),
 	}
 
 }
@@ -34,10 +44,26 @@ func GenerateBaseResponseWithAnyError(result any, success bool, resultCode Resul
 	}
 }
 
-func GenerateBaseResponseWithValidationError(result any, success bool, resultCode ResultCode, err error) *BaseHttpResponse {
+// GenerateBaseResponseWithValidationError translates the validation messages to the language of the request
+func GenerateBaseResponseWithValidationError(c *gin.Context, result any, success bool, resultCode ResultCode, err error) *BaseHttpResponse {
+	validationErrors := validation.GetValidationErrors(err)
+	if validationErrors != nil {
+		validation.Localize(*validationErrors, RequestLanguage(c))
+	}
 	return &BaseHttpResponse{Result: result,
 		Success:          success,
 		ResultCode:       resultCode,
-		ValidationErrors: validation.GetValidationErrors(err),
+		ValidationErrors: validationErrors,
+	}
+}
+
+// RequestLanguage returns the language of the error messages of a request: the preference of the user,
+// a claim of the token, then the Accept-Language header and the default language
+func RequestLanguage(c *gin.Context) string {
+	if preference, ok := c.Keys[constant.LanguageKey].(string); ok && preference != "" {
+		return preference
 	}
+	tag, _ := language.MatchStrings(languageMatcher, c.GetHeader(constant.AcceptLanguageHeaderKey))
+	base, _ := tag.Base()
+	return base.String()
 }
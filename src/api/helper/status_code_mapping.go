@@ -18,6 +18,65 @@ var StatusCodeMapping = map[string]int{
 	service_errors.UsernameExists:   409,
 	service_errors.RecordNotFound:   404,
 	service_errors.PermissionDenied: 403,
+
+	// Limiter
+	service_errors.NotAllowed: 429,
+
+	// Tenant
+	service_errors.TenantSlugExists:    409,
+	service_errors.DefaultTenantLocked: 409,
+
+	// Import
+	service_errors.ImportFormatNotSupported: 400,
+	service_errors.ImportFileInvalid:        400,
+	service_errors.ImportTooManyRows:        400,
+
+	// Webhook
+	service_errors.WebhookEventTypeInvalid: 400,
+	service_errors.WebhookUrlNotAllowed:    400,
+
+	// Price analytics
+	service_errors.PriceBucketInvalid: 400,
+
+	// Exchange rate
+	service_errors.ExchangeRateExists: 409,
+
+	// Price alert
+	service_errors.PriceAlertRuleInvalid: 400,
+
+	// Compare
+	service_errors.CompareCarModelsInvalid: 400,
+
+	// Comment
+	service_errors.CommentAlreadyReported: 409,
+	service_errors.CommentStatusInvalid:   400,
+	service_errors.CommentReportInvalid:   400,
+	service_errors.CommentParentInvalid:   400,
+	service_errors.CommentVoteInvalid:     400,
+
+	// Rating
+	service_errors.RatingStarsInvalid: 400,
+
+	// Watchlist
+	service_errors.WatchlistEntryInvalid: 400,
+	service_errors.WatchlistEntryExists:  409,
+	service_errors.WatchlistFull:         400,
+
+	// Listing
+	service_errors.ListingStatusInvalid: 409,
+	service_errors.ListingImagesFull:    400,
+
+	// Car model image
+	service_errors.CarModelImageMainRequired: 409,
+	service_errors.CarModelImageOrderInvalid: 400,
+
+	// Facets
+	service_errors.FacetFilterInvalid: 400,
+
+	// Property
+	service_errors.PropertyTypeInvalid:  400,
+	service_errors.PropertyValueInvalid: 400,
+	service_errors.PropertyTypeInUse:    409,
 }
 
 func TranslateErrorToStatusCode(err error) int {
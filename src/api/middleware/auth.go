@@ -37,7 +37,7 @@ func Authentication(cfg *config.Config) gin.HandlerFunc {
 		}
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, helper.GenerateBaseResponseWithError(
-				nil, false, helper.AuthError, err,
+				c, nil, false, helper.AuthError, err,
 			))
 			return
 		}
@@ -50,6 +50,16 @@ func Authentication(cfg *config.Config) gin.HandlerFunc {
 		c.Set(constant.MobileNumberKey, claimMap[constant.MobileNumberKey])
 		c.Set(constant.RolesKey, claimMap[constant.RolesKey])
 		c.Set(constant.ExpireTimeKey, claimMap[constant.ExpireTimeKey])
+		c.Set(constant.LanguageKey, claimMap[constant.LanguageKey])
+		// tokens without tenant are rejected instead of falling back to the platform tenant
+		tenantId, ok := claimMap[constant.TenantIdKey].(float64)
+		if !ok || tenantId <= 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, helper.GenerateBaseResponseWithError(
+				c, nil, false, helper.AuthError, &service_errors.ServiceError{EndUserMessage: service_errors.TokenInvalid},
+			))
+			return
+		}
+		c.Set(constant.TenantIdKey, tenantId)
 
 		c.Next()
 	}
@@ -82,3 +92,15 @@ func Authorization(validRoles []string) gin.HandlerFunc {
 		c.AbortWithStatusJSON(http.StatusForbidden, helper.GenerateBaseResponse(nil, false, helper.ForbiddenError))
 	}
 }
+
+// PlatformTenant allows only users of the platform tenant, e.g. for tenant provisioning
+func PlatformTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantId, ok := c.Keys[constant.TenantIdKey].(float64)
+		if !ok || int(tenantId) != constant.DefaultTenantId {
+			c.AbortWithStatusJSON(http.StatusForbidden, helper.GenerateBaseResponse(nil, false, helper.ForbiddenError))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/api/validation"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	"github.com/naeemaei/golang-clean-web-api/pkg/limiter"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+	"golang.org/x/time/rate"
+)
+
+// CatalogLimiter limits the anonymous catalog requests of every client ip
+func CatalogLimiter(cfg *config.Config) gin.HandlerFunc {
+	var limiter = limiter.NewIPRateLimiter(rate.Limit(cfg.Catalog.Rate), cfg.Catalog.Burst)
+	return func(c *gin.Context) {
+		if !limiter.GetLimiter(c.ClientIP()).Allow() {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, helper.GenerateBaseResponseWithError(c, nil, false, helper.LimiterError, &service_errors.ServiceError{EndUserMessage: service_errors.NotAllowed}))
+			return
+		}
+		c.Next()
+	}
+}
+
+// CatalogTenant sets the tenant of an anonymous request from the X-Tenant slug,
+// requests without it read the catalog of the platform tenant
+func CatalogTenant(cfg *config.Config) gin.HandlerFunc {
+	var tenantUsecase = usecase.NewTenantUsecase(cfg, dependency.GetTenantRepository(cfg), dependency.GetUserRepository(cfg))
+	return func(c *gin.Context) {
+		slug := c.GetHeader(constant.TenantHeaderKey)
+		if slug == "" {
+			slug = constant.DefaultTenantSlug
+		}
+		if !validation.IsSlug(slug) {
+			c.AbortWithStatusJSON(http.StatusNotFound, helper.GenerateBaseResponseWithError(c, nil, false, helper.NotFoundError,
+				&service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}))
+			return
+		}
+		tenant, err := tenantUsecase.GetEnabledBySlug(c, slug)
+		if err != nil {
+			c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err), helper.GenerateBaseResponseWithError(c, nil, false, helper.NotFoundError, err))
+			return
+		}
+		c.Set(constant.TenantIdKey, float64(tenant.Id))
+		c.Next()
+	}
+}
+
+// CacheControl lets browsers and proxies cache the successful responses for maxAge seconds,
+// responses differ by tenant
+func CacheControl(maxAge int) gin.HandlerFunc {
+	value := fmt.Sprintf("public, max-age=%d", maxAge)
+	return func(c *gin.Context) {
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, value: value}
+		c.Header("Vary", constant.TenantHeaderKey)
+		c.Next()
+	}
+}
+
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	value string
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		w.Header().Set("Cache-Control", w.value)
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
@@ -9,7 +9,7 @@ import (
 
 func ErrorHandler(c *gin.Context, err any) {
 	if err, ok := err.(error); ok {
-		httpResponse := helper.GenerateBaseResponseWithError(nil, false, helper.CustomRecovery, err)
+		httpResponse := helper.GenerateBaseResponseWithError(c, nil, false, helper.CustomRecovery, err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, httpResponse)
 		return
 	}
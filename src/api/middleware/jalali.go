@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+)
+
+// jalaliSuffix names the Jalali copy of a timestamp, e.g. priceAtJalali for priceAt
+const jalaliSuffix = "Jalali"
+
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// JalaliDates adds a formatted Jalali date of the Tehran time zone next to every timestamp of a json
+// response when the request asks for the Jalali calendar by the X-Calendar header or the calendar query
+func JalaliDates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.EqualFold(c.GetHeader(constant.CalendarHeaderKey), constant.JalaliCalendar) &&
+			!strings.EqualFold(c.Query(constant.CalendarQueryKey), constant.JalaliCalendar) {
+			c.Next()
+			return
+		}
+		w := bufferedWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		body := w.body.Bytes()
+		if strings.HasPrefix(c.Writer.Header().Get("Content-Type"), gin.MIMEJSON) {
+			out := &bytes.Buffer{}
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+			if _, _, err := writeJalaliDates(dec, out); err == nil {
+				body = out.Bytes()
+			}
+		}
+		c.Writer.Write(body)
+	}
+}
+
+// writeJalaliDates copies the next json value of dec to out keeping the order of the keys, a timestamp
+// member of an object is followed by its Jalali copy. The timestamp of a string value is returned
+func writeJalaliDates(dec *json.Decoder, out *bytes.Buffer) (time.Time, bool, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '[' {
+			out.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				if _, _, err := writeJalaliDates(dec, out); err != nil {
+					return time.Time{}, false, err
+				}
+			}
+			out.WriteByte(']')
+		} else {
+			out.WriteByte('{')
+			for i := 0; dec.More(); i++ {
+				key, err := dec.Token()
+				if err != nil {
+					return time.Time{}, false, err
+				}
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				writeJsonString(out, key.(string))
+				out.WriteByte(':')
+				at, ok, err := writeJalaliDates(dec, out)
+				if err != nil {
+					return time.Time{}, false, err
+				}
+				if ok {
+					out.WriteByte(',')
+					writeJsonString(out, key.(string)+jalaliSuffix)
+					out.WriteByte(':')
+					writeJsonString(out, common.FormatJalali(at))
+				}
+			}
+			out.WriteByte('}')
+		}
+		// the closing delimiter
+		_, err = dec.Token()
+		return time.Time{}, false, err
+	case string:
+		writeJsonString(out, value)
+		// zero times are left alone, they are unset dates
+		if at, err := time.Parse(time.RFC3339Nano, value); err == nil && !at.IsZero() {
+			return at, true, nil
+		}
+	case json.Number:
+		out.WriteString(value.String())
+	case bool:
+		if value {
+			out.WriteString("true")
+		} else {
+			out.WriteString("false")
+		}
+	case nil:
+		out.WriteString("null")
+	}
+	return time.Time{}, false, nil
+}
+
+func writeJsonString(out *bytes.Buffer, value string) {
+	raw, _ := json.Marshal(value)
+	out.Write(raw)
+}
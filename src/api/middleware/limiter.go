@@ -6,6 +6,7 @@ import (
 	"github.com/didip/tollbooth"
 	"github.com/gin-gonic/gin"
 	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 )
 
 func LimitByRequest() gin.HandlerFunc {
@@ -14,7 +15,8 @@ func LimitByRequest() gin.HandlerFunc {
 		err := tollbooth.LimitByRequest(lmt, c.Writer, c.Request)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusTooManyRequests,
-				helper.GenerateBaseResponseWithError(nil, false, helper.LimiterError, err))
+				helper.GenerateBaseResponseWithError(c, nil, false, helper.LimiterError,
+					&service_errors.ServiceError{EndUserMessage: service_errors.NotAllowed}))
 			return
 		} else {
 			c.Next()
@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"errors"
 	"net/http"
 	"time"
 
@@ -9,6 +8,7 @@ import (
 	"github.com/naeemaei/golang-clean-web-api/api/helper"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/pkg/limiter"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"golang.org/x/time/rate"
 )
 
@@ -17,7 +17,7 @@ func OtpLimiter(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		limiter := limiter.GetLimiter(c.Request.RemoteAddr)
 		if !limiter.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, helper.GenerateBaseResponseWithError(nil, false, helper.OtpLimiterError, errors.New("not allowed")))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, helper.GenerateBaseResponseWithError(c, nil, false, helper.OtpLimiterError, &service_errors.ServiceError{EndUserMessage: service_errors.NotAllowed}))
 			c.Abort()
 		} else {
 			c.Next()
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/common"
+)
+
+// PersianQuery normalizes the query string like the bound requests, see validation.PersianValidator,
+// so numbers in Persian digits bind to numeric fields. It must run before anything reads the query
+func PersianQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.URL.RawQuery != "" {
+			query := c.Request.URL.Query()
+			changed := false
+			for _, values := range query {
+				for i, value := range values {
+					values[i] = common.NormalizePersian(value)
+					changed = changed || values[i] != value
+				}
+			}
+			if changed {
+				c.Request.URL.RawQuery = query.Encode()
+			}
+		}
+		c.Next()
+	}
+}
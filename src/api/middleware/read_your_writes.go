@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+// ReadYourWrites attaches a primary pin to every request, repositories pin the
+// request to the primary database after a write so later reads see the change.
+// The following requests of the user are pinned for Postgres.PrimaryPinWindow
+func ReadYourWrites(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.Postgres.ReadYourWrites {
+			c.Set(constant.PrimaryPinKey, database.NewPrimaryPin())
+		}
+		c.Next()
+	}
+}
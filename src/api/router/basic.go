@@ -48,6 +48,16 @@ func Company(r *gin.RouterGroup, cfg *config.Config) {
 	r.POST(GetByFilterExp, h.GetByFilter)
 }
 
+func ExchangeRate(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewExchangeRateHandler(cfg)
+
+	r.POST("/", h.Create)
+	r.PUT("/:id", h.Update)
+	r.DELETE("/:id", h.Delete)
+	r.GET("/:id", h.GetById)
+	r.POST(GetByFilterExp, h.GetByFilter)
+}
+
 func Color(r *gin.RouterGroup, cfg *config.Config) {
 	h := handler.NewColorHandler(cfg)
 
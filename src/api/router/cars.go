@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/naeemaei/golang-clean-web-api/api/handler"
+	"github.com/naeemaei/golang-clean-web-api/api/middleware"
 	"github.com/naeemaei/golang-clean-web-api/config"
 )
 
@@ -32,7 +33,12 @@ func CarModel(r *gin.RouterGroup, cfg *config.Config) {
 	r.POST("/", h.Create)
 	r.PUT("/:id", h.Update)
 	r.DELETE("/:id", h.Delete)
+	r.GET("/search", h.Search)
+	r.GET("/compare", h.Compare)
+	r.POST("/facets", h.Facets)
+	r.POST("/import", h.Import)
 	r.GET("/:id", h.GetById)
+	r.GET("/:id/price-analytics", h.PriceAnalytics)
 	r.POST(GetByFilterExp, h.GetByFilter)
 }
 
@@ -53,6 +59,7 @@ func CarModelYear(r *gin.RouterGroup, cfg *config.Config) {
 	r.PUT("/:id", h.Update)
 	r.DELETE("/:id", h.Delete)
 	r.GET("/:id", h.GetById)
+	r.GET("/:id/price-analytics", h.PriceAnalytics)
 	r.POST(GetByFilterExp, h.GetByFilter)
 }
 
@@ -70,7 +77,9 @@ func CarModelImage(r *gin.RouterGroup, cfg *config.Config) {
 	h := handler.NewCarModelImageHandler(cfg)
 
 	r.POST("/", h.Create)
+	r.PUT("/reorder", h.Reorder)
 	r.PUT("/:id", h.Update)
+	r.PUT("/:id/main", h.SetMain)
 	r.DELETE("/:id", h.Delete)
 	r.GET("/:id", h.GetById)
 	r.POST(GetByFilterExp, h.GetByFilter)
@@ -94,4 +103,19 @@ func CarModelComment(r *gin.RouterGroup, cfg *config.Config) {
 	r.DELETE("/:id", h.Delete)
 	r.GET("/:id", h.GetById)
 	r.POST(GetByFilterExp, h.GetByFilter)
+	r.POST("/mine"+GetByFilterExp, h.GetMine)
+	r.POST("/:id/report", h.Report)
+	r.PUT("/:id/vote", h.Vote)
+	r.DELETE("/:id/vote", h.RemoveVote)
+	r.POST("/moderation"+GetByFilterExp, middleware.Authorization([]string{"admin", "tenant-admin"}), h.GetModerationQueue)
+	r.PUT("/:id/moderation", middleware.Authorization([]string{"admin", "tenant-admin"}), h.Moderate)
+}
+
+func CarModelRating(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewCarModelRatingHandler(cfg)
+
+	r.POST("/", h.Create)
+	r.DELETE("/:id", h.Delete)
+	r.GET("/:id", h.GetById)
+	r.POST(GetByFilterExp, h.GetByFilter)
 }
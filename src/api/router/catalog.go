@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/handler"
+	"github.com/naeemaei/golang-clean-web-api/config"
+)
+
+func Catalog(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewCatalogHandler(cfg)
+
+	r.GET("/car-models", h.GetCarModels)
+	r.POST("/car-models/facets", h.FacetCarModels)
+	r.GET("/car-models/:id", h.GetCarModel)
+	r.GET("/companies", h.GetCompanies)
+	r.GET("/companies/:id", h.GetCompany)
+	r.GET("/colors", h.GetColors)
+	r.GET("/colors/:id", h.GetColor)
+	r.GET("/car-types", h.GetCarTypes)
+	r.GET("/car-types/:id", h.GetCarType)
+	r.GET("/gearboxes", h.GetGearboxes)
+	r.GET("/gearboxes/:id", h.GetGearbox)
+}
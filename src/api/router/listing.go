@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/handler"
+	"github.com/naeemaei/golang-clean-web-api/api/middleware"
+	"github.com/naeemaei/golang-clean-web-api/config"
+)
+
+func Listing(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewListingHandler(cfg)
+
+	r.POST("/", h.Create)
+	r.PUT("/:id", h.Update)
+	r.DELETE("/:id", h.Delete)
+	r.GET("/:id", h.GetById)
+	r.POST(GetByFilterExp, h.GetByFilter)
+	r.POST("/mine"+GetByFilterExp, h.GetMine)
+	r.PUT("/:id/status", h.ChangeStatus)
+	r.POST("/:id/images", h.AddImage)
+	r.DELETE("/:id/images/:imageId", h.RemoveImage)
+	r.POST("/moderation"+GetByFilterExp, middleware.Authorization([]string{"admin", "tenant-admin"}), h.GetModerationQueue)
+	r.PUT("/:id/moderation", middleware.Authorization([]string{"admin", "tenant-admin"}), h.Moderate)
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/handler"
+	"github.com/naeemaei/golang-clean-web-api/config"
+)
+
+func PriceAlert(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewPriceAlertHandler(cfg)
+
+	r.POST("/", h.Create)
+	r.PUT("/:id", h.Update)
+	r.DELETE("/:id", h.Delete)
+	r.GET("/:id", h.GetById)
+	r.POST(GetByFilterExp, h.GetByFilter)
+}
+
+func Notification(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewNotificationHandler(cfg)
+
+	r.GET("/:id", h.GetById)
+	r.POST(GetByFilterExp, h.GetByFilter)
+	r.POST("/:id/read", h.MarkRead)
+}
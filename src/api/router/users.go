@@ -14,4 +14,16 @@ func User(router *gin.RouterGroup, cfg *config.Config) {
 	router.POST("/login-by-username", h.LoginByUsername)
 	router.POST("/register-by-username", h.RegisterByUsername)
 	router.POST("/login-by-mobile", h.RegisterLoginByMobileNumber)
+	router.PUT("/language", middleware.Authentication(cfg), h.SetLanguage)
+}
+
+func Tenant(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewTenantHandler(cfg)
+
+	r.POST("/", h.Create)
+	r.PUT("/:id", h.Update)
+	r.DELETE("/:id", h.Delete)
+	r.GET("/:id", h.GetById)
+	r.POST(GetByFilterExp, h.GetByFilter)
+	r.POST("/:id/admins", h.CreateAdmin)
 }
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/handler"
+	"github.com/naeemaei/golang-clean-web-api/config"
+)
+
+func Watchlist(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewWatchlistHandler(cfg)
+
+	r.POST("/", h.Create)
+	r.PUT("/:id", h.Update)
+	r.DELETE("/:id", h.Delete)
+	r.GET("/:id", h.GetById)
+	r.POST(GetByFilterExp, h.GetByFilter)
+	r.POST("/:id/entries", h.AddEntry)
+	r.DELETE("/:id/entries/:entryId", h.RemoveEntry)
+}
+
+func SharedWatchlist(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewWatchlistHandler(cfg)
+
+	r.GET("/:token", h.GetShared)
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api/handler"
+	"github.com/naeemaei/golang-clean-web-api/config"
+)
+
+func Webhook(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewWebhookHandler(cfg)
+
+	r.POST("/", h.Create)
+	r.PUT("/:id", h.Update)
+	r.DELETE("/:id", h.Delete)
+	r.GET("/:id", h.GetById)
+	r.POST(GetByFilterExp, h.GetByFilter)
+}
+
+func WebhookDelivery(r *gin.RouterGroup, cfg *config.Config) {
+	h := handler.NewWebhookHandler(cfg)
+
+	r.GET("/:id", h.GetDeliveryById)
+	r.POST(GetByFilterExp, h.GetDeliveriesByFilter)
+	r.POST("/:id/redeliver", h.Redeliver)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/naeemaei/golang-clean-web-api/constant"
 )
 
 type ValidationError struct {
@@ -22,6 +23,7 @@ func GetValidationErrors(err error) *[]ValidationError {
 			el.Property = err.Field()
 			el.Tag = err.Tag()
 			el.Value = err.Param()
+			el.Message = Message(el.Tag, el.Property, el.Value, constant.DefaultLanguage)
 			validationErrors = append(validationErrors, el)
 		}
 		return &validationErrors
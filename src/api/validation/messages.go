@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+)
+
+// invalidTag is the message of the validator tags without a translation
+const invalidTag = ""
+
+// messages translates the validator tags to every supported language, {property} and {param}
+// are replaced with the field and the parameter of the tag
+var messages = map[string]map[string]string{
+	constant.EnglishLanguage: {
+		invalidTag:    "{property} is invalid",
+		"required":    "{property} is required",
+		"min":         "{property} must not be shorter or less than {param}",
+		"max":         "{property} must not be longer or greater than {param}",
+		"len":         "{property} must have a length of {param}",
+		"gt":          "{property} must be greater than {param}",
+		"gte":         "{property} must be greater than or equal to {param}",
+		"lt":          "{property} must be less than {param}",
+		"lte":         "{property} must be less than or equal to {param}",
+		"alpha":       "{property} must contain only letters",
+		"email":       "{property} must be a valid email",
+		"url":         "{property} must be a valid url",
+		"oneof":       "{property} must be one of {param}",
+		"excludesall": "{property} must not contain any of {param}",
+		"mobile":      "{property} must be a valid mobile number",
+		"password":    "{property} is not strong enough",
+		"slug":        "{property} must contain only lower case letters, digits and hyphens",
+	},
+	constant.PersianLanguage: {
+		invalidTag:    "{property} نامعتبر است",
+		"required":    "{property} الزامی است",
+		"min":         "{property} نباید کوتاه‌تر یا کمتر از {param} باشد",
+		"max":         "{property} نباید طولانی‌تر یا بیشتر از {param} باشد",
+		"len":         "طول {property} باید {param} باشد",
+		"gt":          "{property} باید بیشتر از {param} باشد",
+		"gte":         "{property} باید بیشتر یا مساوی {param} باشد",
+		"lt":          "{property} باید کمتر از {param} باشد",
+		"lte":         "{property} باید کمتر یا مساوی {param} باشد",
+		"alpha":       "{property} باید فقط شامل حروف باشد",
+		"email":       "{property} باید یک ایمیل معتبر باشد",
+		"url":         "{property} باید یک آدرس معتبر باشد",
+		"oneof":       "{property} باید یکی از {param} باشد",
+		"excludesall": "{property} نباید شامل هیچ یک از {param} باشد",
+		"mobile":      "{property} باید یک شماره موبایل معتبر باشد",
+		"password":    "{property} به اندازه کافی قوی نیست",
+		"slug":        "{property} باید فقط شامل حروف کوچک، عدد و خط تیره باشد",
+	},
+}
+
+// Message returns the message of a failed validator tag in language, falling back to English
+func Message(tag string, property string, param string, language string) string {
+	catalog, ok := messages[language]
+	if !ok {
+		catalog = messages[constant.DefaultLanguage]
+	}
+	message, ok := catalog[tag]
+	if !ok {
+		message = catalog[invalidTag]
+	}
+	return strings.NewReplacer("{property}", property, "{param}", param).Replace(message)
+}
+
+// Localize sets the messages of validation errors in language
+func Localize(errors []ValidationError, language string) {
+	for i := range errors {
+		errors[i].Message = Message(errors[i].Tag, errors[i].Property, errors[i].Value, language)
+	}
+}
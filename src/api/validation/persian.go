@@ -0,0 +1,17 @@
+package validation
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/naeemaei/golang-clean-web-api/common"
+)
+
+// PersianValidator normalizes the Persian text of a bound request before validating it, so validators
+// and usecases only see Persian yeh and kaf and latin digits, see common.NormalizePersian
+type PersianValidator struct {
+	binding.StructValidator
+}
+
+func (v PersianValidator) ValidateStruct(obj any) error {
+	common.NormalizePersianFields(obj)
+	return v.StructValidator.ValidateStruct(obj)
+}
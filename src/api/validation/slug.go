@@ -0,0 +1,22 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// SlugValidator accepts lower case words separated by a dash, e.g. "tehran-motors"
+func SlugValidator(fld validator.FieldLevel) bool {
+	value, ok := fld.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+	return IsSlug(value)
+}
+
+func IsSlug(value string) bool {
+	return slugPattern.MatchString(value)
+}
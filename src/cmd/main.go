@@ -1,14 +1,32 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
 	"github.com/naeemaei/golang-clean-web-api/api"
 	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
 	"github.com/naeemaei/golang-clean-web-api/infra/cache"
+	"github.com/naeemaei/golang-clean-web-api/infra/job"
+	"github.com/naeemaei/golang-clean-web-api/infra/outbox"
 	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
 	"github.com/naeemaei/golang-clean-web-api/infra/persistence/migration"
+	"github.com/naeemaei/golang-clean-web-api/pkg/export"
 	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/webhook"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
 )
 
+const migrateUsage = "usage: main migrate up [n] | down [n] | status | redo"
+const importUsage = "usage: main import [-dry-run] [-tenant id] <file.csv|file.xlsx>"
+const webhookReceiverUsage = "usage: main webhook-receiver [-port n] [-secret s] [-status code]"
+
 // @securityDefinitions.apikey AuthBearer
 // @in header
 // @name Authorization
@@ -17,6 +35,19 @@ func main() {
 	cfg := config.GetConfig()
 	logger := logging.NewLogger(cfg)
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(cfg, logger, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImport(cfg, logger, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "webhook-receiver" {
+		runWebhookReceiver(cfg, logger, os.Args[2:])
+		return
+	}
+
 	err := cache.InitRedis(cfg)
 	defer cache.CloseRedis()
 	if err != nil {
@@ -28,7 +59,152 @@ func main() {
 	if err != nil {
 		logger.Fatal(logging.Postgres, logging.Startup, err.Error(), nil)
 	}
-	migration.Up1()
+	pending, err := migration.NewMigrator(database.GetDb()).Pending()
+	if err != nil {
+		logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+	} else if pending > 0 {
+		logger.Warn(logging.Postgres, logging.Migration,
+			fmt.Sprintf("%d pending migration(s), run `migrate up`", pending), nil)
+	}
+
+	expirer := job.NewListingExpirer(cfg, dependency.GetListingRepository(cfg))
+	expirer.Start()
+	defer expirer.Stop()
+
+	if cfg.Outbox.Enabled {
+		sinks, err := outbox.NewSinks(cfg)
+		if err != nil {
+			logger.Fatal(logging.General, logging.Startup, err.Error(), nil)
+		}
+		// webhook deliveries are enqueued by the bus sink
+		webhooks := usecase.NewWebhookUsecase(cfg, dependency.GetWebhookSubscriptionRepository(cfg), dependency.GetWebhookDeliveryRepository(cfg))
+		outbox.GetBus().Subscribe(webhooks.Enqueue)
+		// price alerts are evaluated on PriceChanged events
+		notifications := usecase.NewNotificationUsecase(cfg, dependency.GetNotificationRepository(cfg))
+		alerts := usecase.NewPriceAlertUsecase(cfg, dependency.GetPriceAlertRepository(cfg), dependency.GetCarModelPriceHistoryRepository(cfg), dependency.GetExchangeRateRepository(cfg), notifications)
+		outbox.GetBus().Subscribe(alerts.Evaluate)
+
+		dispatcher := outbox.NewDispatcher(cfg, dependency.GetOutboxRepository(cfg), sinks...)
+		dispatcher.Start()
+		defer dispatcher.Stop()
+		deliverer := outbox.NewWebhookDeliverer(cfg, dependency.GetWebhookDeliveryRepository(cfg))
+		deliverer.Start()
+		defer deliverer.Stop()
+	}
 
 	api.InitServer(cfg)
 }
+
+func runMigrate(cfg *config.Config, logger logging.Logger, args []string) {
+	if len(args) == 0 {
+		logger.Fatal(logging.Postgres, logging.Migration, migrateUsage, nil)
+	}
+	steps := 0
+	if len(args) > 1 {
+		var err error
+		steps, err = strconv.Atoi(args[1])
+		if err != nil || steps < 0 {
+			logger.Fatal(logging.Postgres, logging.Migration, migrateUsage, nil)
+		}
+	}
+
+	err := database.InitDb(cfg)
+	defer database.CloseDb()
+	if err != nil {
+		logger.Fatal(logging.Postgres, logging.Startup, err.Error(), nil)
+	}
+	migrator := migration.NewMigrator(database.GetDb())
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up(steps)
+	case "down":
+		err = migrator.Down(steps)
+	case "redo":
+		err = migrator.Redo()
+	case "status":
+		var status []migration.MigrationStatus
+		status, err = migrator.Status()
+		for _, item := range status {
+			appliedAt := "pending"
+			if item.Applied {
+				appliedAt = item.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%5d  %-30s %s\n", item.Version, item.Name, appliedAt)
+		}
+	default:
+		logger.Fatal(logging.Postgres, logging.Migration, migrateUsage, nil)
+	}
+	if err != nil {
+		logger.Fatal(logging.Postgres, logging.Migration, err.Error(), nil)
+	}
+}
+
+// runImport imports a car model catalog file like POST /v1/car-models/import, it exits with 1 when
+// the file has invalid or no valid rows so it can be used in scripts
+func runImport(cfg *config.Config, logger logging.Logger, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only validate the file")
+	tenantId := flags.Int("tenant", constant.DefaultTenantId, "tenant of the car models")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		logger.Fatal(logging.General, logging.Import, importUsage, nil)
+	}
+	format, ok := export.FormatOfFile(flags.Arg(0))
+	if !ok {
+		logger.Fatal(logging.General, logging.Import, importUsage, nil)
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		logger.Fatal(logging.IO, logging.Import, err.Error(), nil)
+	}
+	defer file.Close()
+
+	err = database.InitDb(cfg)
+	defer database.CloseDb()
+	if err != nil {
+		logger.Fatal(logging.Postgres, logging.Startup, err.Error(), nil)
+	}
+
+	ctx := context.WithValue(context.Background(), constant.TenantIdKey, float64(*tenantId))
+	result, err := usecase.NewCatalogImportUsecase(cfg, dependency.GetCatalogImportRepository(cfg)).
+		Import(ctx, format, file, *dryRun)
+	if err != nil {
+		logger.Fatal(logging.General, logging.Import, err.Error(), nil)
+	}
+	for _, row := range result.Rows {
+		for _, rowErr := range row.Errors {
+			fmt.Printf("row %-6d %-20s %s\n", row.Row, rowErr.Column, rowErr.Message)
+		}
+	}
+	mode := ""
+	if result.DryRun {
+		mode = " (dry run)"
+	}
+	fmt.Printf("%d rows, %d created, %d updated, %d invalid%s\n",
+		result.TotalRows, result.Created, result.Updated, result.InvalidRows, mode)
+	if result.InvalidRows > 0 || result.ValidRows == 0 {
+		database.CloseDb()
+		os.Exit(1)
+	}
+}
+
+// runWebhookReceiver serves a webhook target for development that prints the deliveries,
+// -status makes it fail so retries and dead letters can be tried out
+func runWebhookReceiver(cfg *config.Config, logger logging.Logger, args []string) {
+	flags := flag.NewFlagSet("webhook-receiver", flag.ExitOnError)
+	port := flags.Int("port", cfg.Webhook.ReceiverPort, "port to listen on")
+	secret := flags.String("secret", "", "secret of the subscription, signatures are not checked when empty")
+	status := flags.Int("status", http.StatusOK, "status code of the responses")
+	flags.Parse(args)
+	if flags.NArg() != 0 || *status < 100 || *status > 599 {
+		logger.Fatal(logging.General, logging.Webhook, webhookReceiverUsage, nil)
+	}
+
+	addr := fmt.Sprintf(":%d", *port)
+	fmt.Printf("receiving webhooks on http://localhost%s/\n", addr)
+	err := http.ListenAndServe(addr, webhook.NewReceiver(*secret, *status, os.Stdout))
+	if err != nil {
+		logger.Fatal(logging.General, logging.Webhook, err.Error(), nil)
+	}
+}
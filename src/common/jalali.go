@@ -0,0 +1,159 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+// IranLocation is the time zone of Iran, Iran had daylight saving time until 1401. The zone is embedded
+// by time/tzdata so it loads without the zoneinfo of the system
+var IranLocation = loadLocation("Asia/Tehran")
+
+// years where the 33 year cycles of the Jalali calendar break, see jalaliCalendar
+var jalaliBreaks = []int{-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210, 1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178}
+
+// ToJalali converts the date of t in its location to the Jalali (Persian) calendar
+func ToJalali(t time.Time) (year int, month int, day int) {
+	gy := t.Year()
+	jy := gy - 621
+	leap, _, march := jalaliCalendar(jy)
+	k := julianDay(gy, t.Month(), t.Day()) - julianDay(gy, time.March, march)
+	if k >= 0 {
+		if k <= 185 {
+			return jy, 1 + k/31, k%31 + 1
+		}
+		k -= 186
+	} else {
+		jy--
+		k += 179
+		if leap == 1 {
+			k++
+		}
+	}
+	return jy, 7 + k/30, k%30 + 1
+}
+
+// FromJalali returns the start of a Jalali date in loc, months and days out of range are normalized
+// like time.Date does, e.g. month 13 is the first month of the next year
+func FromJalali(year int, month int, day int, loc *time.Location) time.Time {
+	year += (month - 1) / 12
+	month = (month-1)%12 + 1
+	if month < 1 {
+		year--
+		month += 12
+	}
+	_, gy, march := jalaliCalendar(year)
+	days := (month-1)*31 - (month/7)*(month-7) + day - 1
+	return time.Date(gy, time.March, march+days, 0, 0, 0, 0, loc)
+}
+
+// JalaliYearBounds returns the start of Jalali year and the start of the next year in Iran
+func JalaliYearBounds(year int) (start time.Time, end time.Time) {
+	return FromJalali(year, 1, 1, IranLocation), FromJalali(year+1, 1, 1, IranLocation)
+}
+
+// IsJalaliLeapYear tells whether Esfand, the last month of year, has 30 days
+func IsJalaliLeapYear(year int) bool {
+	leap, _, _ := jalaliCalendar(year)
+	return leap == 0
+}
+
+// JalaliMonthDays returns the number of days of a Jalali month
+func JalaliMonthDays(year int, month int) int {
+	switch {
+	case month <= 6:
+		return 31
+	case month <= 11 || IsJalaliLeapYear(year):
+		return 30
+	}
+	return 29
+}
+
+// FormatJalali formats t in Iran like 1402/01/15 13:45:00
+func FormatJalali(t time.Time) string {
+	t = t.In(IranLocation)
+	year, month, day := ToJalali(t)
+	return fmt.Sprintf("%04d/%02d/%02d %s", year, month, day, t.Format(time.TimeOnly))
+}
+
+// ParseJalali parses a Jalali date like 1402/01/15 or 1402-01-15, optionally followed by a time like 13:45
+// or 13:45:30, in loc. Persian digits are accepted. dateOnly tells whether the time was left out
+func ParseJalali(value string, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	value = strings.TrimSpace(NormalizePersian(value))
+	date, clock, hasClock := strings.Cut(strings.Replace(value, "T", " ", 1), " ")
+	parts := strings.FieldsFunc(date, func(r rune) bool { return r == '/' || r == '-' })
+	invalid := fmt.Errorf("invalid jalali date %s", value)
+	if len(parts) != 3 {
+		return t, false, invalid
+	}
+	numbers := [3]int{}
+	for i, part := range parts {
+		if numbers[i], err = strconv.Atoi(part); err != nil {
+			return t, false, invalid
+		}
+	}
+	year, month, day := numbers[0], numbers[1], numbers[2]
+	if year < 1 || year >= jalaliBreaks[len(jalaliBreaks)-1] || month < 1 || month > 12 || day < 1 || day > JalaliMonthDays(year, month) {
+		return t, false, invalid
+	}
+	t = FromJalali(year, month, day, loc)
+	if !hasClock {
+		return t, true, nil
+	}
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		if c, err := time.Parse(layout, strings.TrimSpace(clock)); err == nil {
+			// the clock is the wall clock of the day, a day of a daylight saving change is not 24 hours
+			return time.Date(t.Year(), t.Month(), t.Day(), c.Hour(), c.Minute(), c.Second(), 0, loc), false, nil
+		}
+	}
+	return time.Time{}, false, invalid
+}
+
+// jalaliCalendar returns the years since the last leap year (0 to 4), the Gregorian year
+// and the day of March of the first day of Jalali year jy (jalaali-js algorithm)
+func jalaliCalendar(jy int) (leap int, gy int, march int) {
+	gy = jy + 621
+	leapJ := -14
+	jp := jalaliBreaks[0]
+	jump := 0
+	for i := 1; i < len(jalaliBreaks); i++ {
+		jm := jalaliBreaks[i]
+		jump = jm - jp
+		if jy < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := jy - jp
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	march = 20 + leapJ - leapG
+
+	if jump-n < 6 {
+		n = n - jump + (jump+4)/33*33
+	}
+	leap = ((n+1)%33 - 1) % 4
+	if leap == -1 {
+		leap = 4
+	}
+	return leap, gy, march
+}
+
+func loadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+func julianDay(year int, month time.Month, day int) int {
+	return int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix()/86400) + 2440588
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJalaliKnownDates(t *testing.T) {
+	cases := []struct {
+		name             string
+		gregorian        time.Time
+		year, month, day int
+		leap             bool
+	}{
+		{"nowruz 1395", time.Date(2016, 3, 20, 0, 0, 0, 0, IranLocation), 1395, 1, 1, true},
+		{"esfand 30 1395", time.Date(2017, 3, 20, 0, 0, 0, 0, IranLocation), 1395, 12, 30, true},
+		{"nowruz 1396", time.Date(2017, 3, 21, 0, 0, 0, 0, IranLocation), 1396, 1, 1, false},
+		{"nowruz 1399", time.Date(2020, 3, 20, 0, 0, 0, 0, IranLocation), 1399, 1, 1, true},
+		{"esfand 30 1399", time.Date(2021, 3, 20, 0, 0, 0, 0, IranLocation), 1399, 12, 30, true},
+		{"esfand 29 1402", time.Date(2024, 3, 19, 0, 0, 0, 0, IranLocation), 1402, 12, 29, false},
+		{"nowruz 1403", time.Date(2024, 3, 20, 0, 0, 0, 0, IranLocation), 1403, 1, 1, true},
+		{"esfand 30 1403", time.Date(2025, 3, 20, 0, 0, 0, 0, IranLocation), 1403, 12, 30, true},
+		{"nowruz 1404", time.Date(2025, 3, 21, 0, 0, 0, 0, IranLocation), 1404, 1, 1, false},
+		{"mehr 1 1402", time.Date(2023, 9, 23, 0, 0, 0, 0, IranLocation), 1402, 7, 1, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if year, month, day := ToJalali(tc.gregorian); year != tc.year || month != tc.month || day != tc.day {
+				t.Errorf("ToJalali(%v) = %d/%d/%d, want %d/%d/%d", tc.gregorian, year, month, day, tc.year, tc.month, tc.day)
+			}
+			if got := FromJalali(tc.year, tc.month, tc.day, IranLocation); !got.Equal(tc.gregorian) {
+				t.Errorf("FromJalali(%d/%d/%d) = %v, want %v", tc.year, tc.month, tc.day, got, tc.gregorian)
+			}
+			if IsJalaliLeapYear(tc.year) != tc.leap {
+				t.Errorf("IsJalaliLeapYear(%d) = %v, want %v", tc.year, !tc.leap, tc.leap)
+			}
+		})
+	}
+}
+
+func TestJalaliRoundTrip(t *testing.T) {
+	end := time.Date(2070, 1, 1, 0, 0, 0, 0, IranLocation)
+	previousYear, previousMonth, previousDay := ToJalali(time.Date(1969, 12, 31, 0, 0, 0, 0, IranLocation))
+	for i := 0; ; i++ {
+		// a day starting with a daylight saving change starts at 01:00, so every day is built from midnight
+		day := time.Date(1970, 1, 1+i, 0, 0, 0, 0, IranLocation)
+		if !day.Before(end) {
+			break
+		}
+		year, month, d := ToJalali(day)
+		if got := FromJalali(year, month, d, IranLocation); !got.Equal(day) {
+			t.Fatalf("FromJalali(ToJalali(%v)) = %v", day, got)
+		}
+		// the days follow each other without gaps
+		next := previousDay == JalaliMonthDays(previousYear, previousMonth)
+		switch {
+		case !next && (year != previousYear || month != previousMonth || d != previousDay+1),
+			next && previousMonth < 12 && (year != previousYear || month != previousMonth+1 || d != 1),
+			next && previousMonth == 12 && (year != previousYear+1 || month != 1 || d != 1):
+			t.Fatalf("%v is %d/%d/%d after %d/%d/%d", day, year, month, d, previousYear, previousMonth, previousDay)
+		}
+		previousYear, previousMonth, previousDay = year, month, d
+	}
+}
+
+func TestIranDaylightSavingTime(t *testing.T) {
+	cases := []struct {
+		name   string
+		at     time.Time
+		jalali string
+	}{
+		// Iran was +04:30 in the summer until 1401
+		{"summer 1400", time.Date(2021, 6, 1, 7, 30, 0, 0, time.UTC), "1400/03/11 12:00:00"},
+		{"winter 1400", time.Date(2022, 1, 1, 8, 30, 0, 0, time.UTC), "1400/10/11 12:00:00"},
+		{"summer 1402", time.Date(2023, 6, 1, 8, 30, 0, 0, time.UTC), "1402/03/11 12:00:00"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FormatJalali(tc.at); got != tc.jalali {
+				t.Errorf("FormatJalali(%v) = %s, want %s", tc.at, got, tc.jalali)
+			}
+			if got, _, err := ParseJalali(tc.jalali, IranLocation); err != nil || !got.Equal(tc.at) {
+				t.Errorf("ParseJalali(%s) = %v, %v, want %v", tc.jalali, got, err, tc.at)
+			}
+		})
+	}
+}
+
+func TestParseJalali(t *testing.T) {
+	cases := []struct {
+		value    string
+		want     time.Time
+		dateOnly bool
+		invalid  bool
+	}{
+		{value: "1403/01/01", want: time.Date(2024, 3, 20, 0, 0, 0, 0, IranLocation), dateOnly: true},
+		{value: "1403-12-30", want: time.Date(2025, 3, 20, 0, 0, 0, 0, IranLocation), dateOnly: true},
+		{value: "۱۴۰۲/۰۱/۱۵ ۱۳:۴۵", want: time.Date(2023, 4, 4, 13, 45, 0, 0, IranLocation)},
+		{value: "1402/01/15T13:45:30", want: time.Date(2023, 4, 4, 13, 45, 30, 0, IranLocation)},
+		{value: "1402/12/30", invalid: true},
+		{value: "1402/13/01", invalid: true},
+		{value: "1402/01/15 25:00", invalid: true},
+		{value: "1402/01", invalid: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, dateOnly, err := ParseJalali(tc.value, IranLocation)
+			switch {
+			case tc.invalid && err == nil:
+				t.Errorf("ParseJalali(%s) = %v, want an error", tc.value, got)
+			case !tc.invalid && (err != nil || !got.Equal(tc.want) || dateOnly != tc.dateOnly):
+				t.Errorf("ParseJalali(%s) = %v, %v, %v, want %v, %v", tc.value, got, dateOnly, err, tc.want, tc.dateOnly)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package common
 
 import (
 	"log"
+	"reflect"
 	"regexp"
+	"strings"
 )
 
 const iranianMobileNumberPattern string = `^09(1[0-9]|2[0-2]|3[0-9]|9[0-9])[0-9]{7}$`
@@ -14,3 +16,63 @@ func IranianMobileNumberValidate(mobileNumber string) bool {
 	}
 	return res
 }
+
+var persianCharReplacer = strings.NewReplacer(
+	// Arabic yeh and kaf to Persian
+	"ي", "ی", "ى", "ی", "ك", "ک",
+	// Persian digits
+	"۰", "0", "۱", "1", "۲", "2", "۳", "3", "۴", "4", "۵", "5", "۶", "6", "۷", "7", "۸", "8", "۹", "9",
+	// Arabic-Indic digits
+	"٠", "0", "١", "1", "٢", "2", "٣", "3", "٤", "4", "٥", "5", "٦", "6", "٧", "7", "٨", "8", "٩", "9",
+	// Tatweel
+	"\u0640", "",
+	// Zero width non joiner splits the words it joins
+	"\u200c", " ",
+)
+
+// NormalizePersian unifies Arabic yeh/kaf with Persian ones, converts Persian and Arabic-Indic digits to latin,
+// drops tatweel and splits words joined by ZWNJ. Text is stored normalized, so it must stay in sync
+// with the normalize_persian sql function that backfilled the stored rows.
+func NormalizePersian(value string) string {
+	return persianCharReplacer.Replace(value)
+}
+
+// NormalizePersianFields applies NormalizePersian to the strings of v, which must be a pointer, through
+// its structs, pointers, slices and maps. Fields tagged normalize:"-", like passwords, are left alone
+func NormalizePersianFields(v any) {
+	normalizePersianValue(reflect.ValueOf(v))
+}
+
+func normalizePersianValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			normalizePersianValue(v.Elem())
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(NormalizePersian(v.String()))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() && field.Tag.Get("normalize") != "-" {
+				normalizePersianValue(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			normalizePersianValue(v.Index(i))
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() == reflect.Interface {
+			return
+		}
+		for _, key := range v.MapKeys() {
+			// map values are not addressable, so a copy is normalized and put back
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			normalizePersianValue(value)
+			v.SetMapIndex(key, value)
+		}
+	}
+}
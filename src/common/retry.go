@@ -0,0 +1,15 @@
+package common
+
+import (
+	"math"
+	"time"
+)
+
+// RetryDelay doubles delay for every failed attempt up to maxDelay
+func RetryDelay(delay time.Duration, maxDelay time.Duration, attempts int) time.Duration {
+	next := float64(delay) * math.Pow(2, float64(attempts))
+	if next > float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(next)
+}
@@ -10,14 +10,22 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Postgres PostgresConfig
-	Redis    RedisConfig
-	Password PasswordConfig
-	Cors     CorsConfig
-	Logger   LoggerConfig
-	Otp      OtpConfig
-	JWT      JWTConfig
+	Server       ServerConfig
+	Postgres     PostgresConfig
+	Redis        RedisConfig
+	Cache        CacheConfig
+	Export       ExportConfig
+	Import       ImportConfig
+	Outbox       OutboxConfig
+	Webhook      WebhookConfig
+	Notification NotificationConfig
+	Catalog      CatalogConfig
+	Listing      ListingConfig
+	Password     PasswordConfig
+	Cors         CorsConfig
+	Logger       LoggerConfig
+	Otp          OtpConfig
+	JWT          JWTConfig
 }
 
 type ServerConfig struct {
@@ -43,6 +51,13 @@ type PostgresConfig struct {
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
+	// Replicas are postgres DSNs used for reads, e.g. "host=replica1 port=5432 user=postgres password=admin dbname=car_sale_db sslmode=disable"
+	Replicas                   []string
+	ReadYourWrites             bool
+	ReplicaHealthCheckInterval time.Duration
+	// Seconds a user keeps reading from the primary after a write with ReadYourWrites, so the next requests
+	// see the write before the replicas catch up, 0 pins only the writing request
+	PrimaryPinWindow time.Duration
 }
 
 type RedisConfig struct {
@@ -58,6 +73,123 @@ type RedisConfig struct {
 	PoolTimeout        time.Duration
 }
 
+type CacheConfig struct {
+	Enabled    bool
+	DefaultTtl time.Duration
+	// Ttl in seconds per entity name, e.g. color: 86400
+	Ttl map[string]time.Duration
+}
+
+type ExportConfig struct {
+	// Rows fetched from repository per query
+	BatchSize int
+	MaxRows   int
+	// XLSX is buffered until the workbook is complete, so it has a lower cap than the streamed CSV
+	XlsxMaxRows int
+}
+
+type ImportConfig struct {
+	// Data rows of a file, larger files are rejected
+	MaxRows int
+}
+
+type OutboxConfig struct {
+	// Entity changes are written to the outbox when enabled
+	Enabled bool
+	// Seconds between polls of the dispatcher
+	PollInterval time.Duration
+	BatchSize    int
+	// Seconds a claimed message is hidden from other dispatchers
+	LockTimeout time.Duration
+	// Seconds before the first retry, the delay doubles up to MaxRetryDelay
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	// Sinks events are published to: bus, redis, http
+	Sinks       []string
+	RedisStream OutboxRedisStreamConfig
+	Http        OutboxHttpConfig
+}
+
+// WebhookConfig configures the deliverer of webhook subscriptions, it runs with the outbox dispatcher
+// and needs the bus sink
+type WebhookConfig struct {
+	// Seconds between polls of the deliverer
+	PollInterval time.Duration
+	BatchSize    int
+	// Seconds a claimed delivery is hidden from other deliverers
+	LockTimeout time.Duration
+	// Seconds to wait for the response of a target
+	Timeout time.Duration
+	// Seconds before the first retry, the delay doubles up to MaxRetryDelay
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	// Failed attempts after which a delivery is dead lettered
+	MaxAttempts int
+	// Lets webhooks target loopback and private addresses, only for development with the local receiver
+	AllowPrivateTargets bool
+	// Port of the development receiver, see `main webhook-receiver`
+	ReceiverPort int
+}
+
+// NotificationConfig configures the sms and email channels of notifications, a channel without
+// a provider url or host logs its notifications instead of sending them
+type NotificationConfig struct {
+	// Seconds to wait for a provider
+	Timeout time.Duration
+	Sms     NotificationSmsConfig
+	Email   NotificationEmailConfig
+}
+
+// NotificationSmsConfig posts {"from", "to", "text"} as json to Url of an sms gateway
+type NotificationSmsConfig struct {
+	Url     string
+	Sender  string
+	Headers map[string]string
+}
+
+type NotificationEmailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type OutboxRedisStreamConfig struct {
+	Name string
+	// Approximate max length, older entries are trimmed
+	MaxLen int64
+}
+
+type OutboxHttpConfig struct {
+	Url     string
+	Timeout time.Duration
+	Headers map[string]string
+}
+
+// CatalogConfig configures the anonymous catalog api
+type CatalogConfig struct {
+	// Seconds the responses may be cached by browsers and proxies
+	MaxAge int
+	// Requests per second allowed per client ip
+	Rate float64
+	// Requests a client ip may send at once
+	Burst int
+}
+
+// ListingConfig configures the used car listings
+type ListingConfig struct {
+	// Days a listing stays published before it expires
+	Lifetime int
+	// Images a listing may have
+	MaxImages int
+	// Seconds between runs of the expiry job, a published listing stays visible at most this long
+	// after its ExpiresAt
+	ExpireInterval time.Duration
+	// Listings expired per transaction
+	ExpireBatchSize int
+}
+
 type PasswordConfig struct {
 	IncludeChars     bool
 	IncludeDigits    bool
@@ -92,6 +224,9 @@ func GetConfig() *Config {
 	}
 
 	cfg, err := ParseConfig(v)
+	if err != nil {
+		log.Fatalf("Error in parse config %v", err)
+	}
 	envPort := os.Getenv("PORT")
 	if envPort != ""{
 		cfg.Server.ExternalPort = envPort
@@ -100,9 +235,6 @@ func GetConfig() *Config {
 		cfg.Server.ExternalPort = cfg.Server.InternalPort
 		log.Printf("Set external port from environment -> %s", cfg.Server.ExternalPort)
 	}
-	if err != nil {
-		log.Fatalf("Error in parse config %v", err)
-	}
 
 	return cfg
 }
@@ -114,13 +246,38 @@ func ParseConfig(v *viper.Viper) (*Config, error) {
 		log.Printf("Unable to parse config: %v", err)
 		return nil, err
 	}
+	if err = cfg.validate(); err != nil {
+		log.Printf("Invalid config: %v", err)
+		return nil, err
+	}
 	return &cfg, nil
 }
+
+// validate rejects settings the background workers can not run with, e.g. a batch size of 0
+// would make the dispatcher poll forever
+func (cfg *Config) validate() error {
+	if cfg.Listing.ExpireBatchSize <= 0 || cfg.Listing.ExpireInterval <= 0 {
+		return errors.New("listing.expireBatchSize and listing.expireInterval must be greater than 0")
+	}
+	if !cfg.Outbox.Enabled {
+		return nil
+	}
+	if cfg.Outbox.BatchSize <= 0 || cfg.Outbox.PollInterval <= 0 {
+		return errors.New("outbox.batchSize and outbox.pollInterval must be greater than 0")
+	}
+	if cfg.Webhook.BatchSize <= 0 || cfg.Webhook.PollInterval <= 0 {
+		return errors.New("webhook.batchSize and webhook.pollInterval must be greater than 0")
+	}
+	return nil
+}
 func LoadConfig(filename string, fileType string) (*viper.Viper, error) {
 	v := viper.New()
 	v.SetConfigType(fileType)
 	v.SetConfigName(filename)
 	v.AddConfigPath(".")
+	// packages nested deeper than cmd, e.g. tests, resolve the relative development path from here
+	v.AddConfigPath("..")
+	v.AddConfigPath("../..")
 	v.AutomaticEnv()
 
 	err := v.ReadInConfig()
@@ -2,10 +2,13 @@ package constant
 
 const (
 	// User
-	AdminRoleName      string = "admin"
-	DefaultRoleName    string = "default"
-	DefaultUserName    string = "admin"
-	RedisOtpDefaultKey string = "otp"
+	AdminRoleName   string = "admin"
+	DefaultRoleName string = "default"
+	// TenantAdminRoleName manages the data of its own tenant, admin is the role of the platform tenant
+	TenantAdminRoleName string = "tenant-admin"
+	DefaultUserName     string = "admin"
+	RedisOtpDefaultKey  string = "otp"
+	RedisRepositoryKey  string = "repo"
 
 	// Claims
 	AuthorizationHeaderKey string = "Authorization"
@@ -17,4 +20,35 @@ const (
 	MobileNumberKey        string = "MobileNumber"
 	RolesKey               string = "Roles"
 	ExpireTimeKey          string = "Exp"
+	TenantIdKey            string = "TenantId"
+	LanguageKey            string = "Language"
+
+	// Database
+	PrimaryPinKey string = "PrimaryPin"
+)
+
+const (
+	// Tenant
+	DefaultTenantId   int    = 1
+	DefaultTenantSlug string = "default"
+	// TenantHeaderKey selects the tenant of anonymous catalog requests by slug
+	TenantHeaderKey string = "X-Tenant"
+)
+
+const (
+	// Calendar
+	// CalendarHeaderKey or the calendar query parameter set to JalaliCalendar adds a Jalali copy of the
+	// timestamps to json responses
+	CalendarHeaderKey string = "X-Calendar"
+	CalendarQueryKey  string = "calendar"
+	JalaliCalendar    string = "jalali"
+)
+
+const (
+	// Language
+	// AcceptLanguageHeaderKey selects the language of the error messages when the user has no preference
+	AcceptLanguageHeaderKey string = "Accept-Language"
+	EnglishLanguage         string = "en"
+	PersianLanguage         string = "fa"
+	DefaultLanguage         string = EnglishLanguage
 )
@@ -8,43 +8,106 @@ import (
 	infraRepository "github.com/naeemaei/golang-clean-web-api/infra/persistence/repository"
 )
 
+// Repositories builds the repositories on another storage than postgres, like the in-memory store of
+// the test harness. Base returns a contractRepository.BaseRepository of the type of entity, or nil to
+// keep the postgres repository
+type Repositories interface {
+	Base(entity any, preloads []database.PreloadEntity) any
+	User() contractRepository.UserRepository
+	CarModelComment(preloads []database.PreloadEntity) contractRepository.CarModelCommentRepository
+	CarModelImage(preloads []database.PreloadEntity) contractRepository.CarModelImageRepository
+	CarModelPriceHistory(preloads []database.PreloadEntity) contractRepository.CarModelPriceHistoryRepository
+	CarModelProperty(preloads []database.PreloadEntity) contractRepository.CarModelPropertyRepository
+	ExchangeRate(preloads []database.PreloadEntity) contractRepository.ExchangeRateRepository
+	CarModelRating(preloads []database.PreloadEntity) contractRepository.CarModelRatingRepository
+	CarModel(preloads []database.PreloadEntity) contractRepository.CarModelRepository
+	CatalogImport() contractRepository.CatalogImportRepository
+	Outbox() contractRepository.OutboxRepository
+	PriceAlert(preloads []database.PreloadEntity) contractRepository.PriceAlertRepository
+	Watchlist(preloads []database.PreloadEntity) contractRepository.WatchlistRepository
+	Listing(preloads []database.PreloadEntity) contractRepository.ListingRepository
+	Notification(preloads []database.PreloadEntity) contractRepository.NotificationRepository
+	WebhookSubscription(preloads []database.PreloadEntity) contractRepository.WebhookSubscriptionRepository
+	WebhookDelivery(preloads []database.PreloadEntity) contractRepository.WebhookDeliveryRepository
+}
+
+// repositories replaces postgres when it is set, see UseRepositories
+var repositories Repositories
+
+// UseRepositories makes this package build its repositories with r instead of postgres, nil restores postgres.
+// It must be called before building the router
+func UseRepositories(r Repositories) {
+	repositories = r
+}
+
 func GetUserRepository(cfg *config.Config) contractRepository.UserRepository {
+	if repositories != nil {
+		return repositories.User()
+	}
 	return infraRepository.NewUserRepository(cfg)
 }
 
 func GetPersianYearRepository(cfg *config.Config) contractRepository.PersianYearRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{}
-	return infraRepository.NewBaseRepository[model.PersianYear](cfg, preloads)
+	return withCache[model.PersianYear](cfg, newBaseRepository[model.PersianYear](cfg, preloads))
 }
 
 func GetCountryRepository(cfg *config.Config) contractRepository.CountryRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Cities"}, {Entity: "Companies"}}
-	return infraRepository.NewBaseRepository[model.Country](cfg, preloads)
+	return withCache[model.Country](cfg, newBaseRepository[model.Country](cfg, preloads), "City", "Company")
 }
 
 func GetCarModelColorRepository(cfg *config.Config) contractRepository.CarModelColorRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Color"}}
-	return infraRepository.NewBaseRepository[model.CarModelColor](cfg, preloads)
+	return newBaseRepository[model.CarModelColor](cfg, preloads)
 }
 
 func GetCarModelCommentRepository(cfg *config.Config) contractRepository.CarModelCommentRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "User"}}
-	return infraRepository.NewBaseRepository[model.CarModelComment](cfg, preloads)
+	if repositories != nil {
+		return repositories.CarModelComment(preloads)
+	}
+	return infraRepository.NewCarModelCommentRepository(cfg, preloads)
 }
 
 func GetCarModelImageRepository(cfg *config.Config) contractRepository.CarModelImageRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Image"}}
-	return infraRepository.NewBaseRepository[model.CarModelImage](cfg, preloads)
+	if repositories != nil {
+		return repositories.CarModelImage(preloads)
+	}
+	return infraRepository.NewCarModelImageRepository(cfg, preloads)
 }
 
 func GetCarModelPriceHistoryRepository(cfg *config.Config) contractRepository.CarModelPriceHistoryRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{}
-	return infraRepository.NewBaseRepository[model.CarModelPriceHistory](cfg, preloads)
+	if repositories != nil {
+		return repositories.CarModelPriceHistory(preloads)
+	}
+	return infraRepository.NewCarModelPriceHistoryRepository(cfg, preloads)
+}
+
+func GetExchangeRateRepository(cfg *config.Config) contractRepository.ExchangeRateRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{}
+	if repositories != nil {
+		return repositories.ExchangeRate(preloads)
+	}
+	return infraRepository.NewExchangeRateRepository(cfg, preloads)
 }
 
 func GetCarModelPropertyRepository(cfg *config.Config) contractRepository.CarModelPropertyRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Property.Category"}}
-	return infraRepository.NewBaseRepository[model.CarModelProperty](cfg, preloads)
+	if repositories != nil {
+		return repositories.CarModelProperty(preloads)
+	}
+	return infraRepository.NewCarModelPropertyRepository(cfg, preloads)
+}
+
+func GetCarModelRatingRepository(cfg *config.Config) contractRepository.CarModelRatingRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{}
+	if repositories != nil {
+		return repositories.CarModelRating(preloads)
+	}
+	return infraRepository.NewCarModelRatingRepository(cfg, preloads)
 }
 
 func GetCarModelRepository(cfg *config.Config) contractRepository.CarModelRepository {
@@ -59,55 +122,173 @@ func GetCarModelRepository(cfg *config.Config) contractRepository.CarModelReposi
 		{Entity: "CarModelImages.Image"},
 		{Entity: "CarModelComments.User"},
 	}
-	return infraRepository.NewBaseRepository[model.CarModel](cfg, preloads)
+	if repositories != nil {
+		return repositories.CarModel(preloads)
+	}
+	return infraRepository.NewCarModelRepository(cfg, preloads)
+}
+
+// GetCatalogCarModelRepository preloads only what the anonymous catalog list shows, comments,
+// colors and property values are left out
+func GetCatalogCarModelRepository(cfg *config.Config) contractRepository.CarModelRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{
+		{Entity: "Company.Country"},
+		{Entity: "CarType"},
+		{Entity: "Gearbox"},
+		{Entity: "CarModelYears.PersianYear"},
+		{Entity: "CarModelYears.CarModelPriceHistories"},
+		{Entity: "CarModelImages.Image"},
+	}
+	if repositories != nil {
+		return repositories.CarModel(preloads)
+	}
+	return infraRepository.NewCarModelRepository(cfg, preloads)
+}
+
+func GetCatalogImportRepository(cfg *config.Config) contractRepository.CatalogImportRepository {
+	if repositories != nil {
+		return repositories.CatalogImport()
+	}
+	return infraRepository.NewCatalogImportRepository(cfg)
+}
+
+func GetOutboxRepository(cfg *config.Config) contractRepository.OutboxRepository {
+	if repositories != nil {
+		return repositories.Outbox()
+	}
+	return infraRepository.NewOutboxRepository(cfg)
+}
+
+func GetPriceAlertRepository(cfg *config.Config) contractRepository.PriceAlertRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{}
+	if repositories != nil {
+		return repositories.PriceAlert(preloads)
+	}
+	return infraRepository.NewPriceAlertRepository(cfg, preloads)
+}
+
+func GetWatchlistRepository(cfg *config.Config) contractRepository.WatchlistRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{}
+	if repositories != nil {
+		return repositories.Watchlist(preloads)
+	}
+	return infraRepository.NewWatchlistRepository(cfg, preloads)
+}
+
+func GetWatchlistEntryRepository(cfg *config.Config) contractRepository.WatchlistEntryRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "CarModel.Company"}, {Entity: "CarModel.CarModelYears.PersianYear"}}
+	return newBaseRepository[model.WatchlistEntry](cfg, preloads)
+}
+
+func GetListingRepository(cfg *config.Config) contractRepository.ListingRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{
+		{Entity: "CarModelYear.CarModel.Company"}, {Entity: "CarModelYear.PersianYear"}, {Entity: "City"}, {Entity: "Color"},
+	}
+	if repositories != nil {
+		return repositories.Listing(preloads)
+	}
+	return infraRepository.NewListingRepository(cfg, preloads)
+}
+
+func GetListingImageRepository(cfg *config.Config) contractRepository.ListingImageRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Image"}}
+	return newBaseRepository[model.ListingImage](cfg, preloads)
+}
+
+func GetNotificationRepository(cfg *config.Config) contractRepository.NotificationRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{}
+	if repositories != nil {
+		return repositories.Notification(preloads)
+	}
+	return infraRepository.NewNotificationRepository(cfg, preloads)
+}
+
+func GetWebhookSubscriptionRepository(cfg *config.Config) contractRepository.WebhookSubscriptionRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{}
+	if repositories != nil {
+		return repositories.WebhookSubscription(preloads)
+	}
+	return infraRepository.NewWebhookSubscriptionRepository(cfg, preloads)
+}
+
+func GetWebhookDeliveryRepository(cfg *config.Config) contractRepository.WebhookDeliveryRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{}
+	if repositories != nil {
+		return repositories.WebhookDelivery(preloads)
+	}
+	return infraRepository.NewWebhookDeliveryRepository(cfg, preloads)
 }
 
 func GetCarModelYearRepository(cfg *config.Config) contractRepository.CarModelYearRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "PersianYear"}}
-	return infraRepository.NewBaseRepository[model.CarModelYear](cfg, preloads)
+	return newBaseRepository[model.CarModelYear](cfg, preloads)
 }
 
 func GetCarTypeRepository(cfg *config.Config) contractRepository.CarTypeRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{}
-	return infraRepository.NewBaseRepository[model.CarType](cfg, preloads)
+	return withCache[model.CarType](cfg, newBaseRepository[model.CarType](cfg, preloads))
 }
 
 func GetCityRepository(cfg *config.Config) contractRepository.CityRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Country"}}
-	return infraRepository.NewBaseRepository[model.City](cfg, preloads)
+	return withCache[model.City](cfg, newBaseRepository[model.City](cfg, preloads), "Country")
 }
 
 func GetColorRepository(cfg *config.Config) contractRepository.ColorRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{}
-	return infraRepository.NewBaseRepository[model.Color](cfg, preloads)
+	return withCache[model.Color](cfg, newBaseRepository[model.Color](cfg, preloads))
 }
 
 func GetCompanyRepository(cfg *config.Config) contractRepository.CompanyRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Country"}}
-	return infraRepository.NewBaseRepository[model.Company](cfg, preloads)
+	return withCache[model.Company](cfg, newBaseRepository[model.Company](cfg, preloads), "Country")
 }
 
 func GetFileRepository(cfg *config.Config) contractRepository.FileRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{}
-	return infraRepository.NewBaseRepository[model.File](cfg, preloads)
+	return newBaseRepository[model.File](cfg, preloads)
 }
 
 func GetGearboxRepository(cfg *config.Config) contractRepository.GearboxRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{}
-	return infraRepository.NewBaseRepository[model.Gearbox](cfg, preloads)
+	return withCache[model.Gearbox](cfg, newBaseRepository[model.Gearbox](cfg, preloads))
 }
 
 func GetPropertyCategoryRepository(cfg *config.Config) contractRepository.PropertyCategoryRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Properties"}}
-	return infraRepository.NewBaseRepository[model.PropertyCategory](cfg, preloads)
+	return withCache[model.PropertyCategory](cfg, newBaseRepository[model.PropertyCategory](cfg, preloads), "Property")
 }
 
 func GetPropertyRepository(cfg *config.Config) contractRepository.PropertyRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{{Entity: "Category"}}
-	return infraRepository.NewBaseRepository[model.Property](cfg, preloads)
+	return withCache[model.Property](cfg, newBaseRepository[model.Property](cfg, preloads), "PropertyCategory")
+}
+
+func GetTenantRepository(cfg *config.Config) contractRepository.TenantRepository {
+	var preloads []database.PreloadEntity = []database.PreloadEntity{}
+	return newBaseRepository[model.Tenant](cfg, preloads)
 }
 
 func GetRoleRepository(cfg *config.Config) contractRepository.RoleRepository {
 	var preloads []database.PreloadEntity = []database.PreloadEntity{}
-	return infraRepository.NewBaseRepository[model.Role](cfg, preloads)
+	return newBaseRepository[model.Role](cfg, preloads)
+}
+
+// withCache wraps a lookup repository with the redis cache decorator when caching is enabled,
+// dependsOn are entity names of preloaded relations that must invalidate this cache
+func withCache[TEntity any](cfg *config.Config, repository contractRepository.BaseRepository[TEntity], dependsOn ...string) contractRepository.BaseRepository[TEntity] {
+	if !cfg.Cache.Enabled {
+		return repository
+	}
+	return infraRepository.NewCachedRepository[TEntity](cfg, repository, dependsOn...)
+}
+
+func newBaseRepository[TEntity any](cfg *config.Config, preloads []database.PreloadEntity) contractRepository.BaseRepository[TEntity] {
+	if repositories != nil {
+		var entity TEntity
+		if repository, ok := repositories.Base(entity, preloads).(contractRepository.BaseRepository[TEntity]); ok {
+			return repository
+		}
+	}
+	return infraRepository.NewBaseRepository[TEntity](cfg, preloads)
 }
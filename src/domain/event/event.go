@@ -0,0 +1,221 @@
+// Package event defines the domain events written to the outbox when entities change.
+package event
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+)
+
+type Type string
+
+const (
+	EntityCreated Type = "EntityCreated"
+	EntityUpdated Type = "EntityUpdated"
+	EntityDeleted Type = "EntityDeleted"
+	// PriceChanged follows the EntityCreated or EntityUpdated event of a CarModelPriceHistory
+	PriceChanged Type = "PriceChanged"
+)
+
+var Types = []Type{EntityCreated, EntityUpdated, EntityDeleted, PriceChanged}
+
+// Event is the message published to the sinks, Id is unique so consumers can drop duplicates
+// of the at least once delivery
+type Event struct {
+	Id         string          `json:"id"`
+	Type       Type            `json:"type"`
+	Entity     string          `json:"entity"`
+	EntityId   int             `json:"entityId"`
+	TenantId   int             `json:"tenantId"`
+	UserId     *int            `json:"userId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// EntityChangedPayload is the payload of EntityCreated, EntityUpdated and EntityDeleted,
+// Entity holds the columns of the entity after the change
+type EntityChangedPayload struct {
+	Entity  map[string]any `json:"entity"`
+	Changes []string       `json:"changes,omitempty"`
+}
+
+type PriceChangedPayload struct {
+	CarModelPriceHistoryId int      `json:"carModelPriceHistoryId"`
+	CarModelYearId         int      `json:"carModelYearId"`
+	Price                  float64  `json:"price"`
+	Currency               string   `json:"currency"`
+	PreviousPrice          *float64 `json:"previousPrice"`
+	// PreviousCurrency and PreviousPriceAt convert PreviousPrice, they are set with it
+	PreviousCurrency string     `json:"previousCurrency,omitempty"`
+	PreviousPriceAt  *time.Time `json:"previousPriceAt,omitempty"`
+	PriceAt          time.Time  `json:"priceAt"`
+}
+
+func New(eventType Type, entity string, entityId int, tenantId int, userId *int, payload any) (Event, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		Id:         uuid.NewString(),
+		Type:       eventType,
+		Entity:     entity,
+		EntityId:   entityId,
+		TenantId:   tenantId,
+		UserId:     userId,
+		OccurredAt: time.Now().UTC(),
+		Payload:    raw,
+	}, nil
+}
+
+// Changed builds the EntityCreated, EntityUpdated or EntityDeleted event of entity,
+// changes are the names of the updated fields
+func Changed[TEntity any](eventType Type, entity TEntity, tenantId int, userId *int, changes []string) (Event, error) {
+	v := reflect.ValueOf(entity)
+	id := int(v.FieldByName("Id").Int())
+	fields := make([]string, 0, len(changes))
+	for _, change := range changes {
+		fields = append(fields, lowerFirst(change))
+	}
+	return New(eventType, v.Type().Name(), id, tenantId, userId, EntityChangedPayload{Entity: Columns(entity), Changes: fields})
+}
+
+// OfChange returns the events of a change of entity, previousPrice looks up the price that a
+// CarModelPriceHistory follows and is only called for them
+func OfChange[TEntity any](eventType Type, entity TEntity, tenantId int, userId *int, changes []string,
+	previousPrice func(model.CarModelPriceHistory) (*model.CarModelPriceHistory, error)) ([]Event, error) {
+	changed, err := Changed(eventType, entity, tenantId, userId, changes)
+	if err != nil {
+		return nil, err
+	}
+	events := []Event{changed}
+	price, ok := any(entity).(model.CarModelPriceHistory)
+	if !ok || eventType == EntityDeleted || eventType == EntityUpdated && !slices.Contains(changes, "Price") {
+		return events, nil
+	}
+	previous, err := previousPrice(price)
+	if err != nil {
+		return nil, err
+	}
+	priceChanged, err := NewPriceChanged(price, previous, userId)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, priceChanged), nil
+}
+
+func NewPriceChanged(price model.CarModelPriceHistory, previous *model.CarModelPriceHistory, userId *int) (Event, error) {
+	payload := PriceChangedPayload{
+		CarModelPriceHistoryId: price.Id,
+		CarModelYearId:         price.CarModelYearId,
+		Price:                  price.Price,
+		Currency:               price.Currency,
+		PriceAt:                price.PriceAt,
+	}
+	if previous != nil {
+		payload.PreviousPrice = &previous.Price
+		payload.PreviousCurrency = previous.Currency
+		payload.PreviousPriceAt = &previous.PriceAt
+	}
+	return New(PriceChanged, reflect.TypeOf(price).Name(), price.Id, price.TenantId, userId, payload)
+}
+
+// Message converts the event to an outbox row
+func (e Event) Message() model.OutboxMessage {
+	return model.OutboxMessage{
+		EventId:    e.Id,
+		Type:       string(e.Type),
+		Entity:     e.Entity,
+		EntityId:   e.EntityId,
+		TenantId:   e.TenantId,
+		UserId:     e.UserId,
+		OccurredAt: e.OccurredAt,
+		Payload:    string(e.Payload),
+
+		NextAttemptAt: e.OccurredAt,
+	}
+}
+
+func FromMessage(m model.OutboxMessage) Event {
+	return Event{
+		Id:         m.EventId,
+		Type:       Type(m.Type),
+		Entity:     m.Entity,
+		EntityId:   m.EntityId,
+		TenantId:   m.TenantId,
+		UserId:     m.UserId,
+		OccurredAt: m.OccurredAt,
+		Payload:    json.RawMessage(m.Payload),
+	}
+}
+
+// Columns returns the column fields of entity by camel case name, relations and fields
+// tagged with event:"-" (e.g. secrets) are skipped
+func Columns(entity any) map[string]any {
+	result := map[string]any{}
+	collectColumns(reflect.ValueOf(entity), result)
+	return result
+}
+
+func collectColumns(v reflect.Value, result map[string]any) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		fld := v.Field(i)
+		if !sf.IsExported() || sf.Tag.Get("event") == "-" {
+			continue
+		}
+		if sf.Anonymous && fld.Kind() == reflect.Struct {
+			collectColumns(fld, result)
+			continue
+		}
+		switch value := fld.Interface().(type) {
+		case sql.NullTime:
+			result[lowerFirst(sf.Name)] = nullable(value.Time, value.Valid)
+		case sql.NullFloat64:
+			result[lowerFirst(sf.Name)] = nullable(value.Float64, value.Valid)
+		case *sql.NullInt64:
+			if value == nil {
+				result[lowerFirst(sf.Name)] = nil
+				continue
+			}
+			result[lowerFirst(sf.Name)] = nullable(value.Int64, value.Valid)
+		case time.Time:
+			result[lowerFirst(sf.Name)] = value
+		default:
+			t := fld.Type()
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Struct || t.Kind() == reflect.Slice {
+				continue
+			}
+			result[lowerFirst(sf.Name)] = value
+		}
+	}
+}
+
+func nullable[T any](value T, valid bool) any {
+	if !valid {
+		return nil
+	}
+	return value
+}
+
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	if strings.ToUpper(name) == name {
+		return strings.ToLower(name)
+	}
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
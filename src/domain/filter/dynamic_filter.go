@@ -1,16 +1,28 @@
 package filter
 
+import (
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+)
+
+// JalaliFilterType marks From and To as Jalali dates of the Tehran time zone, e.g. 1402/01/15 or 1402/01/15 13:45
+const JalaliFilterType = "jalali"
+
+// InFilterType matches one of the comma separated values in From, only the facet fields of the car models take it
+const InFilterType = "in"
+
 type Sort struct {
 	ColId string `json:"colId"`
 	Sort  string `json:"sort"`
 }
 
 type Filter struct {
-	// contains notContains equals notEqual startsWith lessThan lessThanOrEqual greaterThan greaterThanOrEqual inRange endsWith
+	// contains notContains equals notEqual startsWith lessThan lessThanOrEqual greaterThan greaterThanOrEqual inRange endsWith in
 	Type string `json:"type"`
 	From string `json:"from"`
 	To   string `json:"to"`
-	// text number
+	// text number date jalali
 	FilterType string `json:"filterType"`
 }
 
@@ -18,3 +30,41 @@ type DynamicFilter struct {
 	Sort   *[]Sort           `json:"sort"`
 	Filter map[string]Filter `json:"filter"`
 }
+
+func (f Filter) IsJalali() bool {
+	return f.FilterType == JalaliFilterType
+}
+
+// Gregorian converts From and To of a jalali filter to RFC3339 timestamps. A date without a time covers
+// the whole day, so equals becomes an inRange of the day and lessThanOrEqual includes the day
+func (f Filter) Gregorian() (Filter, error) {
+	from, fromDateOnly, err := common.ParseJalali(f.From, common.IranLocation)
+	if err != nil {
+		return f, err
+	}
+	converted := Filter{Type: f.Type, From: from.Format(time.RFC3339Nano), FilterType: "date"}
+	switch f.Type {
+	case "equals":
+		if fromDateOnly {
+			converted.Type = "inRange"
+			converted.To = endOfJalali(from, true).Format(time.RFC3339Nano)
+		}
+	case "lessThanOrEqual", "greaterThan":
+		converted.From = endOfJalali(from, fromDateOnly).Format(time.RFC3339Nano)
+	case "inRange":
+		to, toDateOnly, err := common.ParseJalali(f.To, common.IranLocation)
+		if err != nil {
+			return f, err
+		}
+		converted.To = endOfJalali(to, toDateOnly).Format(time.RFC3339Nano)
+	}
+	return converted, nil
+}
+
+// endOfJalali returns the last microsecond, the precision of postgres, of the day of a date without a time
+func endOfJalali(t time.Time, dateOnly bool) time.Time {
+	if !dateOnly {
+		return t
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Microsecond)
+}
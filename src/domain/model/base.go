@@ -34,6 +34,7 @@ type Color struct {
 
 type File struct {
 	BaseModel
+	TenantModel
 	Name        string `gorm:"size:100;type:string;not null"`
 	Directory   string `gorm:"size:100;type:string;not null"`
 	Description string `gorm:"size:500;type:string;not null"`
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type Gearbox struct {
 	BaseModel
@@ -24,6 +27,7 @@ type Company struct {
 
 type CarModel struct {
 	BaseModel
+	TenantModel
 	Name               string  `gorm:"size:15;type:string;not null,unique;"`
 	Company            Company `gorm:"foreignKey:CompanyId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	CompanyId          int
@@ -36,10 +40,19 @@ type CarModel struct {
 	CarModelProperties []CarModelProperty
 	CarModelImages     []CarModelImage
 	CarModelComments   []CarModelComment
+	// the rating summary is kept up to date by the ratings repository
+	RatingCount    int     `gorm:"not null;default:0"`
+	RatingAverage  float64 `gorm:"type:decimal(3,2);not null;default:0"`
+	OneStarCount   int     `gorm:"not null;default:0"`
+	TwoStarCount   int     `gorm:"not null;default:0"`
+	ThreeStarCount int     `gorm:"not null;default:0"`
+	FourStarCount  int     `gorm:"not null;default:0"`
+	FiveStarCount  int     `gorm:"not null;default:0"`
 }
 
 type CarModelColor struct {
 	BaseModel
+	TenantModel
 	CarModel   CarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	CarModelId int      `gorm:"uniqueIndex:idx_CarModelId_ColorId"`
 	Color      Color    `gorm:"foreignKey:ColorId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
@@ -48,6 +61,7 @@ type CarModelColor struct {
 
 type CarModelYear struct {
 	BaseModel
+	TenantModel
 	CarModel               CarModel    `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	CarModelId             int         `gorm:"uniqueIndex:idx_CarModelId_PersianYearId"`
 	PersianYear            PersianYear `gorm:"foreignKey:PersianYearId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
@@ -57,36 +71,107 @@ type CarModelYear struct {
 
 type CarModelImage struct {
 	BaseModel
+	TenantModel
 	CarModel    CarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	CarModelId  int      `gorm:"uniqueIndex:idx_CarModelId_ImageId"`
 	Image       File     `gorm:"foreignKey:ImageId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	ImageId     int      `gorm:"uniqueIndex:idx_CarModelId_ImageId"`
 	IsMainImage bool
+	SortOrder   int `gorm:"not null;default:0"`
 }
 
 type CarModelPriceHistory struct {
 	BaseModel
+	TenantModel
 	CarModelYear   CarModelYear `gorm:"foreignKey:CarModelYearId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	CarModelYearId int
-	Price          float64   `gorm:"type:decimal(10,2);not null"`
+	Price          float64   `gorm:"type:decimal(20,2);not null"`
+	Currency       string    `gorm:"type:string;size:3;not null;default:'IRT'"`
 	PriceAt        time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
 }
 
 type CarModelProperty struct {
 	BaseModel
+	TenantModel
 	CarModel   CarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	CarModelId int      `gorm:"uniqueIndex:idx_CarModelId_PropertyId"`
 	Property   Property `gorm:"foreignKey:PropertyId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	PropertyId int      `gorm:"uniqueIndex:idx_CarModelId_PropertyId"`
 	Value      string   `gorm:"size:1000,type:string;not null"`
+	// NumericValue is the number of int, decimal and bool (1 or 0) values and the lower bound of range values,
+	// NumericMaxValue is the upper bound of range values and equals NumericValue for the others
+	NumericValue    *float64 `gorm:"type:decimal(18,4);index"`
+	NumericMaxValue *float64 `gorm:"type:decimal(18,4)"`
 }
 
+// Statuses of a comment, only approved comments are public
+const (
+	CommentPending  = "pending"
+	CommentApproved = "approved"
+	CommentRejected = "rejected"
+	CommentHidden   = "hidden"
+)
+
+var CommentStatuses = []string{CommentPending, CommentApproved, CommentRejected, CommentHidden}
+
 type CarModelComment struct {
 	BaseModel
+	TenantModel
 	CarModel   CarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	CarModelId int
 	User       User `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
 	UserId     int
 	Message    string `gorm:"size:500,type:string;not null"`
+	Status     string `gorm:"size:10;type:string;not null;default:'pending';index"`
+	// ModerationNote tells the author why the comment was rejected or hidden
+	ModerationNote string       `gorm:"size:500;type:string;not null;default:''"`
+	ModeratedAt    sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	// ReportCount counts the reports since the last moderation
+	ReportCount int `gorm:"not null;default:0"`
+	// ParentId is the comment replied to, replies belong to the car model of their parent
+	Parent   *CarModelComment `gorm:"foreignKey:ParentId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	ParentId *int             `gorm:"index"`
+	// the vote counts are kept up to date by the comments repository
+	HelpfulCount   int `gorm:"not null;default:0"`
+	UnhelpfulCount int `gorm:"not null;default:0"`
+}
+
+// CarModelCommentVote tells whether a comment was helpful, a user votes a comment once
+type CarModelCommentVote struct {
+	BaseModel
+	TenantModel
+	CarModelComment   CarModelComment `gorm:"foreignKey:CarModelCommentId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelCommentId int             `gorm:"uniqueIndex:idx_vote_CarModelCommentId_UserId"`
+	User              User            `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId            int             `gorm:"uniqueIndex:idx_vote_CarModelCommentId_UserId"`
+	Helpful           bool            `gorm:"not null"`
+}
+
+// Stars of a rating
+const (
+	RatingMinStars = 1
+	RatingMaxStars = 5
+)
+
+// CarModelRating is the 1 to 5 stars rating of a car model, a user rates a car model once
+type CarModelRating struct {
+	BaseModel
+	TenantModel
+	CarModel   CarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelId int      `gorm:"uniqueIndex:idx_rating_CarModelId_UserId"`
+	User       User     `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId     int      `gorm:"uniqueIndex:idx_rating_CarModelId_UserId"`
+	Stars      int      `gorm:"not null"`
+}
+
+// CarModelCommentReport is a report of an abusive comment, a user reports a comment once
+type CarModelCommentReport struct {
+	BaseModel
+	TenantModel
+	CarModelComment   CarModelComment `gorm:"foreignKey:CarModelCommentId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelCommentId int             `gorm:"uniqueIndex:idx_CarModelCommentId_UserId"`
+	User              User            `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId            int             `gorm:"uniqueIndex:idx_CarModelCommentId_UserId"`
+	Reason            string          `gorm:"size:500;type:string;not null"`
 }
 
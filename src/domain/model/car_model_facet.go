@@ -0,0 +1,89 @@
+package model
+
+import "github.com/naeemaei/golang-clean-web-api/domain/filter"
+
+// Facet fields of the car models, the selections hold the ids of their values
+const (
+	FacetCompanyId     = "CompanyId"
+	FacetCountryId     = "CountryId"
+	FacetCarTypeId     = "CarTypeId"
+	FacetGearboxId     = "GearboxId"
+	FacetColorId       = "ColorId"
+	FacetPersianYearId = "PersianYearId"
+)
+
+var FacetFields = []string{FacetCompanyId, FacetCountryId, FacetCarTypeId, FacetGearboxId, FacetColorId, FacetPersianYearId}
+
+// CarModelFacetQuery selects the car models of the faceted search. The prices are the latest prices of
+// the years in DefaultCurrency, converted by the exchange rate at their time, a price without a rate is left out
+type CarModelFacetQuery struct {
+	// Selections match a car model with one of the ids of every field
+	Selections map[string][]int
+	// Price matches a car model with a price in it, nil matches all
+	Price *PriceSelection
+	// Filter holds the filters on the other fields of CarModel
+	Filter map[string]filter.Filter
+}
+
+// Without returns the query without the selection of field
+func (q CarModelFacetQuery) Without(field string) CarModelFacetQuery {
+	selections := make(map[string][]int, len(q.Selections))
+	for name, ids := range q.Selections {
+		if name != field {
+			selections[name] = ids
+		}
+	}
+	q.Selections = selections
+	return q
+}
+
+// WithoutPrice returns the query without the price selection
+func (q CarModelFacetQuery) WithoutPrice() CarModelFacetQuery {
+	q.Price = nil
+	return q
+}
+
+// PriceSelection is a numeric filter on a price, inRange is [From, To) like the price ranges
+type PriceSelection struct {
+	Type string
+	From float64
+	To   float64
+}
+
+func (s PriceSelection) Matches(price float64) bool {
+	switch s.Type {
+	case "equals":
+		return price == s.From
+	case "lessThan":
+		return price < s.From
+	case "lessThanOrEqual":
+		return price <= s.From
+	case "greaterThan":
+		return price > s.From
+	case "greaterThanOrEqual":
+		return price >= s.From
+	case "inRange":
+		return price >= s.From && price < s.To
+	}
+	return false
+}
+
+// CarModelFacetItem prices are the lowest and highest price of the car model, nil without prices
+type CarModelFacetItem struct {
+	Id          int
+	Name        string
+	CompanyName string
+	CountryName string
+	CarTypeName string
+	GearboxName string
+	MinPrice    *float64
+	MaxPrice    *float64
+}
+
+// FacetCount is a value of a facet field with the count of its car models, persian years are named by
+// their persian title
+type FacetCount struct {
+	Id    int
+	Name  string
+	Count int
+}
@@ -0,0 +1,12 @@
+package model
+
+// CarModelSearchResult is a row of car model full text search
+type CarModelSearchResult struct {
+	Id          int
+	Name        string
+	CompanyName string
+	CarTypeName string
+	GearboxName string
+	Rank        float64
+	Highlight   string
+}
@@ -0,0 +1,33 @@
+package model
+
+// CatalogImportItem is a car model row of an import file with its names resolved to ids
+type CatalogImportItem struct {
+	// Row number in the file, the header is row 1
+	Row            int
+	Name           string
+	CompanyId      int
+	CarTypeId      int
+	GearboxId      int
+	ColorIds       []int
+	PersianYearIds []int
+	// Property values by PropertyId
+	Properties map[int]PropertyValue
+
+	// CarModelId and Created come from the lookup in a dry run and are set by the repository on import
+	CarModelId int
+	Created    bool
+}
+
+// CatalogLookup maps names of the reference data and of the car models of the current tenant to ids
+type CatalogLookup struct {
+	Companies map[string]int
+	CarTypes  map[string]int
+	Gearboxes map[string]int
+	Colors    map[string]int
+	// PersianYears are keyed by title and by year, e.g. "1402"
+	PersianYears map[string]int
+	Properties   map[string]int
+	CarModels    map[string]int
+	// PropertyTypes are the properties by id, values are validated against their data types
+	PropertyTypes map[int]Property
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Currencies of the prices, exchange rates are quoted in Rial and a Toman is ten Rials
+const (
+	CurrencyRial  = "IRR"
+	CurrencyToman = "IRT"
+	CurrencyUSD   = "USD"
+	CurrencyEUR   = "EUR"
+)
+
+// DefaultCurrency is the currency of the prices recorded before currencies, the catalog is shown in Toman
+const DefaultCurrency = CurrencyToman
+
+const RialsPerToman = 10
+
+var Currencies = []string{CurrencyRial, CurrencyToman, CurrencyUSD, CurrencyEUR}
+
+// ExchangeRate is the price of one unit of Currency in Rial from RateAt until the next rate of the currency
+type ExchangeRate struct {
+	BaseModel
+	Currency string    `gorm:"type:string;size:3;not null"`
+	Rate     float64   `gorm:"type:decimal(20,4);not null"`
+	RateAt   time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import "database/sql"
+
+// The lifecycle of a listing: a draft is submitted for review, a moderator publishes it or sends it
+// back to draft, and a published listing is sold by its owner or expires
+const (
+	ListingDraft         = "draft"
+	ListingPendingReview = "pending_review"
+	ListingPublished     = "published"
+	ListingSold          = "sold"
+	ListingExpired       = "expired"
+)
+
+var ListingStatuses = []string{ListingDraft, ListingPendingReview, ListingPublished, ListingSold, ListingExpired}
+
+// Listing is a used car for sale by a user
+type Listing struct {
+	BaseModel
+	TenantModel
+	User           User         `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId         int          `gorm:"not null;index"`
+	CarModelYear   CarModelYear `gorm:"foreignKey:CarModelYearId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelYearId int          `gorm:"not null;index"`
+	City           City         `gorm:"foreignKey:CityId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CityId         int          `gorm:"not null;index"`
+	Color          Color        `gorm:"foreignKey:ColorId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	ColorId        int          `gorm:"not null"`
+	Mileage        int          `gorm:"not null"`
+	Price          float64      `gorm:"type:decimal(15,2);not null"`
+	Description    string       `gorm:"size:2000;type:string;not null;default:''"`
+	ContactName    string       `gorm:"size:50;type:string;not null"`
+	ContactPhone   string       `gorm:"size:11;type:string;not null" event:"-"`
+	Status         string       `gorm:"size:20;type:string;not null;default:'draft';index"`
+	// ModerationNote tells the owner why the listing was sent back to draft
+	ModerationNote string       `gorm:"size:500;type:string;not null;default:''"`
+	ModeratedAt    sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	PublishedAt    sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	// ExpiresAt is set on publishing, the listing expires after it unless it was sold
+	ExpiresAt     sql.NullTime `gorm:"type:TIMESTAMP with time zone;null;index"`
+	SoldAt        sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	ListingImages []ListingImage
+}
+
+// ListingImage is an image of the gallery of a listing, the first one by SortOrder is the main image
+type ListingImage struct {
+	BaseModel
+	TenantModel
+	Listing   Listing `gorm:"foreignKey:ListingId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	ListingId int     `gorm:"uniqueIndex:idx_ListingId_ImageId"`
+	Image     File    `gorm:"foreignKey:ImageId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	ImageId   int     `gorm:"uniqueIndex:idx_ListingId_ImageId"`
+	SortOrder int     `gorm:"not null;default:0"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "database/sql"
+
+// Channels of a notification
+const (
+	NotificationInApp = "in_app"
+	NotificationSms   = "sms"
+	NotificationEmail = "email"
+)
+
+// Statuses of a notification, in app notifications are sent when they are created
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+)
+
+// Notification is a message to a user, it is unique by DeduplicationKey so the same
+// notification is never created twice, e.g. when an event is published more than once
+type Notification struct {
+	BaseModel
+	TenantModel
+	User    User   `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId  int    `gorm:"not null;index"`
+	Channel string `gorm:"size:10;type:string;not null"`
+	// Mobile number or email of the user when the notification is sent by sms or email
+	Recipient        string       `gorm:"size:64;type:string;not null;default:''" event:"-"`
+	Title            string       `gorm:"size:100;type:string;not null"`
+	Body             string       `gorm:"size:500;type:string;not null"`
+	DeduplicationKey string       `gorm:"size:100;type:string;not null;uniqueIndex"`
+	Status           string       `gorm:"size:10;type:string;not null"`
+	SentAt           sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	ReadAt           sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	LastError        string       `gorm:"size:1000;type:string;null"`
+}
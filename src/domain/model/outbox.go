@@ -0,0 +1,26 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// OutboxMessage is a domain event waiting to be published, it is written in the transaction
+// of the change. It is not a BaseModel, messages are never soft deleted.
+type OutboxMessage struct {
+	Id         int64     `gorm:"primarykey"`
+	EventId    string    `gorm:"size:36;type:string;not null;uniqueIndex"`
+	Type       string    `gorm:"size:50;type:string;not null"`
+	Entity     string    `gorm:"size:50;type:string;not null"`
+	EntityId   int       `gorm:"not null"`
+	TenantId   int       `gorm:"not null"`
+	UserId     *int      `gorm:"null"`
+	OccurredAt time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
+	Payload    string    `gorm:"type:jsonb;not null"`
+
+	Attempts      int          `gorm:"not null;default:0"`
+	NextAttemptAt time.Time    `gorm:"type:TIMESTAMP with time zone;not null;index:idx_outbox_messages_pending,where:published_at is null"`
+	LockedUntil   sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	PublishedAt   sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	LastError     string       `gorm:"size:1000;type:string;null"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"database/sql"
+)
+
+// Rules of a price alert
+const (
+	// the price rises to Threshold or above it
+	PriceAlertAbove = "above"
+	// the price falls to Threshold or below it
+	PriceAlertBelow = "below"
+	// the price moves Percent percent or more from BasePrice
+	PriceAlertChange = "change"
+)
+
+// PriceAlert notifies a user when a new price of a car model year matches its rule,
+// threshold rules fire when the price crosses the threshold. Threshold and BasePrice are in Currency,
+// prices in other currencies are converted by the rate valid at their time
+type PriceAlert struct {
+	BaseModel
+	TenantModel
+	User           User         `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId         int          `gorm:"not null;index"`
+	CarModelYear   CarModelYear `gorm:"foreignKey:CarModelYearId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelYearId int          `gorm:"not null;index"`
+	Rule           string       `gorm:"size:10;type:string;not null"`
+	Threshold      float64      `gorm:"type:decimal(20,2);not null;default:0"`
+	Currency       string       `gorm:"type:string;size:3;not null;default:'IRT'"`
+	Percent        float64      `gorm:"type:decimal(5,2);not null;default:0"`
+	Channel        string       `gorm:"size:10;type:string;not null"`
+	Enabled        bool         `gorm:"default:true"`
+	// The price a change rule compares with, the last price when the alert is created or triggered
+	BasePrice       sql.NullFloat64 `gorm:"type:decimal(20,2);null"`
+	LastTriggeredAt sql.NullTime    `gorm:"type:TIMESTAMP with time zone;null"`
+}
@@ -1,5 +1,17 @@
 package model
 
+// Data types of a property, int, decimal, bool and range values have a numeric form
+const (
+	PropertyTypeString  = "string"
+	PropertyTypeInt     = "int"
+	PropertyTypeDecimal = "decimal"
+	PropertyTypeBool    = "bool"
+	PropertyTypeEnum    = "enum"
+	PropertyTypeRange   = "range"
+)
+
+var PropertyTypes = []string{PropertyTypeString, PropertyTypeInt, PropertyTypeDecimal, PropertyTypeBool, PropertyTypeEnum, PropertyTypeRange}
+
 type PropertyCategory struct {
 	BaseModel
 	Name       string     `gorm:"size:50;type:string;not null,unique;"`
@@ -16,4 +28,16 @@ type Property struct {
 	Description string `gorm:"size:1000;type:string;not null,unique;"`
 	DataType    string `gorm:"size:15;type:string;not null,unique;"`
 	Unit        string `gorm:"size:15;type:string;not null,unique;"`
+	// MinValue and MaxValue bound int, decimal and range values
+	MinValue *float64 `gorm:"type:decimal(18,4)"`
+	MaxValue *float64 `gorm:"type:decimal(18,4)"`
+	// AllowedValues of an enum, comma separated
+	AllowedValues string `gorm:"size:1000;type:string"`
+}
+
+// PropertyValue is a value normalized for the data type of its property
+type PropertyValue struct {
+	Value           string
+	NumericValue    *float64
+	NumericMaxValue *float64
 }
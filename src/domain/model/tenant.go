@@ -0,0 +1,30 @@
+package model
+
+// Tenant is a dealer organization that owns its catalog, prices and comments.
+// The platform tenant (constant.DefaultTenantId) owns the global reference data
+type Tenant struct {
+	BaseModel
+	Name    string `gorm:"size:50;type:string;not null"`
+	Slug    string `gorm:"size:30;type:string;not null;unique"`
+	Enabled bool   `gorm:"default:true"`
+}
+
+// TenantModel is embedded by entities that belong to a tenant,
+// entities without it are global reference data shared by all tenants. The tenant has no default,
+// rows are always written with the tenant of the request
+type TenantModel struct {
+	TenantId int `gorm:"not null;index"`
+}
+
+type TenantScoped interface {
+	GetTenantId() int
+	SetTenantId(tenantId int)
+}
+
+func (m *TenantModel) GetTenantId() int {
+	return m.TenantId
+}
+
+func (m *TenantModel) SetTenantId(tenantId int) {
+	m.TenantId = tenantId
+}
@@ -2,19 +2,21 @@ package model
 
 type User struct {
 	BaseModel
+	TenantModel
 	Username     string `gorm:"type:string;size:20;not null;unique"`
 	FirstName    string `gorm:"type:string;size:15;null"`
 	LastName     string `gorm:"type:string;size:25;null"`
 	MobileNumber string `gorm:"type:string;size:11;null;unique;default:null"`
 	Email        string `gorm:"type:string;size:64;null;unique;default:null"`
-	Password     string `gorm:"type:string;size:64;not null"`
+	Password     string `gorm:"type:string;size:64;not null" event:"-"`
+	Language     string `gorm:"type:string;size:2;null"`
 	Enabled      bool   `gorm:"default:true"`
 	UserRoles    *[]UserRole
 }
 
 type Role struct {
 	BaseModel
-	Name      string `gorm:"type:string;size:10;not null,unique"`
+	Name      string `gorm:"type:string;size:20;not null,unique"`
 	UserRoles *[]UserRole
 }
 
@@ -0,0 +1,25 @@
+package model
+
+// Watchlist is a private list of car models and car model years of a user,
+// it can be read without a token through its ShareToken when it is shared
+type Watchlist struct {
+	BaseModel
+	TenantModel
+	User   User   `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId int    `gorm:"not null;index"`
+	Name   string `gorm:"size:100;type:string;not null"`
+	// ShareToken is null while the watchlist is private
+	ShareToken *string `gorm:"size:64;type:string;uniqueIndex" event:"-"`
+}
+
+// WatchlistEntry follows a car model, or one year of it when CarModelYearId is set
+type WatchlistEntry struct {
+	BaseModel
+	TenantModel
+	Watchlist      Watchlist     `gorm:"foreignKey:WatchlistId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	WatchlistId    int           `gorm:"not null;index"`
+	CarModel       CarModel      `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelId     int           `gorm:"not null;index"`
+	CarModelYear   *CarModelYear `gorm:"foreignKey:CarModelYearId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelYearId *int
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Statuses of a webhook delivery, failed attempts stay pending until MaxAttempts is reached
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription posts the domain events of its tenant to Url, deliveries are signed with Secret
+type WebhookSubscription struct {
+	BaseModel
+	TenantModel
+	Url string `gorm:"size:500;type:string;not null"`
+	// Comma separated event types, "*" matches all of them
+	EventTypes string `gorm:"size:200;type:string;not null"`
+	// Comma separated entity names, empty matches all entities
+	Entities string `gorm:"size:200;type:string;not null;default:''"`
+	Secret   string `gorm:"size:100;type:string;not null" event:"-"`
+	Enabled  bool   `gorm:"default:true"`
+}
+
+// WebhookDelivery is an event sent to a subscription, it is unique by subscription and event
+// so events published more than once are delivered once
+type WebhookDelivery struct {
+	BaseModel
+	TenantModel
+	SubscriptionId int                 `gorm:"not null;uniqueIndex:idx_webhook_deliveries_event"`
+	Subscription   WebhookSubscription `gorm:"foreignKey:SubscriptionId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	EventId        string              `gorm:"size:36;type:string;not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventType      string              `gorm:"size:50;type:string;not null"`
+	// The event as it is posted
+	Payload string `gorm:"type:jsonb;not null"`
+
+	Status         string       `gorm:"size:10;type:string;not null"`
+	Attempts       int          `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time    `gorm:"type:TIMESTAMP with time zone;not null;index:idx_webhook_deliveries_pending,where:status = 'pending'"`
+	LockedUntil    sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	LastAttemptAt  sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	DeliveredAt    sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	ResponseStatus int          `gorm:"not null;default:0"`
+	LastError      string       `gorm:"size:1000;type:string;null"`
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	"github.com/naeemaei/golang-clean-web-api/domain/model"
@@ -53,6 +54,25 @@ type CompanyRepository interface {
 
 type CarModelRepository interface {
 	BaseRepository[model.CarModel]
+	// Search car models by a normalized full text query, only paging of req is used
+	Search(ctx context.Context, query string, req filter.PaginationInputWithFilter) (int64, *[]model.CarModelSearchResult, error)
+	// FacetCarModels returns the count and a page of the car models matching query, sorted by Id, Name or
+	// Price, the lowest price, then by id
+	FacetCarModels(ctx context.Context, query model.CarModelFacetQuery, sorts *[]filter.Sort, offset int, limit int) (int64, []model.CarModelFacetItem, error)
+	// FacetCounts counts the car models matching query by the values of field ordered by name, the values
+	// of selected are kept without car models
+	FacetCounts(ctx context.Context, query model.CarModelFacetQuery, field string, selected []int) ([]model.FacetCount, error)
+	// FacetPriceBounds returns the lowest and highest price of the car models matching query, false without prices
+	FacetPriceBounds(ctx context.Context, query model.CarModelFacetQuery) (float64, float64, bool, error)
+	// FacetPriceCounts counts the car models matching query with a price in [start+i*step, start+(i+1)*step) by i
+	FacetPriceCounts(ctx context.Context, query model.CarModelFacetQuery, start float64, step float64) (map[int]int, error)
+}
+
+type CatalogImportRepository interface {
+	Lookup(ctx context.Context) (model.CatalogLookup, error)
+	// Import creates or updates the car models of items in one transaction, colors, years and
+	// property values are added to the existing ones. It sets CarModelId and Created of every item.
+	Import(ctx context.Context, items []model.CatalogImportItem) ([]model.CatalogImportItem, error)
 }
 
 type CarModelColorRepository interface {
@@ -63,20 +83,53 @@ type CarModelYearRepository interface {
 	BaseRepository[model.CarModelYear]
 }
 
+// CarModelImageRepository keeps exactly one main image per car model with images: Create makes the
+// first image of a car model main and Delete promotes the first remaining image when the main one is removed
 type CarModelImageRepository interface {
 	BaseRepository[model.CarModelImage]
+	// SetMain makes an image the main image of its car model and demotes the previous main image
+	SetMain(ctx context.Context, id int) error
+	// Reorder sets the gallery order of a car model, ids must be all of its car model image ids
+	Reorder(ctx context.Context, carModelId int, ids []int) error
+}
+
+type ExchangeRateRepository interface {
+	BaseRepository[model.ExchangeRate]
+	// GetByCurrencies returns the rates of currencies at or before until ordered by RateAt
+	GetByCurrencies(ctx context.Context, currencies []string, until time.Time) ([]model.ExchangeRate, error)
 }
 
 type CarModelPriceHistoryRepository interface {
 	BaseRepository[model.CarModelPriceHistory]
+	// GetByCarModelYear returns the prices of a car model year ordered by PriceAt
+	GetByCarModelYear(ctx context.Context, carModelYearId int) ([]model.CarModelPriceHistory, error)
+	// GetByCarModel returns the prices of all years of a car model ordered by PriceAt
+	GetByCarModel(ctx context.Context, carModelId int) ([]model.CarModelPriceHistory, error)
 }
 
 type CarModelPropertyRepository interface {
 	BaseRepository[model.CarModelProperty]
+	// CountByProperty counts the values of a property in all tenants
+	CountByProperty(ctx context.Context, propertyId int) (int64, error)
 }
 
 type CarModelCommentRepository interface {
 	BaseRepository[model.CarModelComment]
+	// AddReport saves the report and counts it on its approved comment, the comment goes back to
+	// pending when it gets threshold reports
+	AddReport(ctx context.Context, report model.CarModelCommentReport, threshold int) error
+	// Vote adds or changes the vote of a user on an approved comment and counts the votes again
+	Vote(ctx context.Context, vote model.CarModelCommentVote) error
+	// RemoveVote removes the vote of a user and counts the votes again
+	RemoveVote(ctx context.Context, commentId int, userId int) error
+}
+
+type CarModelRatingRepository interface {
+	BaseRepository[model.CarModelRating]
+	// Rate adds or changes the rating of a user and updates the rating summary of the car model
+	Rate(ctx context.Context, rating model.CarModelRating) (model.CarModelRating, error)
+	// RemoveRating removes the rating of a user and updates the rating summary of the car model
+	RemoveRating(ctx context.Context, carModelId int, userId int) error
 }
 
 type PropertyCategoryRepository interface {
@@ -94,8 +147,85 @@ type UserRepository interface {
 	FetchUserInfo(ctx context.Context, username string, password string) (model.User, error)
 	GetDefaultRole(ctx context.Context) (roleId int, err error)
 	CreateUser(ctx context.Context, u model.User) (model.User, error)
+	CreateUserWithRole(ctx context.Context, u model.User, roleName string) (model.User, error)
+	Update(ctx context.Context, id int, entity map[string]interface{}) (model.User, error)
+}
+
+type TenantRepository interface {
+	BaseRepository[model.Tenant]
 }
 
 type RoleRepository interface {
 	BaseRepository[model.Role]
 }
+
+type OutboxRepository interface {
+	// Claim returns up to limit due messages and hides them from other dispatchers for lockTimeout
+	Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]model.OutboxMessage, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+}
+
+type PriceAlertRepository interface {
+	BaseRepository[model.PriceAlert]
+	// Watching returns the enabled alerts of a car model year of the tenant with their user and car model year
+	Watching(ctx context.Context, tenantId int, carModelYearId int) ([]model.PriceAlert, error)
+	// UpdateBasePrice sets the BasePrice of an alert and its LastTriggeredAt when it is triggered
+	UpdateBasePrice(ctx context.Context, id int, basePrice float64, triggered bool) error
+}
+
+type NotificationRepository interface {
+	BaseRepository[model.Notification]
+	// Enqueue adds the notifications whose DeduplicationKey does not exist yet and returns them
+	Enqueue(ctx context.Context, notifications []model.Notification) ([]model.Notification, error)
+	MarkSent(ctx context.Context, id int) error
+	MarkFailed(ctx context.Context, id int, lastError string) error
+	// MarkRead marks a notification of the current tenant as read
+	MarkRead(ctx context.Context, id int) error
+}
+
+type WebhookSubscriptionRepository interface {
+	BaseRepository[model.WebhookSubscription]
+	// Subscribers returns the enabled subscriptions of the tenant
+	Subscribers(ctx context.Context, tenantId int) ([]model.WebhookSubscription, error)
+}
+
+type WebhookDeliveryRepository interface {
+	BaseRepository[model.WebhookDelivery]
+	// Enqueue adds the deliveries that do not exist yet
+	Enqueue(ctx context.Context, deliveries []model.WebhookDelivery) error
+	// Claim returns up to limit due deliveries of enabled subscriptions with their subscription
+	// and hides them from other deliverers for lockTimeout
+	Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]model.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int, responseStatus int) error
+	// MarkFailed schedules the next attempt at nextAttemptAt, or dead letters the delivery when dead is set
+	MarkFailed(ctx context.Context, id int, responseStatus int, lastError string, nextAttemptAt time.Time, dead bool) error
+	// Redeliver makes a delivery of the current tenant pending again with all its attempts
+	Redeliver(ctx context.Context, id int) (model.WebhookDelivery, error)
+}
+
+type WatchlistRepository interface {
+	BaseRepository[model.Watchlist]
+	// GetByShareToken returns a shared watchlist of any tenant
+	GetByShareToken(ctx context.Context, shareToken string) (model.Watchlist, error)
+	// Followers counts the users that follow each car model, by its own entries or by entries of its years
+	Followers(ctx context.Context, carModelIds []int) (map[int]int, error)
+}
+
+type WatchlistEntryRepository interface {
+	BaseRepository[model.WatchlistEntry]
+}
+
+type ListingRepository interface {
+	BaseRepository[model.Listing]
+	// Transition updates a listing of the current tenant in one of the from statuses, a listing
+	// whose status changed meanwhile is left as is and ListingStatusInvalid is returned
+	Transition(ctx context.Context, id int, from []string, values map[string]interface{}) error
+	// Expire marks up to limit published listings of any tenant whose ExpiresAt passed as expired,
+	// writes their events and returns how many were expired
+	Expire(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+type ListingImageRepository interface {
+	BaseRepository[model.ListingImage]
+}
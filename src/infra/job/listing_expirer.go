@@ -0,0 +1,77 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+)
+
+// ListingExpirer expires the published listings whose time is up. Every expired listing gets an
+// EntityUpdated event, so the outbox sinks and the webhooks see the change like any other update.
+type ListingExpirer struct {
+	cfg        *config.Config
+	logger     logging.Logger
+	repository repository.ListingRepository
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+func NewListingExpirer(cfg *config.Config, repository repository.ListingRepository) *ListingExpirer {
+	return &ListingExpirer{
+		cfg:        cfg,
+		logger:     logging.NewLogger(cfg),
+		repository: repository,
+	}
+}
+
+// Start expires listings every ExpireInterval until Stop is called
+func (e *ListingExpirer) Start() {
+	e.stop = make(chan struct{})
+	e.done.Add(1)
+	go func() {
+		defer e.done.Done()
+		ticker := time.NewTicker(e.cfg.Listing.ExpireInterval * time.Second)
+		defer ticker.Stop()
+		for {
+			// a full batch means more listings are due, so run again without waiting
+			for !stopped(e.stop) && e.ExpireOnce(context.Background()) == e.cfg.Listing.ExpireBatchSize {
+			}
+			select {
+			case <-e.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop waits for the running batch to finish
+func (e *ListingExpirer) Stop() {
+	close(e.stop)
+	e.done.Wait()
+}
+
+// ExpireOnce expires a batch of due listings and returns the number of expired listings
+func (e *ListingExpirer) ExpireOnce(ctx context.Context) int {
+	expired, err := e.repository.Expire(ctx, time.Now().UTC(), e.cfg.Listing.ExpireBatchSize)
+	if err != nil {
+		e.logger.Error(logging.General, logging.ListingExpiry, err.Error(), nil)
+		return 0
+	}
+	return expired
+}
+
+// stopped reports whether stop is closed without waiting for it
+func stopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+)
+
+var errNoRecipient = errors.New("the user has no recipient for the channel")
+
+// Channel sends a notification to its recipient
+type Channel interface {
+	Send(ctx context.Context, n model.Notification) error
+}
+
+// NewChannels returns the channels by name, sms and email log their notifications when
+// their provider is not configured
+func NewChannels(cfg *config.Config) map[string]Channel {
+	logger := logging.NewLogger(cfg)
+	channels := map[string]Channel{
+		model.NotificationInApp: InAppChannel{},
+		model.NotificationSms:   NewLogChannel(logger, model.NotificationSms),
+		model.NotificationEmail: NewLogChannel(logger, model.NotificationEmail),
+	}
+	if cfg.Notification.Sms.Url != "" {
+		channels[model.NotificationSms] = NewSmsChannel(cfg)
+	}
+	if cfg.Notification.Email.Host != "" {
+		channels[model.NotificationEmail] = NewEmailChannel(cfg)
+	}
+	return channels
+}
+
+// InAppChannel sends nothing, users read their in app notifications from the api
+type InAppChannel struct{}
+
+func (InAppChannel) Send(ctx context.Context, n model.Notification) error {
+	return nil
+}
+
+// LogChannel writes notifications to the log, it stands in for a provider in development
+type LogChannel struct {
+	logger  logging.Logger
+	channel string
+}
+
+func NewLogChannel(logger logging.Logger, channel string) *LogChannel {
+	return &LogChannel{logger: logger, channel: channel}
+}
+
+func (c *LogChannel) Send(ctx context.Context, n model.Notification) error {
+	if n.Recipient == "" {
+		return errNoRecipient
+	}
+	c.logger.Info(logging.General, logging.Notification, n.Title, map[logging.ExtraKey]interface{}{
+		logging.Channel: c.channel,
+		logging.Body:    n.Body,
+	})
+	return nil
+}
@@ -0,0 +1,49 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+)
+
+// EmailChannel sends the notifications as plain text mails through an smtp server
+type EmailChannel struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewEmailChannel(cfg *config.Config) *EmailChannel {
+	email := cfg.Notification.Email
+	var auth smtp.Auth
+	if email.Username != "" {
+		auth = smtp.PlainAuth("", email.Username, email.Password, email.Host)
+	}
+	return &EmailChannel{addr: net.JoinHostPort(email.Host, strconv.Itoa(email.Port)), auth: auth, from: email.From}
+}
+
+func (c *EmailChannel) Send(ctx context.Context, n model.Notification) error {
+	if n.Recipient == "" {
+		return errNoRecipient
+	}
+	message := strings.Join([]string{
+		"From: " + c.from,
+		"To: " + n.Recipient,
+		"Subject: " + mime.QEncoding.Encode("utf-8", n.Title),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		n.Body,
+	}, "\r\n")
+	if err := smtp.SendMail(c.addr, c.auth, c.from, []string{n.Recipient}, []byte(message)); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+	return nil
+}
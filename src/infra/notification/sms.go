@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+)
+
+// SmsChannel posts the notifications to an sms gateway, responses other than 2xx fail the notification
+type SmsChannel struct {
+	client  *http.Client
+	url     string
+	sender  string
+	headers map[string]string
+}
+
+func NewSmsChannel(cfg *config.Config) *SmsChannel {
+	return &SmsChannel{
+		client:  &http.Client{Timeout: cfg.Notification.Timeout * time.Second},
+		url:     cfg.Notification.Sms.Url,
+		sender:  cfg.Notification.Sms.Sender,
+		headers: cfg.Notification.Sms.Headers,
+	}
+}
+
+func (c *SmsChannel) Send(ctx context.Context, n model.Notification) error {
+	if n.Recipient == "" {
+		return errNoRecipient
+	}
+	body, err := json.Marshal(map[string]string{"from": c.sender, "to": n.Recipient, "text": n.Title + "\n" + n.Body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("sms gateway responded %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+)
+
+// Handler handles an event of the bus, an error makes the dispatcher retry the event
+type Handler func(ctx context.Context, e event.Event) error
+
+// Bus is the in process sink, features of this service subscribe to it to react to entity changes
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+var bus = &Bus{}
+
+func GetBus() *Bus {
+	return bus
+}
+
+func (b *Bus) Name() string {
+	return SinkBus
+}
+
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish calls all handlers, a failed handler does not stop the others
+func (b *Bus) Publish(ctx context.Context, e event.Event) error {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	var err error
+	for _, handler := range handlers {
+		if handlerErr := handler(ctx, e); handlerErr != nil && err == nil {
+			err = handlerErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+)
+
+// Dispatcher polls the outbox and publishes the claimed messages to all sinks. Delivery is at least once,
+// a message is retried on every sink when one of them fails.
+type Dispatcher struct {
+	cfg        *config.Config
+	logger     logging.Logger
+	repository repository.OutboxRepository
+	sinks      []Sink
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+func NewDispatcher(cfg *config.Config, repository repository.OutboxRepository, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		cfg:        cfg,
+		logger:     logging.NewLogger(cfg),
+		repository: repository,
+		sinks:      sinks,
+	}
+}
+
+// Start polls the outbox every PollInterval until Stop is called
+func (d *Dispatcher) Start() {
+	d.stop = make(chan struct{})
+	d.done.Add(1)
+	go func() {
+		defer d.done.Done()
+		ticker := time.NewTicker(d.cfg.Outbox.PollInterval * time.Second)
+		defer ticker.Stop()
+		for {
+			// a full batch means more messages are due, so poll again without waiting
+			for !stopped(d.stop) && d.DispatchOnce(context.Background()) == d.cfg.Outbox.BatchSize {
+			}
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop waits for the running batch to finish
+func (d *Dispatcher) Stop() {
+	close(d.stop)
+	d.done.Wait()
+}
+
+// stopped reports whether stop is closed without waiting for it
+func stopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// DispatchOnce publishes a batch of due messages and returns the number of claimed messages
+func (d *Dispatcher) DispatchOnce(ctx context.Context) int {
+	messages, err := d.repository.Claim(ctx, d.cfg.Outbox.BatchSize, d.cfg.Outbox.LockTimeout*time.Second)
+	if err != nil {
+		d.logger.Error(logging.General, logging.Outbox, err.Error(), nil)
+		return 0
+	}
+	for _, message := range messages {
+		d.dispatch(ctx, message)
+	}
+	return len(messages)
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, message model.OutboxMessage) {
+	e := event.FromMessage(message)
+	var publishErr error
+	for _, sink := range d.sinks {
+		if err := sink.Publish(ctx, e); err != nil {
+			d.logger.Error(logging.General, logging.Outbox, err.Error(),
+				map[logging.ExtraKey]interface{}{logging.Sink: sink.Name(), logging.EventId: e.Id})
+			metrics.OutboxPublish.WithLabelValues(sink.Name(), string(e.Type), "Failed").Inc()
+			if publishErr == nil {
+				publishErr = err
+			}
+			continue
+		}
+		metrics.OutboxPublish.WithLabelValues(sink.Name(), string(e.Type), "Success").Inc()
+	}
+
+	var err error
+	if publishErr == nil {
+		err = d.repository.MarkPublished(ctx, message.Id)
+	} else {
+		delay := common.RetryDelay(d.cfg.Outbox.RetryDelay*time.Second, d.cfg.Outbox.MaxRetryDelay*time.Second, message.Attempts)
+		err = d.repository.MarkFailed(ctx, message.Id, publishErr.Error(), time.Now().UTC().Add(delay))
+	}
+	if err != nil {
+		d.logger.Error(logging.General, logging.Outbox, err.Error(), nil)
+	}
+}
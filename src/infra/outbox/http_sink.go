@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+)
+
+// HttpSink posts every event as json to a configured url, responses other than 2xx are retried
+type HttpSink struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+func NewHttpSink(cfg *config.Config) *HttpSink {
+	return &HttpSink{
+		client:  &http.Client{Timeout: cfg.Outbox.Http.Timeout * time.Second},
+		url:     cfg.Outbox.Http.Url,
+		headers: cfg.Outbox.Http.Headers,
+	}
+}
+
+func (s *HttpSink) Name() string {
+	return SinkHttp
+}
+
+func (s *HttpSink) Publish(ctx context.Context, e event.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", e.Id)
+	req.Header.Set("X-Event-Type", string(e.Type))
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s responded %d", s.url, res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	"github.com/naeemaei/golang-clean-web-api/infra/cache"
+)
+
+// RedisStreamSink appends events to a redis stream, consumers read it with consumer groups
+type RedisStreamSink struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+func NewRedisStreamSink(cfg *config.Config) *RedisStreamSink {
+	return &RedisStreamSink{
+		client: cache.GetRedis(),
+		stream: cfg.Outbox.RedisStream.Name,
+		maxLen: cfg.Outbox.RedisStream.MaxLen,
+	}
+}
+
+func (s *RedisStreamSink) Name() string {
+	return SinkRedis
+}
+
+func (s *RedisStreamSink) Publish(ctx context.Context, e event.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.client.WithContext(ctx).XAdd(&redis.XAddArgs{
+		Stream:       s.stream,
+		MaxLenApprox: s.maxLen,
+		Values: map[string]interface{}{
+			"id":    e.Id,
+			"type":  string(e.Type),
+			"event": body,
+		},
+	}).Err()
+}
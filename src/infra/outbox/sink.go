@@ -0,0 +1,40 @@
+// Package outbox publishes the domain events of the outbox table to the configured sinks.
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+)
+
+const (
+	SinkBus   = "bus"
+	SinkRedis = "redis"
+	SinkHttp  = "http"
+)
+
+// Sink delivers events to consumers, Publish must be safe to call again with the same event
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, e event.Event) error
+}
+
+// NewSinks builds the sinks of cfg.Outbox.Sinks
+func NewSinks(cfg *config.Config) ([]Sink, error) {
+	sinks := []Sink{}
+	for _, name := range cfg.Outbox.Sinks {
+		switch name {
+		case SinkBus:
+			sinks = append(sinks, GetBus())
+		case SinkRedis:
+			sinks = append(sinks, NewRedisStreamSink(cfg))
+		case SinkHttp:
+			sinks = append(sinks, NewHttpSink(cfg))
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	return sinks, nil
+}
@@ -0,0 +1,140 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+	"github.com/naeemaei/golang-clean-web-api/pkg/webhook"
+)
+
+const webhookUserAgent = "car-sale-webhooks/1.0"
+
+// the part of a failed response body kept in LastError
+const webhookErrorBodySize = 200
+
+// WebhookDeliverer posts the pending webhook deliveries to their subscriptions, failed deliveries
+// are retried with exponential backoff and dead lettered after MaxAttempts
+type WebhookDeliverer struct {
+	cfg        *config.Config
+	logger     logging.Logger
+	repository repository.WebhookDeliveryRepository
+	client     *http.Client
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+func NewWebhookDeliverer(cfg *config.Config, repository repository.WebhookDeliveryRepository) *WebhookDeliverer {
+	// targets were checked when the subscription was saved, the client checks them again on connect
+	client := webhook.NewClient(cfg.Webhook.Timeout * time.Second)
+	if cfg.Webhook.AllowPrivateTargets {
+		client = &http.Client{Timeout: cfg.Webhook.Timeout * time.Second}
+	}
+	return &WebhookDeliverer{
+		cfg:        cfg,
+		logger:     logging.NewLogger(cfg),
+		repository: repository,
+		client:     client,
+	}
+}
+
+// Start polls the deliveries every PollInterval until Stop is called
+func (d *WebhookDeliverer) Start() {
+	d.stop = make(chan struct{})
+	d.done.Add(1)
+	go func() {
+		defer d.done.Done()
+		ticker := time.NewTicker(d.cfg.Webhook.PollInterval * time.Second)
+		defer ticker.Stop()
+		for {
+			for !stopped(d.stop) && d.DeliverOnce(context.Background()) == d.cfg.Webhook.BatchSize {
+			}
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop waits for the running batch to finish
+func (d *WebhookDeliverer) Stop() {
+	close(d.stop)
+	d.done.Wait()
+}
+
+// DeliverOnce sends a batch of due deliveries and returns the number of claimed deliveries
+func (d *WebhookDeliverer) DeliverOnce(ctx context.Context) int {
+	deliveries, err := d.repository.Claim(ctx, d.cfg.Webhook.BatchSize, d.cfg.Webhook.LockTimeout*time.Second)
+	if err != nil {
+		d.logger.Error(logging.General, logging.Webhook, err.Error(), nil)
+		return 0
+	}
+	for _, delivery := range deliveries {
+		d.deliver(ctx, delivery)
+	}
+	return len(deliveries)
+}
+
+func (d *WebhookDeliverer) deliver(ctx context.Context, delivery model.WebhookDelivery) {
+	status, sendErr := d.send(ctx, delivery)
+
+	var err error
+	if sendErr == nil {
+		metrics.WebhookDelivery.WithLabelValues(delivery.EventType, "Success").Inc()
+		err = d.repository.MarkDelivered(ctx, delivery.Id, status)
+	} else {
+		dead := delivery.Attempts+1 >= d.cfg.Webhook.MaxAttempts
+		result := "Failed"
+		if dead {
+			result = "Dead"
+		}
+		metrics.WebhookDelivery.WithLabelValues(delivery.EventType, result).Inc()
+		delay := common.RetryDelay(d.cfg.Webhook.RetryDelay*time.Second, d.cfg.Webhook.MaxRetryDelay*time.Second, delivery.Attempts)
+		err = d.repository.MarkFailed(ctx, delivery.Id, status, sendErr.Error(), time.Now().UTC().Add(delay), dead)
+	}
+	if err != nil {
+		d.logger.Error(logging.General, logging.Webhook, err.Error(), nil)
+	}
+}
+
+// send posts the delivery and returns the response status, 0 when there was no response
+func (d *WebhookDeliverer) send(ctx context.Context, delivery model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(webhook.DeliveryHeader, strconv.Itoa(delivery.Id))
+	req.Header.Set(webhook.AttemptHeader, strconv.Itoa(delivery.Attempts+1))
+	req.Header.Set(webhook.EventIdHeader, delivery.EventId)
+	req.Header.Set(webhook.EventTypeHeader, delivery.EventType)
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(delivery.Subscription.Secret, time.Now(), body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(res.Body, webhookErrorBodySize))
+		return res.StatusCode, fmt.Errorf("%s responded %d: %s", delivery.Subscription.Url, res.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return res.StatusCode, nil
+}
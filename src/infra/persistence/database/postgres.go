@@ -34,7 +34,7 @@ func InitDb(cfg *config.Config) error {
 	sqlDb.SetConnMaxLifetime(cfg.Postgres.ConnMaxLifetime * time.Minute)
 
 	log.Println("Db connection established")
-	return nil
+	return initReplicas(cfg)
 }
 
 func GetDb() *gorm.DB {
@@ -42,6 +42,7 @@ func GetDb() *gorm.DB {
 }
 
 func CloseDb() {
+	closeReplicas()
 	con, _ := dbClient.DB()
 	con.Close()
 }
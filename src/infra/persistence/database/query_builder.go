@@ -31,9 +31,23 @@ func GenerateDynamicQuery[T any](filter *filter.DynamicFilter) string {
 	return strings.Join(query, " AND ")
 }
 
+// GenerateDynamicFilter normalizes the filter values like the stored text, see common.NormalizePersian,
+// so text columns are compared as they are and their indexes are used
 func GenerateDynamicFilter(fld reflect.StructField, filter filter.Filter) string {
 	conditionQuery := ""
 	fld.Name = common.ToSnakeCase(fld.Name)
+	filter.From, filter.To = common.NormalizePersian(filter.From), common.NormalizePersian(filter.To)
+	if filter.IsJalali() {
+		converted, err := filter.Gregorian()
+		if err != nil {
+			// an invalid date matches nothing
+			return "false"
+		}
+		filter = converted
+		if filter.Type != "equals" && filter.Type != "notEqual" {
+			filter.From, filter.To = fmt.Sprintf("'%s'", filter.From), fmt.Sprintf("'%s'", filter.To)
+		}
+	}
 	switch filter.Type {
 	case "contains":
 		conditionQuery = fmt.Sprintf("%s ILike '%%%s%%'", fld.Name, filter.From)
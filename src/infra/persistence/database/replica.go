@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/infra/cache"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type replica struct {
+	dsn     string
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// PrimaryPin is stored in the request context and routes reads to the primary
+// after the request has written, so the caller always reads its own writes.
+// The next requests of an authenticated user are pinned for PrimaryPinWindow by
+// a redis key, see MarkWrite
+type PrimaryPin struct {
+	pinned atomic.Bool
+}
+
+var (
+	replicas       []*replica
+	replicaCounter atomic.Uint64
+	readYourWrites bool
+	pinWindow      time.Duration
+	stopMonitor    chan struct{}
+	monitorOnce    sync.Once
+)
+
+func NewPrimaryPin() *PrimaryPin {
+	return &PrimaryPin{}
+}
+
+func initReplicas(cfg *config.Config) error {
+	readYourWrites = cfg.Postgres.ReadYourWrites
+	pinWindow = cfg.Postgres.PrimaryPinWindow * time.Second
+	replicas = make([]*replica, 0, len(cfg.Postgres.Replicas))
+	for _, dsn := range cfg.Postgres.Replicas {
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		if err != nil {
+			return err
+		}
+		sqlDb, _ := db.DB()
+		sqlDb.SetMaxIdleConns(cfg.Postgres.MaxIdleConns)
+		sqlDb.SetMaxOpenConns(cfg.Postgres.MaxOpenConns)
+		sqlDb.SetConnMaxLifetime(cfg.Postgres.ConnMaxLifetime * time.Minute)
+
+		r := &replica{dsn: dsn, db: db}
+		r.healthy.Store(sqlDb.Ping() == nil)
+		replicas = append(replicas, r)
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	interval := cfg.Postgres.ReplicaHealthCheckInterval * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	stopMonitor = make(chan struct{})
+	go monitorReplicas(interval)
+	log.Printf("%d db replica(s) registered", len(replicas))
+	return nil
+}
+
+// monitorReplicas ejects replicas that fail to answer a ping and re-admits them when they recover
+func monitorReplicas(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopMonitor:
+			return
+		case <-ticker.C:
+			for _, r := range replicas {
+				sqlDb, _ := r.db.DB()
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				healthy := sqlDb.PingContext(ctx) == nil
+				cancel()
+				if r.healthy.Swap(healthy) != healthy {
+					log.Printf("db replica healthy=%t", healthy)
+				}
+			}
+		}
+	}
+}
+
+// GetReadDb returns a healthy replica in round robin order, or the primary when
+// no replica is available or the request is pinned to the primary
+func GetReadDb(ctx context.Context) *gorm.DB {
+	if len(replicas) == 0 || isPinned(ctx) {
+		return dbClient.WithContext(ctx)
+	}
+	start := replicaCounter.Add(1)
+	for i := 0; i < len(replicas); i++ {
+		r := replicas[(start+uint64(i))%uint64(len(replicas))]
+		if r.healthy.Load() {
+			return r.db.WithContext(ctx)
+		}
+	}
+	return dbClient.WithContext(ctx)
+}
+
+// MarkWrite pins the rest of the request to the primary when read-your-writes is enabled, and the
+// requests of its user for pinWindow, which covers e.g. a redirect to the created entity
+func MarkWrite(ctx context.Context) {
+	if !readYourWrites {
+		return
+	}
+	if pin, ok := ctx.Value(constant.PrimaryPinKey).(*PrimaryPin); ok {
+		pin.pinned.Store(true)
+	}
+	userId := pinUserId(ctx)
+	if pinWindow <= 0 || userId == 0 || len(replicas) == 0 {
+		return
+	}
+	if err := cache.GetRedis().Set(pinKey(userId), 1, pinWindow).Err(); err != nil {
+		log.Printf("db primary pin of user %d failed: %v", userId, err)
+	}
+}
+
+// isPinned reports whether ctx reads from the primary, a user whose pin can not be read is
+// pinned so a failing redis costs replica load rather than stale reads
+func isPinned(ctx context.Context) bool {
+	if pin, ok := ctx.Value(constant.PrimaryPinKey).(*PrimaryPin); ok && pin.pinned.Load() {
+		return true
+	}
+	userId := pinUserId(ctx)
+	if !readYourWrites || pinWindow <= 0 || userId == 0 {
+		return false
+	}
+	pinned, err := cache.GetRedis().Exists(pinKey(userId)).Result()
+	return err != nil || pinned > 0
+}
+
+func pinUserId(ctx context.Context) int {
+	userId, _ := ctx.Value(constant.UserIdKey).(float64)
+	return int(userId)
+}
+
+func pinKey(userId int) string {
+	return fmt.Sprintf("primary-pin:%d", userId)
+}
+
+func closeReplicas() {
+	monitorOnce.Do(func() {
+		if stopMonitor != nil {
+			close(stopMonitor)
+		}
+	})
+	for _, r := range replicas {
+		con, _ := r.db.DB()
+		con.Close()
+	}
+}
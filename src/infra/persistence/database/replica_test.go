@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/infra/cache"
+)
+
+func userContext(userId int) context.Context {
+	return context.WithValue(context.Background(), constant.UserIdKey, float64(userId))
+}
+
+func TestMarkWritePinsTheUserForTheWindow(t *testing.T) {
+	redis, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer redis.Close()
+	cfg := &config.Config{Redis: config.RedisConfig{Host: redis.Host(), Port: redis.Port()}}
+	if err = cache.InitRedis(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer cache.CloseRedis()
+	readYourWrites, pinWindow, replicas = true, 5*time.Second, []*replica{{}}
+	defer func() { readYourWrites, pinWindow, replicas = false, 0, nil }()
+
+	request := NewPrimaryPin()
+	MarkWrite(context.WithValue(userContext(7), constant.PrimaryPinKey, request))
+	if !request.pinned.Load() {
+		t.Error("writing request is not pinned")
+	}
+	if !isPinned(userContext(7)) {
+		t.Error("next request of the writer is not pinned")
+	}
+	if isPinned(userContext(8)) {
+		t.Error("request of another user is pinned")
+	}
+	// anonymous writes pin their own request only
+	MarkWrite(context.Background())
+	if isPinned(context.Background()) {
+		t.Error("anonymous request is pinned")
+	}
+
+	redis.FastForward(pinWindow)
+	if isPinned(userContext(7)) {
+		t.Error("writer is pinned after the window")
+	}
+
+	redis.SetError("down")
+	if !isPinned(userContext(7)) {
+		t.Error("writer is not pinned while redis fails")
+	}
+}
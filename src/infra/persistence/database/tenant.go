@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+var tenantScopedType = reflect.TypeOf((*model.TenantScoped)(nil)).Elem()
+
+// TenantReference is a tenant scoped entity that another entity points to by foreign key
+type TenantReference struct {
+	Type reflect.Type
+	Id   int
+}
+
+// TenantId returns the tenant of the current request from the token claims or the catalog slug,
+// it is 0 when the context has no tenant, which matches no rows
+func TenantId(ctx context.Context) int {
+	value, ok := ctx.Value(constant.TenantIdKey).(float64)
+	if !ok || value <= 0 {
+		return 0
+	}
+	return int(value)
+}
+
+// RequireTenant denies writes of a context without tenant instead of falling back to the platform tenant
+func RequireTenant(ctx context.Context) error {
+	if TenantId(ctx) == 0 {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
+	}
+	return nil
+}
+
+// WithTenant returns ctx for tenantId, background jobs use it to write the events of a tenant
+func WithTenant(ctx context.Context, tenantId int) context.Context {
+	return context.WithValue(ctx, constant.TenantIdKey, float64(tenantId))
+}
+
+// IsTenantScoped reports whether TEntity embeds model.TenantModel
+func IsTenantScoped[TEntity any]() bool {
+	return isTenantScopedType(reflect.TypeOf(*new(TEntity)))
+}
+
+func isTenantScopedType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(tenantScopedType)
+}
+
+// TenantReferences returns the tenant scoped entities that entity points to,
+// e.g. CarModelColor.CarModelId
+func TenantReferences(entity any) []TenantReference {
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	values := map[string]interface{}{}
+	for i := 0; i < v.NumField(); i++ {
+		values[v.Type().Field(i).Name] = v.Field(i).Interface()
+	}
+	return TenantReferencesOf(v.Type(), values)
+}
+
+// TenantReferencesOf does the same as TenantReferences for an update map keyed by field name
+func TenantReferencesOf(t reflect.Type, values map[string]interface{}) []TenantReference {
+	result := []TenantReference{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous || sf.Type.Kind() != reflect.Struct || !isTenantScopedType(sf.Type) {
+			continue
+		}
+		value, ok := values[ForeignKey(sf, sf.Name+"Id")]
+		if !ok {
+			continue
+		}
+		id := toInt(value)
+		if id > 0 {
+			result = append(result, TenantReference{Type: sf.Type, Id: id})
+		}
+	}
+	return result
+}
+
+// ForeignKey reads foreignKey from the gorm tag or falls back to the convention name
+func ForeignKey(sf reflect.StructField, fallback string) string {
+	for _, part := range strings.Split(sf.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(part, "foreignKey:") {
+			return strings.TrimPrefix(part, "foreignKey:")
+		}
+	}
+	return fallback
+}
+
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+type CarModelCommentRepository struct {
+	*BaseRepository[model.CarModelComment]
+}
+
+func NewCarModelCommentRepository(store *Store, preloads []database.PreloadEntity) *CarModelCommentRepository {
+	return &CarModelCommentRepository{BaseRepository: NewBaseRepository[model.CarModelComment](store, preloads)}
+}
+
+func (r *CarModelCommentRepository) AddReport(ctx context.Context, report model.CarModelCommentReport, threshold int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.get(ctx, report.CarModelCommentId)
+	if !ok {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	comment := row.Interface().(model.CarModelComment)
+	if comment.Status != model.CommentApproved {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	for _, reportRow := range r.store.list(typeOf[model.CarModelCommentReport]()) {
+		existing := reportRow.Interface().(model.CarModelCommentReport)
+		if existing.CarModelCommentId == report.CarModelCommentId && existing.UserId == report.UserId {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.CommentAlreadyReported}
+		}
+	}
+	report.Id = 0
+	report.TenantId = comment.TenantId
+	r.store.insert(reflect.ValueOf(&report).Elem(), userIdFromContext(ctx))
+
+	comment.ReportCount++
+	if comment.ReportCount >= threshold {
+		comment.Status = model.CommentPending
+	}
+	r.store.insert(reflect.ValueOf(&comment).Elem(), nil)
+	return nil
+}
+
+func (r *CarModelCommentRepository) Vote(ctx context.Context, vote model.CarModelCommentVote) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, ok := r.comment(ctx, vote.CarModelCommentId)
+	if !ok || comment.Status != model.CommentApproved {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	if existing, ok := r.vote(vote.CarModelCommentId, vote.UserId); ok {
+		existing.Helpful = vote.Helpful
+		existing.ModifiedBy = &sql.NullInt64{Int64: int64(vote.UserId), Valid: true}
+		existing.ModifiedAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+		r.store.insert(reflect.ValueOf(&existing).Elem(), nil)
+	} else {
+		vote.Id = 0
+		vote.TenantId = comment.TenantId
+		r.store.insert(reflect.ValueOf(&vote).Elem(), userIdFromContext(ctx))
+	}
+	r.countVotes(comment)
+	return nil
+}
+
+func (r *CarModelCommentRepository) RemoveVote(ctx context.Context, commentId int, userId int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	comment, ok := r.comment(ctx, commentId)
+	if !ok {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	vote, ok := r.vote(commentId, userId)
+	if !ok {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	vote.DeletedBy = &sql.NullInt64{Int64: int64(*userIdFromContext(ctx)), Valid: true}
+	vote.DeletedAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	r.store.insert(reflect.ValueOf(&vote).Elem(), nil)
+	r.countVotes(comment)
+	return nil
+}
+
+func (r *CarModelCommentRepository) comment(ctx context.Context, id int) (model.CarModelComment, bool) {
+	row, ok := r.get(ctx, id)
+	if !ok {
+		return model.CarModelComment{}, false
+	}
+	return row.Interface().(model.CarModelComment), true
+}
+
+func (r *CarModelCommentRepository) vote(commentId int, userId int) (model.CarModelCommentVote, bool) {
+	for _, row := range r.store.list(typeOf[model.CarModelCommentVote]()) {
+		vote := row.Interface().(model.CarModelCommentVote)
+		if vote.CarModelCommentId == commentId && vote.UserId == userId {
+			return vote, true
+		}
+	}
+	return model.CarModelCommentVote{}, false
+}
+
+func (r *CarModelCommentRepository) countVotes(comment model.CarModelComment) {
+	comment.HelpfulCount, comment.UnhelpfulCount = 0, 0
+	for _, row := range r.store.list(typeOf[model.CarModelCommentVote]()) {
+		vote := row.Interface().(model.CarModelCommentVote)
+		if vote.CarModelCommentId != comment.Id {
+			continue
+		}
+		if vote.Helpful {
+			comment.HelpfulCount++
+		} else {
+			comment.UnhelpfulCount++
+		}
+	}
+	r.store.insert(reflect.ValueOf(&comment).Elem(), nil)
+}
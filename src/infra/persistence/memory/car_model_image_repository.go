@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+type CarModelImageRepository struct {
+	*BaseRepository[model.CarModelImage]
+}
+
+func NewCarModelImageRepository(store *Store, preloads []database.PreloadEntity) *CarModelImageRepository {
+	return &CarModelImageRepository{BaseRepository: NewBaseRepository[model.CarModelImage](store, preloads)}
+}
+
+func (r *CarModelImageRepository) Create(ctx context.Context, image model.CarModelImage) (model.CarModelImage, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	image.TenantId = database.TenantId(ctx)
+	if err := r.checkReferences(ctx, database.TenantReferences(image)); err != nil {
+		return image, err
+	}
+	images := r.gallery(image.CarModelId)
+	image.SortOrder = 1
+	if len(images) == 0 {
+		image.IsMainImage = true
+	} else {
+		image.SortOrder = images[len(images)-1].SortOrder + 1
+	}
+	if image.IsMainImage {
+		if err := r.demote(ctx, images); err != nil {
+			return image, err
+		}
+	}
+	image.Id = 0
+	userId := -1
+	if id := userIdFromContext(ctx); id != nil {
+		userId = *id
+	}
+	r.store.insert(reflect.ValueOf(&image).Elem(), &userId)
+	if err := writeEvents(ctx, r.store, event.EntityCreated, image, nil); err != nil {
+		return image, err
+	}
+	return image, nil
+}
+
+func (r *CarModelImageRepository) Delete(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	userId := userIdFromContext(ctx)
+	if userId == nil {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
+	}
+	row, ok := r.get(ctx, id)
+	if !ok {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	image := row.Interface().(model.CarModelImage)
+	image.DeletedBy = &sql.NullInt64{Int64: int64(*userId), Valid: true}
+	image.DeletedAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	r.store.insert(reflect.ValueOf(&image).Elem(), nil)
+	if err := writeEvents(ctx, r.store, event.EntityDeleted, image, nil); err != nil {
+		return err
+	}
+	if images := r.gallery(image.CarModelId); image.IsMainImage && len(images) > 0 {
+		_, err := r.updateLocked(ctx, images[0].Id, map[string]interface{}{"IsMainImage": true})
+		return err
+	}
+	return nil
+}
+
+func (r *CarModelImageRepository) SetMain(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.get(ctx, id)
+	if !ok {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	image := row.Interface().(model.CarModelImage)
+	if image.IsMainImage {
+		return nil
+	}
+	if err := r.demote(ctx, r.gallery(image.CarModelId)); err != nil {
+		return err
+	}
+	_, err := r.updateLocked(ctx, id, map[string]interface{}{"IsMainImage": true})
+	return err
+}
+
+func (r *CarModelImageRepository) Reorder(ctx context.Context, carModelId int, ids []int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkReferences(ctx, []database.TenantReference{{Type: typeOf[model.CarModel](), Id: carModelId}}); err != nil {
+		return err
+	}
+	images := r.gallery(carModelId)
+	if len(images) != len(ids) || slices.ContainsFunc(images, func(image model.CarModelImage) bool { return !slices.Contains(ids, image.Id) }) {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.CarModelImageOrderInvalid}
+	}
+	for i, id := range ids {
+		image := images[slices.IndexFunc(images, func(image model.CarModelImage) bool { return image.Id == id })]
+		if image.SortOrder == i+1 {
+			continue
+		}
+		if _, err := r.updateLocked(ctx, id, map[string]interface{}{"SortOrder": i + 1}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gallery returns the images of a car model ordered by SortOrder, the store lock must be held
+func (r *CarModelImageRepository) gallery(carModelId int) []model.CarModelImage {
+	images := []model.CarModelImage{}
+	for _, row := range r.store.list(typeOf[model.CarModelImage]()) {
+		if image := row.Interface().(model.CarModelImage); image.CarModelId == carModelId {
+			images = append(images, image)
+		}
+	}
+	slices.SortFunc(images, func(a, b model.CarModelImage) int {
+		return cmp.Or(cmp.Compare(a.SortOrder, b.SortOrder), cmp.Compare(a.Id, b.Id))
+	})
+	return images
+}
+
+func (r *CarModelImageRepository) demote(ctx context.Context, images []model.CarModelImage) error {
+	for _, image := range images {
+		if !image.IsMainImage {
+			continue
+		}
+		if _, err := r.updateLocked(ctx, image.Id, map[string]interface{}{"IsMainImage": false}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+type CarModelPriceHistoryRepository struct {
+	*BaseRepository[model.CarModelPriceHistory]
+}
+
+func NewCarModelPriceHistoryRepository(store *Store, preloads []database.PreloadEntity) *CarModelPriceHistoryRepository {
+	return &CarModelPriceHistoryRepository{BaseRepository: NewBaseRepository[model.CarModelPriceHistory](store, preloads)}
+}
+
+func (r *CarModelPriceHistoryRepository) GetByCarModelYear(ctx context.Context, carModelYearId int) ([]model.CarModelPriceHistory, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.owned(ctx, typeOf[model.CarModelYear](), carModelYearId) {
+		return nil, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return r.prices(ctx, map[int]bool{carModelYearId: true}), nil
+}
+
+func (r *CarModelPriceHistoryRepository) GetByCarModel(ctx context.Context, carModelId int) ([]model.CarModelPriceHistory, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.owned(ctx, typeOf[model.CarModel](), carModelId) {
+		return nil, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	years := map[int]bool{}
+	for _, row := range r.store.list(typeOf[model.CarModelYear]()) {
+		if year := row.Interface().(model.CarModelYear); year.CarModelId == carModelId {
+			years[year.Id] = true
+		}
+	}
+	return r.prices(ctx, years), nil
+}
+
+// owned reports whether the row with id exists in the tenant, the store lock must be held
+func (r *CarModelPriceHistoryRepository) owned(ctx context.Context, t reflect.Type, id int) bool {
+	row, ok := r.store.get(t, id)
+	return ok && int(row.FieldByName("TenantId").Int()) == database.TenantId(ctx)
+}
+
+func (r *CarModelPriceHistoryRepository) prices(ctx context.Context, years map[int]bool) []model.CarModelPriceHistory {
+	prices := []model.CarModelPriceHistory{}
+	for _, row := range r.store.list(typeOf[model.CarModelPriceHistory]()) {
+		if price := row.Interface().(model.CarModelPriceHistory); years[price.CarModelYearId] && r.inTenant(ctx, row) {
+			prices = append(prices, price)
+		}
+	}
+	sort.SliceStable(prices, func(i, j int) bool { return prices[i].PriceAt.Before(prices[j].PriceAt) })
+	return prices
+}
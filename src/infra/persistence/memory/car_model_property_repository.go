@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"context"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+type CarModelPropertyRepository struct {
+	*BaseRepository[model.CarModelProperty]
+}
+
+func NewCarModelPropertyRepository(store *Store, preloads []database.PreloadEntity) *CarModelPropertyRepository {
+	return &CarModelPropertyRepository{BaseRepository: NewBaseRepository[model.CarModelProperty](store, preloads)}
+}
+
+// CountByProperty is not scoped to the tenant, the properties are shared by the tenants
+func (r *CarModelPropertyRepository) CountByProperty(ctx context.Context, propertyId int) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := int64(0)
+	for _, row := range r.store.list(typeOf[model.CarModelProperty]()) {
+		if row.Interface().(model.CarModelProperty).PropertyId == propertyId {
+			count++
+		}
+	}
+	return count, nil
+}
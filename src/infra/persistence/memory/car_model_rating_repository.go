@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"reflect"
+	"time"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+type CarModelRatingRepository struct {
+	*BaseRepository[model.CarModelRating]
+}
+
+func NewCarModelRatingRepository(store *Store, preloads []database.PreloadEntity) *CarModelRatingRepository {
+	return &CarModelRatingRepository{BaseRepository: NewBaseRepository[model.CarModelRating](store, preloads)}
+}
+
+func (r *CarModelRatingRepository) Rate(ctx context.Context, rating model.CarModelRating) (model.CarModelRating, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkReferences(ctx, []database.TenantReference{{Type: typeOf[model.CarModel](), Id: rating.CarModelId}}); err != nil {
+		return rating, err
+	}
+	if existing, ok := r.rating(rating.CarModelId, rating.UserId); ok {
+		existing.Stars = rating.Stars
+		existing.ModifiedBy = &sql.NullInt64{Int64: int64(rating.UserId), Valid: true}
+		existing.ModifiedAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+		rating = existing
+		r.store.insert(reflect.ValueOf(&rating).Elem(), nil)
+	} else {
+		rating.Id = 0
+		rating.TenantId = database.TenantId(ctx)
+		r.store.insert(reflect.ValueOf(&rating).Elem(), userIdFromContext(ctx))
+	}
+	r.summarize(rating.CarModelId)
+	return rating, nil
+}
+
+func (r *CarModelRatingRepository) RemoveRating(ctx context.Context, carModelId int, userId int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkReferences(ctx, []database.TenantReference{{Type: typeOf[model.CarModel](), Id: carModelId}}); err != nil {
+		return err
+	}
+	rating, ok := r.rating(carModelId, userId)
+	if !ok {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	rating.DeletedBy = &sql.NullInt64{Int64: int64(*userIdFromContext(ctx)), Valid: true}
+	rating.DeletedAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	r.store.insert(reflect.ValueOf(&rating).Elem(), nil)
+	r.summarize(carModelId)
+	return nil
+}
+
+func (r *CarModelRatingRepository) rating(carModelId int, userId int) (model.CarModelRating, bool) {
+	for _, row := range r.store.list(typeOf[model.CarModelRating]()) {
+		rating := row.Interface().(model.CarModelRating)
+		if rating.CarModelId == carModelId && rating.UserId == userId {
+			return rating, true
+		}
+	}
+	return model.CarModelRating{}, false
+}
+
+// summarize updates the rating summary of a car model like ratingSummaryExp
+func (r *CarModelRatingRepository) summarize(carModelId int) {
+	row, ok := r.store.get(typeOf[model.CarModel](), carModelId)
+	if !ok {
+		return
+	}
+	carModel := row.Interface().(model.CarModel)
+	counts := [model.RatingMaxStars + 1]int{}
+	total := 0
+	for _, row := range r.store.list(typeOf[model.CarModelRating]()) {
+		rating := row.Interface().(model.CarModelRating)
+		if rating.CarModelId == carModelId {
+			counts[rating.Stars]++
+			total += rating.Stars
+		}
+	}
+	carModel.OneStarCount, carModel.TwoStarCount, carModel.ThreeStarCount = counts[1], counts[2], counts[3]
+	carModel.FourStarCount, carModel.FiveStarCount = counts[4], counts[5]
+	carModel.RatingCount = counts[1] + counts[2] + counts[3] + counts[4] + counts[5]
+	carModel.RatingAverage = 0
+	if carModel.RatingCount > 0 {
+		carModel.RatingAverage = math.Round(float64(total)/float64(carModel.RatingCount)*100) / 100
+	}
+	r.store.insert(reflect.ValueOf(&carModel).Elem(), nil)
+}
@@ -0,0 +1,322 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"html"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	filter "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+type CarModelRepository struct {
+	*BaseRepository[model.CarModel]
+}
+
+func NewCarModelRepository(store *Store, preloads []database.PreloadEntity) *CarModelRepository {
+	return &CarModelRepository{BaseRepository: NewBaseRepository[model.CarModel](store, preloads)}
+}
+
+// Search is a naive version of the postgres full text search, every query word must prefix a word
+// of the document, company and model name matches rank higher than the other fields
+func (r *CarModelRepository) Search(ctx context.Context, query string, req filter.PaginationInputWithFilter) (int64, *[]model.CarModelSearchResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	items := []model.CarModelSearchResult{}
+	terms := strings.Fields(strings.ToLower(common.NormalizePersian(query)))
+	if len(terms) == 0 {
+		return 0, &items, nil
+	}
+	preloads := []database.PreloadEntity{{Entity: "Company"}, {Entity: "CarType"}, {Entity: "Gearbox"}, {Entity: "CarModelProperties"}}
+	for _, row := range r.store.list(typeOf[model.CarModel]()) {
+		if !r.inTenant(ctx, row) {
+			continue
+		}
+		r.store.preload(row, preloads)
+		cm := row.Interface().(model.CarModel)
+		values := []string{}
+		for _, p := range cm.CarModelProperties {
+			values = append(values, p.Value)
+		}
+		primary := strings.Fields(strings.ToLower(cm.Company.Name + " " + cm.Name))
+		document := strings.Join([]string{cm.Company.Name, cm.Name, cm.CarType.Name, cm.Gearbox.Name, strings.Join(values, " ")}, " ")
+		words := strings.Fields(strings.ToLower(document))
+
+		rank, matched := 0.0, true
+		for _, term := range terms {
+			switch {
+			case hasPrefixWord(primary, term):
+				rank += 1
+			case hasPrefixWord(words, term):
+				rank += 0.2
+			default:
+				matched = false
+			}
+		}
+		if !matched {
+			continue
+		}
+		items = append(items, model.CarModelSearchResult{
+			Id:          cm.Id,
+			Name:        cm.Name,
+			CompanyName: cm.Company.Name,
+			CarTypeName: cm.CarType.Name,
+			GearboxName: cm.Gearbox.Name,
+			Rank:        rank,
+			Highlight:   html.EscapeString(strings.ToLower(strings.Join(words, " "))),
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Rank > items[j].Rank })
+
+	total := int64(len(items))
+	from := min(req.GetOffset(), len(items))
+	to := min(from+req.GetPageSize(), len(items))
+	items = items[from:to]
+	return total, &items, nil
+}
+
+// carModelFacetRow is a car model with the values of its facet fields and its latest prices in DefaultCurrency
+type carModelFacetRow struct {
+	item     model.CarModelFacetItem
+	values   map[string][]model.FacetCount
+	prices   []float64
+	carModel reflect.Value
+}
+
+// facetRows returns the car models of the tenant matching query ordered by id
+func (r *CarModelRepository) facetRows(ctx context.Context, query model.CarModelFacetQuery) []carModelFacetRow {
+	preloads := []database.PreloadEntity{
+		{Entity: "Company.Country"}, {Entity: "CarType"}, {Entity: "Gearbox"},
+		{Entity: "CarModelColors.Color"}, {Entity: "CarModelYears.PersianYear"}, {Entity: "CarModelYears.CarModelPriceHistories"},
+	}
+	rows := []carModelFacetRow{}
+	for _, row := range r.store.list(typeOf[model.CarModel]()) {
+		if !r.inTenant(ctx, row) {
+			continue
+		}
+		r.store.preload(row, preloads)
+		cm := row.Interface().(model.CarModel)
+		facetRow := carModelFacetRow{
+			item: model.CarModelFacetItem{
+				Id:          cm.Id,
+				Name:        cm.Name,
+				CompanyName: cm.Company.Name,
+				CountryName: cm.Company.Country.Name,
+				CarTypeName: cm.CarType.Name,
+				GearboxName: cm.Gearbox.Name,
+			},
+			values: map[string][]model.FacetCount{
+				model.FacetCompanyId: {{Id: cm.Company.Id, Name: cm.Company.Name}},
+				model.FacetCountryId: {{Id: cm.Company.Country.Id, Name: cm.Company.Country.Name}},
+				model.FacetCarTypeId: {{Id: cm.CarType.Id, Name: cm.CarType.Name}},
+				model.FacetGearboxId: {{Id: cm.Gearbox.Id, Name: cm.Gearbox.Name}},
+			},
+			prices:   []float64{},
+			carModel: row,
+		}
+		for _, color := range cm.CarModelColors {
+			facetRow.values[model.FacetColorId] = append(facetRow.values[model.FacetColorId], model.FacetCount{Id: color.Color.Id, Name: color.Color.Name})
+		}
+		for _, year := range cm.CarModelYears {
+			facetRow.values[model.FacetPersianYearId] = append(facetRow.values[model.FacetPersianYearId],
+				model.FacetCount{Id: year.PersianYear.Id, Name: year.PersianYear.PersianTitle})
+			var latest *model.CarModelPriceHistory
+			for i, price := range year.CarModelPriceHistories {
+				if latest == nil || price.PriceAt.After(latest.PriceAt) || price.PriceAt.Equal(latest.PriceAt) && price.Id > latest.Id {
+					latest = &year.CarModelPriceHistories[i]
+				}
+			}
+			if latest == nil {
+				continue
+			}
+			if price, ok := r.facetPrice(*latest); ok {
+				facetRow.prices = append(facetRow.prices, price)
+			}
+		}
+		if len(facetRow.prices) > 0 {
+			minPrice, maxPrice := slices.Min(facetRow.prices), slices.Max(facetRow.prices)
+			facetRow.item.MinPrice, facetRow.item.MaxPrice = &minPrice, &maxPrice
+		}
+		if facetRow.matches(query) {
+			rows = append(rows, facetRow)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].item.Id < rows[j].item.Id })
+	return rows
+}
+
+// facetPrice converts price to DefaultCurrency, Toman, by the rate at its time like the postgres query
+func (r *CarModelRepository) facetPrice(price model.CarModelPriceHistory) (float64, bool) {
+	switch price.Currency {
+	case "", model.CurrencyToman:
+		return price.Price, true
+	case model.CurrencyRial:
+		return math.Round(price.Price/model.RialsPerToman*100) / 100, true
+	}
+	var latest *model.ExchangeRate
+	for _, row := range r.store.list(typeOf[model.ExchangeRate]()) {
+		rate := row.Interface().(model.ExchangeRate)
+		if rate.Currency != price.Currency || rate.RateAt.After(price.PriceAt) {
+			continue
+		}
+		if latest == nil || rate.RateAt.After(latest.RateAt) || rate.RateAt.Equal(latest.RateAt) && rate.Id > latest.Id {
+			latest = &rate
+		}
+	}
+	if latest == nil {
+		return 0, false
+	}
+	return math.Round(price.Price*latest.Rate/model.RialsPerToman*100) / 100, true
+}
+
+func (row carModelFacetRow) matches(query model.CarModelFacetQuery) bool {
+	for field, ids := range query.Selections {
+		if !slices.ContainsFunc(row.values[field], func(value model.FacetCount) bool { return slices.Contains(ids, value.Id) }) {
+			return false
+		}
+	}
+	if query.Price != nil && !slices.ContainsFunc(row.prices, query.Price.Matches) {
+		return false
+	}
+	return matches(row.carModel, &filter.DynamicFilter{Filter: query.Filter})
+}
+
+func (r *CarModelRepository) FacetCarModels(ctx context.Context, query model.CarModelFacetQuery, sorts *[]filter.Sort, offset int, limit int) (int64, []model.CarModelFacetItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	items := []model.CarModelFacetItem{}
+	for _, row := range r.facetRows(ctx, query) {
+		items = append(items, row.item)
+	}
+	sortFacetItems(items, sorts)
+	page := items[min(offset, len(items)):min(offset+limit, len(items))]
+	return int64(len(items)), page, nil
+}
+
+// sortFacetItems sorts like the postgres query, the first sort wins and car models without a price come last
+func sortFacetItems(items []model.CarModelFacetItem, sorts *[]filter.Sort) {
+	if sorts == nil {
+		return
+	}
+	for i := len(*sorts) - 1; i >= 0; i-- {
+		s := (*sorts)[i]
+		if s.Sort != "asc" && s.Sort != "desc" {
+			continue
+		}
+		var compare func(a, b model.CarModelFacetItem) int
+		switch s.ColId {
+		case "Id":
+			compare = func(a, b model.CarModelFacetItem) int { return cmp.Compare(a.Id, b.Id) }
+		case "Name":
+			compare = func(a, b model.CarModelFacetItem) int { return strings.Compare(a.Name, b.Name) }
+		case "Price":
+			compare = func(a, b model.CarModelFacetItem) int { return cmp.Compare(*a.MinPrice, *b.MinPrice) }
+		default:
+			continue
+		}
+		desc := s.Sort == "desc"
+		slices.SortStableFunc(items, func(a, b model.CarModelFacetItem) int {
+			if s.ColId == "Price" && (a.MinPrice == nil || b.MinPrice == nil) {
+				return cmp.Compare(priceMissing(a), priceMissing(b))
+			}
+			if desc {
+				return compare(b, a)
+			}
+			return compare(a, b)
+		})
+	}
+}
+
+func priceMissing(item model.CarModelFacetItem) int {
+	if item.MinPrice == nil {
+		return 1
+	}
+	return 0
+}
+
+func (r *CarModelRepository) FacetCounts(ctx context.Context, query model.CarModelFacetQuery, field string, selected []int) ([]model.FacetCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := []model.FacetCount{}
+	index := map[int]int{}
+	matched := map[int]bool{}
+	for _, row := range r.facetRows(ctx, query) {
+		matched[row.item.Id] = true
+	}
+	// every value of the tenant is listed, so a selected value is kept without car models
+	for _, row := range r.facetRows(ctx, model.CarModelFacetQuery{}) {
+		seen := map[int]bool{}
+		for _, value := range row.values[field] {
+			i, ok := index[value.Id]
+			if !ok {
+				i = len(counts)
+				index[value.Id] = i
+				counts = append(counts, model.FacetCount{Id: value.Id, Name: value.Name})
+			}
+			if matched[row.item.Id] && !seen[value.Id] {
+				seen[value.Id] = true
+				counts[i].Count++
+			}
+		}
+	}
+	counts = slices.DeleteFunc(counts, func(value model.FacetCount) bool { return value.Count == 0 && !slices.Contains(selected, value.Id) })
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Name != counts[j].Name {
+			return counts[i].Name < counts[j].Name
+		}
+		return counts[i].Id < counts[j].Id
+	})
+	return counts, nil
+}
+
+func (r *CarModelRepository) FacetPriceBounds(ctx context.Context, query model.CarModelFacetQuery) (float64, float64, bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	low, high, found := math.Inf(1), math.Inf(-1), false
+	for _, row := range r.facetRows(ctx, query) {
+		for _, price := range row.prices {
+			low, high, found = math.Min(low, price), math.Max(high, price), true
+		}
+	}
+	if !found {
+		return 0, 0, false, nil
+	}
+	return low, high, true, nil
+}
+
+func (r *CarModelRepository) FacetPriceCounts(ctx context.Context, query model.CarModelFacetQuery, start float64, step float64) (map[int]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := map[int]int{}
+	for _, row := range r.facetRows(ctx, query) {
+		buckets := map[int]bool{}
+		for _, price := range row.prices {
+			buckets[int(math.Floor((price-start)/step))] = true
+		}
+		for bucket := range buckets {
+			counts[bucket]++
+		}
+	}
+	return counts, nil
+}
+
+func hasPrefixWord(words []string, term string) bool {
+	for _, word := range words {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
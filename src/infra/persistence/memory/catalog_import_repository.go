@@ -0,0 +1,187 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+type CatalogImportRepository struct {
+	store *Store
+}
+
+func NewCatalogImportRepository(store *Store) *CatalogImportRepository {
+	return &CatalogImportRepository{store: store}
+}
+
+func (r *CatalogImportRepository) Lookup(ctx context.Context) (model.CatalogLookup, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tenantId := database.TenantId(ctx)
+	lookup := model.CatalogLookup{
+		Companies:    names(r.store, func(e model.Company) (string, bool) { return e.Name, true }),
+		CarTypes:     names(r.store, func(e model.CarType) (string, bool) { return e.Name, true }),
+		Gearboxes:    names(r.store, func(e model.Gearbox) (string, bool) { return e.Name, true }),
+		Colors:       names(r.store, func(e model.Color) (string, bool) { return e.Name, true }),
+		Properties:   names(r.store, func(e model.Property) (string, bool) { return e.Name, true }),
+		PersianYears: names(r.store, func(e model.PersianYear) (string, bool) { return e.PersianTitle, true }),
+		CarModels:    names(r.store, func(e model.CarModel) (string, bool) { return e.Name, e.TenantId == tenantId }),
+	}
+	for _, row := range r.store.list(typeOf[model.PersianYear]()) {
+		year := row.Interface().(model.PersianYear)
+		lookup.PersianYears[strconv.Itoa(year.Year)] = year.Id
+	}
+	lookup.PropertyTypes = map[int]model.Property{}
+	for _, row := range r.store.list(typeOf[model.Property]()) {
+		property := row.Interface().(model.Property)
+		lookup.PropertyTypes[property.Id] = property
+	}
+	return lookup, nil
+}
+
+// Import applies all items under the store lock, items are validated by the usecase
+// so there is nothing to roll back
+func (r *CatalogImportRepository) Import(ctx context.Context, items []model.CatalogImportItem) ([]model.CatalogImportItem, error) {
+	if err := database.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tenantId := database.TenantId(ctx)
+	for i := range items {
+		if err := r.importItem(ctx, tenantId, &items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+func (r *CatalogImportRepository) importItem(ctx context.Context, tenantId int, item *model.CatalogImportItem) error {
+	userId := -1
+	if id := userIdFromContext(ctx); id != nil {
+		userId = *id
+	}
+
+	item.CarModelId, item.Created = 0, true
+	for _, row := range r.store.list(typeOf[model.CarModel]()) {
+		carModel := row.Interface().(model.CarModel)
+		if carModel.Name != item.Name || carModel.TenantId != tenantId {
+			continue
+		}
+		carModel.CompanyId, carModel.CarTypeId, carModel.GearboxId = item.CompanyId, item.CarTypeId, item.GearboxId
+		setModified(ctx, reflect.ValueOf(&carModel).Elem())
+		r.store.insert(reflect.ValueOf(&carModel).Elem(), nil)
+		item.CarModelId, item.Created = carModel.Id, false
+		if err := writeEvents(ctx, r.store, event.EntityUpdated, carModel, []string{"CompanyId", "CarTypeId", "GearboxId"}); err != nil {
+			return err
+		}
+		break
+	}
+	if item.Created {
+		carModel := model.CarModel{Name: item.Name, CompanyId: item.CompanyId, CarTypeId: item.CarTypeId, GearboxId: item.GearboxId}
+		carModel.TenantId = tenantId
+		r.store.insert(reflect.ValueOf(&carModel).Elem(), &userId)
+		item.CarModelId = carModel.Id
+		if err := writeEvents(ctx, r.store, event.EntityCreated, carModel, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, colorId := range item.ColorIds {
+		color := model.CarModelColor{CarModelId: item.CarModelId, ColorId: colorId}
+		color.TenantId = tenantId
+		if err := upsertRelation(ctx, r.store, color, []string{"CarModelId", "ColorId"}); err != nil {
+			return err
+		}
+	}
+	for _, persianYearId := range item.PersianYearIds {
+		year := model.CarModelYear{CarModelId: item.CarModelId, PersianYearId: persianYearId}
+		year.TenantId = tenantId
+		if err := upsertRelation(ctx, r.store, year, []string{"CarModelId", "PersianYearId"}); err != nil {
+			return err
+		}
+	}
+	propertyIds := make([]int, 0, len(item.Properties))
+	for propertyId := range item.Properties {
+		propertyIds = append(propertyIds, propertyId)
+	}
+	sort.Ints(propertyIds)
+	for _, propertyId := range propertyIds {
+		value := item.Properties[propertyId]
+		property := model.CarModelProperty{CarModelId: item.CarModelId, PropertyId: propertyId,
+			Value: value.Value, NumericValue: value.NumericValue, NumericMaxValue: value.NumericMaxValue}
+		property.TenantId = tenantId
+		if err := upsertRelation(ctx, r.store, property, []string{"CarModelId", "PropertyId"}, "Value", "NumericValue", "NumericMaxValue"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// names maps the names of the not deleted rows that name accepts to ids, the store lock must be held
+func names[TEntity any](s *Store, name func(TEntity) (string, bool)) map[string]int {
+	result := map[string]int{}
+	for _, row := range s.list(typeOf[TEntity]()) {
+		if key, ok := name(row.Interface().(TEntity)); ok {
+			result[key] = int(row.FieldByName("Id").Int())
+		}
+	}
+	return result
+}
+
+// upsertRelation mirrors the postgres version, a row matching the keys fields of entity is restored
+// when it is soft deleted and its values fields are updated, otherwise entity is inserted
+func upsertRelation[TEntity any](ctx context.Context, s *Store, entity TEntity, keys []string, values ...string) error {
+	v := reflect.ValueOf(&entity).Elem()
+	tb := s.table(v.Type())
+	for id := 1; id < tb.nextId; id++ {
+		stored, ok := tb.rows[id]
+		if !ok || !equalFields(stored, v, keys) {
+			continue
+		}
+		if !isDeleted(stored) && equalFields(stored, v, values) {
+			return nil
+		}
+		row := copyValue(stored)
+		for _, name := range values {
+			row.FieldByName(name).Set(v.FieldByName(name))
+		}
+		setField(row, "DeletedBy", reflect.ValueOf((*sql.NullInt64)(nil)))
+		setField(row, "DeletedAt", reflect.ValueOf(sql.NullTime{}))
+		setModified(ctx, row)
+		s.insert(row, nil)
+		return writeEvents(ctx, s, event.EntityUpdated, row.Interface().(TEntity), values)
+	}
+	userId := -1
+	if id := userIdFromContext(ctx); id != nil {
+		userId = *id
+	}
+	s.insert(v, &userId)
+	return writeEvents(ctx, s, event.EntityCreated, entity, nil)
+}
+
+func equalFields(a reflect.Value, b reflect.Value, names []string) bool {
+	for _, name := range names {
+		// pointers like NumericValue are equal when they point to equal values
+		if !reflect.DeepEqual(a.FieldByName(name).Interface(), b.FieldByName(name).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+func setModified(ctx context.Context, row reflect.Value) {
+	if userId := userIdFromContext(ctx); userId != nil {
+		setField(row, "ModifiedBy", reflect.ValueOf(&sql.NullInt64{Int64: int64(*userId), Valid: true}))
+	}
+	setField(row, "ModifiedAt", reflect.ValueOf(sql.NullTime{Time: time.Now().UTC(), Valid: true}))
+}
@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+type ExchangeRateRepository struct {
+	*BaseRepository[model.ExchangeRate]
+}
+
+func NewExchangeRateRepository(store *Store, preloads []database.PreloadEntity) *ExchangeRateRepository {
+	return &ExchangeRateRepository{BaseRepository: NewBaseRepository[model.ExchangeRate](store, preloads)}
+}
+
+func (r *ExchangeRateRepository) GetByCurrencies(ctx context.Context, currencies []string, until time.Time) ([]model.ExchangeRate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rates := []model.ExchangeRate{}
+	for _, row := range r.store.list(typeOf[model.ExchangeRate]()) {
+		if rate := row.Interface().(model.ExchangeRate); slices.Contains(currencies, rate.Currency) && !rate.RateAt.After(until) {
+			rates = append(rates, rate)
+		}
+	}
+	sort.SliceStable(rates, func(i, j int) bool { return rates[i].RateAt.Before(rates[j].RateAt) })
+	return rates, nil
+}
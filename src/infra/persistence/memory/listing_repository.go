@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+type ListingRepository struct {
+	*BaseRepository[model.Listing]
+}
+
+func NewListingRepository(store *Store, preloads []database.PreloadEntity) *ListingRepository {
+	return &ListingRepository{BaseRepository: NewBaseRepository[model.Listing](store, preloads)}
+}
+
+func (r *ListingRepository) Transition(ctx context.Context, id int, from []string, values map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.get(ctx, id)
+	if !ok || !slices.Contains(from, row.Interface().(model.Listing).Status) {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.ListingStatusInvalid}
+	}
+	_, err := r.updateLocked(ctx, id, values)
+	return err
+}
+
+func (r *ListingRepository) Expire(ctx context.Context, now time.Time, limit int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	expired := 0
+	for _, row := range r.store.list(typeOf[model.Listing]()) {
+		if expired == limit {
+			break
+		}
+		listing := row.Interface().(model.Listing)
+		if listing.Status != model.ListingPublished || !listing.ExpiresAt.Valid || listing.ExpiresAt.Time.After(now) {
+			continue
+		}
+		listing.Status = model.ListingExpired
+		listing.ModifiedAt = sql.NullTime{Valid: true, Time: now}
+		r.store.insert(reflect.ValueOf(&listing).Elem(), nil)
+		if err := writeEvents(database.WithTenant(ctx, listing.TenantId), r.store, event.EntityUpdated, listing, []string{"Status", "ModifiedAt"}); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+type NotificationRepository struct {
+	*BaseRepository[model.Notification]
+}
+
+func NewNotificationRepository(store *Store, preloads []database.PreloadEntity) *NotificationRepository {
+	return &NotificationRepository{BaseRepository: NewBaseRepository[model.Notification](store, preloads)}
+}
+
+func (r *NotificationRepository) Enqueue(ctx context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	keys := map[string]bool{}
+	for _, row := range r.store.list(typeOf[model.Notification]()) {
+		keys[row.Interface().(model.Notification).DeduplicationKey] = true
+	}
+	created := []model.Notification{}
+	userId := -1
+	for _, notification := range notifications {
+		if keys[notification.DeduplicationKey] {
+			continue
+		}
+		keys[notification.DeduplicationKey] = true
+		notification.Id = 0
+		v := reflect.ValueOf(&notification).Elem()
+		r.store.insert(v, &userId)
+		created = append(created, notification)
+	}
+	return created, nil
+}
+
+func (r *NotificationRepository) MarkSent(ctx context.Context, id int) error {
+	r.update(id, func(notification *model.Notification) {
+		notification.Status = model.NotificationSent
+		notification.SentAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+		notification.LastError = ""
+	})
+	return nil
+}
+
+func (r *NotificationRepository) MarkFailed(ctx context.Context, id int, lastError string) error {
+	r.update(id, func(notification *model.Notification) {
+		notification.Status = model.NotificationFailed
+		notification.LastError = lastError
+	})
+	return nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.get(ctx, id)
+	if !ok {
+		return nil
+	}
+	notification := row.Interface().(model.Notification)
+	if !notification.ReadAt.Valid {
+		notification.ReadAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+		r.store.insert(reflect.ValueOf(&notification).Elem(), nil)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) update(id int, change func(notification *model.Notification)) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.get(typeOf[model.Notification](), id)
+	if !ok {
+		return
+	}
+	notification := row.Interface().(model.Notification)
+	change(&notification)
+	r.store.insert(reflect.ValueOf(&notification).Elem(), nil)
+}
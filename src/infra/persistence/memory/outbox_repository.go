@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+type OutboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{store: store}
+}
+
+func (r *OutboxRepository) Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]model.OutboxMessage, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	messages := []model.OutboxMessage{}
+	for _, row := range r.store.list(typeOf[model.OutboxMessage]()) {
+		if len(messages) >= limit {
+			break
+		}
+		message := row.Interface().(model.OutboxMessage)
+		if message.PublishedAt.Valid || message.NextAttemptAt.After(now) ||
+			message.LockedUntil.Valid && !message.LockedUntil.Time.Before(now) {
+			continue
+		}
+		message.LockedUntil = sql.NullTime{Valid: true, Time: now.Add(lockTimeout)}
+		r.store.insert(reflect.ValueOf(&message).Elem(), nil)
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	r.update(id, func(message *model.OutboxMessage) {
+		message.PublishedAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+		message.LastError = ""
+	})
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	r.update(id, func(message *model.OutboxMessage) {
+		message.NextAttemptAt = nextAttemptAt
+		message.LastError = lastError
+	})
+	return nil
+}
+
+func (r *OutboxRepository) update(id int64, change func(message *model.OutboxMessage)) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.get(typeOf[model.OutboxMessage](), int(id))
+	if !ok {
+		return
+	}
+	message := row.Interface().(model.OutboxMessage)
+	change(&message)
+	message.Attempts++
+	message.LockedUntil = sql.NullTime{}
+	r.store.insert(reflect.ValueOf(&message).Elem(), nil)
+}
+
+// writeEvents adds the events of a change of entity to the store like the postgres repositories
+// do with the outbox, the store lock must be held
+func writeEvents[TEntity any](ctx context.Context, s *Store, eventType event.Type, entity TEntity, changes []string) error {
+	sort.Strings(changes)
+	events, err := event.OfChange(eventType, entity, database.TenantId(ctx), userIdFromContext(ctx), changes,
+		func(price model.CarModelPriceHistory) (*model.CarModelPriceHistory, error) {
+			return s.previousPrice(price), nil
+		})
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		message := e.Message()
+		s.insert(reflect.ValueOf(&message).Elem(), nil)
+	}
+	return nil
+}
+
+// previousPrice returns the latest price of the car model year before price, the store lock must be held
+func (s *Store) previousPrice(price model.CarModelPriceHistory) *model.CarModelPriceHistory {
+	var previous *model.CarModelPriceHistory
+	for _, row := range s.list(typeOf[model.CarModelPriceHistory]()) {
+		other := row.Interface().(model.CarModelPriceHistory)
+		if other.CarModelYearId != price.CarModelYearId || other.Id == price.Id || other.PriceAt.After(price.PriceAt) {
+			continue
+		}
+		if previous == nil || !other.PriceAt.Before(previous.PriceAt) {
+			previous = &other
+		}
+	}
+	return previous
+}
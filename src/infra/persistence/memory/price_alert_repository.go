@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+type PriceAlertRepository struct {
+	*BaseRepository[model.PriceAlert]
+}
+
+func NewPriceAlertRepository(store *Store, preloads []database.PreloadEntity) *PriceAlertRepository {
+	return &PriceAlertRepository{BaseRepository: NewBaseRepository[model.PriceAlert](store, preloads)}
+}
+
+func (r *PriceAlertRepository) Watching(ctx context.Context, tenantId int, carModelYearId int) ([]model.PriceAlert, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	preloads := []database.PreloadEntity{{Entity: "User"}, {Entity: "CarModelYear.CarModel"}, {Entity: "CarModelYear.PersianYear"}}
+	alerts := []model.PriceAlert{}
+	for _, row := range r.store.list(typeOf[model.PriceAlert]()) {
+		alert := row.Interface().(model.PriceAlert)
+		if alert.TenantId != tenantId || alert.CarModelYearId != carModelYearId || !alert.Enabled {
+			continue
+		}
+		r.store.preload(row, preloads)
+		alerts = append(alerts, row.Interface().(model.PriceAlert))
+	}
+	return alerts, nil
+}
+
+func (r *PriceAlertRepository) UpdateBasePrice(ctx context.Context, id int, basePrice float64, triggered bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.get(typeOf[model.PriceAlert](), id)
+	if !ok {
+		return nil
+	}
+	alert := row.Interface().(model.PriceAlert)
+	alert.BasePrice = sql.NullFloat64{Valid: true, Float64: basePrice}
+	if triggered {
+		alert.LastTriggeredAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	}
+	r.store.insert(reflect.ValueOf(&alert).Elem(), nil)
+	return nil
+}
@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	filter "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+// matches evaluates a DynamicFilter like database.GenerateDynamicQuery does, text is compared by its
+// normalized Persian, filters on unknown fields are ignored
+func matches(v reflect.Value, dynamicFilter *filter.DynamicFilter) bool {
+	for name, f := range dynamicFilter.Filter {
+		fld := v.FieldByName(name)
+		if !fld.IsValid() {
+			continue
+		}
+		if !matchFilter(fld, f) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchFilter(fld reflect.Value, f filter.Filter) bool {
+	if f.IsJalali() {
+		converted, err := f.Gregorian()
+		if err != nil {
+			return false
+		}
+		f = converted
+	}
+	f.From, f.To = common.NormalizePersian(f.From), common.NormalizePersian(f.To)
+	if fld.Kind() == reflect.String {
+		value := strings.ToLower(fld.String())
+		from := strings.ToLower(f.From)
+		switch f.Type {
+		case "contains":
+			return strings.Contains(value, from)
+		case "notContains":
+			return !strings.Contains(value, from)
+		case "startsWith":
+			return strings.HasPrefix(value, from)
+		case "endsWith":
+			return strings.HasSuffix(value, from)
+		case "equals":
+			return fld.String() == f.From
+		case "notEqual":
+			return fld.String() != f.From
+		}
+	}
+
+	cmpFrom, ok := compare(fld, f.From)
+	if !ok {
+		return false
+	}
+	switch f.Type {
+	case "equals":
+		return cmpFrom == 0
+	case "notEqual":
+		return cmpFrom != 0
+	case "lessThan":
+		return cmpFrom < 0
+	case "lessThanOrEqual":
+		return cmpFrom <= 0
+	case "greaterThan":
+		return cmpFrom > 0
+	case "greaterThanOrEqual":
+		return cmpFrom >= 0
+	case "inRange":
+		cmpTo, ok := compare(fld, f.To)
+		return ok && cmpFrom >= 0 && cmpTo <= 0
+	}
+	return true
+}
+
+// compare returns -1, 0 or 1 when fld is less, equal or greater than raw, nil pointers
+// like NULL columns match nothing
+func compare(fld reflect.Value, raw string) (int, bool) {
+	if fld.Kind() == reflect.Ptr {
+		if fld.IsNil() {
+			return 0, false
+		}
+		fld = fld.Elem()
+	}
+	switch fld.Kind() {
+	case reflect.String:
+		return strings.Compare(fld.String(), raw), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		other, err := strconv.ParseFloat(raw, 64)
+		return compareFloat(float64(fld.Int()), other), err == nil
+	case reflect.Float32, reflect.Float64:
+		other, err := strconv.ParseFloat(raw, 64)
+		return compareFloat(fld.Float(), other), err == nil
+	case reflect.Bool:
+		other, err := strconv.ParseBool(raw)
+		return compareFloat(boolToFloat(fld.Bool()), boolToFloat(other)), err == nil
+	}
+	if t, ok := fld.Interface().(sql.NullTime); ok {
+		if !t.Valid {
+			return 0, false
+		}
+		fld = reflect.ValueOf(t.Time)
+	}
+	if t, ok := fld.Interface().(time.Time); ok {
+		other, err := parseTime(raw)
+		return t.Compare(other), err == nil
+	}
+	return 0, false
+}
+
+func compareFloat(a float64, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func parseTime(raw string) (time.Time, error) {
+	raw = strings.Trim(raw, "'")
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %s", raw)
+}
+
+// sortRows applies DynamicFilter.Sort like database.GenerateDynamicSort does
+func sortRows(rows []reflect.Value, dynamicFilter *filter.DynamicFilter) {
+	if dynamicFilter.Sort == nil {
+		return
+	}
+	sorts := *dynamicFilter.Sort
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, s := range sorts {
+			if s.Sort != "asc" && s.Sort != "desc" {
+				continue
+			}
+			a := rows[i].FieldByName(s.ColId)
+			b := rows[j].FieldByName(s.ColId)
+			if !a.IsValid() || !b.IsValid() || b.Kind() == reflect.Ptr && b.IsNil() {
+				continue
+			}
+			c, ok := compare(a, fmt.Sprint(reflect.Indirect(b).Interface()))
+			if !ok || c == 0 {
+				continue
+			}
+			return (c < 0) == (s.Sort == "asc")
+		}
+		return false
+	})
+}
+
+// preload resolves navigation properties like gorm Preload, e.g. "Company.Country" or "CarModelYears.PersianYear"
+func (s *Store) preload(v reflect.Value, preloads []database.PreloadEntity) {
+	for _, item := range preloads {
+		s.resolve(v, strings.Split(item.Entity, "."))
+	}
+}
+
+func (s *Store) resolve(v reflect.Value, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	sf, ok := v.Type().FieldByName(path[0])
+	if !ok {
+		return
+	}
+	fld := v.FieldByIndex(sf.Index)
+
+	switch {
+	case isEntityType(fld.Type()):
+		// belongs to, e.g. CarModel.Company by CompanyId
+		fk := v.FieldByName(database.ForeignKey(sf, sf.Name+"Id"))
+		if !fk.IsValid() {
+			return
+		}
+		// already loaded by a previous path with the same prefix
+		if fk.Int() == 0 || fld.FieldByName("Id").Int() != fk.Int() {
+			related, ok := s.get(fld.Type(), int(fk.Int()))
+			if !ok {
+				return
+			}
+			fld.Set(related)
+		}
+		s.resolve(fld, path[1:])
+
+	case fld.Kind() == reflect.Slice && isEntityType(fld.Type().Elem()),
+		fld.Kind() == reflect.Ptr && fld.Type().Elem().Kind() == reflect.Slice && isEntityType(fld.Type().Elem().Elem()):
+		// has many, e.g. CarModel.CarModelColors by CarModelId
+		sliceType := fld.Type()
+		if sliceType.Kind() == reflect.Ptr {
+			sliceType = sliceType.Elem()
+		}
+		if !fld.IsNil() {
+			// already loaded by a previous path with the same prefix
+			loaded := fld
+			if loaded.Kind() == reflect.Ptr {
+				loaded = loaded.Elem()
+			}
+			for i := 0; i < loaded.Len(); i++ {
+				s.resolve(loaded.Index(i), path[1:])
+			}
+			return
+		}
+		fkName := database.ForeignKey(sf, v.Type().Name()+"Id")
+		ownerId := v.FieldByName("Id").Int()
+		children := reflect.MakeSlice(sliceType, 0, 0)
+		for _, row := range s.list(sliceType.Elem()) {
+			if fk := row.FieldByName(fkName); fk.IsValid() && fk.Int() == ownerId {
+				children = reflect.Append(children, row)
+			}
+		}
+		for i := 0; i < children.Len(); i++ {
+			s.resolve(children.Index(i), path[1:])
+		}
+		if fld.Kind() == reflect.Ptr {
+			ptr := reflect.New(sliceType)
+			ptr.Elem().Set(children)
+			fld.Set(ptr)
+		} else {
+			fld.Set(children)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	filter "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+type BaseRepository[TEntity any] struct {
+	store        *Store
+	preloads     []database.PreloadEntity
+	tenantScoped bool
+}
+
+func NewBaseRepository[TEntity any](store *Store, preloads []database.PreloadEntity) *BaseRepository[TEntity] {
+	return &BaseRepository[TEntity]{store: store, preloads: preloads, tenantScoped: database.IsTenantScoped[TEntity]()}
+}
+
+func (r BaseRepository[TEntity]) Create(ctx context.Context, entity TEntity) (TEntity, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.authorizeWrite(ctx); err != nil {
+		return entity, err
+	}
+	if r.tenantScoped {
+		any(&entity).(model.TenantScoped).SetTenantId(database.TenantId(ctx))
+		if err := r.checkReferences(ctx, database.TenantReferences(entity)); err != nil {
+			return entity, err
+		}
+	}
+	v := reflect.ValueOf(&entity).Elem()
+	v.FieldByName("Id").SetInt(0)
+	userId := -1
+	if id := userIdFromContext(ctx); id != nil {
+		userId = *id
+	}
+	r.store.insert(v, &userId)
+	if err := writeEvents(ctx, r.store, event.EntityCreated, entity, nil); err != nil {
+		return entity, err
+	}
+	return entity, nil
+}
+
+func (r BaseRepository[TEntity]) Update(ctx context.Context, id int, entity map[string]interface{}) (TEntity, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.updateLocked(ctx, id, entity)
+}
+
+// updateLocked is Update for callers holding the lock of the store
+func (r BaseRepository[TEntity]) updateLocked(ctx context.Context, id int, entity map[string]interface{}) (TEntity, error) {
+	model := new(TEntity)
+	if err := r.authorizeWrite(ctx); err != nil {
+		return *model, err
+	}
+	delete(entity, "TenantId")
+	if r.tenantScoped {
+		if err := r.checkReferences(ctx, database.TenantReferencesOf(reflect.TypeOf(*model), entity)); err != nil {
+			return *model, err
+		}
+	}
+	row, ok := r.get(ctx, id)
+	if !ok {
+		return *model, nil
+	}
+	for k, value := range entity {
+		fld := row.FieldByName(k)
+		if !fld.IsValid() || !fld.CanSet() {
+			continue
+		}
+		// values come from a json round trip, so decode them the same way
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return *model, err
+		}
+		if err = json.Unmarshal(raw, fld.Addr().Interface()); err != nil {
+			return *model, err
+		}
+	}
+	if userId := userIdFromContext(ctx); userId != nil {
+		setField(row, "ModifiedBy", reflect.ValueOf(&sql.NullInt64{Int64: int64(*userId), Valid: true}))
+	}
+	setField(row, "ModifiedAt", reflect.ValueOf(sql.NullTime{Time: time.Now().UTC(), Valid: true}))
+	r.store.insert(row, nil)
+	changes := make([]string, 0, len(entity))
+	for k := range entity {
+		changes = append(changes, k)
+	}
+	if err := writeEvents(ctx, r.store, event.EntityUpdated, row.Interface().(TEntity), changes); err != nil {
+		return *model, err
+	}
+	return row.Interface().(TEntity), nil
+}
+
+func (r BaseRepository[TEntity]) Delete(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	userId := userIdFromContext(ctx)
+	if userId == nil {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
+	}
+	if err := r.authorizeWrite(ctx); err != nil {
+		return err
+	}
+	row, ok := r.get(ctx, id)
+	if !ok {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	setField(row, "DeletedBy", reflect.ValueOf(&sql.NullInt64{Int64: int64(*userId), Valid: true}))
+	setField(row, "DeletedAt", reflect.ValueOf(sql.NullTime{Time: time.Now().UTC(), Valid: true}))
+	r.store.insert(row, nil)
+	return writeEvents(ctx, r.store, event.EntityDeleted, row.Interface().(TEntity), nil)
+}
+
+func (r BaseRepository[TEntity]) GetById(ctx context.Context, id int) (TEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	model := new(TEntity)
+	row, ok := r.get(ctx, id)
+	if !ok {
+		return *model, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	r.store.preload(row, r.preloads)
+	return row.Interface().(TEntity), nil
+}
+
+func (r BaseRepository[TEntity]) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]TEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := []reflect.Value{}
+	for _, row := range r.store.list(reflect.TypeOf(*new(TEntity))) {
+		if r.inTenant(ctx, row) && matches(row, &req.DynamicFilter) {
+			rows = append(rows, row)
+		}
+	}
+	sortRows(rows, &req.DynamicFilter)
+
+	items := []TEntity{}
+	for i := req.GetOffset(); i < len(rows) && len(items) < req.GetPageSize(); i++ {
+		r.store.preload(rows[i], r.preloads)
+		items = append(items, rows[i].Interface().(TEntity))
+	}
+	return int64(len(rows)), &items, nil
+}
+
+// get returns a row of the current tenant, the store lock must be held
+func (r BaseRepository[TEntity]) get(ctx context.Context, id int) (reflect.Value, bool) {
+	row, ok := r.store.get(reflect.TypeOf(*new(TEntity)), id)
+	if !ok || !r.inTenant(ctx, row) {
+		return reflect.Value{}, false
+	}
+	return row, true
+}
+
+func (r BaseRepository[TEntity]) inTenant(ctx context.Context, row reflect.Value) bool {
+	return !r.tenantScoped || int(row.FieldByName("TenantId").Int()) == database.TenantId(ctx)
+}
+
+// authorizeWrite allows writes on global reference data only to the platform tenant
+func (r BaseRepository[TEntity]) authorizeWrite(ctx context.Context) error {
+	if err := database.RequireTenant(ctx); err != nil {
+		return err
+	}
+	if r.tenantScoped || database.TenantId(ctx) == constant.DefaultTenantId {
+		return nil
+	}
+	return &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
+}
+
+// checkReferences rejects foreign keys to tenant scoped rows of other tenants, the store lock must be held
+func (r BaseRepository[TEntity]) checkReferences(ctx context.Context, references []database.TenantReference) error {
+	for _, ref := range references {
+		row, ok := r.store.get(ref.Type, ref.Id)
+		if !ok || int(row.FieldByName("TenantId").Int()) != database.TenantId(ctx) {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+	}
+	return nil
+}
+
+// All returns every not deleted entity, it is useful to assert on the store state in tests
+func All[TEntity any](s *Store) []TEntity {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := []TEntity{}
+	for _, row := range s.list(reflect.TypeOf(*new(TEntity))) {
+		items = append(items, row.Interface().(TEntity))
+	}
+	return items
+}
@@ -0,0 +1,192 @@
+// Package memory keeps entities in process memory instead of postgres.
+//
+// It implements the domain repositories with the same behaviour as the postgres ones
+// (soft delete, audit fields, DynamicFilter, preloads) so handlers and usecases can be
+// exercised without external services.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+)
+
+type Store struct {
+	mu     sync.RWMutex
+	tables map[reflect.Type]*table
+}
+
+type table struct {
+	nextId int
+	rows   map[int]reflect.Value
+}
+
+func NewStore() *Store {
+	return &Store{tables: map[reflect.Type]*table{}}
+}
+
+// Seed inserts entities as they are, entities without id get the next one
+func Seed[TEntity any](s *Store, entities ...TEntity) []TEntity {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]TEntity, 0, len(entities))
+	for _, entity := range entities {
+		v := reflect.ValueOf(&entity).Elem()
+		s.insert(v, nil)
+		result = append(result, entity)
+	}
+	return result
+}
+
+func (s *Store) table(t reflect.Type) *table {
+	tb, ok := s.tables[t]
+	if !ok {
+		tb = &table{nextId: 1, rows: map[int]reflect.Value{}}
+		s.tables[t] = tb
+	}
+	return tb
+}
+
+// insert stores a copy of v, v must be addressable so id and audit fields are written back
+func (s *Store) insert(v reflect.Value, userId *int) {
+	tb := s.table(v.Type())
+	id := int(v.FieldByName("Id").Int())
+	if id == 0 {
+		id = tb.nextId
+		v.FieldByName("Id").SetInt(int64(id))
+	}
+	if id >= tb.nextId {
+		tb.nextId = id + 1
+	}
+	if userId != nil {
+		setField(v, "CreatedAt", reflect.ValueOf(time.Now().UTC()))
+		setField(v, "CreatedBy", reflect.ValueOf(*userId))
+		applyDefaults(v)
+	}
+	row := copyValue(v)
+	stripRelations(row)
+	tb.rows[id] = row
+}
+
+// get returns a copy of a not deleted row
+func (s *Store) get(t reflect.Type, id int) (reflect.Value, bool) {
+	row, ok := s.table(t).rows[id]
+	if !ok || isDeleted(row) {
+		return reflect.Value{}, false
+	}
+	return copyValue(row), true
+}
+
+// list returns copies of not deleted rows ordered by id
+func (s *Store) list(t reflect.Type) []reflect.Value {
+	tb := s.table(t)
+	result := make([]reflect.Value, 0, len(tb.rows))
+	for id := 1; id < tb.nextId; id++ {
+		row, ok := tb.rows[id]
+		if ok && !isDeleted(row) {
+			result = append(result, copyValue(row))
+		}
+	}
+	return result
+}
+
+func copyValue(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+// stripRelations clears navigation properties, they are resolved again by preloads on read
+func stripRelations(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		fld := v.Field(i)
+		if v.Type().Field(i).Anonymous || !fld.CanSet() {
+			continue
+		}
+		t := fld.Type()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		if isEntityType(t) {
+			fld.Set(reflect.Zero(fld.Type()))
+		}
+	}
+}
+
+// applyDefaults sets zero fields that have a gorm default like postgres does on insert
+func applyDefaults(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		fld := v.Field(i)
+		if sf.Anonymous && fld.Kind() == reflect.Struct {
+			applyDefaults(fld)
+			continue
+		}
+		if !fld.CanSet() || !fld.IsZero() {
+			continue
+		}
+		for _, part := range strings.Split(sf.Tag.Get("gorm"), ";") {
+			if !strings.HasPrefix(part, "default:") {
+				continue
+			}
+			value := strings.TrimPrefix(part, "default:")
+			switch fld.Kind() {
+			case reflect.Bool:
+				b, _ := strconv.ParseBool(value)
+				fld.SetBool(b)
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n, _ := strconv.ParseInt(value, 10, 64)
+				fld.SetInt(n)
+			case reflect.String:
+				if value != "null" {
+					fld.SetString(strings.Trim(value, "'"))
+				}
+			}
+		}
+	}
+}
+
+func isEntityType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := t.FieldByName("BaseModel")
+	return ok
+}
+
+func setField(v reflect.Value, name string, value reflect.Value) {
+	fld := v.FieldByName(name)
+	if fld.IsValid() && fld.CanSet() && value.Type().AssignableTo(fld.Type()) {
+		fld.Set(value)
+	}
+}
+
+func isDeleted(v reflect.Value) bool {
+	fld := v.FieldByName("DeletedBy")
+	if !fld.IsValid() || fld.IsNil() {
+		return false
+	}
+	return fld.Interface().(*sql.NullInt64).Valid
+}
+
+func userIdFromContext(ctx context.Context) *int {
+	value, ok := ctx.Value(constant.UserIdKey).(float64)
+	if !ok {
+		return nil
+	}
+	id := int(value)
+	return &id
+}
+
+func typeOf[TEntity any]() reflect.Type {
+	return reflect.TypeOf(*new(TEntity))
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserRepository struct {
+	*BaseRepository[model.User]
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{BaseRepository: NewBaseRepository[model.User](store, []database.PreloadEntity{})}
+}
+
+func (r *UserRepository) CreateUser(ctx context.Context, u model.User) (model.User, error) {
+	return r.CreateUserWithRole(ctx, u, constant.DefaultRoleName)
+}
+
+func (r *UserRepository) CreateUserWithRole(ctx context.Context, u model.User, roleName string) (model.User, error) {
+	roleId, err := r.getRoleId(roleName)
+	if err != nil {
+		return u, err
+	}
+	// users are registered to an explicit tenant, there is no fallback to the platform tenant
+	if u.TenantId == 0 {
+		return u, &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	// the user may belong to another tenant than the caller, so it is not created by the base repository
+	userId := -1
+	if id := userIdFromContext(ctx); id != nil {
+		userId = *id
+	}
+	r.store.insert(reflect.ValueOf(&u).Elem(), &userId)
+	userRole := model.UserRole{RoleId: roleId, UserId: u.Id}
+	r.store.insert(reflect.ValueOf(&userRole).Elem(), &userId)
+	return u, nil
+}
+
+func (r *UserRepository) FetchUserInfo(ctx context.Context, username string, password string) (model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var user model.User
+	for _, row := range r.store.list(typeOf[model.User]()) {
+		if row.FieldByName("Username").String() == username && r.tenantEnabled(int(row.FieldByName("TenantId").Int())) {
+			r.store.preload(row, []database.PreloadEntity{{Entity: "UserRoles.Role"}})
+			user = row.Interface().(model.User)
+			break
+		}
+	}
+	if user.UserRoles == nil {
+		user.UserRoles = &[]model.UserRole{}
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if err != nil {
+		return user, err
+	}
+	return user, nil
+}
+
+func (r *UserRepository) ExistsEmail(ctx context.Context, email string) (bool, error) {
+	return r.exists("Email", email), nil
+}
+
+func (r *UserRepository) ExistsUsername(ctx context.Context, username string) (bool, error) {
+	return r.exists("Username", username), nil
+}
+
+func (r *UserRepository) ExistsMobileNumber(ctx context.Context, mobileNumber string) (bool, error) {
+	return r.exists("MobileNumber", mobileNumber), nil
+}
+
+func (r *UserRepository) GetDefaultRole(ctx context.Context) (roleId int, err error) {
+	return r.getRoleId(constant.DefaultRoleName)
+}
+
+func (r *UserRepository) getRoleId(roleName string) (int, error) {
+	for _, role := range All[model.Role](r.store) {
+		if role.Name == roleName {
+			return role.Id, nil
+		}
+	}
+	return 0, errors.New("record not found")
+}
+
+func (r *UserRepository) exists(field string, value string) bool {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, row := range r.store.list(typeOf[model.User]()) {
+		if row.FieldByName(field).String() == value {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantEnabled must be called with the store lock held
+func (r *UserRepository) tenantEnabled(tenantId int) bool {
+	row, ok := r.store.get(typeOf[model.Tenant](), tenantId)
+	return ok && row.FieldByName("Enabled").Bool()
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+type WatchlistRepository struct {
+	*BaseRepository[model.Watchlist]
+}
+
+func NewWatchlistRepository(store *Store, preloads []database.PreloadEntity) *WatchlistRepository {
+	return &WatchlistRepository{BaseRepository: NewBaseRepository[model.Watchlist](store, preloads)}
+}
+
+func (r *WatchlistRepository) GetByShareToken(ctx context.Context, shareToken string) (model.Watchlist, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, row := range r.store.list(typeOf[model.Watchlist]()) {
+		watchlist := row.Interface().(model.Watchlist)
+		if watchlist.ShareToken != nil && *watchlist.ShareToken == shareToken {
+			r.store.preload(row, r.preloads)
+			return row.Interface().(model.Watchlist), nil
+		}
+	}
+	return model.Watchlist{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+}
+
+func (r *WatchlistRepository) Followers(ctx context.Context, carModelIds []int) (map[int]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := map[int]map[int]bool{}
+	for _, id := range carModelIds {
+		users[id] = map[int]bool{}
+	}
+	for _, row := range r.store.list(typeOf[model.WatchlistEntry]()) {
+		entry := row.Interface().(model.WatchlistEntry)
+		watchlist, ok := r.get(ctx, entry.WatchlistId)
+		if _, followed := users[entry.CarModelId]; !ok || !followed {
+			continue
+		}
+		users[entry.CarModelId][watchlist.Interface().(model.Watchlist).UserId] = true
+	}
+	followers := map[int]int{}
+	for id, ids := range users {
+		if len(ids) > 0 {
+			followers[id] = len(ids)
+		}
+	}
+	return followers, nil
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+type WebhookSubscriptionRepository struct {
+	*BaseRepository[model.WebhookSubscription]
+}
+
+func NewWebhookSubscriptionRepository(store *Store, preloads []database.PreloadEntity) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{BaseRepository: NewBaseRepository[model.WebhookSubscription](store, preloads)}
+}
+
+func (r *WebhookSubscriptionRepository) Subscribers(ctx context.Context, tenantId int) ([]model.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscriptions := []model.WebhookSubscription{}
+	for _, row := range r.store.list(typeOf[model.WebhookSubscription]()) {
+		subscription := row.Interface().(model.WebhookSubscription)
+		if subscription.TenantId == tenantId && subscription.Enabled {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+type WebhookDeliveryRepository struct {
+	*BaseRepository[model.WebhookDelivery]
+}
+
+func NewWebhookDeliveryRepository(store *Store, preloads []database.PreloadEntity) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{BaseRepository: NewBaseRepository[model.WebhookDelivery](store, preloads)}
+}
+
+func (r *WebhookDeliveryRepository) Enqueue(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing := map[int]map[string]bool{}
+	for _, row := range r.store.list(typeOf[model.WebhookDelivery]()) {
+		delivery := row.Interface().(model.WebhookDelivery)
+		if existing[delivery.SubscriptionId] == nil {
+			existing[delivery.SubscriptionId] = map[string]bool{}
+		}
+		existing[delivery.SubscriptionId][delivery.EventId] = true
+	}
+	userId := -1
+	for _, delivery := range deliveries {
+		if existing[delivery.SubscriptionId][delivery.EventId] {
+			continue
+		}
+		delivery.Id = 0
+		r.store.insert(reflect.ValueOf(&delivery).Elem(), &userId)
+	}
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]model.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	deliveries := []model.WebhookDelivery{}
+	for _, row := range r.store.list(typeOf[model.WebhookDelivery]()) {
+		if len(deliveries) >= limit {
+			break
+		}
+		delivery := row.Interface().(model.WebhookDelivery)
+		if delivery.Status != model.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) ||
+			delivery.LockedUntil.Valid && !delivery.LockedUntil.Time.Before(now) {
+			continue
+		}
+		subscription, ok := r.store.get(typeOf[model.WebhookSubscription](), delivery.SubscriptionId)
+		if !ok || !subscription.Interface().(model.WebhookSubscription).Enabled {
+			continue
+		}
+		delivery.LockedUntil = sql.NullTime{Valid: true, Time: now.Add(lockTimeout)}
+		r.store.insert(reflect.ValueOf(&delivery).Elem(), nil)
+		delivery.Subscription = subscription.Interface().(model.WebhookSubscription)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) MarkDelivered(ctx context.Context, id int, responseStatus int) error {
+	r.update(id, func(delivery *model.WebhookDelivery) {
+		now := time.Now().UTC()
+		delivery.Status = model.WebhookDeliveryDelivered
+		delivery.Attempts++
+		delivery.LastAttemptAt = sql.NullTime{Valid: true, Time: now}
+		delivery.DeliveredAt = sql.NullTime{Valid: true, Time: now}
+		delivery.ResponseStatus = responseStatus
+		delivery.LastError = ""
+	})
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) MarkFailed(ctx context.Context, id int, responseStatus int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	r.update(id, func(delivery *model.WebhookDelivery) {
+		delivery.Status = model.WebhookDeliveryPending
+		if dead {
+			delivery.Status = model.WebhookDeliveryDead
+		}
+		delivery.Attempts++
+		delivery.NextAttemptAt = nextAttemptAt
+		delivery.LastAttemptAt = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+		delivery.ResponseStatus = responseStatus
+		delivery.LastError = lastError
+	})
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) Redeliver(ctx context.Context, id int) (model.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.get(ctx, id)
+	if !ok {
+		return model.WebhookDelivery{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	delivery := row.Interface().(model.WebhookDelivery)
+	delivery.Status = model.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.LockedUntil = sql.NullTime{}
+	delivery.NextAttemptAt = time.Now().UTC()
+	delivery.DeliveredAt = sql.NullTime{}
+	r.store.insert(reflect.ValueOf(&delivery).Elem(), nil)
+	return delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) update(id int, change func(delivery *model.WebhookDelivery)) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.get(typeOf[model.WebhookDelivery](), id)
+	if !ok {
+		return
+	}
+	delivery := row.Interface().(model.WebhookDelivery)
+	change(&delivery)
+	delivery.LockedUntil = sql.NullTime{}
+	r.store.insert(reflect.ValueOf(&delivery).Elem(), nil)
+}
@@ -0,0 +1,29 @@
+package migration
+
+import (
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Up10 adds the watchlists of the users and their entries
+func Up10(database *gorm.DB) error {
+	tables := []interface{}{&models.Watchlist{}, &models.WatchlistEntry{}}
+	for _, table := range tables {
+		if database.Migrator().HasTable(table) {
+			continue
+		}
+		if err := database.Migrator().CreateTable(table); err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	return nil
+}
+
+func Down10(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP TABLE IF EXISTS watchlist_entries",
+		"DROP TABLE IF EXISTS watchlists",
+	})
+}
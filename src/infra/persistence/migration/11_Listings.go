@@ -0,0 +1,29 @@
+package migration
+
+import (
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Up11 adds the used car listings and their images
+func Up11(database *gorm.DB) error {
+	tables := []interface{}{&models.Listing{}, &models.ListingImage{}}
+	for _, table := range tables {
+		if database.Migrator().HasTable(table) {
+			continue
+		}
+		if err := database.Migrator().CreateTable(table); err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	return nil
+}
+
+func Down11(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP TABLE IF EXISTS listing_images",
+		"DROP TABLE IF EXISTS listings",
+	})
+}
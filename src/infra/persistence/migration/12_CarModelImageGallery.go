@@ -0,0 +1,27 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// Up12 orders the car model galleries, keeps the first main image of every car model (or makes its first
+// image main) and allows at most one main image per car model
+func Up12(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"ALTER TABLE car_model_images ADD COLUMN IF NOT EXISTS sort_order bigint NOT NULL DEFAULT 0",
+		"UPDATE car_model_images i SET sort_order = o.position " +
+			"FROM (SELECT id, row_number() OVER (PARTITION BY car_model_id ORDER BY is_main_image DESC, id) AS position " +
+			"FROM car_model_images WHERE deleted_by IS NULL) o WHERE i.id = o.id",
+		"UPDATE car_model_images SET is_main_image = (sort_order = 1) WHERE deleted_by IS NULL",
+		"UPDATE car_model_images SET is_main_image = false WHERE deleted_by IS NOT NULL",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_car_model_images_main ON car_model_images (car_model_id) " +
+			"WHERE is_main_image AND deleted_by IS NULL",
+	})
+}
+
+func Down12(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP INDEX IF EXISTS idx_car_model_images_main",
+		"ALTER TABLE car_model_images DROP COLUMN IF EXISTS sort_order",
+	})
+}
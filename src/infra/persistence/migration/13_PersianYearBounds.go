@@ -0,0 +1,42 @@
+package migration
+
+import (
+	"github.com/naeemaei/golang-clean-web-api/common"
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Up13 replaces the hand entered bounds of the persian years by the ones computed from their year
+func Up13(database *gorm.DB) error {
+	return database.Transaction(func(tx *gorm.DB) error {
+		years := []models.PersianYear{}
+		if err := tx.Order("year").Find(&years).Error; err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+		// the bounds are unique, so they are cleared first to let a year take the old bounds of another one
+		if err := tx.Exec("UPDATE persian_years SET start_at = timestamptz '0001-01-01 00:00:00+00' + id * interval '1 second', " +
+			"end_at = timestamptz '0001-01-01 00:00:00+00' + id * interval '1 second'").Error; err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+		for _, year := range years {
+			startAt, endAt := common.JalaliYearBounds(year.Year)
+			err := tx.Model(&models.PersianYear{}).
+				Where("id = ?", year.Id).
+				Updates(map[string]interface{}{"start_at": startAt, "end_at": endAt}).
+				Error
+			if err != nil {
+				logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down13 keeps the computed bounds, the hand entered ones are not known anymore
+func Down13(database *gorm.DB) error {
+	return nil
+}
@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// normalize_persian_chars compared the text columns of the dynamic filters, migration 17 normalizes
+// the stored text instead and drops it
+const normalizePersianChars14 string = `CREATE OR REPLACE FUNCTION normalize_persian_chars(value text) RETURNS text AS $$
+		SELECT translate(value,
+			'يىك٠١٢٣٤٥٦٧٨٩۰۱۲۳۴۵۶۷۸۹' || chr(1600),
+			'ییک01234567890123456789')
+	$$ LANGUAGE sql IMMUTABLE`
+
+var up14Statements = []string{normalizePersianChars14}
+
+func Up14(database *gorm.DB) error {
+	return execStatements(database, up14Statements)
+}
+
+func Down14(database *gorm.DB) error {
+	return execStatements(database, []string{`DROP FUNCTION IF EXISTS normalize_persian_chars(text)`})
+}
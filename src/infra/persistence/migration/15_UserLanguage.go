@@ -0,0 +1,18 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+// Up15 stores the preferred language of the error messages of every user, null uses Accept-Language
+func Up15(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS language varchar(2) NULL",
+	})
+}
+
+func Down15(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"ALTER TABLE users DROP COLUMN IF EXISTS language",
+	})
+}
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Up16 adds the currency of the prices and price alerts, the existing ones are in Toman, widens the prices
+// and alert amounts for Rial and adds the exchange rates with at most one rate of a currency at a time
+func Up16(database *gorm.DB) error {
+	if !database.Migrator().HasTable(&models.ExchangeRate{}) {
+		if err := database.Migrator().CreateTable(&models.ExchangeRate{}); err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	return execStatements(database, []string{
+		"ALTER TABLE car_model_price_histories ALTER COLUMN price TYPE decimal(20,2)",
+		"ALTER TABLE car_model_price_histories ADD COLUMN IF NOT EXISTS currency varchar(3) NOT NULL DEFAULT 'IRT'",
+		"ALTER TABLE price_alerts ALTER COLUMN threshold TYPE decimal(20,2)",
+		"ALTER TABLE price_alerts ALTER COLUMN base_price TYPE decimal(20,2)",
+		"ALTER TABLE price_alerts ADD COLUMN IF NOT EXISTS currency varchar(3) NOT NULL DEFAULT 'IRT'",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_exchange_rates_currency_rate_at ON exchange_rates (currency, rate_at) " +
+			"WHERE deleted_by IS NULL",
+	})
+}
+
+// Down16 restores the column types, it fails on amounts that only fit the wide columns
+func Down16(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP TABLE IF EXISTS exchange_rates",
+		"ALTER TABLE price_alerts DROP COLUMN IF EXISTS currency",
+		"ALTER TABLE price_alerts ALTER COLUMN base_price TYPE decimal(10,2)",
+		"ALTER TABLE price_alerts ALTER COLUMN threshold TYPE decimal(10,2)",
+		"ALTER TABLE car_model_price_histories DROP COLUMN IF EXISTS currency",
+		"ALTER TABLE car_model_price_histories ALTER COLUMN price TYPE decimal(10,2)",
+	})
+}
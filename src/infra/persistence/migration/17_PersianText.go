@@ -0,0 +1,146 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// normalizePersian17 normalizes like common.NormalizePersian and must stay in sync with it, text is
+// stored normalized so the dynamic filters compare plain columns and use their indexes
+const normalizePersian17 string = `CREATE OR REPLACE FUNCTION normalize_persian(value text) RETURNS text AS $$
+		SELECT translate(value,
+			'يىك٠١٢٣٤٥٦٧٨٩۰۱۲۳۴۵۶۷۸۹' || chr(8204) || chr(1600),
+			'ییک01234567890123456789 ')
+	$$ LANGUAGE sql IMMUTABLE`
+
+// refreshCarModelSearch17 lower cases and collapses white spaces of the document itself, the stored
+// text is already normalized
+const refreshCarModelSearch17 string = `CREATE OR REPLACE FUNCTION refresh_car_model_search(p_car_model_id integer) RETURNS void AS $$
+	BEGIN
+		DELETE FROM car_model_search WHERE car_model_id = p_car_model_id;
+		INSERT INTO car_model_search (car_model_id, document, search_vector)
+		SELECT cm.id,
+			lower(btrim(regexp_replace(concat_ws(' ', co.name, cm.name, ct.name, g.name, p.values), '\s+', ' ', 'g'))),
+			setweight(to_tsvector('simple', concat_ws(' ', co.name, cm.name)), 'A') ||
+			setweight(to_tsvector('simple', concat_ws(' ', ct.name, g.name)), 'B') ||
+			setweight(to_tsvector('simple', coalesce(p.values, '')), 'C')
+		FROM car_models cm
+		JOIN companies co ON co.id = cm.company_id
+		JOIN car_types ct ON ct.id = cm.car_type_id
+		JOIN gearboxes g ON g.id = cm.gearbox_id
+		LEFT JOIN LATERAL (
+			SELECT string_agg(cmp.value, ' ') AS values
+			FROM car_model_properties cmp
+			WHERE cmp.car_model_id = cm.id AND cmp.deleted_by IS NULL
+		) p ON true
+		WHERE cm.id = p_car_model_id AND cm.deleted_by IS NULL;
+	END;
+	$$ LANGUAGE plpgsql`
+
+// persianName is a name that is unique in practice within its partition, e.g. a city in its country,
+// and is matched by the catalog import regardless of spelling and case
+type persianName struct {
+	table     string
+	column    string
+	partition string
+}
+
+var persianNames = []persianName{
+	{table: "countries", column: "name"},
+	{table: "cities", column: "name", partition: "country_id"},
+	{table: "companies", column: "name"},
+	{table: "car_types", column: "name"},
+	{table: "gearboxes", column: "name"},
+	{table: "colors", column: "name"},
+	{table: "persian_years", column: "persian_title"},
+	{table: "property_categories", column: "name"},
+	{table: "properties", column: "name", partition: "category_id"},
+	{table: "car_models", column: "name", partition: "tenant_id"},
+}
+
+// persianText are the text columns users write and filter on
+var persianText = []struct {
+	table   string
+	columns []string
+}{
+	{"countries", []string{"name"}},
+	{"cities", []string{"name"}},
+	{"companies", []string{"name"}},
+	{"car_types", []string{"name"}},
+	{"gearboxes", []string{"name"}},
+	{"colors", []string{"name"}},
+	{"persian_years", []string{"persian_title"}},
+	{"property_categories", []string{"name"}},
+	{"properties", []string{"name", "description", "unit", "allowed_values"}},
+	{"car_models", []string{"name"}},
+	{"car_model_properties", []string{"value"}},
+	{"car_model_comments", []string{"message", "moderation_note"}},
+	{"files", []string{"name", "description"}},
+	{"listings", []string{"description", "contact_name", "moderation_note"}},
+	{"watchlists", []string{"name"}},
+	{"tenants", []string{"name"}},
+	{"users", []string{"first_name", "last_name"}},
+}
+
+// collisionQuery returns a row per group of names that only differ by spelling, case or spacing within
+// their partition, e.g. "Kia (3), KIA (9)"
+func collisionQuery(name persianName) string {
+	partition := fmt.Sprintf(`lower(regexp_replace(btrim(normalize_persian(%s)), '\s+', ' ', 'g'))`, name.column)
+	if name.partition != "" {
+		partition = name.partition + ", " + partition
+	}
+	return fmt.Sprintf(`SELECT string_agg(%[2]s || ' (' || id || ')', ', ' ORDER BY id) AS names FROM %[1]s
+		WHERE deleted_by IS NULL GROUP BY %[3]s HAVING count(*) > 1`, name.table, name.column, partition)
+}
+
+// persianNameCollisions fails with every group of names that would collide after normalization, they
+// are merged by hand before migrating since other rows reference them
+func persianNameCollisions(database *gorm.DB) error {
+	collisions := []string{}
+	for _, name := range persianNames {
+		groups := []string{}
+		if err := database.Raw(collisionQuery(name)).Scan(&groups).Error; err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+		for _, group := range groups {
+			collisions = append(collisions, fmt.Sprintf("%s.%s: %s", name.table, name.column, group))
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("names collide after persian normalization, merge them and migrate again: %s", strings.Join(collisions, "; "))
+	}
+	return nil
+}
+
+// Up17 merges normalize_persian_chars into normalize_persian and normalizes the stored text, it fails
+// without changes when names collide after normalization
+func Up17(database *gorm.DB) error {
+	if err := execStatements(database, []string{normalizePersian17, refreshCarModelSearch17}); err != nil {
+		return err
+	}
+	if err := persianNameCollisions(database); err != nil {
+		return err
+	}
+	statements := []string{}
+	for _, text := range persianText {
+		for _, column := range text.columns {
+			statements = append(statements, fmt.Sprintf(
+				"UPDATE %[1]s SET %[2]s = normalize_persian(%[2]s) WHERE %[2]s IS DISTINCT FROM normalize_persian(%[2]s)",
+				text.table, column))
+		}
+	}
+	statements = append(statements,
+		"DROP FUNCTION IF EXISTS normalize_persian_chars(text)",
+		"SELECT refresh_car_model_search(id) FROM car_models",
+	)
+	return execStatements(database, statements)
+}
+
+// Down17 restores the functions, the normalized text is kept
+func Down17(database *gorm.DB) error {
+	return execStatements(database, []string{normalizePersianChars14, normalizePersian2, refreshCarModelSearch2})
+}
@@ -5,8 +5,6 @@ import (
 
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/constant"
-	models "github.com/naeemaei/golang-clean-web-api/domain/model"
-	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
 	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -16,56 +14,63 @@ const countStarExp = "count(*)"
 
 var logger = logging.NewLogger(config.GetConfig())
 
-func Up1() {
-	database := database.GetDb()
-
-	createTables(database)
-	createDefaultUserInformation(database)
-	createCountry(database)
-	createPropertyCategory(database)
-	createCarType(database)
-	createGearbox(database)
-	createColor(database)
-	createYear(database)
-
+func Up1(database *gorm.DB) error {
+	seeds := []func(*gorm.DB) error{
+		createTables,
+		createDefaultUserInformation,
+		createCountry,
+		createPropertyCategory,
+		createCarType,
+		createGearbox,
+		createColor,
+		createYear,
+	}
+	for _, seed := range seeds {
+		if err := seed(database); err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	return nil
 }
 
-func createTables(database *gorm.DB) {
+func createTables(database *gorm.DB) error {
 	tables := []interface{}{}
 
 	// Basic
-	tables = addNewTable(database, models.Country{}, tables)
-	tables = addNewTable(database, models.City{}, tables)
-	tables = addNewTable(database, models.File{}, tables)
-	tables = addNewTable(database, models.PersianYear{}, tables)
+	tables = addNewTable(database, initCountry{}, tables)
+	tables = addNewTable(database, initCity{}, tables)
+	tables = addNewTable(database, initFile{}, tables)
+	tables = addNewTable(database, initPersianYear{}, tables)
 	// Property
-	tables = addNewTable(database, models.PropertyCategory{}, tables)
-	tables = addNewTable(database, models.Property{}, tables)
+	tables = addNewTable(database, initPropertyCategory{}, tables)
+	tables = addNewTable(database, initProperty{}, tables)
 
 	// User
-	tables = addNewTable(database, models.User{}, tables)
-	tables = addNewTable(database, models.Role{}, tables)
-	tables = addNewTable(database, models.UserRole{}, tables)
+	tables = addNewTable(database, initUser{}, tables)
+	tables = addNewTable(database, initRole{}, tables)
+	tables = addNewTable(database, initUserRole{}, tables)
 
 	// Car
-	tables = addNewTable(database, models.Company{}, tables)
-	tables = addNewTable(database, models.Gearbox{}, tables)
-	tables = addNewTable(database, models.Color{}, tables)
-	tables = addNewTable(database, models.CarType{}, tables)
-
-	tables = addNewTable(database, models.CarModel{}, tables)
-	tables = addNewTable(database, models.CarModelColor{}, tables)
-	tables = addNewTable(database, models.CarModelYear{}, tables)
-	tables = addNewTable(database, models.CarModelImage{}, tables)
-	tables = addNewTable(database, models.CarModelPriceHistory{}, tables)
-	tables = addNewTable(database, models.CarModelProperty{}, tables)
-	tables = addNewTable(database, models.CarModelComment{}, tables)
+	tables = addNewTable(database, initCompany{}, tables)
+	tables = addNewTable(database, initGearbox{}, tables)
+	tables = addNewTable(database, initColor{}, tables)
+	tables = addNewTable(database, initCarType{}, tables)
+
+	tables = addNewTable(database, initCarModel{}, tables)
+	tables = addNewTable(database, initCarModelColor{}, tables)
+	tables = addNewTable(database, initCarModelYear{}, tables)
+	tables = addNewTable(database, initCarModelImage{}, tables)
+	tables = addNewTable(database, initCarModelPriceHistory{}, tables)
+	tables = addNewTable(database, initCarModelProperty{}, tables)
+	tables = addNewTable(database, initCarModelComment{}, tables)
 
 	err := database.Migrator().CreateTable(tables...)
 	if err != nil {
-		logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+		return err
 	}
 	logger.Info(logging.Postgres, logging.Migration, "tables created", nil)
+	return nil
 }
 
 func addNewTable(database *gorm.DB, model interface{}, tables []interface{}) []interface{} {
@@ -75,172 +80,216 @@ func addNewTable(database *gorm.DB, model interface{}, tables []interface{}) []i
 	return tables
 }
 
-func createDefaultUserInformation(database *gorm.DB) {
+func createDefaultUserInformation(database *gorm.DB) error {
 
-	adminRole := models.Role{Name: constant.AdminRoleName}
-	createRoleIfNotExists(database, &adminRole)
+	adminRole := initRole{Name: constant.AdminRoleName}
+	if err := createRoleIfNotExists(database, &adminRole); err != nil {
+		return err
+	}
 
-	defaultRole := models.Role{Name: constant.DefaultRoleName}
-	createRoleIfNotExists(database, &defaultRole)
+	defaultRole := initRole{Name: constant.DefaultRoleName}
+	if err := createRoleIfNotExists(database, &defaultRole); err != nil {
+		return err
+	}
 
-	u := models.User{Username: constant.DefaultUserName, FirstName: "Test", LastName: "Test",
+	u := initUser{Username: constant.DefaultUserName, FirstName: "Test", LastName: "Test",
 		MobileNumber: "09111112222", Email: "admin@admin.com"}
 	pass := "12345678"
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
 	u.Password = string(hashedPassword)
 
-	createAdminUserIfNotExists(database, &u, adminRole.Id)
-
+	return createAdminUserIfNotExists(database, &u, adminRole.Id)
 }
 
-func createRoleIfNotExists(database *gorm.DB, r *models.Role) {
-	exists := 0
-	database.
-		Model(&models.Role{}).
-		Select("1").
+// createRoleIfNotExists fills the id of r from the existing role when it is already created
+func createRoleIfNotExists(database *gorm.DB, r *initRole) error {
+	existing := []initRole{}
+	err := database.
 		Where("name = ?", r.Name).
-		First(&exists)
-	if exists == 0 {
-		database.Create(r)
+		Limit(1).
+		Find(&existing).
+		Error
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		r.Id = existing[0].Id
+		return nil
 	}
+	return database.Create(r).Error
 }
 
-func createAdminUserIfNotExists(database *gorm.DB, u *models.User, roleId int) {
-	exists := 0
-	database.
-		Model(&models.User{}).
-		Select("1").
+func createAdminUserIfNotExists(database *gorm.DB, u *initUser, roleId int) error {
+	var exists int64
+	err := database.
+		Model(&initUser{}).
 		Where("username = ?", u.Username).
-		First(&exists)
-	if exists == 0 {
-		database.Create(u)
-		ur := models.UserRole{UserId: u.Id, RoleId: roleId}
-		database.Create(&ur)
+		Count(&exists).
+		Error
+	if err != nil || exists > 0 {
+		return err
 	}
+	if err := database.Create(u).Error; err != nil {
+		return err
+	}
+	return database.Create(&initUserRole{UserId: u.Id, RoleId: roleId}).Error
 }
 
-func createCountry(database *gorm.DB) {
-	count := 0
-	database.
-		Model(&models.Country{}).
-		Select(countStarExp).
-		Find(&count)
-	if count == 0 {
-		database.Create(&models.Country{Name: "Iran", Cities: []models.City{
+// countRows is the guard of the seeds, a table that already has rows is not seeded again
+func countRows(database *gorm.DB, model interface{}) (int64, error) {
+	var count int64
+	err := database.
+		Model(model).
+		Count(&count).
+		Error
+	return count, err
+}
+
+func createCountry(database *gorm.DB) error {
+	count, err := countRows(database, &initCountry{})
+	if err != nil || count > 0 {
+		return err
+	}
+	countries := []initCountry{
+		{Name: "Iran", Cities: []initCity{
 			{Name: "Tehran"},
 			{Name: "Isfahan"},
 			{Name: "Shiraz"},
 			{Name: "Chalus"},
 			{Name: "Ahwaz"},
-		}, Companies: []models.Company{
+		}, Companies: []initCompany{
 			{Name: "Saipa"},
 			{Name: "Iran khodro"},
-		}})
-		database.Create(&models.Country{Name: "USA", Cities: []models.City{
+		}},
+		{Name: "USA", Cities: []initCity{
 			{Name: "New York"},
 			{Name: "Washington"},
-		}, Companies: []models.Company{
+		}, Companies: []initCompany{
 			{Name: "Tesla"},
 			{Name: "Jeep"},
-		}})
-		database.Create(&models.Country{Name: "Germany", Cities: []models.City{
+		}},
+		{Name: "Germany", Cities: []initCity{
 			{Name: "Berlin"},
 			{Name: "Munich"},
-		}, Companies: []models.Company{
+		}, Companies: []initCompany{
 			{Name: "Opel"},
 			{Name: "Benz"},
-		}})
-		database.Create(&models.Country{Name: "China", Cities: []models.City{
+		}},
+		{Name: "China", Cities: []initCity{
 			{Name: "Beijing"},
 			{Name: "Shanghai"},
-		}, Companies: []models.Company{
+		}, Companies: []initCompany{
 			{Name: "Chery"},
 			{Name: "Geely"},
-		}})
-		database.Create(&models.Country{Name: "Italy", Cities: []models.City{
+		}},
+		{Name: "Italy", Cities: []initCity{
 			{Name: "Roma"},
 			{Name: "Turin"},
-		}, Companies: []models.Company{
+		}, Companies: []initCompany{
 			{Name: "Ferrari"},
 			{Name: "Fiat"},
-		}})
-		database.Create(&models.Country{Name: "France", Cities: []models.City{
+		}},
+		{Name: "France", Cities: []initCity{
 			{Name: "Paris"},
 			{Name: "Lyon"},
-		}, Companies: []models.Company{
+		}, Companies: []initCompany{
 			{Name: "Renault"},
 			{Name: "Bugatti"},
-		}})
-		database.Create(&models.Country{Name: "Japan", Cities: []models.City{
+		}},
+		{Name: "Japan", Cities: []initCity{
 			{Name: "Tokyo"},
 			{Name: "Kyoto"},
-		}, Companies: []models.Company{
+		}, Companies: []initCompany{
 			{Name: "Toyota"},
 			{Name: "Honda"},
-		}})
-		database.Create(&models.Country{Name: "South Korea", Cities: []models.City{
+		}},
+		{Name: "South Korea", Cities: []initCity{
 			{Name: "Seoul"},
 			{Name: "Ulsan"},
-		}, Companies: []models.Company{
+		}, Companies: []initCompany{
 			{Name: "Kia"},
 			{Name: "Hyundai"},
-		}})
+		}},
+	}
+	for i := range countries {
+		if err := database.Create(&countries[i]).Error; err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func createPropertyCategory(database *gorm.DB) {
-	count := 0
-
-	database.
-		Model(&models.PropertyCategory{}).
-		Select(countStarExp).
-		Find(&count)
+func createPropertyCategory(database *gorm.DB) error {
+	count, err := countRows(database, &initPropertyCategory{})
+	if err != nil {
+		return err
+	}
 	if count == 0 {
-		database.Create(&models.PropertyCategory{Name: "Body"})                     // بدنه
-		database.Create(&models.PropertyCategory{Name: "Engine"})                   // موتور
-		database.Create(&models.PropertyCategory{Name: "Drivetrain"})               // پیشرانه
-		database.Create(&models.PropertyCategory{Name: "Suspension"})               // تعلیق
-		database.Create(&models.PropertyCategory{Name: "Equipment"})                // تجهیزات
-		database.Create(&models.PropertyCategory{Name: "Driver support systems"})   // سیستم های پشتیبانی راننده
-		database.Create(&models.PropertyCategory{Name: "Lights"})                   // چراغ ها
-		database.Create(&models.PropertyCategory{Name: "Multimedia"})               // چند رسانه ای
-		database.Create(&models.PropertyCategory{Name: "Safety equipment"})         // تجهیزات ایمنی
-		database.Create(&models.PropertyCategory{Name: "Seats and steering wheel"}) // صندلی و فرمان
-		database.Create(&models.PropertyCategory{Name: "Windows and mirrors"})      // پنجره و آینه
+		categories := []initPropertyCategory{
+			{Name: "Body"},                     // بدنه
+			{Name: "Engine"},                   // موتور
+			{Name: "Drivetrain"},               // پیشرانه
+			{Name: "Suspension"},               // تعلیق
+			{Name: "Equipment"},                // تجهیزات
+			{Name: "Driver support systems"},   // سیستم های پشتیبانی راننده
+			{Name: "Lights"},                   // چراغ ها
+			{Name: "Multimedia"},               // چند رسانه ای
+			{Name: "Safety equipment"},         // تجهیزات ایمنی
+			{Name: "Seats and steering wheel"}, // صندلی و فرمان
+			{Name: "Windows and mirrors"},      // پنجره و آینه
+		}
+		for i := range categories {
+			if err := database.Create(&categories[i]).Error; err != nil {
+				return err
+			}
+		}
 	}
-	createProperty(database, "Body")
-	createProperty(database, "Engine")
-	createProperty(database, "Drivetrain")
-	createProperty(database, "Suspension")
-	createProperty(database, "Comfort")
-	createProperty(database, "Driver support systems")
-	createProperty(database, "Lights")
-	createProperty(database, "Multimedia")
-	createProperty(database, "Safety equipment")
-	createProperty(database, "Seats and steering wheel")
-	createProperty(database, "Windows and mirrors")
-
+	categories := []string{
+		"Body",
+		"Engine",
+		"Drivetrain",
+		"Suspension",
+		"Comfort",
+		"Driver support systems",
+		"Lights",
+		"Multimedia",
+		"Safety equipment",
+		"Seats and steering wheel",
+		"Windows and mirrors",
+	}
+	for _, cat := range categories {
+		if err := createProperty(database, cat); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func createProperty(database *gorm.DB, cat string) {
-	count := 0
-	catModel := models.PropertyCategory{}
-
-	database.
-		Model(models.PropertyCategory{}).
+func createProperty(database *gorm.DB, cat string) error {
+	categories := []initPropertyCategory{}
+	err := database.
 		Where("name = ?", cat).
-		Find(&catModel)
+		Limit(1).
+		Find(&categories).
+		Error
+	if err != nil || len(categories) == 0 {
+		return err
+	}
+	catModel := categories[0]
 
-	database.
-		Model(&models.Property{}).
-		Select(countStarExp).
+	var count int64
+	err = database.
+		Model(&initProperty{}).
 		Where("category_id = ?", catModel.Id).
-		Find(&count)
-
-	if count > 0 || catModel.Id == 0 {
-		return
+		Count(&count).
+		Error
+	if err != nil || count > 0 {
+		return err
 	}
-	var props *[]models.Property
+	var props *[]initProperty
 	switch cat {
 	case "Body":
 		props = getBodyProperties(catModel.Id)
@@ -276,106 +325,133 @@ func createProperty(database *gorm.DB, cat string) {
 		props = getWindowsProperties(catModel.Id)
 
 	default:
-		props = &([]models.Property{})
+		props = &([]initProperty{})
 	}
 
 	for _, prop := range *props {
-		database.Create(&prop)
+		if err := database.Create(&prop).Error; err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func createCarType(database *gorm.DB) {
-	count := 0
-	database.
-		Model(&models.CarType{}).
-		Select(countStarExp).
-		Find(&count)
-	if count == 0 {
-		database.Create(&models.CarType{Name: "Crossover"})
-		database.Create(&models.CarType{Name: "Sedan"})
-		database.Create(&models.CarType{Name: "Sports"})
-		database.Create(&models.CarType{Name: "Coupe"})
-		database.Create(&models.CarType{Name: "Hatchback"})
+func createCarType(database *gorm.DB) error {
+	count, err := countRows(database, &initCarType{})
+	if err != nil || count > 0 {
+		return err
 	}
+	return database.Create(&[]initCarType{
+		{Name: "Crossover"},
+		{Name: "Sedan"},
+		{Name: "Sports"},
+		{Name: "Coupe"},
+		{Name: "Hatchback"},
+	}).Error
 }
 
-func createGearbox(database *gorm.DB) {
-	count := 0
-	database.
-		Model(&models.Gearbox{}).
-		Select(countStarExp).
-		Find(&count)
-	if count == 0 {
-		database.Create(&models.Gearbox{Name: "Manual"})
-		database.Create(&models.Gearbox{Name: "Automatic"})
+func createGearbox(database *gorm.DB) error {
+	count, err := countRows(database, &initGearbox{})
+	if err != nil || count > 0 {
+		return err
 	}
+	return database.Create(&[]initGearbox{
+		{Name: "Manual"},
+		{Name: "Automatic"},
+	}).Error
 }
 
-func createColor(database *gorm.DB) {
-	count := 0
-	database.
-		Model(&models.Color{}).
-		Select(countStarExp).
-		Find(&count)
-	if count == 0 {
-		database.Create(&models.Color{Name: "Black", HexCode: "#000000"})
-		database.Create(&models.Color{Name: "White", HexCode: "#ffffff"})
-		database.Create(&models.Color{Name: "Blue", HexCode: "#0000ff"})
+func createColor(database *gorm.DB) error {
+	count, err := countRows(database, &initColor{})
+	if err != nil || count > 0 {
+		return err
 	}
+	return database.Create(&[]initColor{
+		{Name: "Black", HexCode: "#000000"},
+		{Name: "White", HexCode: "#ffffff"},
+		{Name: "Blue", HexCode: "#0000ff"},
+	}).Error
 }
 
-func createYear(database *gorm.DB) {
-	count := 0
-	database.
-		Model(&models.PersianYear{}).
-		Select(countStarExp).
-		Find(&count)
-	if count == 0 {
-
-		database.Create(&models.PersianYear{
+func createYear(database *gorm.DB) error {
+	count, err := countRows(database, &initPersianYear{})
+	if err != nil || count > 0 {
+		return err
+	}
+	return database.Create(&[]initPersianYear{
+		{
 			PersianTitle: "1402",
 			Year:         1402,
 			StartAt:      time.Date(2023, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
 			EndAt:        time.Date(2024, time.Month(3), 20, 0, 0, 0, 0, time.UTC),
-		})
-
-		database.Create(&models.PersianYear{
+		},
+		{
 			PersianTitle: "1401",
 			Year:         1401,
 			StartAt:      time.Date(2022, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
 			EndAt:        time.Date(2023, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
-		})
-
-		database.Create(&models.PersianYear{
+		},
+		{
 			PersianTitle: "1400",
 			Year:         1400,
 			StartAt:      time.Date(2021, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
 			EndAt:        time.Date(2022, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
-		})
-
-		database.Create(&models.PersianYear{
+		},
+		{
 			PersianTitle: "1399",
 			Year:         1399,
 			StartAt:      time.Date(2020, time.Month(3), 20, 0, 0, 0, 0, time.UTC),
 			EndAt:        time.Date(2021, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
-		})
-
-		database.Create(&models.PersianYear{
+		},
+		{
 			PersianTitle: "1398",
 			Year:         1398,
 			StartAt:      time.Date(2019, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
 			EndAt:        time.Date(2020, time.Month(3), 20, 0, 0, 0, 0, time.UTC),
-		})
-
-		database.Create(&models.PersianYear{
-			PersianTitle: "1398",
-			Year:         1398,
+		},
+		{
+			// was seeded as a second 1398, which the unique title and year reject
+			PersianTitle: "1397",
+			Year:         1397,
 			StartAt:      time.Date(2018, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
 			EndAt:        time.Date(2019, time.Month(3), 21, 0, 0, 0, 0, time.UTC),
-		})
-	}
+		},
+	}).Error
 }
 
-func Down1() {
-	// nothing
+func Down1(database *gorm.DB) error {
+	// Reverse order of createTables so foreign keys are dropped before their targets
+	tables := []interface{}{
+		initCarModelComment{},
+		initCarModelProperty{},
+		initCarModelPriceHistory{},
+		initCarModelImage{},
+		initCarModelYear{},
+		initCarModelColor{},
+		initCarModel{},
+
+		initCarType{},
+		initColor{},
+		initGearbox{},
+		initCompany{},
+
+		initUserRole{},
+		initRole{},
+		initUser{},
+
+		initProperty{},
+		initPropertyCategory{},
+
+		initPersianYear{},
+		initFile{},
+		initCity{},
+		initCountry{},
+	}
+	err := database.Migrator().DropTable(tables...)
+	if err != nil {
+		logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+		return err
+	}
+	logger.Info(logging.Postgres, logging.Migration, "tables dropped", nil)
+	return nil
 }
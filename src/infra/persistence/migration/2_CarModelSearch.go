@@ -0,0 +1,132 @@
+package migration
+
+import (
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// normalizePersian2 lower cases, collapses white spaces and normalizes like common.NormalizePersian,
+// migration 17 replaced it and restores it on down
+const normalizePersian2 string = `CREATE OR REPLACE FUNCTION normalize_persian(value text) RETURNS text AS $$
+		SELECT lower(btrim(regexp_replace(
+			translate(coalesce(value, ''),
+				'يىك٠١٢٣٤٥٦٧٨٩۰۱۲۳۴۵۶۷۸۹' || chr(8204) || chr(1600),
+				'ییک01234567890123456789 '),
+			'\s+', ' ', 'g')))
+	$$ LANGUAGE sql IMMUTABLE`
+
+// refreshCarModelSearch2 indexes a car model, migration 17 replaced it and restores it on down
+const refreshCarModelSearch2 string = `CREATE OR REPLACE FUNCTION refresh_car_model_search(p_car_model_id integer) RETURNS void AS $$
+	BEGIN
+		DELETE FROM car_model_search WHERE car_model_id = p_car_model_id;
+		INSERT INTO car_model_search (car_model_id, document, search_vector)
+		SELECT cm.id,
+			normalize_persian(concat_ws(' ', co.name, cm.name, ct.name, g.name, p.values)),
+			setweight(to_tsvector('simple', normalize_persian(concat_ws(' ', co.name, cm.name))), 'A') ||
+			setweight(to_tsvector('simple', normalize_persian(concat_ws(' ', ct.name, g.name))), 'B') ||
+			setweight(to_tsvector('simple', normalize_persian(p.values)), 'C')
+		FROM car_models cm
+		JOIN companies co ON co.id = cm.company_id
+		JOIN car_types ct ON ct.id = cm.car_type_id
+		JOIN gearboxes g ON g.id = cm.gearbox_id
+		LEFT JOIN LATERAL (
+			SELECT string_agg(cmp.value, ' ') AS values
+			FROM car_model_properties cmp
+			WHERE cmp.car_model_id = cm.id AND cmp.deleted_by IS NULL
+		) p ON true
+		WHERE cm.id = p_car_model_id AND cm.deleted_by IS NULL;
+	END;
+	$$ LANGUAGE plpgsql`
+
+// Full text search index of car models over company, model, car type, gearbox and property values
+var up2Statements = []string{
+	normalizePersian2,
+
+	`CREATE TABLE IF NOT EXISTS car_model_search (
+		car_model_id integer PRIMARY KEY REFERENCES car_models(id),
+		document text NOT NULL,
+		search_vector tsvector NOT NULL
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_car_model_search_vector ON car_model_search USING GIN (search_vector)`,
+
+	refreshCarModelSearch2,
+
+	`CREATE OR REPLACE FUNCTION car_models_search_trigger() RETURNS trigger AS $$
+	BEGIN
+		PERFORM refresh_car_model_search(NEW.id);
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql`,
+
+	`CREATE OR REPLACE FUNCTION car_model_properties_search_trigger() RETURNS trigger AS $$
+	BEGIN
+		IF TG_OP = 'DELETE' THEN
+			PERFORM refresh_car_model_search(OLD.car_model_id);
+			RETURN NULL;
+		END IF;
+		PERFORM refresh_car_model_search(NEW.car_model_id);
+		IF TG_OP = 'UPDATE' AND OLD.car_model_id <> NEW.car_model_id THEN
+			PERFORM refresh_car_model_search(OLD.car_model_id);
+		END IF;
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql`,
+
+	`CREATE OR REPLACE FUNCTION car_model_lookups_search_trigger() RETURNS trigger AS $$
+	BEGIN
+		PERFORM refresh_car_model_search(cm.id)
+		FROM car_models cm
+		WHERE (TG_TABLE_NAME = 'companies' AND cm.company_id = NEW.id)
+			OR (TG_TABLE_NAME = 'car_types' AND cm.car_type_id = NEW.id)
+			OR (TG_TABLE_NAME = 'gearboxes' AND cm.gearbox_id = NEW.id);
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql`,
+
+	`CREATE TRIGGER car_models_search AFTER INSERT OR UPDATE ON car_models
+		FOR EACH ROW EXECUTE FUNCTION car_models_search_trigger()`,
+	`CREATE TRIGGER car_model_properties_search AFTER INSERT OR UPDATE OR DELETE ON car_model_properties
+		FOR EACH ROW EXECUTE FUNCTION car_model_properties_search_trigger()`,
+	`CREATE TRIGGER companies_search AFTER UPDATE OF name ON companies
+		FOR EACH ROW EXECUTE FUNCTION car_model_lookups_search_trigger()`,
+	`CREATE TRIGGER car_types_search AFTER UPDATE OF name ON car_types
+		FOR EACH ROW EXECUTE FUNCTION car_model_lookups_search_trigger()`,
+	`CREATE TRIGGER gearboxes_search AFTER UPDATE OF name ON gearboxes
+		FOR EACH ROW EXECUTE FUNCTION car_model_lookups_search_trigger()`,
+
+	// Index existing car models
+	`SELECT refresh_car_model_search(id) FROM car_models`,
+}
+
+var down2Statements = []string{
+	`DROP TRIGGER IF EXISTS gearboxes_search ON gearboxes`,
+	`DROP TRIGGER IF EXISTS car_types_search ON car_types`,
+	`DROP TRIGGER IF EXISTS companies_search ON companies`,
+	`DROP TRIGGER IF EXISTS car_model_properties_search ON car_model_properties`,
+	`DROP TRIGGER IF EXISTS car_models_search ON car_models`,
+	`DROP FUNCTION IF EXISTS car_model_lookups_search_trigger()`,
+	`DROP FUNCTION IF EXISTS car_model_properties_search_trigger()`,
+	`DROP FUNCTION IF EXISTS car_models_search_trigger()`,
+	`DROP FUNCTION IF EXISTS refresh_car_model_search(integer)`,
+	`DROP TABLE IF EXISTS car_model_search`,
+	`DROP FUNCTION IF EXISTS normalize_persian(text)`,
+}
+
+func Up2(database *gorm.DB) error {
+	return execStatements(database, up2Statements)
+}
+
+func Down2(database *gorm.DB) error {
+	return execStatements(database, down2Statements)
+}
+
+func execStatements(database *gorm.DB, statements []string) error {
+	for _, statement := range statements {
+		if err := database.Exec(statement).Error; err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Tables of entities that embed model.TenantModel, existing rows move to the platform tenant.
+// The default only fills the existing rows, new rows always get the tenant of the request
+var tenantScopedTables = []string{
+	"users",
+	"files",
+	"car_models",
+	"car_model_colors",
+	"car_model_years",
+	"car_model_images",
+	"car_model_price_histories",
+	"car_model_properties",
+	"car_model_comments",
+}
+
+func Up3(database *gorm.DB) error {
+	if !database.Migrator().HasTable(&models.Tenant{}) {
+		if err := database.Migrator().CreateTable(&models.Tenant{}); err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	if err := createDefaultTenant(database); err != nil {
+		return err
+	}
+	// the name of the tenant admin role does not fit in the size of the init migration
+	if err := database.Exec("ALTER TABLE roles ALTER COLUMN name TYPE varchar(20)").Error; err != nil {
+		logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+		return err
+	}
+	if err := createRoleIfNotExists(database, &initRole{Name: constant.TenantAdminRoleName}); err != nil {
+		logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+		return err
+	}
+
+	statements := []string{}
+	for _, table := range tenantScopedTables {
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS tenant_id integer NOT NULL DEFAULT %d", table, constant.DefaultTenantId),
+			// rows inserted with a zero tenant by a model that already had the column
+			fmt.Sprintf("UPDATE %s SET tenant_id = %d WHERE tenant_id = 0", table, constant.DefaultTenantId),
+			fmt.Sprintf("ALTER TABLE %s ALTER COLUMN tenant_id DROP DEFAULT", table),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_tenant_id ON %s (tenant_id)", table, table),
+			fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS fk_%s_tenant, ADD CONSTRAINT fk_%s_tenant FOREIGN KEY (tenant_id) REFERENCES tenants(id)", table, table, table),
+		)
+	}
+	return execStatements(database, statements)
+}
+
+func Down3(database *gorm.DB) error {
+	statements := []string{}
+	for i := len(tenantScopedTables) - 1; i >= 0; i-- {
+		table := tenantScopedTables[i]
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS fk_%s_tenant", table, table),
+			fmt.Sprintf("DROP INDEX IF EXISTS idx_%s_tenant_id", table),
+			fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS tenant_id", table),
+		)
+	}
+	statements = append(statements,
+		fmt.Sprintf("DELETE FROM user_roles WHERE role_id IN (SELECT id FROM roles WHERE name = '%s')", constant.TenantAdminRoleName),
+		fmt.Sprintf("DELETE FROM roles WHERE name = '%s'", constant.TenantAdminRoleName),
+		"ALTER TABLE roles ALTER COLUMN name TYPE varchar(10)",
+		"DROP TABLE IF EXISTS tenants",
+	)
+	return execStatements(database, statements)
+}
+
+func createDefaultTenant(database *gorm.DB) error {
+	exists := 0
+	database.
+		Model(&models.Tenant{}).
+		Select(countStarExp).
+		Where("id = ?", constant.DefaultTenantId).
+		Find(&exists)
+	if exists > 0 {
+		return nil
+	}
+	tenant := models.Tenant{Name: "Default", Slug: constant.DefaultTenantSlug, Enabled: true}
+	tenant.Id = constant.DefaultTenantId
+	if err := database.Create(&tenant).Error; err != nil {
+		logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+		return err
+	}
+	// the id was set explicitly, so move the sequence past it
+	return database.Exec("SELECT setval(pg_get_serial_sequence('tenants', 'id'), (SELECT max(id) FROM tenants))").Error
+}
@@ -0,0 +1,22 @@
+package migration
+
+import (
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+func Up4(database *gorm.DB) error {
+	if database.Migrator().HasTable(&models.OutboxMessage{}) {
+		return nil
+	}
+	if err := database.Migrator().CreateTable(&models.OutboxMessage{}); err != nil {
+		logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+		return err
+	}
+	return nil
+}
+
+func Down4(database *gorm.DB) error {
+	return execStatements(database, []string{"DROP TABLE IF EXISTS outbox_messages"})
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+func Up5(database *gorm.DB) error {
+	tables := []interface{}{&models.WebhookSubscription{}, &models.WebhookDelivery{}}
+	for _, table := range tables {
+		if database.Migrator().HasTable(table) {
+			continue
+		}
+		if err := database.Migrator().CreateTable(table); err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	return nil
+}
+
+func Down5(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP TABLE IF EXISTS webhook_deliveries",
+		"DROP TABLE IF EXISTS webhook_subscriptions",
+	})
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+func Up6(database *gorm.DB) error {
+	tables := []interface{}{&models.PriceAlert{}, &models.Notification{}}
+	for _, table := range tables {
+		if database.Migrator().HasTable(table) {
+			continue
+		}
+		if err := database.Migrator().CreateTable(table); err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	return nil
+}
+
+func Down6(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP TABLE IF EXISTS notifications",
+		"DROP TABLE IF EXISTS price_alerts",
+	})
+}
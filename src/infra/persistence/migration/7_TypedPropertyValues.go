@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"gorm.io/gorm"
+)
+
+// Up7 adds the bounds and allowed values of properties and the numeric form of property values,
+// existing numbers and bools get their numeric form and unknown data types become string
+func Up7(database *gorm.DB) error {
+	dataTypes := "'" + strings.Join(models.PropertyTypes, "','") + "'"
+	return execStatements(database, []string{
+		"ALTER TABLE properties ADD COLUMN IF NOT EXISTS min_value decimal(18,4), ADD COLUMN IF NOT EXISTS max_value decimal(18,4), " +
+			"ADD COLUMN IF NOT EXISTS allowed_values varchar(1000) NOT NULL DEFAULT ''",
+		"ALTER TABLE car_model_properties ADD COLUMN IF NOT EXISTS numeric_value decimal(18,4), ADD COLUMN IF NOT EXISTS numeric_max_value decimal(18,4)",
+		"CREATE INDEX IF NOT EXISTS idx_car_model_properties_numeric_value ON car_model_properties (numeric_value)",
+		fmt.Sprintf("UPDATE properties SET data_type = '%s' WHERE data_type NOT IN (%s)", models.PropertyTypeString, dataTypes),
+		fmt.Sprintf(`UPDATE car_model_properties cp SET value = trim(cp.value), numeric_value = trim(cp.value)::numeric, numeric_max_value = trim(cp.value)::numeric
+			FROM properties p WHERE p.id = cp.property_id AND p.data_type IN ('%s', '%s') AND trim(cp.value) ~ '^-?[0-9]+(\.[0-9]+)?$'`,
+			models.PropertyTypeInt, models.PropertyTypeDecimal),
+		fmt.Sprintf(`UPDATE car_model_properties cp SET value = 'true', numeric_value = 1, numeric_max_value = 1
+			FROM properties p WHERE p.id = cp.property_id AND p.data_type = '%s' AND lower(trim(cp.value)) IN ('true', 'yes', '1')`,
+			models.PropertyTypeBool),
+		fmt.Sprintf(`UPDATE car_model_properties cp SET value = 'false', numeric_value = 0, numeric_max_value = 0
+			FROM properties p WHERE p.id = cp.property_id AND p.data_type = '%s' AND lower(trim(cp.value)) IN ('false', 'no', '0')`,
+			models.PropertyTypeBool),
+	})
+}
+
+func Down7(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP INDEX IF EXISTS idx_car_model_properties_numeric_value",
+		"ALTER TABLE car_model_properties DROP COLUMN IF EXISTS numeric_value, DROP COLUMN IF EXISTS numeric_max_value",
+		"ALTER TABLE properties DROP COLUMN IF EXISTS min_value, DROP COLUMN IF EXISTS max_value, DROP COLUMN IF EXISTS allowed_values",
+	})
+}
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"fmt"
+
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Up8 adds the moderation status of comments and their reports, the comments written so far
+// were public, so they are approved
+func Up8(database *gorm.DB) error {
+	err := execStatements(database, []string{
+		fmt.Sprintf("ALTER TABLE car_model_comments ADD COLUMN IF NOT EXISTS status varchar(10) NOT NULL DEFAULT '%s', "+
+			"ADD COLUMN IF NOT EXISTS moderation_note varchar(500) NOT NULL DEFAULT '', ADD COLUMN IF NOT EXISTS moderated_at timestamptz, "+
+			"ADD COLUMN IF NOT EXISTS report_count bigint NOT NULL DEFAULT 0", models.CommentApproved),
+		fmt.Sprintf("ALTER TABLE car_model_comments ALTER COLUMN status SET DEFAULT '%s'", models.CommentPending),
+		"CREATE INDEX IF NOT EXISTS idx_car_model_comments_status ON car_model_comments (status)",
+	})
+	if err != nil {
+		return err
+	}
+	if database.Migrator().HasTable(&models.CarModelCommentReport{}) {
+		return nil
+	}
+	if err := database.Migrator().CreateTable(&models.CarModelCommentReport{}); err != nil {
+		logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+		return err
+	}
+	return nil
+}
+
+func Down8(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP TABLE IF EXISTS car_model_comment_reports",
+		"DROP INDEX IF EXISTS idx_car_model_comments_status",
+		"ALTER TABLE car_model_comments DROP COLUMN IF EXISTS status, DROP COLUMN IF EXISTS moderation_note, " +
+			"DROP COLUMN IF EXISTS moderated_at, DROP COLUMN IF EXISTS report_count",
+	})
+}
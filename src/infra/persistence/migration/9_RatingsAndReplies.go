@@ -0,0 +1,47 @@
+package migration
+
+import (
+	models "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Up9 adds comment replies and votes and car model ratings with their summary on car models
+func Up9(database *gorm.DB) error {
+	err := execStatements(database, []string{
+		"ALTER TABLE car_model_comments ADD COLUMN IF NOT EXISTS parent_id bigint REFERENCES car_model_comments (id), " +
+			"ADD COLUMN IF NOT EXISTS helpful_count bigint NOT NULL DEFAULT 0, ADD COLUMN IF NOT EXISTS unhelpful_count bigint NOT NULL DEFAULT 0",
+		"CREATE INDEX IF NOT EXISTS idx_car_model_comments_parent_id ON car_model_comments (parent_id)",
+		"ALTER TABLE car_models ADD COLUMN IF NOT EXISTS rating_count bigint NOT NULL DEFAULT 0, " +
+			"ADD COLUMN IF NOT EXISTS rating_average decimal(3,2) NOT NULL DEFAULT 0, " +
+			"ADD COLUMN IF NOT EXISTS one_star_count bigint NOT NULL DEFAULT 0, ADD COLUMN IF NOT EXISTS two_star_count bigint NOT NULL DEFAULT 0, " +
+			"ADD COLUMN IF NOT EXISTS three_star_count bigint NOT NULL DEFAULT 0, ADD COLUMN IF NOT EXISTS four_star_count bigint NOT NULL DEFAULT 0, " +
+			"ADD COLUMN IF NOT EXISTS five_star_count bigint NOT NULL DEFAULT 0",
+	})
+	if err != nil {
+		return err
+	}
+	tables := []interface{}{&models.CarModelCommentVote{}, &models.CarModelRating{}}
+	for _, table := range tables {
+		if database.Migrator().HasTable(table) {
+			continue
+		}
+		if err := database.Migrator().CreateTable(table); err != nil {
+			logger.Error(logging.Postgres, logging.Migration, err.Error(), nil)
+			return err
+		}
+	}
+	return nil
+}
+
+func Down9(database *gorm.DB) error {
+	return execStatements(database, []string{
+		"DROP TABLE IF EXISTS car_model_ratings",
+		"DROP TABLE IF EXISTS car_model_comment_votes",
+		"ALTER TABLE car_models DROP COLUMN IF EXISTS rating_count, DROP COLUMN IF EXISTS rating_average, " +
+			"DROP COLUMN IF EXISTS one_star_count, DROP COLUMN IF EXISTS two_star_count, DROP COLUMN IF EXISTS three_star_count, " +
+			"DROP COLUMN IF EXISTS four_star_count, DROP COLUMN IF EXISTS five_star_count",
+		"DROP INDEX IF EXISTS idx_car_model_comments_parent_id",
+		"ALTER TABLE car_model_comments DROP COLUMN IF EXISTS parent_id, DROP COLUMN IF EXISTS helpful_count, DROP COLUMN IF EXISTS unhelpful_count",
+	})
+}
@@ -1,9 +1,7 @@
 package migration
 
-import models "github.com/naeemaei/golang-clean-web-api/domain/model"
-
-func getBodyProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getBodyProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Height",
 			CategoryId:  cat,
@@ -28,8 +26,8 @@ func getBodyProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getEngineProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getEngineProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Displacement",
 			CategoryId:  cat,
@@ -66,8 +64,8 @@ func getEngineProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getDrivetrainProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getDrivetrainProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Gears",
 			CategoryId:  cat,
@@ -80,8 +78,8 @@ func getDrivetrainProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getSuspensionProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getSuspensionProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Front brakes",
 			CategoryId:  cat,
@@ -106,8 +104,8 @@ func getSuspensionProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getComfortProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getComfortProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Central lock",
 			CategoryId:  cat,
@@ -132,8 +130,8 @@ func getComfortProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getDriverSupportSystemProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getDriverSupportSystemProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Parking radar",
 			CategoryId:  cat,
@@ -164,8 +162,8 @@ func getDriverSupportSystemProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getLightsProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getLightsProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Low beam",
 			CategoryId:  cat,
@@ -196,8 +194,8 @@ func getLightsProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getMultimediaProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getMultimediaProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Bluetooth",
 			CategoryId:  cat,
@@ -210,8 +208,8 @@ func getMultimediaProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getSafetyEquipmentProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getSafetyEquipmentProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Driver front airbag",
 			CategoryId:  cat,
@@ -230,8 +228,8 @@ func getSafetyEquipmentProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getSeatsProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getSeatsProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Driver seat electric adjustment",
 			CategoryId:  cat,
@@ -250,8 +248,8 @@ func getSeatsProperties(cat int) *[]models.Property {
 	return &props
 }
 
-func getWindowsProperties(cat int) *[]models.Property {
-	var props []models.Property = []models.Property{
+func getWindowsProperties(cat int) *[]initProperty {
+	var props []initProperty = []initProperty{
 		{
 			Name:        "Power windows",
 			CategoryId:  cat,
@@ -0,0 +1,240 @@
+package migration
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Snapshot of the tables as the first migration created them. Later migrations alter these tables,
+// so the init migration must not follow the domain model
+
+// InitBaseModel is exported only because gorm skips the fields of unexported embedded structs
+type InitBaseModel struct {
+	Id int `gorm:"primarykey"`
+
+	CreatedAt  time.Time    `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+func (m *InitBaseModel) BeforeCreate(tx *gorm.DB) (err error) {
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = -1
+	return
+}
+
+type initCountry struct {
+	InitBaseModel
+	Name      string        `gorm:"size:15;type:string;not null;"`
+	Cities    []initCity    `gorm:"foreignKey:CountryId"`
+	Companies []initCompany `gorm:"foreignKey:CountryId"`
+}
+
+func (initCountry) TableName() string { return "countries" }
+
+type initCity struct {
+	InitBaseModel
+	Name      string `gorm:"size:10;type:string;not null;"`
+	CountryId int
+	Country   initCountry `gorm:"foreignKey:CountryId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+}
+
+func (initCity) TableName() string { return "cities" }
+
+type initPersianYear struct {
+	InitBaseModel
+	PersianTitle  string             `gorm:"size:10;type:string;not null;unique"`
+	Year          int                `gorm:"type:int;uniqueIndex;not null"`
+	StartAt       time.Time          `gorm:"type:TIMESTAMP with time zone;not null;unique"`
+	EndAt         time.Time          `gorm:"type:TIMESTAMP with time zone;not null;unique"`
+	CarModelYears []initCarModelYear `gorm:"foreignKey:PersianYearId"`
+}
+
+func (initPersianYear) TableName() string { return "persian_years" }
+
+type initColor struct {
+	InitBaseModel
+	Name           string              `gorm:"size:15;type:string;not null,unique"`
+	HexCode        string              `gorm:"size:7;type:string;not null,unique"`
+	CarModelColors []initCarModelColor `gorm:"foreignKey:ColorId"`
+}
+
+func (initColor) TableName() string { return "colors" }
+
+type initFile struct {
+	InitBaseModel
+	Name        string `gorm:"size:100;type:string;not null"`
+	Directory   string `gorm:"size:100;type:string;not null"`
+	Description string `gorm:"size:500;type:string;not null"`
+	MimeType    string `gorm:"size:20;type:string;not null"`
+}
+
+func (initFile) TableName() string { return "files" }
+
+type initPropertyCategory struct {
+	InitBaseModel
+	Name       string         `gorm:"size:50;type:string;not null,unique;"`
+	Icon       string         `gorm:"size:1000;type:string;not null,unique;"`
+	Properties []initProperty `gorm:"foreignKey:CategoryId"`
+}
+
+func (initPropertyCategory) TableName() string { return "property_categories" }
+
+type initProperty struct {
+	InitBaseModel
+	Name        string               `gorm:"size:50;type:string;not null,unique;"`
+	Icon        string               `gorm:"size:1000;type:string;not null,unique;"`
+	Category    initPropertyCategory `gorm:"foreignKey:CategoryId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CategoryId  int
+	Description string `gorm:"size:1000;type:string;not null,unique;"`
+	DataType    string `gorm:"size:15;type:string;not null,unique;"`
+	Unit        string `gorm:"size:15;type:string;not null,unique;"`
+}
+
+func (initProperty) TableName() string { return "properties" }
+
+type initUser struct {
+	InitBaseModel
+	Username     string          `gorm:"type:string;size:20;not null;unique"`
+	FirstName    string          `gorm:"type:string;size:15;null"`
+	LastName     string          `gorm:"type:string;size:25;null"`
+	MobileNumber string          `gorm:"type:string;size:11;null;unique;default:null"`
+	Email        string          `gorm:"type:string;size:64;null;unique;default:null"`
+	Password     string          `gorm:"type:string;size:64;not null"`
+	Enabled      bool            `gorm:"default:true"`
+	UserRoles    *[]initUserRole `gorm:"foreignKey:UserId"`
+}
+
+func (initUser) TableName() string { return "users" }
+
+type initRole struct {
+	InitBaseModel
+	Name      string          `gorm:"type:string;size:10;not null,unique"`
+	UserRoles *[]initUserRole `gorm:"foreignKey:RoleId"`
+}
+
+func (initRole) TableName() string { return "roles" }
+
+type initUserRole struct {
+	InitBaseModel
+	User   initUser `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	Role   initRole `gorm:"foreignKey:RoleId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId int
+	RoleId int
+}
+
+func (initUserRole) TableName() string { return "user_roles" }
+
+type initGearbox struct {
+	InitBaseModel
+	Name      string         `gorm:"size:15;type:string;not null,unique;"`
+	CarModels []initCarModel `gorm:"foreignKey:GearboxId"`
+}
+
+func (initGearbox) TableName() string { return "gearboxes" }
+
+type initCarType struct {
+	InitBaseModel
+	Name      string         `gorm:"size:15;type:string;not null,unique;"`
+	CarModels []initCarModel `gorm:"foreignKey:CarTypeId"`
+}
+
+func (initCarType) TableName() string { return "car_types" }
+
+type initCompany struct {
+	InitBaseModel
+	Name      string      `gorm:"size:15;type:string;not null,unique;"`
+	Country   initCountry `gorm:"foreignKey:CountryId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CountryId int
+	CarModels []initCarModel `gorm:"foreignKey:CompanyId"`
+}
+
+func (initCompany) TableName() string { return "companies" }
+
+type initCarModel struct {
+	InitBaseModel
+	Name               string      `gorm:"size:15;type:string;not null,unique;"`
+	Company            initCompany `gorm:"foreignKey:CompanyId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CompanyId          int
+	CarType            initCarType `gorm:"foreignKey:CarTypeId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarTypeId          int
+	Gearbox            initGearbox `gorm:"foreignKey:GearboxId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	GearboxId          int
+	CarModelColors     []initCarModelColor    `gorm:"foreignKey:CarModelId"`
+	CarModelYears      []initCarModelYear     `gorm:"foreignKey:CarModelId"`
+	CarModelProperties []initCarModelProperty `gorm:"foreignKey:CarModelId"`
+	CarModelImages     []initCarModelImage    `gorm:"foreignKey:CarModelId"`
+	CarModelComments   []initCarModelComment  `gorm:"foreignKey:CarModelId"`
+}
+
+func (initCarModel) TableName() string { return "car_models" }
+
+type initCarModelColor struct {
+	InitBaseModel
+	CarModel   initCarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelId int          `gorm:"uniqueIndex:idx_CarModelId_ColorId"`
+	Color      initColor    `gorm:"foreignKey:ColorId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	ColorId    int          `gorm:"uniqueIndex:idx_CarModelId_ColorId"`
+}
+
+func (initCarModelColor) TableName() string { return "car_model_colors" }
+
+type initCarModelYear struct {
+	InitBaseModel
+	CarModel               initCarModel               `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelId             int                        `gorm:"uniqueIndex:idx_CarModelId_PersianYearId"`
+	PersianYear            initPersianYear            `gorm:"foreignKey:PersianYearId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	PersianYearId          int                        `gorm:"uniqueIndex:idx_CarModelId_PersianYearId"`
+	CarModelPriceHistories []initCarModelPriceHistory `gorm:"foreignKey:CarModelYearId"`
+}
+
+func (initCarModelYear) TableName() string { return "car_model_years" }
+
+type initCarModelImage struct {
+	InitBaseModel
+	CarModel    initCarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelId  int          `gorm:"uniqueIndex:idx_CarModelId_ImageId"`
+	Image       initFile     `gorm:"foreignKey:ImageId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	ImageId     int          `gorm:"uniqueIndex:idx_CarModelId_ImageId"`
+	IsMainImage bool
+}
+
+func (initCarModelImage) TableName() string { return "car_model_images" }
+
+type initCarModelPriceHistory struct {
+	InitBaseModel
+	CarModelYear   initCarModelYear `gorm:"foreignKey:CarModelYearId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelYearId int
+	Price          float64   `gorm:"type:decimal(10,2);not null"`
+	PriceAt        time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
+}
+
+func (initCarModelPriceHistory) TableName() string { return "car_model_price_histories" }
+
+type initCarModelProperty struct {
+	InitBaseModel
+	CarModel   initCarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelId int          `gorm:"uniqueIndex:idx_CarModelId_PropertyId"`
+	Property   initProperty `gorm:"foreignKey:PropertyId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	PropertyId int          `gorm:"uniqueIndex:idx_CarModelId_PropertyId"`
+	Value      string       `gorm:"size:1000,type:string;not null"`
+}
+
+func (initCarModelProperty) TableName() string { return "car_model_properties" }
+
+type initCarModelComment struct {
+	InitBaseModel
+	CarModel   initCarModel `gorm:"foreignKey:CarModelId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	CarModelId int
+	User       initUser `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId     int
+	Message    string `gorm:"size:500,type:string;not null"`
+}
+
+func (initCarModelComment) TableName() string { return "car_model_comments" }
@@ -0,0 +1,241 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Key of the postgres advisory lock that serializes concurrent migrators
+const migrationLockKey int64 = 2_024_042_901
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(database *gorm.DB) error
+	Down    func(database *gorm.DB) error
+}
+
+// New migrations must be appended with an increasing version
+var migrations = []Migration{
+	{Version: 1, Name: "init", Up: Up1, Down: Down1},
+	{Version: 2, Name: "car_model_search", Up: Up2, Down: Down2},
+	{Version: 3, Name: "tenants", Up: Up3, Down: Down3},
+	{Version: 4, Name: "outbox", Up: Up4, Down: Down4},
+	{Version: 5, Name: "webhooks", Up: Up5, Down: Down5},
+	{Version: 6, Name: "price_alerts", Up: Up6, Down: Down6},
+	{Version: 7, Name: "typed_property_values", Up: Up7, Down: Down7},
+	{Version: 8, Name: "comment_moderation", Up: Up8, Down: Down8},
+	{Version: 9, Name: "ratings_and_replies", Up: Up9, Down: Down9},
+	{Version: 10, Name: "watchlists", Up: Up10, Down: Down10},
+	{Version: 11, Name: "listings", Up: Up11, Down: Down11},
+	{Version: 12, Name: "car_model_image_gallery", Up: Up12, Down: Down12},
+	{Version: 13, Name: "persian_year_bounds", Up: Up13, Down: Down13},
+	{Version: 14, Name: "persian_filters", Up: Up14, Down: Down14},
+	{Version: 15, Name: "user_language", Up: Up15, Down: Down15},
+	{Version: 16, Name: "currencies", Up: Up16, Down: Down16},
+	{Version: 17, Name: "persian_text", Up: Up17, Down: Down17},
+}
+
+type SchemaMigration struct {
+	Version   int       `gorm:"primarykey;autoIncrement:false"`
+	Name      string    `gorm:"size:100;type:string;not null"`
+	AppliedAt time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	database   *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(database *gorm.DB) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{database: database, migrations: sorted}
+}
+
+// Up applies pending migrations in order, steps <= 0 applies all of them
+func (m *Migrator) Up(steps int) error {
+	return m.withLock(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		done := 0
+		for _, mg := range m.migrations {
+			if steps > 0 && done >= steps {
+				break
+			}
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			if err := m.apply(conn, mg); err != nil {
+				return err
+			}
+			done++
+		}
+		logger.Info(logging.Postgres, logging.Migration, fmt.Sprintf("%d migration(s) applied", done), nil)
+		return nil
+	})
+}
+
+// Down reverts the latest applied migrations, steps <= 0 reverts only the last one
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		steps = 1
+	}
+	return m.withLock(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		done := 0
+		for i := len(m.migrations) - 1; i >= 0 && done < steps; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			if err := m.revert(conn, mg); err != nil {
+				return err
+			}
+			done++
+		}
+		logger.Info(logging.Postgres, logging.Migration, fmt.Sprintf("%d migration(s) reverted", done), nil)
+		return nil
+	})
+}
+
+// Redo reverts and re-applies the latest applied migration
+func (m *Migrator) Redo() error {
+	return m.withLock(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			if err := m.revert(conn, mg); err != nil {
+				return err
+			}
+			return m.apply(conn, mg)
+		}
+		return nil
+	})
+}
+
+// Status only reads the schema, a database without schema_migrations has no migration applied
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied := map[int]SchemaMigration{}
+	if m.database.Migrator().HasTable(&SchemaMigration{}) {
+		var err error
+		if applied, err = appliedVersions(m.database); err != nil {
+			return nil, err
+		}
+	}
+	result := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		status := MigrationStatus{Version: mg.Version, Name: mg.Name}
+		if row, ok := applied[mg.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &row.AppliedAt
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// Pending returns count of migrations that are not applied yet
+func (m *Migrator) Pending() (int, error) {
+	status, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, item := range status {
+		if !item.Applied {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Migrator) apply(conn *gorm.DB, mg Migration) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := mg.Up(tx); err != nil {
+			return err
+		}
+		return tx.Create(&SchemaMigration{Version: mg.Version, Name: mg.Name, AppliedAt: time.Now().UTC()}).Error
+	})
+	if err != nil {
+		logger.Error(logging.Postgres, logging.Migration, fmt.Sprintf("up %d_%s: %s", mg.Version, mg.Name, err.Error()), nil)
+		return err
+	}
+	logger.Info(logging.Postgres, logging.Migration, fmt.Sprintf("up %d_%s", mg.Version, mg.Name), nil)
+	return nil
+}
+
+func (m *Migrator) revert(conn *gorm.DB, mg Migration) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if mg.Down != nil {
+			if err := mg.Down(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&SchemaMigration{}, mg.Version).Error
+	})
+	if err != nil {
+		logger.Error(logging.Postgres, logging.Migration, fmt.Sprintf("down %d_%s: %s", mg.Version, mg.Name, err.Error()), nil)
+		return err
+	}
+	logger.Info(logging.Postgres, logging.Migration, fmt.Sprintf("down %d_%s", mg.Version, mg.Name), nil)
+	return nil
+}
+
+// withLock runs fn on a single connection that holds the migration advisory lock, schema_migrations is created on first use
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.database.Connection(func(conn *gorm.DB) error {
+		// statements chained on the connection instance share their clauses, a session keeps them apart
+		conn = conn.Session(&gorm.Session{})
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+
+		if !conn.Migrator().HasTable(&SchemaMigration{}) {
+			if err := conn.Migrator().CreateTable(&SchemaMigration{}); err != nil {
+				return err
+			}
+		}
+		return fn(conn)
+	})
+}
+
+func appliedVersions(database *gorm.DB) (map[int]SchemaMigration, error) {
+	rows := []SchemaMigration{}
+	if err := database.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
@@ -0,0 +1,424 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// fakeDatabase is a database/sql driver that keeps the rows of schema_migrations and the names of the created tables
+// and accepts any other statement, enough for the migrator to run without postgres
+type fakeDatabase struct {
+	mu         sync.Mutex
+	versions   map[int64]fakeSchemaMigration
+	tables     map[string]bool
+	ids        map[string]int64
+	statements []string
+	locks      int
+	// results are the single column rows of the selects containing their key
+	results map[string][]driver.Value
+}
+
+type fakeSchemaMigration struct {
+	name      string
+	appliedAt time.Time
+}
+
+func newFakeDatabase(t *testing.T) (*fakeDatabase, *gorm.DB) {
+	fake := &fakeDatabase{versions: map[int64]fakeSchemaMigration{}, tables: map[string]bool{}, ids: map[string]int64{},
+		results: map[string][]driver.Value{}}
+	database, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(fake)}),
+		&gorm.Config{Logger: gormLogger.Default.LogMode(gormLogger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, database
+}
+
+func (f *fakeDatabase) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{database: f}, nil
+}
+
+func (f *fakeDatabase) Driver() driver.Driver {
+	return nil
+}
+
+func (f *fakeDatabase) applied() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]int64, 0, len(f.versions))
+	for version := range f.versions {
+		result = append(result, version)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+type fakeConn struct {
+	database *fakeDatabase
+	snapshot map[int64]fakeSchemaMigration
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.database.mu.Lock()
+	defer c.database.mu.Unlock()
+	c.snapshot = make(map[int64]fakeSchemaMigration, len(c.database.versions))
+	for version, row := range c.database.versions {
+		c.snapshot[version] = row
+	}
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.database.mu.Lock()
+	defer c.database.mu.Unlock()
+	c.database.versions = c.snapshot
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.database.mu.Lock()
+	defer c.database.mu.Unlock()
+	c.database.statements = append(c.database.statements, query)
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE "):
+		c.database.tables[quotedName(query, "CREATE TABLE ")] = true
+	case strings.Contains(query, "pg_advisory_lock"):
+		c.database.locks++
+	case strings.Contains(query, "pg_advisory_unlock"):
+		c.database.locks--
+	case strings.HasPrefix(query, `INSERT INTO "schema_migrations"`):
+		c.database.versions[args[0].Value.(int64)] = fakeSchemaMigration{name: args[1].Value.(string), appliedAt: args[2].Value.(time.Time)}
+	case strings.HasPrefix(query, `DELETE FROM "schema_migrations"`):
+		delete(c.database.versions, args[0].Value.(int64))
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.database.mu.Lock()
+	defer c.database.mu.Unlock()
+	switch {
+	case strings.Contains(query, "information_schema.tables"):
+		// only the created tables exist
+		count := int64(0)
+		for _, arg := range args {
+			if name, ok := arg.Value.(string); ok && c.database.tables[name] {
+				count = 1
+			}
+		}
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{count}}}, nil
+	case strings.HasPrefix(query, `SELECT * FROM "schema_migrations"`):
+		rows := &fakeRows{columns: []string{"version", "name", "applied_at"}}
+		for version, row := range c.database.versions {
+			rows.values = append(rows.values, []driver.Value{version, row.name, row.appliedAt})
+		}
+		sort.Slice(rows.values, func(i, j int) bool { return rows.values[i][0].(int64) < rows.values[j][0].(int64) })
+		return rows, nil
+	case strings.HasPrefix(query, "INSERT INTO ") && strings.Contains(query, " RETURNING "):
+		// every inserted row gets the next id of its table, the other returned columns are null
+		c.database.statements = append(c.database.statements, query)
+		table := quotedName(query, "INSERT INTO ")
+		columns := strings.Count(query[:strings.Index(query, ")")], ",") + 1
+		rows := &fakeRows{}
+		for _, column := range strings.Split(query[strings.LastIndex(query, " RETURNING ")+len(" RETURNING "):], ",") {
+			rows.columns = append(rows.columns, strings.Trim(column, `"`))
+		}
+		for i := 0; i < len(args)/columns; i++ {
+			c.database.ids[table]++
+			row := make([]driver.Value, len(rows.columns))
+			for j, column := range rows.columns {
+				if column == "id" {
+					row[j] = c.database.ids[table]
+				}
+			}
+			rows.values = append(rows.values, row)
+		}
+		return rows, nil
+	case strings.HasPrefix(query, "SELECT count(*)"):
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{int64(0)}}}, nil
+	case strings.HasPrefix(query, "SELECT "):
+		for key, values := range c.database.results {
+			if strings.Contains(query, key) {
+				rows := &fakeRows{columns: []string{"value"}}
+				for _, value := range values {
+					rows.values = append(rows.values, []driver.Value{value})
+				}
+				return rows, nil
+			}
+		}
+		// the other tables are always empty
+		return &fakeRows{}, nil
+	}
+	return nil, fmt.Errorf("unexpected query %s", query)
+}
+
+// quotedName returns the first quoted identifier after prefix
+func quotedName(query string, prefix string) string {
+	rest := strings.TrimPrefix(query, prefix)
+	if start := strings.Index(rest, `"`); start >= 0 {
+		if end := strings.Index(rest[start+1:], `"`); end >= 0 {
+			return rest[start+1 : start+1+end]
+		}
+	}
+	return ""
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+// recorder builds migrations that log their up and down calls
+type recorder struct {
+	calls []string
+	fail  map[string]bool
+}
+
+func (r *recorder) migration(version int) Migration {
+	step := func(direction string) func(*gorm.DB) error {
+		return func(*gorm.DB) error {
+			call := fmt.Sprintf("%s %d", direction, version)
+			if r.fail[call] {
+				return errors.New(call + " failed")
+			}
+			r.calls = append(r.calls, call)
+			return nil
+		}
+	}
+	return Migration{Version: version, Name: fmt.Sprintf("m%d", version), Up: step("up"), Down: step("down")}
+}
+
+func newTestMigrator(t *testing.T, versions ...int) (*Migrator, *fakeDatabase, *recorder) {
+	fake, database := newFakeDatabase(t)
+	r := &recorder{fail: map[string]bool{}}
+	m := &Migrator{database: database}
+	for _, version := range versions {
+		m.migrations = append(m.migrations, r.migration(version))
+	}
+	return m, fake, r
+}
+
+func TestMigrationsAreOrdered(t *testing.T) {
+	for i, mg := range migrations {
+		if mg.Up == nil || mg.Down == nil || mg.Name == "" {
+			t.Errorf("migration %d must have a name, up and down", mg.Version)
+		}
+		if mg.Version != i+1 {
+			t.Errorf("migration %d_%s is at position %d, versions must increase by one", mg.Version, mg.Name, i+1)
+		}
+	}
+}
+
+func TestMigratorUp(t *testing.T) {
+	m, fake, r := newTestMigrator(t, 1, 2, 3)
+
+	if err := m.Up(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.applied(); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Fatalf("applied %v, want [1 2]", got)
+	}
+	if err := m.Up(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(0); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"up 1", "up 2", "up 3"}; !reflect.DeepEqual(r.calls, want) {
+		t.Fatalf("calls %v, want %v", r.calls, want)
+	}
+	if fake.locks != 0 {
+		t.Fatalf("advisory lock held %d time(s) after up", fake.locks)
+	}
+
+	pending, err := m.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != 0 {
+		t.Fatalf("pending %d, want 0", pending)
+	}
+}
+
+func TestMigratorUpStopsAtFailure(t *testing.T) {
+	m, fake, r := newTestMigrator(t, 1, 2, 3)
+	r.fail["up 2"] = true
+
+	if err := m.Up(0); err == nil {
+		t.Fatal("up must return the error of migration 2")
+	}
+	if got := fake.applied(); !reflect.DeepEqual(got, []int64{1}) {
+		t.Fatalf("applied %v, want [1]", got)
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range status {
+		if item.Applied != (item.Version == 1) {
+			t.Errorf("migration %d applied %v", item.Version, item.Applied)
+		}
+	}
+}
+
+func TestMigratorStatusDoesNotChangeSchema(t *testing.T) {
+	m, fake, _ := newTestMigrator(t, 1, 2)
+
+	pending, err := m.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != 2 {
+		t.Fatalf("pending %d, want 2", pending)
+	}
+	if len(fake.statements) != 0 {
+		t.Fatalf("status executed %v, it must only read", fake.statements)
+	}
+}
+
+func TestMigratorDown(t *testing.T) {
+	m, fake, r := newTestMigrator(t, 1, 2, 3)
+	if err := m.Up(0); err != nil {
+		t.Fatal(err)
+	}
+	r.calls = nil
+
+	if err := m.Down(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.applied(); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Fatalf("applied %v after down, want [1 2]", got)
+	}
+	if err := m.Down(5); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.applied(); len(got) != 0 {
+		t.Fatalf("applied %v after down 5, want none", got)
+	}
+	if want := []string{"down 3", "down 2", "down 1"}; !reflect.DeepEqual(r.calls, want) {
+		t.Fatalf("calls %v, want %v", r.calls, want)
+	}
+}
+
+func TestMigratorDownKeepsVersionOnFailure(t *testing.T) {
+	m, fake, r := newTestMigrator(t, 1, 2)
+	if err := m.Up(0); err != nil {
+		t.Fatal(err)
+	}
+	r.fail["down 2"] = true
+
+	if err := m.Down(1); err == nil {
+		t.Fatal("down must return the error of migration 2")
+	}
+	if got := fake.applied(); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Fatalf("applied %v, want [1 2]", got)
+	}
+}
+
+func TestMigratorRedo(t *testing.T) {
+	m, fake, r := newTestMigrator(t, 1, 2, 3)
+	if err := m.Up(2); err != nil {
+		t.Fatal(err)
+	}
+	r.calls = nil
+
+	if err := m.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"down 2", "up 2"}; !reflect.DeepEqual(r.calls, want) {
+		t.Fatalf("calls %v, want %v", r.calls, want)
+	}
+	if got := fake.applied(); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Fatalf("applied %v, want [1 2]", got)
+	}
+}
+
+func TestMigrationsUpFromEmptySchema(t *testing.T) {
+	fake, database := newFakeDatabase(t)
+
+	if err := NewMigrator(database).Up(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(fake.applied()); got != len(migrations) {
+		t.Fatalf("applied %d migration(s), want %d", got, len(migrations))
+	}
+
+	// the existing rows must move to the default tenant before the foreign key checks them
+	position := func(prefix string) int {
+		for i, statement := range fake.statements {
+			if strings.HasPrefix(statement, prefix) {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, table := range tenantScopedTables {
+		backfill := position(fmt.Sprintf("UPDATE %s SET tenant_id = 1 WHERE tenant_id = 0", table))
+		foreignKey := position(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS fk_%s_tenant, ADD CONSTRAINT", table, table))
+		if backfill < 0 || foreignKey < 0 || backfill > foreignKey {
+			t.Errorf("%s: backfill at %d, foreign key at %d", table, backfill, foreignKey)
+		}
+	}
+}
+
+func TestUp17FailsOnCollidingNames(t *testing.T) {
+	fake, database := newFakeDatabase(t)
+	fake.results["FROM companies"] = []driver.Value{"Kia (3), KIA (9)"}
+
+	err := Up17(database)
+	if err == nil || !strings.Contains(err.Error(), "companies.name: Kia (3), KIA (9)") {
+		t.Fatalf("up 17 failed with %v, want the colliding companies", err)
+	}
+	for _, statement := range fake.statements {
+		if strings.HasPrefix(statement, "UPDATE ") {
+			t.Fatalf("up 17 changed rows before failing: %s", statement)
+		}
+	}
+}
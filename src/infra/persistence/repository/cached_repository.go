@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	filter "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	contractRepository "github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/infra/cache"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+)
+
+// CachedRepository decorates a BaseRepository and caches GetById and GetByFilter results in redis.
+// Every cache key embeds the current version of the entity tag and its dependency tags,
+// a write bumps the entity tag version so all previously cached entries become unreachable.
+type CachedRepository[TEntity any] struct {
+	next     contractRepository.BaseRepository[TEntity]
+	redis    *redis.Client
+	logger   logging.Logger
+	ttl      time.Duration
+	typeName string
+	tags     []string
+	// tenant scoped entities are cached per tenant
+	tenantScoped bool
+}
+
+type cachedPage[TEntity any] struct {
+	Total int64
+	Items []TEntity
+}
+
+// NewCachedRepository wraps next with a redis cache. dependsOn lists the entity names
+// of preloaded relations, a write on any of them invalidates this cache too.
+func NewCachedRepository[TEntity any](cfg *config.Config, next contractRepository.BaseRepository[TEntity], dependsOn ...string) *CachedRepository[TEntity] {
+	typeName := reflect.TypeOf(*new(TEntity)).Name()
+	ttl, ok := cfg.Cache.Ttl[strings.ToLower(typeName)]
+	if !ok {
+		ttl = cfg.Cache.DefaultTtl
+	}
+	return &CachedRepository[TEntity]{
+		next:     next,
+		redis:    cache.GetRedis(),
+		logger:   logging.NewLogger(cfg),
+		ttl:      ttl * time.Second,
+		typeName: typeName,
+		tags:     append([]string{typeName}, dependsOn...),
+
+		tenantScoped: database.IsTenantScoped[TEntity](),
+	}
+}
+
+func (r CachedRepository[TEntity]) Create(ctx context.Context, entity TEntity) (TEntity, error) {
+	entity, err := r.next.Create(ctx, entity)
+	if err == nil {
+		r.invalidate()
+	}
+	return entity, err
+}
+
+func (r CachedRepository[TEntity]) Update(ctx context.Context, id int, entity map[string]interface{}) (TEntity, error) {
+	result, err := r.next.Update(ctx, id, entity)
+	if err == nil {
+		r.invalidate()
+	}
+	return result, err
+}
+
+func (r CachedRepository[TEntity]) Delete(ctx context.Context, id int) error {
+	err := r.next.Delete(ctx, id)
+	if err == nil {
+		r.invalidate()
+	}
+	return err
+}
+
+func (r CachedRepository[TEntity]) GetById(ctx context.Context, id int) (TEntity, error) {
+	key, err := r.key(ctx, fmt.Sprintf("id:%d", id))
+	if err == nil {
+		if entity, err := cache.Get[TEntity](r.redis, key); err == nil {
+			metrics.CacheCall.WithLabelValues(r.typeName, "GetById", "Hit").Inc()
+			return entity, nil
+		}
+	}
+	metrics.CacheCall.WithLabelValues(r.typeName, "GetById", "Miss").Inc()
+
+	entity, err := r.next.GetById(ctx, id)
+	if err != nil || key == "" {
+		return entity, err
+	}
+	r.store(key, entity)
+	return entity, nil
+}
+
+func (r CachedRepository[TEntity]) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]TEntity, error) {
+	// apply paging defaults so equal requests share the same key
+	req.GetPageNumber()
+	req.GetPageSize()
+	reqJson, _ := json.Marshal(req)
+	hash := sha1.Sum(reqJson)
+
+	key, err := r.key(ctx, "filter:"+hex.EncodeToString(hash[:]))
+	if err == nil {
+		if page, err := cache.Get[cachedPage[TEntity]](r.redis, key); err == nil {
+			metrics.CacheCall.WithLabelValues(r.typeName, "GetByFilter", "Hit").Inc()
+			return page.Total, &page.Items, nil
+		}
+	}
+	metrics.CacheCall.WithLabelValues(r.typeName, "GetByFilter", "Miss").Inc()
+
+	total, items, err := r.next.GetByFilter(ctx, req)
+	if err != nil || key == "" {
+		return total, items, err
+	}
+	page := cachedPage[TEntity]{Total: total, Items: []TEntity{}}
+	if items != nil {
+		page.Items = *items
+	}
+	r.store(key, page)
+	return total, items, nil
+}
+
+// key builds the cache key from the versions of all tags
+func (r CachedRepository[TEntity]) key(ctx context.Context, suffix string) (string, error) {
+	versionKeys := make([]string, 0, len(r.tags))
+	for _, tag := range r.tags {
+		versionKeys = append(versionKeys, versionKey(tag))
+	}
+	versions, err := r.redis.MGet(versionKeys...).Result()
+	if err != nil {
+		metrics.CacheCall.WithLabelValues(r.typeName, "Version", "Failed").Inc()
+		r.logger.Error(logging.Redis, logging.Select, err.Error(), nil)
+		return "", err
+	}
+	parts := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if v == nil {
+			v = "0"
+		}
+		parts = append(parts, fmt.Sprint(v))
+	}
+	if r.tenantScoped {
+		suffix = fmt.Sprintf("t%d:%s", database.TenantId(ctx), suffix)
+	}
+	return fmt.Sprintf("%s:%s:v%s:%s", constant.RedisRepositoryKey, r.typeName, strings.Join(parts, "."), suffix), nil
+}
+
+func (r CachedRepository[TEntity]) store(key string, value any) {
+	if err := cache.Set(r.redis, key, value, r.ttl); err != nil {
+		r.logger.Error(logging.Redis, logging.Insert, err.Error(), nil)
+	}
+}
+
+func (r CachedRepository[TEntity]) invalidate() {
+	if err := r.redis.Incr(versionKey(r.typeName)).Err(); err != nil {
+		metrics.CacheCall.WithLabelValues(r.typeName, "Invalidate", "Failed").Inc()
+		r.logger.Error(logging.Redis, logging.Update, err.Error(), nil)
+		return
+	}
+	metrics.CacheCall.WithLabelValues(r.typeName, "Invalidate", "Success").Inc()
+}
+
+func versionKey(tag string) string {
+	return fmt.Sprintf("%s:%s:version", constant.RedisRepositoryKey, tag)
+}
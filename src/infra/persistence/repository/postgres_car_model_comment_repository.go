@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reportCommentExp counts a report on an approved comment and sends the comment back to moderation
+// when it reaches the threshold
+const reportCommentExp string = `UPDATE car_model_comments SET report_count = report_count + 1,
+	status = CASE WHEN report_count + 1 >= @threshold THEN @pending ELSE status END
+	WHERE id = @id AND tenant_id = @tenant AND status = @approved AND deleted_by IS NULL`
+
+// lockCommentExp locks a comment of the tenant so its votes are counted one vote at a time
+const lockCommentExp string = `SELECT id FROM car_model_comments
+	WHERE id = @id AND tenant_id = @tenant AND deleted_by IS NULL AND (status = @approved OR NOT @approvedOnly) FOR UPDATE`
+
+const countVotesExp string = `UPDATE car_model_comments SET
+	helpful_count = (SELECT count(*) FROM car_model_comment_votes WHERE car_model_comment_id = @id AND helpful AND deleted_by IS NULL),
+	unhelpful_count = (SELECT count(*) FROM car_model_comment_votes WHERE car_model_comment_id = @id AND NOT helpful AND deleted_by IS NULL)
+	WHERE id = @id`
+
+type PostgresCarModelCommentRepository struct {
+	*BaseRepository[model.CarModelComment]
+}
+
+func NewCarModelCommentRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresCarModelCommentRepository {
+	return &PostgresCarModelCommentRepository{BaseRepository: NewBaseRepository[model.CarModelComment](cfg, preloads)}
+}
+
+func (r *PostgresCarModelCommentRepository) AddReport(ctx context.Context, report model.CarModelCommentReport, threshold int) error {
+	report.TenantId = database.TenantId(ctx)
+	err := r.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(reportCommentExp, map[string]interface{}{
+			"id": report.CarModelCommentId, "tenant": report.TenantId, "threshold": threshold,
+			"pending": model.CommentPending, "approved": model.CommentApproved,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+		res = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.CommentAlreadyReported}
+		}
+		return nil
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return err
+	}
+	if err == nil {
+		database.MarkWrite(ctx)
+	}
+	return dbResult[model.CarModelComment](r.logger, err, "AddReport", logging.Insert)
+}
+
+func (r *PostgresCarModelCommentRepository) Vote(ctx context.Context, vote model.CarModelCommentVote) error {
+	vote.TenantId = database.TenantId(ctx)
+	err := r.countVotes(ctx, vote.CarModelCommentId, true, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "car_model_comment_id"}, {Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"helpful":     vote.Helpful,
+				"modified_by": &sql.NullInt64{Int64: int64(vote.UserId), Valid: true},
+				"modified_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+				"deleted_by":  nil,
+				"deleted_at":  nil,
+			}),
+		}).Create(&vote).Error
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return err
+	}
+	return dbResult[model.CarModelCommentVote](r.logger, err, "Vote", logging.Insert)
+}
+
+func (r *PostgresCarModelCommentRepository) RemoveVote(ctx context.Context, commentId int, userId int) error {
+	err := r.countVotes(ctx, commentId, false, func(tx *gorm.DB) error {
+		res := tx.Model(&model.CarModelCommentVote{}).
+			Where("car_model_comment_id = ? AND user_id = ? AND deleted_by IS NULL", commentId, userId).
+			Updates(map[string]interface{}{
+				"deleted_by": &sql.NullInt64{Int64: int64(ctx.Value(constant.UserIdKey).(float64)), Valid: true},
+				"deleted_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+			})
+		if res.Error == nil && res.RowsAffected == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+		return res.Error
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return err
+	}
+	return dbResult[model.CarModelCommentVote](r.logger, err, "RemoveVote", logging.Update)
+}
+
+// countVotes runs change on the votes of a locked comment and counts them again
+func (r *PostgresCarModelCommentRepository) countVotes(ctx context.Context, commentId int, approvedOnly bool, change func(tx *gorm.DB) error) error {
+	err := r.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []int{}
+		err := tx.Raw(lockCommentExp, map[string]interface{}{
+			"id": commentId, "tenant": database.TenantId(ctx), "approved": model.CommentApproved, "approvedOnly": approvedOnly,
+		}).Scan(&ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+		if err = change(tx); err != nil {
+			return err
+		}
+		return tx.Exec(countVotesExp, map[string]interface{}{"id": commentId}).Error
+	})
+	if err == nil {
+		database.MarkWrite(ctx)
+	}
+	return err
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"gorm.io/gorm"
+)
+
+type PostgresCarModelImageRepository struct {
+	*BaseRepository[model.CarModelImage]
+}
+
+func NewCarModelImageRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresCarModelImageRepository {
+	return &PostgresCarModelImageRepository{BaseRepository: NewBaseRepository[model.CarModelImage](cfg, preloads)}
+}
+
+// Create adds the image to the end of the gallery, the first image of a car model is always the main image
+func (r *PostgresCarModelImageRepository) Create(ctx context.Context, image model.CarModelImage) (model.CarModelImage, error) {
+	image.TenantId = database.TenantId(ctx)
+	if err := r.checkReferences(ctx, database.TenantReferences(image)); err != nil {
+		return image, err
+	}
+	err := r.gallery(ctx, image.CarModelId, func(tx *gorm.DB, images []model.CarModelImage) error {
+		image.SortOrder = 1
+		if len(images) == 0 {
+			image.IsMainImage = true
+		} else {
+			image.SortOrder = images[len(images)-1].SortOrder + 1
+		}
+		if image.IsMainImage {
+			if err := r.demote(ctx, tx, images); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&image).Error; err != nil {
+			return err
+		}
+		if r.events {
+			return writeEvents(ctx, tx, event.EntityCreated, image, nil)
+		}
+		return nil
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return image, err
+	}
+	return image, dbResult[model.CarModelImage](r.logger, err, "Create", logging.Insert)
+}
+
+// Delete removes the image, the first remaining image becomes main when the main image is removed
+func (r *PostgresCarModelImageRepository) Delete(ctx context.Context, id int) error {
+	if ctx.Value(constant.UserIdKey) == nil {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
+	}
+	carModelId, err := r.carModelId(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = r.gallery(ctx, carModelId, func(tx *gorm.DB, images []model.CarModelImage) error {
+		i := slices.IndexFunc(images, func(image model.CarModelImage) bool { return image.Id == id })
+		if i < 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+		err := tx.Model(&model.CarModelImage{}).Where(softDeleteExp, id).Updates(map[string]interface{}{
+			"deleted_by": &sql.NullInt64{Int64: int64(ctx.Value(constant.UserIdKey).(float64)), Valid: true},
+			"deleted_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+		}).Error
+		if err == nil && r.events {
+			err = writeRowEvents[model.CarModelImage](ctx, tx, event.EntityDeleted, id, nil)
+		}
+		if err != nil || !images[i].IsMainImage {
+			return err
+		}
+		images = slices.Delete(images, i, i+1)
+		if len(images) == 0 {
+			return nil
+		}
+		return r.change(ctx, tx, images[0].Id, map[string]interface{}{"IsMainImage": true})
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return err
+	}
+	return dbResult[model.CarModelImage](r.logger, err, "Delete", logging.Update)
+}
+
+func (r *PostgresCarModelImageRepository) SetMain(ctx context.Context, id int) error {
+	carModelId, err := r.carModelId(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = r.gallery(ctx, carModelId, func(tx *gorm.DB, images []model.CarModelImage) error {
+		i := slices.IndexFunc(images, func(image model.CarModelImage) bool { return image.Id == id })
+		if i < 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+		if images[i].IsMainImage {
+			return nil
+		}
+		// the previous main image is demoted first, the unique index allows one main image at a time
+		if err := r.demote(ctx, tx, images); err != nil {
+			return err
+		}
+		return r.change(ctx, tx, id, map[string]interface{}{"IsMainImage": true})
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return err
+	}
+	return dbResult[model.CarModelImage](r.logger, err, "SetMain", logging.Update)
+}
+
+func (r *PostgresCarModelImageRepository) Reorder(ctx context.Context, carModelId int, ids []int) error {
+	err := r.gallery(ctx, carModelId, func(tx *gorm.DB, images []model.CarModelImage) error {
+		if !sameImages(images, ids) {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.CarModelImageOrderInvalid}
+		}
+		for i, id := range ids {
+			image := images[slices.IndexFunc(images, func(image model.CarModelImage) bool { return image.Id == id })]
+			if image.SortOrder == i+1 {
+				continue
+			}
+			if err := r.change(ctx, tx, id, map[string]interface{}{"SortOrder": i + 1}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return err
+	}
+	return dbResult[model.CarModelImage](r.logger, err, "Reorder", logging.Update)
+}
+
+// gallery runs change on the not deleted images of a locked car model ordered by SortOrder
+func (r *PostgresCarModelImageRepository) gallery(ctx context.Context, carModelId int, change func(tx *gorm.DB, images []model.CarModelImage) error) error {
+	err := r.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []int{}
+		if err := tx.Raw(lockCarModelExp, map[string]interface{}{"id": carModelId, "tenant": database.TenantId(ctx)}).Scan(&ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+		images := []model.CarModelImage{}
+		err := tx.Where("car_model_id = ? AND deleted_by IS NULL", carModelId).
+			Order("sort_order, id").
+			Find(&images).
+			Error
+		if err != nil {
+			return err
+		}
+		return change(tx, images)
+	})
+	if err == nil {
+		database.MarkWrite(ctx)
+	}
+	return err
+}
+
+// demote clears the main image of a gallery
+func (r *PostgresCarModelImageRepository) demote(ctx context.Context, tx *gorm.DB, images []model.CarModelImage) error {
+	for _, image := range images {
+		if !image.IsMainImage {
+			continue
+		}
+		if err := r.change(ctx, tx, image.Id, map[string]interface{}{"IsMainImage": false}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgresCarModelImageRepository) change(ctx context.Context, tx *gorm.DB, id int, values map[string]interface{}) error {
+	snakeMap := map[string]interface{}{}
+	for k, v := range values {
+		snakeMap[common.ToSnakeCase(k)] = v
+	}
+	snakeMap["modified_by"] = &sql.NullInt64{Int64: int64(ctx.Value(constant.UserIdKey).(float64)), Valid: true}
+	snakeMap["modified_at"] = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	if err := tx.Model(&model.CarModelImage{}).Where(softDeleteExp, id).Updates(snakeMap).Error; err != nil {
+		return err
+	}
+	if r.events {
+		return writeRowEvents[model.CarModelImage](ctx, tx, event.EntityUpdated, id, fieldNames(values))
+	}
+	return nil
+}
+
+func (r *PostgresCarModelImageRepository) carModelId(ctx context.Context, id int) (int, error) {
+	ids := []int{}
+	err := r.scope(ctx, r.database.WithContext(ctx).Model(&model.CarModelImage{})).
+		Where(softDeleteExp, id).
+		Pluck("car_model_id", &ids).
+		Error
+	if err != nil {
+		return 0, dbResult[model.CarModelImage](r.logger, err, "GetById", logging.Select)
+	}
+	if len(ids) == 0 {
+		return 0, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return ids[0], nil
+}
+
+// sameImages tells whether ids are the ids of images, each one once
+func sameImages(images []model.CarModelImage, ids []int) bool {
+	if len(images) != len(ids) {
+		return false
+	}
+	for _, image := range images {
+		if !slices.Contains(ids, image.Id) {
+			return false
+		}
+	}
+	return true
+}
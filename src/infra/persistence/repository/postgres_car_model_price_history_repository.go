@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+)
+
+const carModelYearPricesExp string = "car_model_year_id = ?"
+
+const carModelPricesExp string = "car_model_year_id IN (SELECT id FROM car_model_years WHERE car_model_id = ? AND deleted_by IS NULL)"
+
+type PostgresCarModelPriceHistoryRepository struct {
+	*BaseRepository[model.CarModelPriceHistory]
+}
+
+func NewCarModelPriceHistoryRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresCarModelPriceHistoryRepository {
+	return &PostgresCarModelPriceHistoryRepository{BaseRepository: NewBaseRepository[model.CarModelPriceHistory](cfg, preloads)}
+}
+
+func (r *PostgresCarModelPriceHistoryRepository) GetByCarModelYear(ctx context.Context, carModelYearId int) ([]model.CarModelPriceHistory, error) {
+	return r.prices(ctx, "GetByCarModelYear", &model.CarModelYear{}, carModelYearId, carModelYearPricesExp)
+}
+
+func (r *PostgresCarModelPriceHistoryRepository) GetByCarModel(ctx context.Context, carModelId int) ([]model.CarModelPriceHistory, error) {
+	return r.prices(ctx, "GetByCarModel", &model.CarModel{}, carModelId, carModelPricesExp)
+}
+
+// prices returns the prices matching exp, or RecordNotFound when the owner with id is not in the tenant
+func (r *PostgresCarModelPriceHistoryRepository) prices(ctx context.Context, operation string, owner interface{}, id int, exp string) ([]model.CarModelPriceHistory, error) {
+	typeName := reflect.TypeOf(model.CarModelPriceHistory{}).String()
+	prices := []model.CarModelPriceHistory{}
+	db := database.GetReadDb(ctx)
+
+	var count int64
+	err := db.Model(owner).
+		Where(softDeleteExp, id).
+		Where(tenantExp, database.TenantId(ctx)).
+		Count(&count).
+		Error
+	if err == nil && count > 0 {
+		err = r.scope(ctx, db).
+			Where(exp, id).
+			Where(notDeletedExp).
+			Order("price_at, id").
+			Find(&prices).
+			Error
+	}
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, operation, "Failed").Inc()
+		return nil, err
+	}
+	metrics.DbCall.WithLabelValues(typeName, operation, "Success").Inc()
+	if count == 0 {
+		return nil, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return prices, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+)
+
+type PostgresCarModelPropertyRepository struct {
+	*BaseRepository[model.CarModelProperty]
+}
+
+func NewCarModelPropertyRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresCarModelPropertyRepository {
+	return &PostgresCarModelPropertyRepository{BaseRepository: NewBaseRepository[model.CarModelProperty](cfg, preloads)}
+}
+
+// CountByProperty is not scoped to the tenant, the properties are shared by the tenants
+func (r *PostgresCarModelPropertyRepository) CountByProperty(ctx context.Context, propertyId int) (int64, error) {
+	var count int64
+	err := database.GetReadDb(ctx).
+		Model(&model.CarModelProperty{}).
+		Where("property_id = ?", propertyId).
+		Where(notDeletedExp).
+		Count(&count).
+		Error
+	return count, dbResult[model.CarModelProperty](r.logger, err, "CountByProperty", logging.Select)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockCarModelExp locks a car model of the tenant so its ratings are summarized one rating at a time
+const lockCarModelExp string = `SELECT id FROM car_models WHERE id = @id AND tenant_id = @tenant AND deleted_by IS NULL FOR UPDATE`
+
+const ratingSummaryExp string = `UPDATE car_models SET rating_count = s.total, rating_average = coalesce(round(s.average, 2), 0),
+	one_star_count = s.one, two_star_count = s.two, three_star_count = s.three, four_star_count = s.four, five_star_count = s.five
+	FROM (SELECT count(*) total, avg(stars) average,
+		count(*) FILTER (WHERE stars = 1) one, count(*) FILTER (WHERE stars = 2) two, count(*) FILTER (WHERE stars = 3) three,
+		count(*) FILTER (WHERE stars = 4) four, count(*) FILTER (WHERE stars = 5) five
+		FROM car_model_ratings WHERE car_model_id = @id AND deleted_by IS NULL) s
+	WHERE car_models.id = @id`
+
+type PostgresCarModelRatingRepository struct {
+	*BaseRepository[model.CarModelRating]
+}
+
+func NewCarModelRatingRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresCarModelRatingRepository {
+	return &PostgresCarModelRatingRepository{BaseRepository: NewBaseRepository[model.CarModelRating](cfg, preloads)}
+}
+
+func (r *PostgresCarModelRatingRepository) Rate(ctx context.Context, rating model.CarModelRating) (model.CarModelRating, error) {
+	rating.TenantId = database.TenantId(ctx)
+	err := r.summarize(ctx, rating.CarModelId, func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "car_model_id"}, {Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"stars":       rating.Stars,
+				"modified_by": &sql.NullInt64{Int64: int64(rating.UserId), Valid: true},
+				"modified_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+				"deleted_by":  nil,
+				"deleted_at":  nil,
+			}),
+		}).Create(&rating).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("car_model_id = ? AND user_id = ?", rating.CarModelId, rating.UserId).First(&rating).Error
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return rating, err
+	}
+	return rating, dbResult[model.CarModelRating](r.logger, err, "Rate", logging.Insert)
+}
+
+func (r *PostgresCarModelRatingRepository) RemoveRating(ctx context.Context, carModelId int, userId int) error {
+	err := r.summarize(ctx, carModelId, func(tx *gorm.DB) error {
+		res := tx.Model(&model.CarModelRating{}).
+			Where("car_model_id = ? AND user_id = ? AND deleted_by IS NULL", carModelId, userId).
+			Updates(map[string]interface{}{
+				"deleted_by": &sql.NullInt64{Int64: int64(ctx.Value(constant.UserIdKey).(float64)), Valid: true},
+				"deleted_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+			})
+		if res.Error == nil && res.RowsAffected == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+		return res.Error
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return err
+	}
+	return dbResult[model.CarModelRating](r.logger, err, "RemoveRating", logging.Update)
+}
+
+// summarize runs change on the ratings of a locked car model and updates its rating summary
+func (r *PostgresCarModelRatingRepository) summarize(ctx context.Context, carModelId int, change func(tx *gorm.DB) error) error {
+	err := r.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []int{}
+		if err := tx.Raw(lockCarModelExp, map[string]interface{}{"id": carModelId, "tenant": database.TenantId(ctx)}).Scan(&ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+		if err := change(tx); err != nil {
+			return err
+		}
+		return tx.Exec(ratingSummaryExp, map[string]interface{}{"id": carModelId}).Error
+	})
+	if err == nil {
+		database.MarkWrite(ctx)
+	}
+	return err
+}
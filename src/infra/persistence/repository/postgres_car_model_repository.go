@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	filter "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+)
+
+const carModelSearchFromExp string = `FROM car_model_search s
+	JOIN car_models cm ON cm.id = s.car_model_id
+	JOIN companies co ON co.id = cm.company_id
+	JOIN car_types ct ON ct.id = cm.car_type_id
+	JOIN gearboxes g ON g.id = cm.gearbox_id
+	WHERE s.search_vector @@ to_tsquery('simple', @query) AND cm.tenant_id = @tenant`
+
+// carModelSearchSelectExp escapes the document before marking the matches, so the highlight is safe html
+const carModelSearchSelectExp string = `SELECT cm.id, cm.name,
+	co.name AS company_name, ct.name AS car_type_name, g.name AS gearbox_name,
+	ts_rank_cd(s.search_vector, to_tsquery('simple', @query)) AS rank,
+	ts_headline('simple', replace(replace(replace(s.document, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), to_tsquery('simple', @query),
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=3, MaxWords=12') AS highlight `
+
+// carModelFacetPricesExp selects the latest price of every year of the tenant in DefaultCurrency, Toman,
+// converted like the usecases do by the rate at its time. Rial and Toman need no rate, a price without a rate is left out
+var carModelFacetPricesExp = fmt.Sprintf(`WITH prices AS (SELECT car_model_id, price FROM (
+	SELECT my.car_model_id, round(CASE
+		WHEN h.currency IN ('', '%[1]s') THEN h.price
+		WHEN h.currency = '%[2]s' THEN h.price / %[3]d
+		ELSE h.price * r.rate / %[3]d END, 2) AS price
+	FROM car_model_years my
+	JOIN LATERAL (SELECT price, currency, price_at FROM car_model_price_histories
+		WHERE car_model_year_id = my.id AND deleted_by IS NULL
+		ORDER BY price_at DESC, id DESC LIMIT 1) h ON true
+	LEFT JOIN LATERAL (SELECT rate FROM exchange_rates
+		WHERE currency = h.currency AND rate_at <= h.price_at AND deleted_by IS NULL
+		ORDER BY rate_at DESC, id DESC LIMIT 1) r ON true
+	WHERE my.tenant_id = @tenant AND my.deleted_by IS NULL) converted
+	WHERE price IS NOT NULL)
+`, model.CurrencyToman, model.CurrencyRial, model.RialsPerToman)
+
+const carModelFacetFromExp string = `FROM car_models cm
+	JOIN companies co ON co.id = cm.company_id
+	JOIN countries cn ON cn.id = co.country_id
+	JOIN car_types ct ON ct.id = cm.car_type_id
+	JOIN gearboxes g ON g.id = cm.gearbox_id
+`
+
+const carModelFacetItemsExp string = `SELECT cm.id, cm.name,
+	co.name AS company_name, cn.name AS country_name, ct.name AS car_type_name, g.name AS gearbox_name,
+	(SELECT min(price) FROM prices p WHERE p.car_model_id = cm.id) AS min_price,
+	(SELECT max(price) FROM prices p WHERE p.car_model_id = cm.id) AS max_price
+`
+
+// carModelFacetSelectionExps match a car model with one of the selected ids of a facet field
+var carModelFacetSelectionExps = map[string]string{
+	model.FacetCompanyId: "cm.company_id IN @CompanyId",
+	model.FacetCountryId: "co.country_id IN @CountryId",
+	model.FacetCarTypeId: "cm.car_type_id IN @CarTypeId",
+	model.FacetGearboxId: "cm.gearbox_id IN @GearboxId",
+	model.FacetColorId: `EXISTS (SELECT 1 FROM car_model_colors mc
+		WHERE mc.car_model_id = cm.id AND mc.deleted_by IS NULL AND mc.color_id IN @ColorId)`,
+	model.FacetPersianYearId: `EXISTS (SELECT 1 FROM car_model_years my
+		WHERE my.car_model_id = cm.id AND my.deleted_by IS NULL AND my.persian_year_id IN @PersianYearId)`,
+}
+
+// carModelFacetValueExps join the values of a facet field to the car models and name them
+var carModelFacetValueExps = map[string]struct{ join, id, name string }{
+	model.FacetCompanyId: {"", "co.id", "co.name"},
+	model.FacetCountryId: {"", "cn.id", "cn.name"},
+	model.FacetCarTypeId: {"", "ct.id", "ct.name"},
+	model.FacetGearboxId: {"", "g.id", "g.name"},
+	model.FacetColorId: {`JOIN car_model_colors mc ON mc.car_model_id = cm.id AND mc.deleted_by IS NULL
+		JOIN colors c ON c.id = mc.color_id
+	`, "c.id", "c.name"},
+	model.FacetPersianYearId: {`JOIN car_model_years my ON my.car_model_id = cm.id AND my.deleted_by IS NULL
+		JOIN persian_years py ON py.id = my.persian_year_id
+	`, "py.id", "py.persian_title"},
+}
+
+// carModelFacetPriceExps match a price of the prices cte
+var carModelFacetPriceExps = map[string]string{
+	"equals":             "p.price = @priceFrom",
+	"lessThan":           "p.price < @priceFrom",
+	"lessThanOrEqual":    "p.price <= @priceFrom",
+	"greaterThan":        "p.price > @priceFrom",
+	"greaterThanOrEqual": "p.price >= @priceFrom",
+	"inRange":            "p.price >= @priceFrom AND p.price < @priceTo",
+}
+
+var tsQueryReplacer = strings.NewReplacer("&", "", "|", "", "!", "", "(", "", ")", "", ":", "", "*", "", "'", "", "\\", "", "<", "", ">", "")
+
+type PostgresCarModelRepository struct {
+	*BaseRepository[model.CarModel]
+}
+
+func NewCarModelRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresCarModelRepository {
+	return &PostgresCarModelRepository{BaseRepository: NewBaseRepository[model.CarModel](cfg, preloads)}
+}
+
+func (r *PostgresCarModelRepository) Search(ctx context.Context, query string, req filter.PaginationInputWithFilter) (int64, *[]model.CarModelSearchResult, error) {
+	items := []model.CarModelSearchResult{}
+	tsQuery := toPrefixTsQuery(query)
+	if tsQuery == "" {
+		return 0, &items, nil
+	}
+	typeName := reflect.TypeOf(model.CarModel{}).String()
+	args := map[string]interface{}{
+		"query":  tsQuery,
+		"tenant": database.TenantId(ctx),
+		"limit":  req.GetPageSize(),
+		"offset": req.GetOffset(),
+	}
+	db := database.GetReadDb(ctx)
+
+	var totalRows int64
+	if err := db.Raw("SELECT count(*) "+carModelSearchFromExp, args).Scan(&totalRows).Error; err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "Search", "Failed").Inc()
+		return 0, &items, err
+	}
+
+	err := db.Raw(carModelSearchSelectExp+carModelSearchFromExp+" ORDER BY rank DESC, cm.id LIMIT @limit OFFSET @offset", args).
+		Scan(&items).
+		Error
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "Search", "Failed").Inc()
+		return 0, &items, err
+	}
+	metrics.DbCall.WithLabelValues(typeName, "Search", "Success").Inc()
+	return totalRows, &items, nil
+}
+
+// facetWhere returns the condition and the arguments of the car models of the tenant matching query,
+// the other filters are applied to car_models like GetByFilter does
+func facetWhere(ctx context.Context, query model.CarModelFacetQuery) (string, map[string]interface{}) {
+	conditions := []string{"cm.tenant_id = @tenant", "cm.deleted_by IS NULL"}
+	args := map[string]interface{}{"tenant": database.TenantId(ctx)}
+	for _, field := range model.FacetFields {
+		if ids, ok := query.Selections[field]; ok {
+			conditions = append(conditions, carModelFacetSelectionExps[field])
+			args[field] = ids
+		}
+	}
+	if query.Price != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM prices p WHERE p.car_model_id = cm.id AND "+carModelFacetPriceExps[query.Price.Type]+")")
+		args["priceFrom"], args["priceTo"] = query.Price.From, query.Price.To
+	}
+	if len(query.Filter) > 0 {
+		conditions = append(conditions, "cm.id IN (SELECT id FROM car_models WHERE "+
+			database.GenerateDynamicQuery[model.CarModel](&filter.DynamicFilter{Filter: query.Filter})+")")
+	}
+	return "WHERE " + strings.Join(conditions, " AND ") + "\n", args
+}
+
+// facetOrder sorts by Id, Name or Price, the lowest price, car models without a price come last
+func facetOrder(sorts *[]filter.Sort) string {
+	order := []string{}
+	if sorts != nil {
+		for _, s := range *sorts {
+			if s.Sort != "asc" && s.Sort != "desc" {
+				continue
+			}
+			switch s.ColId {
+			case "Id":
+				order = append(order, "cm.id "+s.Sort)
+			case "Name":
+				order = append(order, "cm.name "+s.Sort)
+			case "Price":
+				order = append(order, "min_price "+s.Sort+" NULLS LAST")
+			}
+		}
+	}
+	return "ORDER BY " + strings.Join(append(order, "cm.id"), ", ")
+}
+
+func (r *PostgresCarModelRepository) FacetCarModels(ctx context.Context, query model.CarModelFacetQuery, sorts *[]filter.Sort, offset int, limit int) (int64, []model.CarModelFacetItem, error) {
+	typeName := reflect.TypeOf(model.CarModel{}).String()
+	where, args := facetWhere(ctx, query)
+	args["offset"], args["limit"] = offset, limit
+	db := database.GetReadDb(ctx)
+
+	var count int64
+	items := []model.CarModelFacetItem{}
+	err := db.Raw(carModelFacetPricesExp+"SELECT count(*) "+carModelFacetFromExp+where, args).Scan(&count).Error
+	if err == nil {
+		err = db.Raw(carModelFacetPricesExp+carModelFacetItemsExp+carModelFacetFromExp+where+facetOrder(sorts)+" LIMIT @limit OFFSET @offset", args).
+			Scan(&items).
+			Error
+	}
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "FacetCarModels", "Failed").Inc()
+		return 0, nil, err
+	}
+	metrics.DbCall.WithLabelValues(typeName, "FacetCarModels", "Success").Inc()
+	return count, items, nil
+}
+
+func (r *PostgresCarModelRepository) FacetCounts(ctx context.Context, query model.CarModelFacetQuery, field string, selected []int) ([]model.FacetCount, error) {
+	typeName := reflect.TypeOf(model.CarModel{}).String()
+	value, ok := carModelFacetValueExps[field]
+	if !ok {
+		return nil, fmt.Errorf("unknown facet field %s", field)
+	}
+	where, args := facetWhere(ctx, query)
+	args["selected"] = selected
+	// every value of the tenant is counted under the query, so a selected value is kept without car models
+	where = strings.Replace(where, "WHERE ", "", 1)
+	exp := fmt.Sprintf(`%sSELECT id, name, count FROM (
+	SELECT %s AS id, %s AS name, count(DISTINCT cm.id) FILTER (WHERE %s) AS count
+	%s%sWHERE cm.tenant_id = @tenant AND cm.deleted_by IS NULL
+	GROUP BY %s, %s) v
+	WHERE count > 0 OR id IN @selected
+	ORDER BY name, id`, carModelFacetPricesExp, value.id, value.name, where, carModelFacetFromExp, value.join, value.id, value.name)
+
+	counts := []model.FacetCount{}
+	if err := database.GetReadDb(ctx).Raw(exp, args).Scan(&counts).Error; err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "FacetCounts", "Failed").Inc()
+		return nil, err
+	}
+	metrics.DbCall.WithLabelValues(typeName, "FacetCounts", "Success").Inc()
+	return counts, nil
+}
+
+func (r *PostgresCarModelRepository) FacetPriceBounds(ctx context.Context, query model.CarModelFacetQuery) (float64, float64, bool, error) {
+	typeName := reflect.TypeOf(model.CarModel{}).String()
+	where, args := facetWhere(ctx, query)
+	bounds := struct {
+		Low  *float64
+		High *float64
+	}{}
+	err := database.GetReadDb(ctx).
+		Raw(carModelFacetPricesExp+"SELECT min(p.price) AS low, max(p.price) AS high "+carModelFacetFromExp+
+			"JOIN prices p ON p.car_model_id = cm.id\n"+where, args).
+		Scan(&bounds).
+		Error
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "FacetPriceBounds", "Failed").Inc()
+		return 0, 0, false, err
+	}
+	metrics.DbCall.WithLabelValues(typeName, "FacetPriceBounds", "Success").Inc()
+	if bounds.Low == nil || bounds.High == nil {
+		return 0, 0, false, nil
+	}
+	return *bounds.Low, *bounds.High, true, nil
+}
+
+func (r *PostgresCarModelRepository) FacetPriceCounts(ctx context.Context, query model.CarModelFacetQuery, start float64, step float64) (map[int]int, error) {
+	typeName := reflect.TypeOf(model.CarModel{}).String()
+	where, args := facetWhere(ctx, query)
+	args["start"], args["step"] = start, step
+	rows := []struct {
+		Bucket int
+		Count  int
+	}{}
+	err := database.GetReadDb(ctx).
+		Raw(carModelFacetPricesExp+"SELECT floor((p.price - @start) / @step)::int AS bucket, count(DISTINCT cm.id) AS count "+
+			carModelFacetFromExp+"JOIN prices p ON p.car_model_id = cm.id\n"+where+"GROUP BY bucket", args).
+		Scan(&rows).
+		Error
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "FacetPriceCounts", "Failed").Inc()
+		return nil, err
+	}
+	metrics.DbCall.WithLabelValues(typeName, "FacetPriceCounts", "Success").Inc()
+	counts := map[int]int{}
+	for _, row := range rows {
+		counts[row.Bucket] = row.Count
+	}
+	return counts, nil
+}
+
+// toPrefixTsQuery converts "peugeot 20" to 'peugeot':* & '20':*
+func toPrefixTsQuery(query string) string {
+	terms := []string{}
+	for _, word := range strings.Fields(tsQueryReplacer.Replace(query)) {
+		terms = append(terms, fmt.Sprintf("'%s':*", word))
+	}
+	return strings.Join(terms, " & ")
+}
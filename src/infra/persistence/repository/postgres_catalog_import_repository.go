@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+const notDeletedExp string = "deleted_by is null"
+
+type PostgresCatalogImportRepository struct {
+	database *gorm.DB
+	logger   logging.Logger
+	// changes are written to the outbox
+	events bool
+}
+
+type lookupRow struct {
+	Id   int
+	Name string
+}
+
+func NewCatalogImportRepository(cfg *config.Config) *PostgresCatalogImportRepository {
+	return &PostgresCatalogImportRepository{
+		database: database.GetDb(),
+		logger:   logging.NewLogger(cfg),
+		events:   cfg.Outbox.Enabled,
+	}
+}
+
+// Lookup reads from the primary, an import right after it must see the same car models
+func (r *PostgresCatalogImportRepository) Lookup(ctx context.Context) (model.CatalogLookup, error) {
+	lookup := model.CatalogLookup{}
+	typeName := reflect.TypeOf(lookup).String()
+	db := r.database.WithContext(ctx)
+
+	var err error
+	lookup.Companies, err = r.names(db.Model(&model.Company{}), "name")
+	if err == nil {
+		lookup.CarTypes, err = r.names(db.Model(&model.CarType{}), "name")
+	}
+	if err == nil {
+		lookup.Gearboxes, err = r.names(db.Model(&model.Gearbox{}), "name")
+	}
+	if err == nil {
+		lookup.Colors, err = r.names(db.Model(&model.Color{}), "name")
+	}
+	if err == nil {
+		lookup.Properties, err = r.names(db.Model(&model.Property{}), "name")
+	}
+	if err == nil {
+		lookup.CarModels, err = r.names(db.Model(&model.CarModel{}).Where(tenantExp, database.TenantId(ctx)), "name")
+	}
+	if err == nil {
+		lookup.PersianYears, err = r.names(db.Model(&model.PersianYear{}), "persian_title")
+	}
+	var years map[string]int
+	if err == nil {
+		years, err = r.names(db.Model(&model.PersianYear{}), "year::text")
+	}
+	properties := []model.Property{}
+	if err == nil {
+		err = db.Where(notDeletedExp).Find(&properties).Error
+	}
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "Lookup", "Failed").Inc()
+		return lookup, err
+	}
+	for year, id := range years {
+		lookup.PersianYears[year] = id
+	}
+	lookup.PropertyTypes = make(map[int]model.Property, len(properties))
+	for _, property := range properties {
+		lookup.PropertyTypes[property.Id] = property
+	}
+	metrics.DbCall.WithLabelValues(typeName, "Lookup", "Success").Inc()
+	return lookup, nil
+}
+
+func (r *PostgresCatalogImportRepository) Import(ctx context.Context, items []model.CatalogImportItem) ([]model.CatalogImportItem, error) {
+	typeName := reflect.TypeOf(model.CatalogImportItem{}).String()
+	if err := database.RequireTenant(ctx); err != nil {
+		return nil, err
+	}
+	tenantId := database.TenantId(ctx)
+
+	err := r.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range items {
+			if err := r.importItem(ctx, tx, tenantId, &items[i]); err != nil {
+				return fmt.Errorf("row %d: %w", items[i].Row, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Rollback, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "Import", "Failed").Inc()
+		return nil, err
+	}
+	database.MarkWrite(ctx)
+	metrics.DbCall.WithLabelValues(typeName, "Import", "Success").Inc()
+	return items, nil
+}
+
+// names maps the nameExp column of the not deleted rows of query to ids
+func (r *PostgresCatalogImportRepository) names(query *gorm.DB, nameExp string) (map[string]int, error) {
+	rows := []lookupRow{}
+	err := query.
+		Select("id, " + nameExp + " AS name").
+		Where(notDeletedExp).
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]int, len(rows))
+	for _, row := range rows {
+		result[row.Name] = row.Id
+	}
+	return result, nil
+}
+
+func (r *PostgresCatalogImportRepository) importItem(ctx context.Context, tx *gorm.DB, tenantId int, item *model.CatalogImportItem) error {
+	ids := []int{}
+	err := tx.Model(&model.CarModel{}).
+		Where("name = ?", item.Name).
+		Where(notDeletedExp).
+		Where(tenantExp, tenantId).
+		Limit(1).
+		Pluck("id", &ids).
+		Error
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		carModel := model.CarModel{Name: item.Name, CompanyId: item.CompanyId, CarTypeId: item.CarTypeId, GearboxId: item.GearboxId}
+		carModel.TenantId = tenantId
+		if err = tx.Create(&carModel).Error; err != nil {
+			return err
+		}
+		item.CarModelId, item.Created = carModel.Id, true
+		if r.events {
+			if err = writeEvents(ctx, tx, event.EntityCreated, carModel, nil); err != nil {
+				return err
+			}
+		}
+	} else {
+		item.CarModelId = ids[0]
+		err = tx.Model(&model.CarModel{}).
+			Where("id = ?", item.CarModelId).
+			Updates(map[string]interface{}{
+				"company_id":  item.CompanyId,
+				"car_type_id": item.CarTypeId,
+				"gearbox_id":  item.GearboxId,
+				"modified_by": auditUser(ctx),
+				"modified_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+			}).
+			Error
+		if err == nil && r.events {
+			err = writeRowEvents[model.CarModel](ctx, tx, event.EntityUpdated, item.CarModelId, []string{"CompanyId", "CarTypeId", "GearboxId"})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, colorId := range item.ColorIds {
+		color := model.CarModelColor{CarModelId: item.CarModelId, ColorId: colorId}
+		color.TenantId = tenantId
+		keys := map[string]interface{}{"car_model_id": item.CarModelId, "color_id": colorId}
+		if err = upsertRelation(ctx, tx, r.events, color, keys, nil); err != nil {
+			return err
+		}
+	}
+	for _, persianYearId := range item.PersianYearIds {
+		year := model.CarModelYear{CarModelId: item.CarModelId, PersianYearId: persianYearId}
+		year.TenantId = tenantId
+		keys := map[string]interface{}{"car_model_id": item.CarModelId, "persian_year_id": persianYearId}
+		if err = upsertRelation(ctx, tx, r.events, year, keys, nil); err != nil {
+			return err
+		}
+	}
+	propertyIds := make([]int, 0, len(item.Properties))
+	for propertyId := range item.Properties {
+		propertyIds = append(propertyIds, propertyId)
+	}
+	sort.Ints(propertyIds)
+	for _, propertyId := range propertyIds {
+		value := item.Properties[propertyId]
+		property := model.CarModelProperty{CarModelId: item.CarModelId, PropertyId: propertyId,
+			Value: value.Value, NumericValue: value.NumericValue, NumericMaxValue: value.NumericMaxValue}
+		property.TenantId = tenantId
+		keys := map[string]interface{}{"car_model_id": item.CarModelId, "property_id": propertyId}
+		values := map[string]interface{}{"Value": value.Value, "NumericValue": value.NumericValue, "NumericMaxValue": value.NumericMaxValue}
+		if err = upsertRelation(ctx, tx, r.events, property, keys, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsertRelation inserts entity unless a row matches keys, a matching row is restored when it is
+// soft deleted and its values fields are updated. The unique indexes of the relation tables
+// also cover soft deleted rows, so they can not be inserted again.
+func upsertRelation[TEntity any](ctx context.Context, tx *gorm.DB, events bool, entity TEntity, keys map[string]interface{}, values map[string]interface{}) error {
+	updates := map[string]interface{}{
+		"deleted_by":  nil,
+		"deleted_at":  nil,
+		"modified_by": auditUser(ctx),
+		"modified_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+	}
+	changed := []string{"deleted_by is not null"}
+	args := []interface{}{}
+	changes := []string{}
+	for field, value := range values {
+		column := common.ToSnakeCase(field)
+		updates[column] = value
+		changed = append(changed, column+" IS DISTINCT FROM ?")
+		args = append(args, value)
+		changes = append(changes, field)
+	}
+	res := tx.Model(new(TEntity)).
+		Where(keys).
+		Where("("+strings.Join(changed, " or ")+")", args...).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		if !events {
+			return nil
+		}
+		row := new(TEntity)
+		if err := tx.Where(keys).First(row).Error; err != nil {
+			return err
+		}
+		return writeEvents(ctx, tx, event.EntityUpdated, *row, changes)
+	}
+
+	var count int64
+	if err := tx.Model(new(TEntity)).Where(keys).Count(&count).Error; err != nil || count > 0 {
+		return err
+	}
+	if err := tx.Create(&entity).Error; err != nil || !events {
+		return err
+	}
+	return writeEvents(ctx, tx, event.EntityCreated, entity, nil)
+}
+
+// auditUser returns the user of the request for modified_by, imports of the cli have no user
+func auditUser(ctx context.Context) *sql.NullInt64 {
+	userId, ok := ctx.Value(constant.UserIdKey).(float64)
+	return &sql.NullInt64{Int64: int64(userId), Valid: ok}
+}
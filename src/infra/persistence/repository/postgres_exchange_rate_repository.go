@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+)
+
+type PostgresExchangeRateRepository struct {
+	*BaseRepository[model.ExchangeRate]
+}
+
+func NewExchangeRateRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresExchangeRateRepository {
+	return &PostgresExchangeRateRepository{BaseRepository: NewBaseRepository[model.ExchangeRate](cfg, preloads)}
+}
+
+func (r *PostgresExchangeRateRepository) GetByCurrencies(ctx context.Context, currencies []string, until time.Time) ([]model.ExchangeRate, error) {
+	typeName := reflect.TypeOf(model.ExchangeRate{}).String()
+	rates := []model.ExchangeRate{}
+	err := database.GetReadDb(ctx).
+		Where("currency IN ? AND rate_at <= ?", currencies, until).
+		Where(notDeletedExp).
+		Order("rate_at, id").
+		Find(&rates).
+		Error
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, "GetByCurrencies", "Failed").Inc()
+		return nil, err
+	}
+	metrics.DbCall.WithLabelValues(typeName, "GetByCurrencies", "Success").Inc()
+	return rates, nil
+}
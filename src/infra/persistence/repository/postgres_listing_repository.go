@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"gorm.io/gorm"
+)
+
+// expireListingsExp expires a batch of the published listings of every tenant whose time is up,
+// rows locked by another instance are skipped
+const expireListingsExp string = `UPDATE listings SET status = @expired, modified_at = @now
+	WHERE id IN (SELECT id FROM listings WHERE status = @published AND expires_at <= @now AND deleted_by IS NULL
+		ORDER BY expires_at LIMIT @limit FOR UPDATE SKIP LOCKED)
+	RETURNING *`
+
+type PostgresListingRepository struct {
+	*BaseRepository[model.Listing]
+}
+
+func NewListingRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresListingRepository {
+	return &PostgresListingRepository{BaseRepository: NewBaseRepository[model.Listing](cfg, preloads)}
+}
+
+func (r *PostgresListingRepository) Transition(ctx context.Context, id int, from []string, values map[string]interface{}) error {
+	snakeMap := map[string]interface{}{}
+	for k, v := range values {
+		snakeMap[common.ToSnakeCase(k)] = v
+	}
+	snakeMap["modified_by"] = &sql.NullInt64{Int64: int64(ctx.Value(constant.UserIdKey).(float64)), Valid: true}
+	snakeMap["modified_at"] = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	err := r.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Listing{}).
+			Where(softDeleteExp, id).
+			Where(tenantExp, database.TenantId(ctx)).
+			Where("status IN ?", from).
+			Updates(snakeMap)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.ListingStatusInvalid}
+		}
+		if r.events {
+			return writeRowEvents[model.Listing](ctx, tx, event.EntityUpdated, id, fieldNames(values))
+		}
+		return nil
+	})
+	if _, ok := err.(*service_errors.ServiceError); ok {
+		return err
+	}
+	if err == nil {
+		database.MarkWrite(ctx)
+	}
+	return dbResult[model.Listing](r.logger, err, "Transition", logging.Update)
+}
+
+func (r *PostgresListingRepository) Expire(ctx context.Context, now time.Time, limit int) (int, error) {
+	listings := []model.Listing{}
+	err := r.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(expireListingsExp, map[string]interface{}{
+			"expired": model.ListingExpired, "published": model.ListingPublished, "now": now, "limit": limit,
+		}).Scan(&listings).Error
+		if err != nil || !r.events {
+			return err
+		}
+		for _, listing := range listings {
+			err = writeEvents(database.WithTenant(ctx, listing.TenantId), tx, event.EntityUpdated, listing, []string{"Status", "ModifiedAt"})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, dbResult[model.Listing](r.logger, err, "Expire", logging.Update)
+	}
+	return len(listings), nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresNotificationRepository struct {
+	*BaseRepository[model.Notification]
+}
+
+func NewNotificationRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresNotificationRepository {
+	return &PostgresNotificationRepository{BaseRepository: NewBaseRepository[model.Notification](cfg, preloads)}
+}
+
+// Enqueue inserts the notifications one by one, ids returned by a batch insert that skips
+// conflicting rows can not be matched to the rows
+func (r *PostgresNotificationRepository) Enqueue(ctx context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	created := []model.Notification{}
+	for _, notification := range notifications {
+		res := r.database.WithContext(ctx).
+			Omit("User").
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&notification)
+		if err := dbResult[model.Notification](r.logger, res.Error, "Enqueue", logging.Insert); err != nil {
+			return created, err
+		}
+		if res.RowsAffected > 0 {
+			created = append(created, notification)
+		}
+	}
+	return created, nil
+}
+
+func (r *PostgresNotificationRepository) MarkSent(ctx context.Context, id int) error {
+	err := r.database.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     model.NotificationSent,
+			"sent_at":    sql.NullTime{Valid: true, Time: time.Now().UTC()},
+			"last_error": "",
+		}).
+		Error
+	return dbResult[model.Notification](r.logger, err, "MarkSent", logging.Update)
+}
+
+func (r *PostgresNotificationRepository) MarkFailed(ctx context.Context, id int, lastError string) error {
+	err := r.database.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     model.NotificationFailed,
+			"last_error": truncate(lastError, 1000),
+		}).
+		Error
+	return dbResult[model.Notification](r.logger, err, "MarkFailed", logging.Update)
+}
+
+func (r *PostgresNotificationRepository) MarkRead(ctx context.Context, id int) error {
+	res := r.database.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where(softDeleteExp, id).
+		Where(tenantExp, database.TenantId(ctx)).
+		Where("read_at IS NULL").
+		Update("read_at", sql.NullTime{Valid: true, Time: time.Now().UTC()})
+	if err := dbResult[model.Notification](r.logger, res.Error, "MarkRead", logging.Update); err != nil {
+		return err
+	}
+	if res.RowsAffected > 0 {
+		database.MarkWrite(ctx)
+	}
+	return nil
+}
+
+// dbResult logs a failed query and counts the db call of operation
+func dbResult[TEntity any](logger logging.Logger, err error, operation string, subCategory logging.SubCategory) error {
+	typeName := reflect.TypeOf(*new(TEntity)).String()
+	if err != nil {
+		logger.Error(logging.Postgres, subCategory, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(typeName, operation, "Failed").Inc()
+		return err
+	}
+	metrics.DbCall.WithLabelValues(typeName, operation, "Success").Inc()
+	return nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+// claimOutboxExp locks due messages with skip locked, so dispatchers of several instances never
+// claim the same message while its lock is valid
+const claimOutboxExp string = `UPDATE outbox_messages SET locked_until = @lockedUntil
+	WHERE id IN (
+		SELECT id FROM outbox_messages
+		WHERE published_at IS NULL AND next_attempt_at <= @now AND (locked_until IS NULL OR locked_until < @now)
+		ORDER BY id
+		LIMIT @limit
+		FOR UPDATE SKIP LOCKED)
+	RETURNING *`
+
+const previousPriceExp string = `SELECT * FROM car_model_price_histories
+	WHERE car_model_year_id = ? AND id <> ? AND price_at <= ? AND deleted_by IS NULL
+	ORDER BY price_at DESC, id DESC
+	LIMIT 1`
+
+type PostgresOutboxRepository struct {
+	database *gorm.DB
+	logger   logging.Logger
+}
+
+func NewOutboxRepository(cfg *config.Config) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{
+		database: database.GetDb(),
+		logger:   logging.NewLogger(cfg),
+	}
+}
+
+func (r *PostgresOutboxRepository) Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]model.OutboxMessage, error) {
+	messages := []model.OutboxMessage{}
+	now := time.Now().UTC()
+	err := r.database.WithContext(ctx).
+		Raw(claimOutboxExp, map[string]interface{}{"lockedUntil": now.Add(lockTimeout), "now": now, "limit": limit}).
+		Scan(&messages).
+		Error
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Update, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(reflect.TypeOf(model.OutboxMessage{}).String(), "Claim", "Failed").Inc()
+		return nil, err
+	}
+	// RETURNING does not keep the order of the sub query
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Id < messages[j].Id })
+	metrics.DbCall.WithLabelValues(reflect.TypeOf(model.OutboxMessage{}).String(), "Claim", "Success").Inc()
+	return messages, nil
+}
+
+func (r *PostgresOutboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	return r.update(ctx, id, "MarkPublished", map[string]interface{}{
+		"published_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+		"locked_until": nil,
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   "",
+	})
+}
+
+func (r *PostgresOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	return r.update(ctx, id, "MarkFailed", map[string]interface{}{
+		"next_attempt_at": nextAttemptAt,
+		"locked_until":    nil,
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      truncate(lastError, 1000),
+	})
+}
+
+func (r *PostgresOutboxRepository) update(ctx context.Context, id int64, operation string, values map[string]interface{}) error {
+	err := r.database.WithContext(ctx).
+		Model(&model.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(values).
+		Error
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Update, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(reflect.TypeOf(model.OutboxMessage{}).String(), operation, "Failed").Inc()
+		return err
+	}
+	metrics.DbCall.WithLabelValues(reflect.TypeOf(model.OutboxMessage{}).String(), operation, "Success").Inc()
+	return nil
+}
+
+// writeEvents adds the events of a change of entity to the outbox in the transaction tx of the change,
+// changes are the updated fields
+func writeEvents[TEntity any](ctx context.Context, tx *gorm.DB, eventType event.Type, entity TEntity, changes []string) error {
+	sort.Strings(changes)
+	events, err := event.OfChange(eventType, entity, database.TenantId(ctx), eventUser(ctx), changes,
+		func(price model.CarModelPriceHistory) (*model.CarModelPriceHistory, error) {
+			previous := []model.CarModelPriceHistory{}
+			err := tx.Raw(previousPriceExp, price.CarModelYearId, price.Id, price.PriceAt).Scan(&previous).Error
+			if err != nil || len(previous) == 0 {
+				return nil, err
+			}
+			return &previous[0], nil
+		})
+	if err != nil {
+		return err
+	}
+	messages := make([]model.OutboxMessage, 0, len(events))
+	for _, e := range events {
+		messages = append(messages, e.Message())
+	}
+	return tx.Create(&messages).Error
+}
+
+// writeRowEvents reads the changed row in tx and adds its events to the outbox
+func writeRowEvents[TEntity any](ctx context.Context, tx *gorm.DB, eventType event.Type, id int, changes []string) error {
+	entity := new(TEntity)
+	if err := tx.Where("id = ?", id).First(entity).Error; err != nil {
+		return err
+	}
+	return writeEvents(ctx, tx, eventType, *entity, changes)
+}
+
+func fieldNames(entity map[string]interface{}) []string {
+	names := make([]string, 0, len(entity))
+	for name := range entity {
+		names = append(names, name)
+	}
+	return names
+}
+
+func eventUser(ctx context.Context) *int {
+	value, ok := ctx.Value(constant.UserIdKey).(float64)
+	if !ok {
+		return nil
+	}
+	userId := int(value)
+	return &userId
+}
+
+func truncate(value string, size int) string {
+	if len(value) <= size {
+		return value
+	}
+	return strings.ToValidUTF8(value[:size], "")
+}
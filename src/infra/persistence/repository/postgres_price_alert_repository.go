@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+)
+
+type PostgresPriceAlertRepository struct {
+	*BaseRepository[model.PriceAlert]
+}
+
+func NewPriceAlertRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresPriceAlertRepository {
+	return &PostgresPriceAlertRepository{BaseRepository: NewBaseRepository[model.PriceAlert](cfg, preloads)}
+}
+
+func (r *PostgresPriceAlertRepository) Watching(ctx context.Context, tenantId int, carModelYearId int) ([]model.PriceAlert, error) {
+	alerts := []model.PriceAlert{}
+	err := r.database.WithContext(ctx).
+		Preload("User").
+		Preload("CarModelYear.CarModel").
+		Preload("CarModelYear.PersianYear").
+		Where(tenantExp, tenantId).
+		Where("car_model_year_id = ? AND enabled = ?", carModelYearId, true).
+		Where(notDeletedExp).
+		Order("id").
+		Find(&alerts).
+		Error
+	return alerts, dbResult[model.PriceAlert](r.logger, err, "Watching", logging.Select)
+}
+
+func (r *PostgresPriceAlertRepository) UpdateBasePrice(ctx context.Context, id int, basePrice float64, triggered bool) error {
+	values := map[string]interface{}{"base_price": sql.NullFloat64{Valid: true, Float64: basePrice}}
+	if triggered {
+		values["last_triggered_at"] = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	}
+	err := r.database.WithContext(ctx).
+		Model(&model.PriceAlert{}).
+		Where("id = ?", id).
+		Updates(values).
+		Error
+	return dbResult[model.PriceAlert](r.logger, err, "UpdateBasePrice", logging.Update)
+}
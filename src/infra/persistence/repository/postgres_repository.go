@@ -9,7 +9,9 @@ import (
 	"github.com/naeemaei/golang-clean-web-api/common"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
 	filter "github.com/naeemaei/golang-clean-web-api/domain/filter"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
 	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
 	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
 	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
@@ -18,26 +20,44 @@ import (
 )
 
 const softDeleteExp string = "id = ? and deleted_by is null"
+const tenantExp string = "tenant_id = ?"
 
 type BaseRepository[TEntity any] struct {
-	database *gorm.DB
-	logger   logging.Logger
-	preloads []database.PreloadEntity
+	database     *gorm.DB
+	logger       logging.Logger
+	preloads     []database.PreloadEntity
+	tenantScoped bool
+	// changes are written to the outbox
+	events bool
 }
 
 func NewBaseRepository[TEntity any](cfg *config.Config, preloads []database.PreloadEntity) *BaseRepository[TEntity] {
 	return &BaseRepository[TEntity]{
-		database: database.GetDb(),
-		logger:   logging.NewLogger(cfg),
-		preloads: preloads,
+		database:     database.GetDb(),
+		logger:       logging.NewLogger(cfg),
+		preloads:     preloads,
+		tenantScoped: database.IsTenantScoped[TEntity](),
+		events:       cfg.Outbox.Enabled,
 	}
 }
 
 func (r BaseRepository[TEntity]) Create(ctx context.Context, entity TEntity) (TEntity, error) {
+	if err := r.authorizeWrite(ctx); err != nil {
+		return entity, err
+	}
+	if r.tenantScoped {
+		any(&entity).(model.TenantScoped).SetTenantId(database.TenantId(ctx))
+		if err := r.checkReferences(ctx, database.TenantReferences(entity)); err != nil {
+			return entity, err
+		}
+	}
 	tx := r.database.WithContext(ctx).Begin()
 	err := tx.
 		Create(&entity).
 		Error
+	if err == nil && r.events {
+		err = writeEvents(ctx, tx, event.EntityCreated, entity, nil)
+	}
 	if err != nil {
 		tx.Rollback()
 		r.logger.Error(logging.Postgres, logging.Insert, err.Error(), nil)
@@ -45,30 +65,46 @@ func (r BaseRepository[TEntity]) Create(ctx context.Context, entity TEntity) (TE
 		return entity, err
 	}
 	tx.Commit()
+	database.MarkWrite(ctx)
 
 	metrics.DbCall.WithLabelValues(reflect.TypeOf(entity).String(), "Create", "Success").Inc()
 	return entity, nil
 }
 
 func (r BaseRepository[TEntity]) Update(ctx context.Context, id int, entity map[string]interface{}) (TEntity, error) {
+	model := new(TEntity)
+	if err := r.authorizeWrite(ctx); err != nil {
+		return *model, err
+	}
+	// tenant of an entity never changes
+	delete(entity, "TenantId")
+	if r.tenantScoped {
+		if err := r.checkReferences(ctx, database.TenantReferencesOf(reflect.TypeOf(*model), entity)); err != nil {
+			return *model, err
+		}
+	}
 	snakeMap := map[string]interface{}{}
 	for k, v := range entity {
 		snakeMap[common.ToSnakeCase(k)] = v
 	}
 	snakeMap["modified_by"] = &sql.NullInt64{Int64: int64(ctx.Value(constant.UserIdKey).(float64)), Valid: true}
 	snakeMap["modified_at"] = sql.NullTime{Valid: true, Time: time.Now().UTC()}
-	model := new(TEntity)
 	tx := r.database.WithContext(ctx).Begin()
-	if err := tx.Model(model).
+	res := r.scope(ctx, tx.Model(model)).
 		Where(softDeleteExp, id).
-		Updates(snakeMap).
-		Error; err != nil {
+		Updates(snakeMap)
+	err := res.Error
+	if err == nil && r.events && res.RowsAffected > 0 {
+		err = writeRowEvents[TEntity](ctx, tx, event.EntityUpdated, id, fieldNames(entity))
+	}
+	if err != nil {
 		tx.Rollback()
 		r.logger.Error(logging.Postgres, logging.Update, err.Error(), nil)
 		metrics.DbCall.WithLabelValues(reflect.TypeOf(*model).String(), "Update", "Failed").Inc()
 		return *model, err
 	}
 	tx.Commit()
+	database.MarkWrite(ctx)
 	metrics.DbCall.WithLabelValues(reflect.TypeOf(*model).String(), "Update", "Success").Inc()
 	return *model, nil
 }
@@ -86,8 +122,11 @@ func (r BaseRepository[TEntity]) Delete(ctx context.Context, id int) error {
 	if ctx.Value(constant.UserIdKey) == nil {
 		return &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
 	}
-	if cnt := tx.
-		Model(model).
+	if err := r.authorizeWrite(ctx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if cnt := r.scope(ctx, tx.Model(model)).
 		Where(softDeleteExp, id).
 		Updates(deleteMap).
 		RowsAffected; cnt == 0 {
@@ -96,15 +135,24 @@ func (r BaseRepository[TEntity]) Delete(ctx context.Context, id int) error {
 		metrics.DbCall.WithLabelValues(reflect.TypeOf(*model).String(), "Delete", "Failed").Inc()
 		return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
 	}
+	if r.events {
+		if err := writeRowEvents[TEntity](ctx, tx, event.EntityDeleted, id, nil); err != nil {
+			tx.Rollback()
+			r.logger.Error(logging.Postgres, logging.Rollback, err.Error(), nil)
+			metrics.DbCall.WithLabelValues(reflect.TypeOf(*model).String(), "Delete", "Failed").Inc()
+			return err
+		}
+	}
 	tx.Commit()
+	database.MarkWrite(ctx)
 	metrics.DbCall.WithLabelValues(reflect.TypeOf(*model).String(), "Delete", "Success").Inc()
 	return nil
 }
 
 func (r BaseRepository[TEntity]) GetById(ctx context.Context, id int) (TEntity, error) {
 	model := new(TEntity)
-	db := database.Preload(r.database, r.preloads)
-	err := db.
+	db := database.Preload(database.GetReadDb(ctx), r.preloads)
+	err := r.scope(ctx, db).
 		Where(softDeleteExp, id).
 		First(model).
 		Error
@@ -120,7 +168,7 @@ func (r BaseRepository[TEntity]) GetByFilter(ctx context.Context, req filter.Pag
 	model := new(TEntity)
 	var items *[]TEntity
 
-	db := database.Preload(r.database, r.preloads)
+	db := r.scope(ctx, database.Preload(database.GetReadDb(ctx), r.preloads))
 	query := database.GenerateDynamicQuery[TEntity](&req.DynamicFilter)
 	sort := database.GenerateDynamicSort[TEntity](&req.DynamicFilter)
 	var totalRows int64 = 0
@@ -144,3 +192,44 @@ func (r BaseRepository[TEntity]) GetByFilter(ctx context.Context, req filter.Pag
 	return totalRows, items, err
 
 }
+
+// scope limits db to rows of the current tenant when the entity is tenant scoped
+func (r BaseRepository[TEntity]) scope(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if !r.tenantScoped {
+		return db
+	}
+	return db.Where(tenantExp, database.TenantId(ctx))
+}
+
+// authorizeWrite allows writes on global reference data only to the platform tenant
+func (r BaseRepository[TEntity]) authorizeWrite(ctx context.Context) error {
+	if err := database.RequireTenant(ctx); err != nil {
+		return err
+	}
+	if r.tenantScoped || database.TenantId(ctx) == constant.DefaultTenantId {
+		return nil
+	}
+	return &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
+}
+
+// checkReferences rejects foreign keys to tenant scoped rows of other tenants
+func (r BaseRepository[TEntity]) checkReferences(ctx context.Context, references []database.TenantReference) error {
+	tenantId := database.TenantId(ctx)
+	for _, ref := range references {
+		var count int64
+		err := r.database.WithContext(ctx).
+			Model(reflect.New(ref.Type).Interface()).
+			Where(softDeleteExp, ref.Id).
+			Where(tenantExp, tenantId).
+			Count(&count).
+			Error
+		if err != nil {
+			r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+			return err
+		}
+		if count == 0 {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+		}
+	}
+	return nil
+}
@@ -8,11 +8,13 @@ import (
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
 	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
 	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 const userFilterExp string = "username = ?"
+const enabledTenantExp string = "tenant_id in (select id from tenants where enabled and deleted_by is null)"
 const countFilterExp string = "count(*) > 0"
 
 type PostgresUserRepository struct {
@@ -25,12 +27,19 @@ func NewUserRepository(cfg *config.Config) *PostgresUserRepository {
 }
 
 func (r *PostgresUserRepository) CreateUser(ctx context.Context, u model.User) (model.User, error) {
+	return r.CreateUserWithRole(ctx, u, constant.DefaultRoleName)
+}
 
-	roleId, err := r.GetDefaultRole(ctx)
+func (r *PostgresUserRepository) CreateUserWithRole(ctx context.Context, u model.User, roleName string) (model.User, error) {
+	roleId, err := r.getRoleId(ctx, roleName)
 	if err != nil {
 		r.logger.Error(logging.Postgres, logging.DefaultRoleNotFound, err.Error(), nil)
 		return u, err
 	}
+	// users are registered to an explicit tenant, there is no fallback to the platform tenant
+	if u.TenantId == 0 {
+		return u, &service_errors.ServiceError{EndUserMessage: service_errors.PermissionDenied}
+	}
 	tx := r.database.WithContext(ctx).Begin()
 	err = tx.Create(&u).Error
 	if err != nil {
@@ -53,6 +62,7 @@ func (r *PostgresUserRepository) FetchUserInfo(ctx context.Context, username str
 	err := r.database.WithContext(ctx).
 		Model(&model.User{}).
 		Where(userFilterExp, username).
+		Where(enabledTenantExp).
 		Preload("UserRoles", func(tx *gorm.DB) *gorm.DB {
 			return tx.Preload("Role")
 		}).
@@ -110,10 +120,13 @@ func (r *PostgresUserRepository) ExistsMobileNumber(ctx context.Context, mobileN
 }
 
 func (r *PostgresUserRepository) GetDefaultRole(ctx context.Context) (roleId int, err error) {
+	return r.getRoleId(ctx, constant.DefaultRoleName)
+}
 
+func (r *PostgresUserRepository) getRoleId(ctx context.Context, roleName string) (roleId int, err error) {
 	if err = r.database.WithContext(ctx).Model(&model.Role{}).
 		Select("id").
-		Where("name = ?", constant.DefaultRoleName).
+		Where("name = ?", roleName).
 		First(&roleId).Error; err != nil {
 		return 0, err
 	}
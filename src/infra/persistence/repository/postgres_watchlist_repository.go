@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"gorm.io/gorm"
+)
+
+const watchlistFollowersExp string = `SELECT e.car_model_id, count(DISTINCT w.user_id) AS followers
+	FROM watchlist_entries e JOIN watchlists w ON w.id = e.watchlist_id AND w.deleted_by IS NULL
+	WHERE e.car_model_id IN @ids AND e.tenant_id = @tenant AND e.deleted_by IS NULL
+	GROUP BY e.car_model_id`
+
+type PostgresWatchlistRepository struct {
+	*BaseRepository[model.Watchlist]
+}
+
+func NewWatchlistRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresWatchlistRepository {
+	return &PostgresWatchlistRepository{BaseRepository: NewBaseRepository[model.Watchlist](cfg, preloads)}
+}
+
+func (r *PostgresWatchlistRepository) GetByShareToken(ctx context.Context, shareToken string) (model.Watchlist, error) {
+	watchlist := model.Watchlist{}
+	err := database.Preload(database.GetReadDb(ctx), r.preloads).
+		Where("share_token = ?", shareToken).
+		Where(notDeletedExp).
+		First(&watchlist).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return watchlist, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return watchlist, dbResult[model.Watchlist](r.logger, err, "GetByShareToken", logging.Select)
+}
+
+func (r *PostgresWatchlistRepository) Followers(ctx context.Context, carModelIds []int) (map[int]int, error) {
+	followers := map[int]int{}
+	if len(carModelIds) == 0 {
+		return followers, nil
+	}
+	rows := []struct {
+		CarModelId int
+		Followers  int
+	}{}
+	err := database.GetReadDb(ctx).
+		Raw(watchlistFollowersExp, map[string]interface{}{"ids": carModelIds, "tenant": database.TenantId(ctx)}).
+		Scan(&rows).
+		Error
+	for _, row := range rows {
+		followers[row.CarModelId] = row.Followers
+	}
+	return followers, dbResult[model.WatchlistEntry](r.logger, err, "Followers", logging.Select)
+}
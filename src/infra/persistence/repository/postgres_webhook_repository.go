@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// claimWebhookDeliveriesExp works like claimOutboxExp, deliveries of disabled or deleted
+// subscriptions wait until the subscription is enabled again
+const claimWebhookDeliveriesExp string = `UPDATE webhook_deliveries SET locked_until = @lockedUntil
+	WHERE id IN (
+		SELECT d.id FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = @pending AND d.next_attempt_at <= @now AND (d.locked_until IS NULL OR d.locked_until < @now)
+			AND d.deleted_by IS NULL AND s.enabled AND s.deleted_by IS NULL
+		ORDER BY d.id
+		LIMIT @limit
+		FOR UPDATE OF d SKIP LOCKED)
+	RETURNING id`
+
+type PostgresWebhookSubscriptionRepository struct {
+	*BaseRepository[model.WebhookSubscription]
+}
+
+func NewWebhookSubscriptionRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresWebhookSubscriptionRepository {
+	return &PostgresWebhookSubscriptionRepository{BaseRepository: NewBaseRepository[model.WebhookSubscription](cfg, preloads)}
+}
+
+func (r *PostgresWebhookSubscriptionRepository) Subscribers(ctx context.Context, tenantId int) ([]model.WebhookSubscription, error) {
+	subscriptions := []model.WebhookSubscription{}
+	err := r.database.WithContext(ctx).
+		Where(tenantExp, tenantId).
+		Where("enabled = ?", true).
+		Where(notDeletedExp).
+		Order("id").
+		Find(&subscriptions).
+		Error
+	if err != nil {
+		r.logger.Error(logging.Postgres, logging.Select, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(reflect.TypeOf(model.WebhookSubscription{}).String(), "Subscribers", "Failed").Inc()
+		return nil, err
+	}
+	metrics.DbCall.WithLabelValues(reflect.TypeOf(model.WebhookSubscription{}).String(), "Subscribers", "Success").Inc()
+	return subscriptions, nil
+}
+
+type PostgresWebhookDeliveryRepository struct {
+	*BaseRepository[model.WebhookDelivery]
+}
+
+func NewWebhookDeliveryRepository(cfg *config.Config, preloads []database.PreloadEntity) *PostgresWebhookDeliveryRepository {
+	return &PostgresWebhookDeliveryRepository{BaseRepository: NewBaseRepository[model.WebhookDelivery](cfg, preloads)}
+}
+
+func (r *PostgresWebhookDeliveryRepository) Enqueue(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err := r.database.WithContext(ctx).
+		Omit("Subscription").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries).
+		Error
+	return r.result(err, "Enqueue", logging.Insert)
+}
+
+func (r *PostgresWebhookDeliveryRepository) Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]model.WebhookDelivery, error) {
+	deliveries := []model.WebhookDelivery{}
+	now := time.Now().UTC()
+	ids := []int{}
+	db := r.database.WithContext(ctx)
+	err := db.
+		Raw(claimWebhookDeliveriesExp, map[string]interface{}{
+			"lockedUntil": now.Add(lockTimeout), "now": now, "limit": limit, "pending": model.WebhookDeliveryPending,
+		}).
+		Scan(&ids).
+		Error
+	if err == nil && len(ids) > 0 {
+		err = db.Preload("Subscription").Where("id IN ?", ids).Find(&deliveries).Error
+	}
+	if err = r.result(err, "Claim", logging.Update); err != nil {
+		return nil, err
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].Id < deliveries[j].Id })
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) MarkDelivered(ctx context.Context, id int, responseStatus int) error {
+	now := time.Now().UTC()
+	err := r.database.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          model.WebhookDeliveryDelivered,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"locked_until":    nil,
+			"last_attempt_at": sql.NullTime{Valid: true, Time: now},
+			"delivered_at":    sql.NullTime{Valid: true, Time: now},
+			"response_status": responseStatus,
+			"last_error":      "",
+		}).
+		Error
+	return r.result(err, "MarkDelivered", logging.Update)
+}
+
+func (r *PostgresWebhookDeliveryRepository) MarkFailed(ctx context.Context, id int, responseStatus int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := model.WebhookDeliveryPending
+	if dead {
+		status = model.WebhookDeliveryDead
+	}
+	err := r.database.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"locked_until":    nil,
+			"next_attempt_at": nextAttemptAt,
+			"last_attempt_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+			"response_status": responseStatus,
+			"last_error":      truncate(lastError, 1000),
+		}).
+		Error
+	return r.result(err, "MarkFailed", logging.Update)
+}
+
+func (r *PostgresWebhookDeliveryRepository) Redeliver(ctx context.Context, id int) (model.WebhookDelivery, error) {
+	res := r.database.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where(softDeleteExp, id).
+		Where(tenantExp, database.TenantId(ctx)).
+		Updates(map[string]interface{}{
+			"status":          model.WebhookDeliveryPending,
+			"attempts":        0,
+			"locked_until":    nil,
+			"next_attempt_at": time.Now().UTC(),
+			"delivered_at":    nil,
+		})
+	if err := r.result(res.Error, "Redeliver", logging.Update); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	if res.RowsAffected == 0 {
+		return model.WebhookDelivery{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	database.MarkWrite(ctx)
+	return r.GetById(ctx, id)
+}
+
+func (r *PostgresWebhookDeliveryRepository) result(err error, operation string, subCategory logging.SubCategory) error {
+	if err != nil {
+		r.logger.Error(logging.Postgres, subCategory, err.Error(), nil)
+		metrics.DbCall.WithLabelValues(reflect.TypeOf(model.WebhookDelivery{}).String(), operation, "Failed").Inc()
+		return err
+	}
+	metrics.DbCall.WithLabelValues(reflect.TypeOf(model.WebhookDelivery{}).String(), operation, "Success").Inc()
+	return nil
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Excel reads csv files without BOM as ANSI, so Persian text would be garbled
+const utf8Bom = "\xEF\xBB\xBF"
+
+type csvWriter struct {
+	out    io.Writer
+	writer *csv.Writer
+}
+
+func newCsvWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := io.WriteString(w, utf8Bom); err != nil {
+		return nil, err
+	}
+	return &csvWriter{out: w, writer: csv.NewWriter(w)}, nil
+}
+
+func (w *csvWriter) WriteHeader(columns []Column) error {
+	record := make([]string, 0, len(columns))
+	for _, column := range columns {
+		record = append(record, sanitize(column.Header))
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) WriteRow(values []any) error {
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			record = append(record, "")
+		case string:
+			record = append(record, sanitize(v))
+		default:
+			record = append(record, fmt.Sprint(v))
+		}
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return err
+	}
+	if flusher, ok := w.out.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	return w.Flush()
+}
+
+// sanitize prevents spreadsheet formula injection by user provided text
+func sanitize(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
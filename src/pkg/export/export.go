@@ -0,0 +1,181 @@
+// Package export writes rows of response dtos as CSV or XLSX spreadsheets.
+package export
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	Csv  Format = "csv"
+	Xlsx Format = "xlsx"
+)
+
+const (
+	CsvContentType  = "text/csv; charset=utf-8"
+	XlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// nested dtos are flattened up to this depth, e.g. company.country.name
+const maxDepth = 3
+
+const timeLayout = "2006-01-02 15:04:05"
+
+type Column struct {
+	// json path of the field, e.g. company.name
+	Path   string
+	Header string
+}
+
+// Writer writes a header and rows of a spreadsheet, CSV rows are flushed to the
+// underlying writer on Flush so large exports are streamed, XLSX is written on Close
+type Writer interface {
+	WriteHeader(columns []Column) error
+	WriteRow(values []any) error
+	Flush() error
+	Close() error
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case Csv:
+		return newCsvWriter(w)
+	case Xlsx:
+		return newXlsxWriter(w)
+	}
+	return nil, fmt.Errorf("export format %s is not supported", format)
+}
+
+func (f Format) ContentType() string {
+	if f == Xlsx {
+		return XlsxContentType
+	}
+	return CsvContentType
+}
+
+// ParseFormat accepts a format name or a content type of the Accept header
+func ParseFormat(value string) (Format, bool) {
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(strings.Split(part, ";")[0]))
+		switch part {
+		case string(Csv), "text/csv":
+			return Csv, true
+		case string(Xlsx), XlsxContentType:
+			return Xlsx, true
+		}
+	}
+	return "", false
+}
+
+// Columns returns the scalar fields of t by json path, nested structs are flattened and slices are skipped
+func Columns(t reflect.Type) []Column {
+	columns := []Column{}
+	collectColumns(indirectType(t), "", 0, &columns)
+	return columns
+}
+
+// ParseColumns parses "name,company.name:Company" to columns, a header defaults to the path
+func ParseColumns(value string) []Column {
+	columns := []Column{}
+	for _, item := range strings.Split(value, ",") {
+		path, header, _ := strings.Cut(strings.TrimSpace(item), ":")
+		if path == "" {
+			continue
+		}
+		if header == "" {
+			header = path
+		}
+		columns = append(columns, Column{Path: path, Header: header})
+	}
+	return columns
+}
+
+// Values resolves the columns on item, missing or nil fields are empty
+func Values(item any, columns []Column) []any {
+	v := reflect.ValueOf(item)
+	values := make([]any, 0, len(columns))
+	for _, column := range columns {
+		values = append(values, resolve(v, strings.Split(column.Path, ".")))
+	}
+	return values
+}
+
+func collectColumns(t reflect.Type, prefix string, depth int, columns *[]Column) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+		ft := indirectType(sf.Type)
+		switch {
+		case ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}):
+			if depth < maxDepth {
+				collectColumns(ft, prefix+name+".", depth+1, columns)
+			}
+		case ft.Kind() == reflect.Slice || ft.Kind() == reflect.Map:
+		default:
+			*columns = append(*columns, Column{Path: prefix + name, Header: prefix + name})
+		}
+	}
+}
+
+func resolve(v reflect.Value, path []string) any {
+	for _, name := range path {
+		v = reflect.Indirect(v)
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return nil
+		}
+		v = fieldByJsonName(v, name)
+	}
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return nil
+		}
+		return t.Format(timeLayout)
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		return nil
+	}
+	return v.Interface()
+}
+
+func fieldByJsonName(v reflect.Value, name string) reflect.Value {
+	for i := 0; i < v.NumField(); i++ {
+		if n, ok := jsonName(v.Type().Field(i)); ok && n == name {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+func jsonName(sf reflect.StructField) (string, bool) {
+	if !sf.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = sf.Name
+	}
+	return name, true
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Reader reads the rows of a csv or xlsx file, it is the counterpart of Writer for imports.
+// Read returns io.EOF after the last row.
+type Reader interface {
+	Read() ([]string, error)
+	Close() error
+}
+
+func NewReader(format Format, r io.Reader) (Reader, error) {
+	switch format {
+	case Csv:
+		return newCsvReader(r), nil
+	case Xlsx:
+		return newXlsxReader(r)
+	}
+	return nil, fmt.Errorf("import format %s is not supported", format)
+}
+
+// FormatOfFile returns the format of a file name by its extension
+func FormatOfFile(name string) (Format, bool) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+// csvReader returns blank lines as empty rows, encoding/csv skips them but row numbers
+// of an import report must match the rows spreadsheet applications show
+type csvReader struct {
+	reader  *csv.Reader
+	line    int
+	pending []string
+}
+
+func newCsvReader(r io.Reader) *csvReader {
+	reader := csv.NewReader(r)
+	// rows may have less cells than the header
+	reader.FieldsPerRecord = -1
+	return &csvReader{reader: reader}
+}
+
+func (r *csvReader) Read() ([]string, error) {
+	if r.pending == nil {
+		record, err := r.reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		if r.line == 0 && len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], utf8Bom)
+		}
+		r.pending = record
+	}
+	r.line++
+	if line, _ := r.reader.FieldPos(0); line > r.line {
+		return []string{}, nil
+	}
+	record := r.pending
+	r.pending = nil
+	// a quoted cell may span lines
+	r.line, _ = r.reader.FieldPos(len(record) - 1)
+	return record, nil
+}
+
+func (r *csvReader) Close() error {
+	return nil
+}
+
+// xlsxReader reads the first sheet of a workbook
+type xlsxReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+}
+
+func newXlsxReader(r io.Reader) (*xlsxReader, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := file.Rows(file.GetSheetName(0))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxReader{file: file, rows: rows}, nil
+}
+
+func (r *xlsxReader) Read() ([]string, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return r.rows.Columns()
+}
+
+func (r *xlsxReader) Close() error {
+	r.rows.Close()
+	return r.file.Close()
+}
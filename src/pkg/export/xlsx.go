@@ -0,0 +1,61 @@
+package export
+
+import (
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const sheetName = "Sheet1"
+
+// xlsxWriter uses the excelize stream writer, rows are kept in a temp file
+// instead of memory and the workbook is written to out on Close. A workbook is a zip
+// that can not be sent before it is complete, so unlike CSV nothing is streamed and
+// the handler caps XLSX exports with ExportConfig.XlsxMaxRows
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXlsxWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(sheetName)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: file, stream: stream, row: 1}, nil
+}
+
+func (w *xlsxWriter) WriteHeader(columns []Column) error {
+	values := make([]any, 0, len(columns))
+	for _, column := range columns {
+		values = append(values, column.Header)
+	}
+	return w.WriteRow(values)
+}
+
+func (w *xlsxWriter) WriteRow(values []any) error {
+	// strings are written as shared strings, never as formulas, so no sanitize is needed here
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	w.row++
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxWriter) Flush() error {
+	return nil
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := w.file.WriteTo(w.out)
+	return err
+}
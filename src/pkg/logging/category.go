@@ -33,6 +33,11 @@ const (
 	HashPassword        SubCategory = "HashPassword"
 	DefaultRoleNotFound SubCategory = "DefaultRoleNotFound"
 	FailedToCreateUser  SubCategory = "FailedToCreateUser"
+	Import              SubCategory = "Import"
+	Outbox              SubCategory = "Outbox"
+	Webhook             SubCategory = "Webhook"
+	Notification        SubCategory = "Notification"
+	ListingExpiry       SubCategory = "ListingExpiry"
 
 	// Validation
 	MobileValidation   SubCategory = "MobileValidation"
@@ -55,4 +60,8 @@ const (
 	RequestBody  ExtraKey = "RequestBody"
 	ResponseBody ExtraKey = "ResponseBody"
 	ErrorMessage ExtraKey = "ErrorMessage"
+	Sink         ExtraKey = "Sink"
+	EventId      ExtraKey = "EventId"
+	Channel      ExtraKey = "Channel"
+	Body         ExtraKey = "Body"
 )
@@ -9,4 +9,32 @@ var DbCall = prometheus.NewCounterVec(
 		Name: "db_calls_total",
 		Help: "Number of database calls",
 	},[]string{"type_name","operation_name", "status"},
-)
\ No newline at end of file
+)
+
+var CacheCall = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "repository_cache_calls_total",
+		Help: "Number of repository cache lookups",
+	}, []string{"type_name", "operation_name", "status"},
+)
+
+var OutboxPublish = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "outbox_publish_total",
+		Help: "Number of events published to outbox sinks",
+	}, []string{"sink", "event_type", "status"},
+)
+
+var Notification = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "notifications_total",
+		Help: "Number of notifications sent to users",
+	}, []string{"channel", "status"},
+)
+
+var WebhookDelivery = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "webhook_deliveries_total",
+		Help: "Number of webhook delivery attempts",
+	}, []string{"event_type", "status"},
+)
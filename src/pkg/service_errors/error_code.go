@@ -19,6 +19,65 @@ const (
 	PermissionDenied = "Permission denied"
 	UsernameOrPasswordInvalid = "username or password invalid"
 
+	// Limiter
+	NotAllowed = "not allowed"
+
+	// Tenant
+	TenantSlugExists    = "Tenant slug exists"
+	DefaultTenantLocked = "Default tenant locked"
+
+	// Import
+	ImportFormatNotSupported = "Import file format not supported"
+	ImportFileInvalid        = "Import file invalid"
+	ImportTooManyRows        = "Import file has too many rows"
+
+	// Webhook
+	WebhookEventTypeInvalid = "Webhook event type invalid"
+	WebhookUrlNotAllowed    = "Webhook url not allowed"
+
+	// Price analytics
+	PriceBucketInvalid = "Price bucket invalid"
+
+	// Exchange rate
+	ExchangeRateExists = "Exchange rate exists"
+
+	// Price alert
+	PriceAlertRuleInvalid = "Price alert rule invalid"
+
+	// Compare
+	CompareCarModelsInvalid = "Car models to compare invalid"
+
+	// Comment
+	CommentAlreadyReported = "Comment already reported"
+	CommentStatusInvalid   = "Comment status invalid"
+	CommentReportInvalid   = "Comment report invalid"
+	CommentParentInvalid   = "Comment parent invalid"
+	CommentVoteInvalid     = "Comment vote invalid"
+
+	// Rating
+	RatingStarsInvalid = "Rating stars invalid"
+
+	// Watchlist
+	WatchlistEntryInvalid = "Watchlist entry invalid"
+	WatchlistEntryExists  = "Watchlist entry exists"
+	WatchlistFull         = "Watchlist full"
+
+	// Listing
+	ListingStatusInvalid = "Listing status invalid"
+	ListingImagesFull    = "Listing images full"
+
+	// Car model image
+	CarModelImageMainRequired = "Car model image main required"
+	CarModelImageOrderInvalid = "Car model image order invalid"
+
+	// Facets
+	FacetFilterInvalid = "Facet filter invalid"
+
+	// Property
+	PropertyTypeInvalid  = "Property type invalid"
+	PropertyValueInvalid = "Property value invalid"
+	PropertyTypeInUse    = "Property type in use"
+
 	// DB
 	RecordNotFound = "record not found"
 )
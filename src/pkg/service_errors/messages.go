@@ -0,0 +1,181 @@
+package service_errors
+
+import "github.com/naeemaei/golang-clean-web-api/constant"
+
+// messages translates the error codes to the end user messages of every supported language
+var messages = map[string]map[string]string{
+	constant.EnglishLanguage: {
+		// Token
+		UnExpectedError: "An unexpected error occurred",
+		ClaimsNotFound:  "Token claims not found",
+		TokenRequired:   "Token is required",
+		TokenExpired:    "Token has expired",
+		TokenInvalid:    "Token is invalid",
+
+		// OTP
+		OptExists:   "An otp has already been sent, try again later",
+		OtpUsed:     "Otp has already been used",
+		OtpNotValid: "Otp is invalid",
+
+		// User
+		EmailExists:               "Email already exists",
+		UsernameExists:            "Username already exists",
+		PermissionDenied:          "Permission denied",
+		UsernameOrPasswordInvalid: "Username or password is invalid",
+
+		// Limiter
+		NotAllowed: "Too many requests, try again later",
+
+		// Tenant
+		TenantSlugExists:    "Tenant slug already exists",
+		DefaultTenantLocked: "The platform tenant can not be disabled or deleted",
+
+		// Import
+		ImportFormatNotSupported: "Import file format is not supported",
+		ImportFileInvalid:        "Import file is invalid",
+		ImportTooManyRows:        "Import file has too many rows",
+
+		// Webhook
+		WebhookEventTypeInvalid: "Webhook event type is invalid",
+		WebhookUrlNotAllowed:    "Webhook url must be a public http or https address",
+
+		// Price analytics
+		PriceBucketInvalid: "Price bucket is invalid",
+
+		// Exchange rate
+		ExchangeRateExists: "An exchange rate of the currency at this time already exists",
+
+		// Price alert
+		PriceAlertRuleInvalid: "Price alert rule is invalid",
+
+		// Compare
+		CompareCarModelsInvalid: "Car models to compare are invalid",
+
+		// Comment
+		CommentAlreadyReported: "Comment has already been reported",
+		CommentStatusInvalid:   "Comment status is invalid",
+		CommentReportInvalid:   "Comment report is invalid",
+		CommentParentInvalid:   "Parent comment is invalid",
+		CommentVoteInvalid:     "Comment vote is invalid",
+
+		// Rating
+		RatingStarsInvalid: "Rating stars are invalid",
+
+		// Watchlist
+		WatchlistEntryInvalid: "Watchlist entry is invalid",
+		WatchlistEntryExists:  "Watchlist entry already exists",
+		WatchlistFull:         "Watchlist is full",
+
+		// Listing
+		ListingStatusInvalid: "Listing status does not allow this change",
+		ListingImagesFull:    "Listing has the maximum number of images",
+
+		// Car model image
+		CarModelImageMainRequired: "A car model must have a main image",
+		CarModelImageOrderInvalid: "Car model image order is invalid",
+
+		// Facets
+		FacetFilterInvalid: "Facet filter is invalid",
+
+		// Property
+		PropertyTypeInvalid:  "Property type is invalid",
+		PropertyValueInvalid: "Property value is invalid",
+		PropertyTypeInUse:    "Property type can not be narrowed while car models have values of it",
+
+		// DB
+		RecordNotFound: "Record not found",
+	},
+	constant.PersianLanguage: {
+		// Token
+		UnExpectedError: "خطای غیرمنتظره‌ای رخ داد",
+		ClaimsNotFound:  "اطلاعات توکن یافت نشد",
+		TokenRequired:   "توکن الزامی است",
+		TokenExpired:    "توکن منقضی شده است",
+		TokenInvalid:    "توکن نامعتبر است",
+
+		// OTP
+		OptExists:   "کد یکبار مصرف قبلا ارسال شده است، بعدا تلاش کنید",
+		OtpUsed:     "کد یکبار مصرف قبلا استفاده شده است",
+		OtpNotValid: "کد یکبار مصرف نامعتبر است",
+
+		// User
+		EmailExists:               "ایمیل تکراری است",
+		UsernameExists:            "نام کاربری تکراری است",
+		PermissionDenied:          "دسترسی مجاز نیست",
+		UsernameOrPasswordInvalid: "نام کاربری یا رمز عبور نادرست است",
+
+		// Limiter
+		NotAllowed: "تعداد درخواست‌ها بیش از حد مجاز است، بعدا تلاش کنید",
+
+		// Tenant
+		TenantSlugExists:    "شناسه مستاجر تکراری است",
+		DefaultTenantLocked: "مستاجر اصلی قابل غیرفعال‌سازی یا حذف نیست",
+
+		// Import
+		ImportFormatNotSupported: "قالب فایل ورودی پشتیبانی نمی‌شود",
+		ImportFileInvalid:        "فایل ورودی نامعتبر است",
+		ImportTooManyRows:        "تعداد ردیف‌های فایل ورودی بیش از حد مجاز است",
+
+		// Webhook
+		WebhookEventTypeInvalid: "نوع رویداد وب‌هوک نامعتبر است",
+		WebhookUrlNotAllowed:    "آدرس وب‌هوک باید یک آدرس عمومی http یا https باشد",
+
+		// Price analytics
+		PriceBucketInvalid: "بازه قیمت نامعتبر است",
+
+		// Exchange rate
+		ExchangeRateExists: "نرخ ارز برای این زمان قبلا ثبت شده است",
+
+		// Price alert
+		PriceAlertRuleInvalid: "شرط هشدار قیمت نامعتبر است",
+
+		// Compare
+		CompareCarModelsInvalid: "مدل‌های خودرو برای مقایسه نامعتبر هستند",
+
+		// Comment
+		CommentAlreadyReported: "این نظر قبلا گزارش شده است",
+		CommentStatusInvalid:   "وضعیت نظر نامعتبر است",
+		CommentReportInvalid:   "گزارش نظر نامعتبر است",
+		CommentParentInvalid:   "نظر والد نامعتبر است",
+		CommentVoteInvalid:     "رای نظر نامعتبر است",
+
+		// Rating
+		RatingStarsInvalid: "تعداد ستاره‌های امتیاز نامعتبر است",
+
+		// Watchlist
+		WatchlistEntryInvalid: "مورد فهرست پیگیری نامعتبر است",
+		WatchlistEntryExists:  "این مورد در فهرست پیگیری وجود دارد",
+		WatchlistFull:         "فهرست پیگیری پر است",
+
+		// Listing
+		ListingStatusInvalid: "وضعیت آگهی اجازه این تغییر را نمی‌دهد",
+		ListingImagesFull:    "تعداد تصاویر آگهی به حداکثر رسیده است",
+
+		// Car model image
+		CarModelImageMainRequired: "مدل خودرو باید یک تصویر اصلی داشته باشد",
+		CarModelImageOrderInvalid: "ترتیب تصاویر مدل خودرو نامعتبر است",
+
+		// Facets
+		FacetFilterInvalid: "فیلتر دسته‌بندی نامعتبر است",
+
+		// Property
+		PropertyTypeInvalid:  "نوع ویژگی نامعتبر است",
+		PropertyValueInvalid: "مقدار ویژگی نامعتبر است",
+		PropertyTypeInUse:    "نوع ویژگی تا زمانی که مدل‌های خودرو مقداری از آن دارند قابل محدود کردن نیست",
+
+		// DB
+		RecordNotFound: "رکورد یافت نشد",
+	},
+}
+
+// Message returns the end user message of an error code in language, falling back to English and
+// then to the code itself, so errors without a translation are returned as they are
+func Message(code string, language string) string {
+	if message, ok := messages[language][code]; ok {
+		return message
+	}
+	if message, ok := messages[constant.DefaultLanguage][code]; ok {
+		return message
+	}
+	return code
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Receiver is a webhook target for development, it prints every delivery to out and answers
+// with status so retries and dead lettering can be tried out. Signatures are verified when
+// secret is set.
+type Receiver struct {
+	secret    string
+	status    int
+	tolerance time.Duration
+	out       io.Writer
+	received  uint64
+}
+
+func NewReceiver(secret string, status int, out io.Writer) *Receiver {
+	return &Receiver{secret: secret, status: status, tolerance: 5 * time.Minute, out: out}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n := atomic.AddUint64(&r.received, 1)
+	verified := "not checked"
+	status := r.status
+	if r.secret != "" {
+		verified = "valid"
+		if err := Verify(r.secret, req.Header.Get(SignatureHeader), body, r.tolerance); err != nil {
+			verified = err.Error()
+			status = http.StatusUnauthorized
+		}
+	}
+	fmt.Fprintf(r.out, "#%d %s %s delivery=%s attempt=%s event=%s type=%s signature=%s -> %d\n%s\n",
+		n, req.Method, req.URL.Path, req.Header.Get(DeliveryHeader), req.Header.Get(AttemptHeader),
+		req.Header.Get(EventIdHeader), req.Header.Get(EventTypeHeader), verified, status, body)
+	w.WriteHeader(status)
+}
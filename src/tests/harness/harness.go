@@ -0,0 +1,171 @@
+// Package harness runs the whole gin router against in-memory repositories and a fake redis,
+// so handlers and usecases can be tested end to end without postgres or redis.
+package harness
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/naeemaei/golang-clean-web-api/api"
+	"github.com/naeemaei/golang-clean-web-api/api/helper"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/infra/cache"
+	"github.com/naeemaei/golang-clean-web-api/infra/persistence/memory"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AdminUsername = constant.DefaultUserName
+	AdminPassword = "12345678"
+)
+
+type Harness struct {
+	Cfg    *config.Config
+	Store  *memory.Store
+	Redis  *miniredis.Miniredis
+	Router *gin.Engine
+
+	requests uint32
+}
+
+// New starts a fake redis, seeds the platform tenant, roles and the admin user like the migrations do
+// and builds the router, configure can change the config before that
+func New(configure ...func(cfg *config.Config)) (*Harness, error) {
+	cfg := config.GetConfig()
+	cfg.Server.RunMode = gin.TestMode
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	redis, err := miniredis.Run()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Redis.Password != "" {
+		redis.RequireAuth(cfg.Redis.Password)
+	}
+	cfg.Redis.Host = redis.Host()
+	cfg.Redis.Port = redis.Port()
+	if err = cache.InitRedis(cfg); err != nil {
+		redis.Close()
+		return nil, err
+	}
+
+	store := memory.NewStore()
+	memory.Seed(store, model.Tenant{Name: "Default", Slug: constant.DefaultTenantSlug, Enabled: true})
+	roles := memory.Seed(store, model.Role{Name: constant.AdminRoleName}, model.Role{Name: constant.DefaultRoleName},
+		model.Role{Name: constant.TenantAdminRoleName})
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(AdminPassword), bcrypt.MinCost)
+	if err != nil {
+		redis.Close()
+		return nil, err
+	}
+	admin := memory.Seed(store, model.User{TenantModel: model.TenantModel{TenantId: constant.DefaultTenantId}, Username: AdminUsername, FirstName: "Test", LastName: "Test",
+		MobileNumber: "09111112222", Email: "admin@admin.com", Password: string(hashedPassword), Enabled: true})[0]
+	memory.Seed(store, model.UserRole{UserId: admin.Id, RoleId: roles[0].Id})
+
+	var missing []reflect.Type
+	dependency.UseRepositories(repositories{store: store, missing: &missing})
+	router := api.NewRouter(cfg)
+	if len(missing) > 0 {
+		cache.CloseRedis()
+		redis.Close()
+		dependency.UseRepositories(nil)
+		return nil, fmt.Errorf("harness: no in-memory repository for %v, register them in repositories.go", missing)
+	}
+	return &Harness{Cfg: cfg, Store: store, Redis: redis, Router: router}, nil
+}
+
+func (h *Harness) Close() {
+	cache.CloseRedis()
+	h.Redis.Close()
+	dependency.UseRepositories(nil)
+}
+
+// Do sends a request to the router, body is encoded as json when it is not nil.
+// Every request gets its own remote address so the ip rate limiters do not kick in
+func (h *Harness) Do(method string, path string, body any, token string) *httptest.ResponseRecorder {
+	return h.DoWithHeaders(method, path, body, token, nil)
+}
+
+// DoWithHeaders sends a request like Do with the headers set
+func (h *Harness) DoWithHeaders(method string, path string, body any, token string, headers map[string]string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		raw, _ := json.Marshal(body)
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return h.send(req, token)
+}
+
+// Upload posts a multipart form with fields and content as its file field
+func (h *Harness) Upload(path string, fields map[string]string, fileName string, content []byte, token string) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for k, v := range fields {
+		_ = writer.WriteField(k, v)
+	}
+	part, _ := writer.CreateFormFile("file", fileName)
+	_, _ = part.Write(content)
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return h.send(req, token)
+}
+
+func (h *Harness) send(req *http.Request, token string) *httptest.ResponseRecorder {
+	if token != "" {
+		req.Header.Set(constant.AuthorizationHeaderKey, "Bearer "+token)
+	}
+	n := atomic.AddUint32(&h.requests, 1)
+	req.RemoteAddr = fmt.Sprintf("10.%d.%d.%d:1234", n>>16&0xff, n>>8&0xff, n&0xff)
+
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, req)
+	return w
+}
+
+// Login returns an access token of the user
+func (h *Harness) Login(username string, password string) (string, error) {
+	w := h.Do(http.MethodPost, "/api/v1/users/login-by-username",
+		map[string]string{"username": username, "password": password}, "")
+	res := struct {
+		Result struct {
+			AccessToken string `json:"accessToken"`
+		} `json:"result"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		return "", err
+	}
+	if w.Code >= http.StatusBadRequest {
+		return "", fmt.Errorf("login failed with status %d: %s", w.Code, w.Body.String())
+	}
+	return res.Result.AccessToken, nil
+}
+
+// Decode reads a BaseHttpResponse and decodes its result into result
+func Decode(w *httptest.ResponseRecorder, result any) (*helper.BaseHttpResponse, error) {
+	res := &helper.BaseHttpResponse{Result: result}
+	if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
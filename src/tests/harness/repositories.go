@@ -0,0 +1,124 @@
+package harness
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+	contractRepository "github.com/naeemaei/golang-clean-web-api/domain/repository"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/infra/persistence/memory"
+)
+
+// repositories builds the repositories of the dependency package on the in-memory store
+type repositories struct {
+	store *memory.Store
+	// missing collects the entities Base has no repository for, New fails on them
+	missing *[]reflect.Type
+}
+
+// bases builds the in-memory base repositories by entity type, see register
+var bases = map[reflect.Type]func(store *memory.Store, preloads []database.PreloadEntity) any{}
+
+// register lets Base build the in-memory base repository of TEntity
+func register[TEntity any]() {
+	bases[reflect.TypeFor[TEntity]()] = func(store *memory.Store, preloads []database.PreloadEntity) any {
+		return memory.NewBaseRepository[TEntity](store, preloads)
+	}
+}
+
+func init() {
+	register[model.PersianYear]()
+	register[model.Country]()
+	register[model.City]()
+	register[model.Company]()
+	register[model.CarType]()
+	register[model.Color]()
+	register[model.Gearbox]()
+	register[model.File]()
+	register[model.PropertyCategory]()
+	register[model.Property]()
+	register[model.CarModelColor]()
+	register[model.CarModelYear]()
+	register[model.WatchlistEntry]()
+	register[model.ListingImage]()
+	register[model.Tenant]()
+	register[model.Role]()
+}
+
+// Base builds the repository of a registered entity. The router is built with every repository, so
+// an entity that is not registered fails New instead of reaching postgres
+func (r repositories) Base(entity any, preloads []database.PreloadEntity) any {
+	build, ok := bases[reflect.TypeOf(entity)]
+	if !ok {
+		if !slices.Contains(*r.missing, reflect.TypeOf(entity)) {
+			*r.missing = append(*r.missing, reflect.TypeOf(entity))
+		}
+		return nil
+	}
+	return build(r.store, preloads)
+}
+
+func (r repositories) User() contractRepository.UserRepository {
+	return memory.NewUserRepository(r.store)
+}
+
+func (r repositories) CarModelComment(preloads []database.PreloadEntity) contractRepository.CarModelCommentRepository {
+	return memory.NewCarModelCommentRepository(r.store, preloads)
+}
+
+func (r repositories) CarModelImage(preloads []database.PreloadEntity) contractRepository.CarModelImageRepository {
+	return memory.NewCarModelImageRepository(r.store, preloads)
+}
+
+func (r repositories) CarModelPriceHistory(preloads []database.PreloadEntity) contractRepository.CarModelPriceHistoryRepository {
+	return memory.NewCarModelPriceHistoryRepository(r.store, preloads)
+}
+
+func (r repositories) CarModelProperty(preloads []database.PreloadEntity) contractRepository.CarModelPropertyRepository {
+	return memory.NewCarModelPropertyRepository(r.store, preloads)
+}
+
+func (r repositories) ExchangeRate(preloads []database.PreloadEntity) contractRepository.ExchangeRateRepository {
+	return memory.NewExchangeRateRepository(r.store, preloads)
+}
+
+func (r repositories) CarModelRating(preloads []database.PreloadEntity) contractRepository.CarModelRatingRepository {
+	return memory.NewCarModelRatingRepository(r.store, preloads)
+}
+
+func (r repositories) CarModel(preloads []database.PreloadEntity) contractRepository.CarModelRepository {
+	return memory.NewCarModelRepository(r.store, preloads)
+}
+
+func (r repositories) CatalogImport() contractRepository.CatalogImportRepository {
+	return memory.NewCatalogImportRepository(r.store)
+}
+
+func (r repositories) Outbox() contractRepository.OutboxRepository {
+	return memory.NewOutboxRepository(r.store)
+}
+
+func (r repositories) PriceAlert(preloads []database.PreloadEntity) contractRepository.PriceAlertRepository {
+	return memory.NewPriceAlertRepository(r.store, preloads)
+}
+
+func (r repositories) Watchlist(preloads []database.PreloadEntity) contractRepository.WatchlistRepository {
+	return memory.NewWatchlistRepository(r.store, preloads)
+}
+
+func (r repositories) Listing(preloads []database.PreloadEntity) contractRepository.ListingRepository {
+	return memory.NewListingRepository(r.store, preloads)
+}
+
+func (r repositories) Notification(preloads []database.PreloadEntity) contractRepository.NotificationRepository {
+	return memory.NewNotificationRepository(r.store, preloads)
+}
+
+func (r repositories) WebhookSubscription(preloads []database.PreloadEntity) contractRepository.WebhookSubscriptionRepository {
+	return memory.NewWebhookSubscriptionRepository(r.store, preloads)
+}
+
+func (r repositories) WebhookDelivery(preloads []database.PreloadEntity) contractRepository.WebhookDeliveryRepository {
+	return memory.NewWebhookDeliveryRepository(r.store, preloads)
+}
@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/tests/harness"
+)
+
+type cityResponse struct {
+	Name    string `json:"name"`
+	Country struct {
+		Name string `json:"name"`
+	} `json:"country"`
+}
+
+func cachedKeys(h *harness.Harness, typeName string) []string {
+	result := []string{}
+	for _, key := range h.Redis.Keys() {
+		if strings.HasPrefix(key, fmt.Sprintf("%s:%s:v", constant.RedisRepositoryKey, typeName)) && !strings.HasSuffix(key, ":version") {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+func TestCacheIsInvalidatedByWrites(t *testing.T) {
+	h, token := newHarness(t, func(cfg *config.Config) { cfg.Cache.Enabled = true })
+	countryId := create(t, h, "/api/v1/countries/", map[string]any{"name": "Iran"}, token)
+	cityId := create(t, h, "/api/v1/cities/", map[string]any{"name": "Tehran", "countryId": countryId}, token)
+	path := fmt.Sprintf("/api/v1/cities/%d", cityId)
+
+	city := cityResponse{}
+	call(t, h, http.MethodGet, path, nil, token, http.StatusOK, &city)
+	call(t, h, http.MethodGet, path, nil, token, http.StatusOK, &city)
+	if keys := cachedKeys(h, "City"); len(keys) != 1 {
+		t.Fatalf("cached city keys %v, want one", keys)
+	}
+
+	versionKey := fmt.Sprintf("%s:City:version", constant.RedisRepositoryKey)
+	before, _ := h.Redis.Get(versionKey)
+	call(t, h, http.MethodPut, path, map[string]any{"name": "Shiraz"}, token, http.StatusOK, nil)
+	if after, _ := h.Redis.Get(versionKey); after == before {
+		t.Fatalf("city version %q is not bumped by the update", after)
+	}
+	call(t, h, http.MethodGet, path, nil, token, http.StatusOK, &city)
+	if city.Name != "Shiraz" {
+		t.Fatalf("city name %q after update, want Shiraz", city.Name)
+	}
+}
+
+func TestCacheIsInvalidatedByPreloadedRelations(t *testing.T) {
+	h, token := newHarness(t, func(cfg *config.Config) { cfg.Cache.Enabled = true })
+	countryId := create(t, h, "/api/v1/countries/", map[string]any{"name": "Iran"}, token)
+	cityId := create(t, h, "/api/v1/cities/", map[string]any{"name": "Tehran", "countryId": countryId}, token)
+	path := fmt.Sprintf("/api/v1/cities/%d", cityId)
+
+	city := cityResponse{}
+	call(t, h, http.MethodGet, path, nil, token, http.StatusOK, &city)
+	if city.Country.Name != "Iran" {
+		t.Fatalf("country of city %q, want Iran", city.Country.Name)
+	}
+
+	call(t, h, http.MethodPut, fmt.Sprintf("/api/v1/countries/%d", countryId), map[string]any{"name": "Persia"}, token, http.StatusOK, nil)
+	call(t, h, http.MethodGet, path, nil, token, http.StatusOK, &city)
+	if city.Country.Name != "Persia" {
+		t.Fatalf("country of city %q after the country update, want Persia", city.Country.Name)
+	}
+	if keys := cachedKeys(h, "City"); len(keys) != 2 {
+		t.Fatalf("cached city keys %v, want one per country version", keys)
+	}
+}
@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCommentOwnershipAndModeration(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	author := newUser(t, h, "author")
+	reader := newUser(t, h, "reader")
+
+	commentId := create(t, h, "/api/v1/car-model-comments/", map[string]any{"carModelId": c.carModelId, "message": "Good car"}, author)
+	path := fmt.Sprintf("/api/v1/car-model-comments/%d", commentId)
+	comment := struct {
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	}{}
+	visible := func(token string) int64 {
+		t.Helper()
+		page := struct {
+			TotalRows int64 `json:"totalRows"`
+		}{}
+		call(t, h, http.MethodPost, "/api/v1/car-model-comments/get-by-filter", map[string]any{"pageNumber": 1, "pageSize": 10}, token, http.StatusOK, &page)
+		return page.TotalRows
+	}
+
+	// a pending comment is seen by its author and the moderators only
+	call(t, h, http.MethodGet, path, nil, author, http.StatusOK, &comment)
+	if comment.Status != "pending" {
+		t.Fatalf("new comment is %q, want pending", comment.Status)
+	}
+	call(t, h, http.MethodGet, path, nil, reader, http.StatusNotFound, nil)
+	call(t, h, http.MethodGet, path, nil, token, http.StatusOK, nil)
+	if rows := visible(reader); rows != 0 {
+		t.Fatalf("reader lists %d pending comment(s)", rows)
+	}
+	call(t, h, http.MethodPut, path+"/vote", map[string]any{"helpful": true}, reader, http.StatusNotFound, nil)
+
+	// only the author changes the comment
+	call(t, h, http.MethodPut, path, map[string]any{"message": "Hacked"}, reader, http.StatusNotFound, nil)
+	call(t, h, http.MethodDelete, path, nil, reader, http.StatusNotFound, nil)
+	call(t, h, http.MethodPut, path+"/moderation", map[string]any{"status": "approved"}, reader, http.StatusForbidden, nil)
+
+	call(t, h, http.MethodPut, path+"/moderation", map[string]any{"status": "approved"}, token, http.StatusOK, nil)
+	call(t, h, http.MethodGet, path, nil, reader, http.StatusOK, &comment)
+	if comment.Message != "Good car" || comment.Status != "approved" {
+		t.Fatalf("approved comment is %+v", comment)
+	}
+	if rows := visible(reader); rows != 1 {
+		t.Fatalf("reader lists %d approved comment(s), want 1", rows)
+	}
+	call(t, h, http.MethodPut, path+"/vote", map[string]any{"helpful": true}, author, http.StatusBadRequest, nil)
+
+	// an edit is moderated again
+	call(t, h, http.MethodPut, path, map[string]any{"message": "Very good car"}, author, http.StatusOK, nil)
+	call(t, h, http.MethodGet, path, nil, reader, http.StatusNotFound, nil)
+
+	call(t, h, http.MethodDelete, path, nil, author, http.StatusOK, nil)
+	call(t, h, http.MethodGet, path, nil, author, http.StatusNotFound, nil)
+}
@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type convertedPrice struct {
+	Id                int      `json:"id"`
+	Price             float64  `json:"price"`
+	Currency          string   `json:"currency"`
+	ConvertedPrice    *float64 `json:"convertedPrice"`
+	ConvertedCurrency string   `json:"convertedCurrency"`
+}
+
+func TestPricesAreConvertedWithTheRateAtTheirTime(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	// rials of one dollar, a toman is 10 rials
+	create(t, h, "/api/v1/exchange-rates/", map[string]any{"currency": "USD", "rate": 500000, "rateAt": "2023-04-01T00:00:00Z"}, token)
+	create(t, h, "/api/v1/exchange-rates/", map[string]any{"currency": "USD", "rate": 600000, "rateAt": "2023-05-01T00:00:00Z"}, token)
+	addPrice := func(priceAt string, price float64, currency string) int {
+		return create(t, h, "/api/v1/car-model-price-histories/", map[string]any{"carModelYearId": c.carModelYearId,
+			"priceAt": priceAt, "price": price, "currency": currency}, token)
+	}
+	beforeRates := addPrice("2023-03-25T00:00:00Z", 10000, "USD")
+	april := addPrice("2023-04-10T00:00:00Z", 10000, "USD")
+	may := addPrice("2023-05-10T00:00:00Z", 10000, "USD")
+	rials := addPrice("2023-05-10T00:00:00Z", 6000000000, "IRR")
+
+	cases := []struct {
+		id       int
+		currency string
+		want     *float64
+	}{
+		{april, "IRT", ptr(500000000)},
+		{may, "IRT", ptr(600000000)},
+		{may, "EUR", nil},
+		{beforeRates, "IRT", nil},
+		{rials, "IRT", ptr(600000000)},
+		{rials, "USD", ptr(10000)},
+	}
+	for _, tc := range cases {
+		price := convertedPrice{}
+		call(t, h, http.MethodGet, fmt.Sprintf("/api/v1/car-model-price-histories/%d?currency=%s", tc.id, tc.currency), nil, token, http.StatusOK, &price)
+		switch {
+		case tc.want == nil && price.ConvertedPrice != nil:
+			t.Errorf("price %d in %s is converted to %v without a rate", tc.id, tc.currency, *price.ConvertedPrice)
+		case tc.want != nil && (price.ConvertedPrice == nil || *price.ConvertedPrice != *tc.want || price.ConvertedCurrency != tc.currency):
+			t.Errorf("price %d in %s is %v %s, want %v", tc.id, tc.currency, price.ConvertedPrice, price.ConvertedCurrency, *tc.want)
+		}
+	}
+
+	page := struct {
+		Items []convertedPrice `json:"items"`
+	}{}
+	call(t, h, http.MethodPost, "/api/v1/car-model-price-histories/get-by-filter?currency=IRT", map[string]any{"pageNumber": 1, "pageSize": 10},
+		token, http.StatusOK, &page)
+	converted := 0
+	for _, price := range page.Items {
+		if price.ConvertedPrice != nil {
+			converted++
+		}
+	}
+	if len(page.Items) != 4 || converted != 3 {
+		t.Fatalf("%d of %d filtered prices are converted, want 3 of 4", converted, len(page.Items))
+	}
+}
+
+func TestCompareConvertsPricesToToman(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	otherId := newCarModel(t, h, token, c, "Tiba")
+	otherYearId := create(t, h, "/api/v1/car-model-years/", map[string]any{"carModelId": otherId, "persianYearId": c.persianYearId}, token)
+	create(t, h, "/api/v1/exchange-rates/", map[string]any{"currency": "EUR", "rate": 550000, "rateAt": "2023-04-01T00:00:00Z"}, token)
+	create(t, h, "/api/v1/car-model-price-histories/", map[string]any{"carModelYearId": c.carModelYearId,
+		"priceAt": "2023-04-10T00:00:00Z", "price": 300000000}, token)
+	create(t, h, "/api/v1/car-model-price-histories/", map[string]any{"carModelYearId": otherYearId,
+		"priceAt": "2023-04-10T00:00:00Z", "price": 10000, "currency": "EUR"}, token)
+
+	comparison := struct {
+		Currency string `json:"currency"`
+		Prices   []struct {
+			Prices []*float64 `json:"prices"`
+		} `json:"prices"`
+	}{}
+	call(t, h, http.MethodGet, fmt.Sprintf("/api/v1/car-models/compare?ids=%d,%d", c.carModelId, otherId), nil, token, http.StatusOK, &comparison)
+	if comparison.Currency != "IRT" || len(comparison.Prices) != 1 {
+		t.Fatalf("comparison prices %+v in %q", comparison.Prices, comparison.Currency)
+	}
+	prices := comparison.Prices[0].Prices
+	if len(prices) != 2 || prices[0] == nil || *prices[0] != 300000000 || prices[1] == nil || *prices[1] != 550000000 {
+		t.Fatalf("compared prices %v, want [300000000 550000000]", prices)
+	}
+}
+
+func TestExchangeRateTimesAreUnique(t *testing.T) {
+	h, token := newHarness(t)
+	create(t, h, "/api/v1/exchange-rates/", map[string]any{"currency": "USD", "rate": 500000, "rateAt": "2023-04-01T00:00:00Z"}, token)
+	id := create(t, h, "/api/v1/exchange-rates/", map[string]any{"currency": "USD", "rate": 600000, "rateAt": "2023-05-01T00:00:00Z"}, token)
+	create(t, h, "/api/v1/exchange-rates/", map[string]any{"currency": "EUR", "rate": 550000, "rateAt": "2023-04-01T00:00:00Z"}, token)
+
+	w := h.Do(http.MethodPost, "/api/v1/exchange-rates/", map[string]any{"currency": "USD", "rate": 1, "rateAt": "2023-04-01T00:00:00Z"}, token)
+	if w.Code < http.StatusBadRequest {
+		t.Fatalf("a duplicate rate is created with status %d", w.Code)
+	}
+	w = h.Do(http.MethodPut, fmt.Sprintf("/api/v1/exchange-rates/%d", id), map[string]any{"currency": "USD", "rate": 1, "rateAt": "2023-04-01T00:00:00Z"}, token)
+	if w.Code < http.StatusBadRequest {
+		t.Fatalf("a rate is moved to the time of another one with status %d", w.Code)
+	}
+	call(t, h, http.MethodPut, fmt.Sprintf("/api/v1/exchange-rates/%d", id), map[string]any{"currency": "USD", "rate": 650000, "rateAt": "2023-05-01T00:00:00Z"},
+		token, http.StatusOK, nil)
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,28 @@
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+)
+
+func TestExportFollowsTheConfig(t *testing.T) {
+	h, token := newHarness(t, func(cfg *config.Config) {
+		cfg.Export.BatchSize = 1
+		cfg.Export.MaxRows = 2
+	})
+	for _, name := range []string{"Iran", "Germany", "Japan"} {
+		create(t, h, "/api/v1/countries/", map[string]any{"name": name}, token)
+	}
+
+	w := h.Do(http.MethodPost, "/api/v1/countries/get-by-filter?format=csv&columns=name", map[string]any{}, token)
+	if w.Code != http.StatusOK || w.Header().Get("X-Export-Truncated") != "true" {
+		t.Fatalf("export is %d truncated %q: %s", w.Code, w.Header().Get("X-Export-Truncated"), w.Body.String())
+	}
+	lines := strings.Fields(strings.TrimPrefix(w.Body.String(), "\xEF\xBB\xBF"))
+	if strings.Join(lines, ",") != "name,Iran,Germany" {
+		t.Fatalf("export is %q, want the header and the first 2 countries", w.Body.String())
+	}
+}
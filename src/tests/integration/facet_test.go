@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type facetedCarModels struct {
+	Items struct {
+		TotalRows int64 `json:"totalRows"`
+		Items     []struct {
+			Id       int      `json:"id"`
+			Name     string   `json:"name"`
+			MinPrice *float64 `json:"minPrice"`
+		} `json:"items"`
+	} `json:"items"`
+	Facets []struct {
+		Field  string `json:"field"`
+		Values []struct {
+			Id       int    `json:"id"`
+			Name     string `json:"name"`
+			Count    int    `json:"count"`
+			Selected bool   `json:"selected"`
+		} `json:"values"`
+	} `json:"facets"`
+	PriceRanges []struct {
+		From  float64 `json:"from"`
+		To    float64 `json:"to"`
+		Count int     `json:"count"`
+	} `json:"priceRanges"`
+}
+
+func (f facetedCarModels) names() []string {
+	names := []string{}
+	for _, item := range f.Items.Items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+// counts returns the count and selection of every value of field by name
+func (f facetedCarModels) counts(field string) map[string][2]any {
+	counts := map[string][2]any{}
+	for _, facet := range f.Facets {
+		if facet.Field == field {
+			for _, value := range facet.Values {
+				counts[value.Name] = [2]any{value.Count, value.Selected}
+			}
+		}
+	}
+	return counts
+}
+
+func TestFacetedSearch(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	automaticId := create(t, h, "/api/v1/gearboxes/", map[string]any{"name": "Automatic"}, token)
+	tibaId := create(t, h, "/api/v1/car-models/", map[string]any{"name": "Tiba", "companyId": c.companyId,
+		"carTypeId": c.carTypeId, "gearboxId": automaticId}, token)
+	tibaYearId := create(t, h, "/api/v1/car-model-years/", map[string]any{"carModelId": tibaId, "persianYearId": c.persianYearId}, token)
+	create(t, h, "/api/v1/exchange-rates/", map[string]any{"currency": "USD", "rate": 500000, "rateAt": "2023-04-01T00:00:00Z"}, token)
+	create(t, h, "/api/v1/car-model-price-histories/", map[string]any{"carModelYearId": c.carModelYearId,
+		"priceAt": "2023-04-10T00:00:00Z", "price": 300000000}, token)
+	create(t, h, "/api/v1/car-model-price-histories/", map[string]any{"carModelYearId": tibaYearId,
+		"priceAt": "2023-04-10T00:00:00Z", "price": 1000, "currency": "USD"}, token)
+
+	search := func(filter map[string]any, sort []map[string]any, status int) facetedCarModels {
+		result := facetedCarModels{}
+		body := map[string]any{"pageNumber": 1, "pageSize": 10, "filter": filter, "sort": sort}
+		call(t, h, http.MethodPost, "/api/v1/car-models/facets", body, token, status, &result)
+		return result
+	}
+	both := search(map[string]any{"GearboxId": map[string]any{"type": "in", "from": fmt.Sprintf("%d,%d", c.gearboxId, automaticId)}},
+		[]map[string]any{{"colId": "Price", "sort": "desc"}}, http.StatusOK)
+	if names := both.names(); len(names) != 2 || names[0] != "Pride" {
+		t.Fatalf("in both gearboxes sorted by price found %v, want [Pride Tiba]", names)
+	}
+	if got := both.counts("GearboxId"); got["Manual"] != [2]any{1, true} || got["Automatic"] != [2]any{1, true} {
+		t.Fatalf("gearbox facet %v", got)
+	}
+
+	manual := search(map[string]any{"GearboxId": map[string]any{"type": "equals", "from": strconv.Itoa(c.gearboxId)}}, nil, http.StatusOK)
+	if names := manual.names(); len(names) != 1 || names[0] != "Pride" {
+		t.Fatalf("manual gearbox found %v, want [Pride]", names)
+	}
+	// the counts of a field ignore its own selection
+	if got := manual.counts("GearboxId"); got["Automatic"] != [2]any{1, false} || got["Manual"] != [2]any{1, true} {
+		t.Fatalf("gearbox facet of the manual selection %v", got)
+	}
+	if got := manual.counts("CarTypeId"); got["Sedan"] != [2]any{1, false} {
+		t.Fatalf("car type facet of the manual selection %v", got)
+	}
+
+	byName := search(map[string]any{"Name": map[string]any{"type": "contains", "from": "tib"}}, nil, http.StatusOK)
+	if names := byName.names(); len(names) != 1 || names[0] != "Tiba" {
+		t.Fatalf("name contains tib found %v, want [Tiba]", names)
+	}
+	if got := byName.counts("GearboxId"); len(got) != 1 || got["Automatic"] != [2]any{1, false} {
+		t.Fatalf("gearbox facet of the name filter %v", got)
+	}
+
+	// 1000 dollars at 500000 rials are 50000000 toman
+	expensive := search(map[string]any{"Price": map[string]any{"type": "inRange", "from": "40000000", "to": "60000000"}}, nil, http.StatusOK)
+	if names := expensive.names(); len(names) != 1 || names[0] != "Tiba" || *expensive.Items.Items[0].MinPrice != 50000000 {
+		t.Fatalf("price range found %+v, want Tiba at 50000000", expensive.Items.Items)
+	}
+	counted := 0
+	for _, priceRange := range expensive.PriceRanges {
+		counted += priceRange.Count
+	}
+	if counted != 2 {
+		t.Fatalf("price ranges %+v count %d car models, want both", expensive.PriceRanges, counted)
+	}
+
+	search(map[string]any{"Unknown": map[string]any{"type": "contains", "from": "x"}}, nil, http.StatusBadRequest)
+	search(map[string]any{"GearboxId": map[string]any{"type": "equals", "from": fmt.Sprintf("%d,%d", c.gearboxId, automaticId)}}, nil, http.StatusBadRequest)
+}
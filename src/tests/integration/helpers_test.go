@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/tests/harness"
+)
+
+func newHarness(t *testing.T, configure ...func(cfg *config.Config)) (*harness.Harness, string) {
+	t.Helper()
+	h, err := harness.New(configure...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(h.Close)
+	return h, login(t, h, harness.AdminUsername, harness.AdminPassword)
+}
+
+func login(t *testing.T, h *harness.Harness, username string, password string) string {
+	t.Helper()
+	token, err := h.Login(username, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// call sends a request, checks its status and decodes the result into result when it is not nil
+func call(t *testing.T, h *harness.Harness, method string, path string, body any, token string, status int, result any) {
+	t.Helper()
+	w := h.Do(method, path, body, token)
+	if w.Code != status {
+		t.Fatalf("%s %s: status %d, want %d: %s", method, path, w.Code, status, w.Body.String())
+	}
+	if result == nil {
+		return
+	}
+	if _, err := harness.Decode(w, result); err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+}
+
+// create posts body to path and returns the id of the created entity
+func create(t *testing.T, h *harness.Harness, path string, body any, token string) int {
+	t.Helper()
+	result := struct {
+		Id int `json:"id"`
+	}{}
+	call(t, h, http.MethodPost, path, body, token, http.StatusCreated, &result)
+	return result.Id
+}
+
+// catalog is the reference data of one car model year
+type catalog struct {
+	countryId, companyId, carTypeId, gearboxId, colorId, persianYearId int
+	carModelId, carModelYearId                                         int
+}
+
+func newCatalog(t *testing.T, h *harness.Harness, token string) catalog {
+	t.Helper()
+	c := catalog{}
+	c.countryId = create(t, h, "/api/v1/countries/", map[string]any{"name": "Iran"}, token)
+	c.companyId = create(t, h, "/api/v1/companies/", map[string]any{"name": "Saipa", "countryId": c.countryId}, token)
+	c.carTypeId = create(t, h, "/api/v1/car-types/", map[string]any{"name": "Sedan"}, token)
+	c.gearboxId = create(t, h, "/api/v1/gearboxes/", map[string]any{"name": "Manual"}, token)
+	c.colorId = create(t, h, "/api/v1/colors/", map[string]any{"name": "White", "hexCode": "#ffffff"}, token)
+	c.persianYearId = create(t, h, "/api/v1/years/", map[string]any{"persianTitle": "1402", "year": 1402}, token)
+	c.carModelId = newCarModel(t, h, token, c, "Pride")
+	c.carModelYearId = create(t, h, "/api/v1/car-model-years/", map[string]any{"carModelId": c.carModelId, "persianYearId": c.persianYearId}, token)
+	return c
+}
+
+func newCarModel(t *testing.T, h *harness.Harness, token string, c catalog, name string) int {
+	t.Helper()
+	return create(t, h, "/api/v1/car-models/", map[string]any{"name": name, "companyId": c.companyId,
+		"carTypeId": c.carTypeId, "gearboxId": c.gearboxId}, token)
+}
+
+// newTenant provisions a tenant with an admin and returns the token of the admin
+func newTenant(t *testing.T, h *harness.Harness, token string, slug string) (int, string) {
+	t.Helper()
+	tenantId := create(t, h, "/api/v1/tenants/", map[string]any{"name": slug, "slug": slug}, token)
+	username := slug + "admin"
+	create(t, h, fmt.Sprintf("/api/v1/tenants/%d/admins", tenantId), map[string]any{"firstName": "Tenant", "lastName": "Administrator",
+		"username": username, "email": username + "@example.com", "password": "Aa123456"}, token)
+	return tenantId, login(t, h, username, "Aa123456")
+}
+
+// newUser registers a user with the default role and returns its token
+func newUser(t *testing.T, h *harness.Harness, username string) string {
+	t.Helper()
+	call(t, h, http.MethodPost, "/api/v1/users/register-by-username", map[string]any{"firstName": "Test", "lastName": "Customer",
+		"username": username, "email": username + "@example.com", "password": "Aa123456"}, "", http.StatusCreated, nil)
+	return login(t, h, username, "Aa123456")
+}
@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/infra/persistence/memory"
+	"github.com/naeemaei/golang-clean-web-api/tests/harness"
+)
+
+// mainImages returns the main car model image ids of the gallery of a car model
+func mainImages(t *testing.T, h *harness.Harness, token string, carModelId int) []int {
+	t.Helper()
+	page := struct {
+		Items []struct {
+			Id          int  `json:"id"`
+			IsMainImage bool `json:"isMainImage"`
+		} `json:"items"`
+	}{}
+	call(t, h, http.MethodPost, "/api/v1/car-model-images/get-by-filter", map[string]any{"pageNumber": 1, "pageSize": 10,
+		"filter": map[string]any{"CarModelId": map[string]any{"type": "equals", "from": fmt.Sprint(carModelId), "filterType": "number"}}},
+		token, http.StatusOK, &page)
+	result := []int{}
+	for _, image := range page.Items {
+		if image.IsMainImage {
+			result = append(result, image.Id)
+		}
+	}
+	return result
+}
+
+func TestCarModelHasOneMainImage(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	files := memory.Seed(h.Store,
+		model.File{TenantModel: model.TenantModel{TenantId: constant.DefaultTenantId}, Name: "front.jpg", Directory: "uploads", MimeType: "image/jpeg"},
+		model.File{TenantModel: model.TenantModel{TenantId: constant.DefaultTenantId}, Name: "side.jpg", Directory: "uploads", MimeType: "image/jpeg"},
+		model.File{TenantModel: model.TenantModel{TenantId: constant.DefaultTenantId}, Name: "back.jpg", Directory: "uploads", MimeType: "image/jpeg"})
+	addImage := func(fileId int, isMain bool) int {
+		return create(t, h, "/api/v1/car-model-images/", map[string]any{"carModelId": c.carModelId, "imageId": fileId, "isMainImage": isMain}, token)
+	}
+	expectMain := func(step string, id int) {
+		t.Helper()
+		if ids := mainImages(t, h, token, c.carModelId); len(ids) != 1 || ids[0] != id {
+			t.Fatalf("%s: main images %v, want [%d]", step, ids, id)
+		}
+	}
+
+	first := addImage(files[0].Id, false)
+	expectMain("the first image", first)
+	second := addImage(files[1].Id, false)
+	expectMain("an image that is not main", first)
+	third := addImage(files[2].Id, true)
+	expectMain("a main image", third)
+
+	call(t, h, http.MethodPut, fmt.Sprintf("/api/v1/car-model-images/%d/main", second), nil, token, http.StatusOK, nil)
+	expectMain("set main", second)
+
+	call(t, h, http.MethodPut, fmt.Sprintf("/api/v1/car-model-images/%d", second), map[string]any{"isMainImage": false}, token, http.StatusConflict, nil)
+	expectMain("unset main", second)
+
+	call(t, h, http.MethodDelete, fmt.Sprintf("/api/v1/car-model-images/%d", second), nil, token, http.StatusOK, nil)
+	expectMain("delete the main image", first)
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/naeemaei/golang-clean-web-api/tests/harness"
+)
+
+type importResponse struct {
+	DryRun      bool `json:"dryRun"`
+	TotalRows   int  `json:"totalRows"`
+	ValidRows   int  `json:"validRows"`
+	InvalidRows int  `json:"invalidRows"`
+	Created     int  `json:"created"`
+	Updated     int  `json:"updated"`
+	Rows        []struct {
+		Row    int    `json:"row"`
+		Name   string `json:"name"`
+		Action string `json:"action"`
+		Errors []struct {
+			Column  string `json:"column"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"rows"`
+}
+
+const importFile = `company,model,car_type,gearbox,colors,years
+Saipa,Pride,Sedan,Manual,White,1402
+Saipa,Tiba,Sedan,Manual,White|Black,1402
+
+Unknown,Quick,Sedan,Manual,,
+saipa,Saina,SEDAN,manual,,1402
+`
+
+func importCarModels(t *testing.T, h *harness.Harness, token string, dryRun string) importResponse {
+	t.Helper()
+	w := h.Upload("/api/v1/car-models/import", map[string]string{"dryRun": dryRun}, "car-models.csv", []byte(importFile), token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("import: status %d: %s", w.Code, w.Body.String())
+	}
+	result := importResponse{}
+	if _, err := harness.Decode(w, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func countCarModels(t *testing.T, h *harness.Harness, token string) int {
+	t.Helper()
+	page := struct {
+		TotalRows int `json:"totalRows"`
+	}{}
+	call(t, h, http.MethodPost, "/api/v1/car-models/get-by-filter", map[string]any{"pageNumber": 1, "pageSize": 10}, token, http.StatusOK, &page)
+	return page.TotalRows
+}
+
+func TestImportDryRunReportsRows(t *testing.T) {
+	h, token := newHarness(t)
+	newCatalog(t, h, token)
+
+	result := importCarModels(t, h, token, "true")
+	if !result.DryRun || result.TotalRows != 4 || result.ValidRows != 2 || result.InvalidRows != 2 || result.Created != 1 || result.Updated != 1 {
+		t.Fatalf("unexpected dry run counts %+v", result)
+	}
+	if len(result.Rows) != 4 {
+		t.Fatalf("%d report rows, want 4", len(result.Rows))
+	}
+	// row numbers are the lines of the file, the blank line is skipped
+	want := []struct {
+		row    int
+		action string
+		column string
+	}{{2, "update", ""}, {3, "", "colors"}, {5, "", "company"}, {6, "create", ""}}
+	for i, row := range result.Rows {
+		if row.Row != want[i].row {
+			t.Errorf("report row %d is line %d, want %d", i, row.Row, want[i].row)
+		}
+		if row.Action != want[i].action {
+			t.Errorf("line %d has action %q, want %q", row.Row, row.Action, want[i].action)
+		}
+		if want[i].column == "" && len(row.Errors) > 0 {
+			t.Errorf("line %d has errors %+v", row.Row, row.Errors)
+		}
+		if want[i].column != "" && (len(row.Errors) != 1 || row.Errors[0].Column != want[i].column) {
+			t.Errorf("line %d errors %+v, want one on %s", row.Row, row.Errors, want[i].column)
+		}
+	}
+	if count := countCarModels(t, h, token); count != 1 {
+		t.Fatalf("%d car models after a dry run, want 1", count)
+	}
+}
+
+func TestImportSavesValidRows(t *testing.T) {
+	h, token := newHarness(t)
+	newCatalog(t, h, token)
+
+	result := importCarModels(t, h, token, "false")
+	if result.DryRun || result.Created != 1 || result.Updated != 1 {
+		t.Fatalf("unexpected import counts %+v", result)
+	}
+	if result.Rows[0].Action != "update" || result.Rows[3].Action != "create" {
+		t.Fatalf("unexpected actions %q and %q", result.Rows[0].Action, result.Rows[3].Action)
+	}
+	if count := countCarModels(t, h, token); count != 2 {
+		t.Fatalf("%d car models after the import, want 2", count)
+	}
+}
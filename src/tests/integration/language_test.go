@@ -0,0 +1,46 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/naeemaei/golang-clean-web-api/tests/harness"
+)
+
+func TestErrorLanguage(t *testing.T) {
+	h, token := newHarness(t)
+	farsiToken := login(t, h, harness.AdminUsername, harness.AdminPassword)
+	call(t, h, http.MethodPut, "/api/v1/users/language", map[string]any{"language": "fa"}, farsiToken, http.StatusOK, nil)
+	// the preference is a claim of the tokens issued after it is set
+	farsiToken = login(t, h, harness.AdminUsername, harness.AdminPassword)
+
+	cases := []struct {
+		name           string
+		token          string
+		acceptLanguage string
+		notFound       string
+		required       string
+	}{
+		{"default", token, "", "Record not found", "Name is required"},
+		{"accept language fa", token, "fa-IR,fa;q=0.9,en;q=0.8", "رکورد یافت نشد", "Name الزامی است"},
+		{"accept language en", token, "en-US", "Record not found", "Name is required"},
+		{"unsupported accept language", token, "de", "Record not found", "Name is required"},
+		{"token preference", farsiToken, "", "رکورد یافت نشد", "Name الزامی است"},
+		{"token preference over accept language", farsiToken, "en", "رکورد یافت نشد", "Name الزامی است"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := map[string]string{"Accept-Language": tc.acceptLanguage}
+			w := h.DoWithHeaders(http.MethodGet, "/api/v1/countries/999999", nil, tc.token, headers)
+			res, err := harness.Decode(w, nil)
+			if err != nil || w.Code != http.StatusNotFound || res.Error != tc.notFound {
+				t.Errorf("not found error is %d %v, want %q", w.Code, res, tc.notFound)
+			}
+			w = h.DoWithHeaders(http.MethodPost, "/api/v1/countries/", map[string]any{}, tc.token, headers)
+			res, err = harness.Decode(w, nil)
+			if err != nil || res.ValidationErrors == nil || len(*res.ValidationErrors) == 0 || (*res.ValidationErrors)[0].Message != tc.required {
+				t.Errorf("validation error is %d %s, want %q", w.Code, w.Body.String(), tc.required)
+			}
+		})
+	}
+}
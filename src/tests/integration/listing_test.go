@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	"github.com/naeemaei/golang-clean-web-api/infra/job"
+	"github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+)
+
+func TestListingLifecycle(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	cityId := create(t, h, "/api/v1/cities/", map[string]any{"name": "Tehran", "countryId": c.countryId}, token)
+	owner := newUser(t, h, "seller")
+	buyer := newUser(t, h, "buyer")
+
+	listingId := create(t, h, "/api/v1/listings/", map[string]any{"carModelYearId": c.carModelYearId, "cityId": cityId, "colorId": c.colorId,
+		"mileage": 1000, "price": 500, "contactName": "Seller", "contactPhone": "09121234567"}, owner)
+	path := fmt.Sprintf("/api/v1/listings/%d", listingId)
+	status := func(want string) {
+		t.Helper()
+		listing := struct {
+			Status string `json:"status"`
+		}{}
+		call(t, h, http.MethodGet, path, nil, owner, http.StatusOK, &listing)
+		if listing.Status != want {
+			t.Fatalf("listing is %q, want %q", listing.Status, want)
+		}
+	}
+	change := func(token string, to string, code int) {
+		t.Helper()
+		call(t, h, http.MethodPut, path+"/status", map[string]any{"status": to}, token, code, nil)
+	}
+	moderate := func(to string, code int) {
+		t.Helper()
+		call(t, h, http.MethodPut, path+"/moderation", map[string]any{"status": to}, token, code, nil)
+	}
+
+	status("draft")
+	call(t, h, http.MethodGet, path, nil, buyer, http.StatusNotFound, nil)
+	change(owner, "sold", http.StatusConflict)
+	moderate("published", http.StatusConflict)
+	change(buyer, "pending_review", http.StatusNotFound)
+
+	change(owner, "pending_review", http.StatusOK)
+	moderate("draft", http.StatusOK)
+	status("draft")
+	change(owner, "pending_review", http.StatusOK)
+	moderate("published", http.StatusOK)
+	status("published")
+	call(t, h, http.MethodGet, path, nil, buyer, http.StatusOK, nil)
+
+	// a published listing expires when its time is up and is renewed by a review
+	expirer := job.NewListingExpirer(h.Cfg, dependency.GetListingRepository(h.Cfg))
+	if expired := expirer.ExpireOnce(context.Background()); expired != 0 {
+		t.Fatalf("expired %d listing(s) before their time", expired)
+	}
+	ctx := database.WithTenant(context.Background(), constant.DefaultTenantId)
+	past := sql.NullTime{Valid: true, Time: time.Now().UTC().Add(-time.Minute)}
+	if _, err := dependency.GetListingRepository(h.Cfg).Update(ctx, listingId, map[string]interface{}{"ExpiresAt": past}); err != nil {
+		t.Fatal(err)
+	}
+	if expired := expirer.ExpireOnce(context.Background()); expired != 1 {
+		t.Fatalf("expired %d listing(s), want 1", expired)
+	}
+	status("expired")
+	call(t, h, http.MethodGet, path, nil, buyer, http.StatusNotFound, nil)
+	call(t, h, http.MethodPut, path, map[string]any{"carModelYearId": c.carModelYearId, "cityId": cityId, "colorId": c.colorId,
+		"price": 400, "contactName": "Seller", "contactPhone": "09121234567"}, owner, http.StatusConflict, nil)
+	change(owner, "sold", http.StatusConflict)
+
+	change(owner, "pending_review", http.StatusOK)
+	moderate("published", http.StatusOK)
+	change(owner, "sold", http.StatusOK)
+	status("sold")
+	change(owner, "pending_review", http.StatusConflict)
+	moderate("draft", http.StatusConflict)
+}
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type pagedNames struct {
+	TotalRows int `json:"totalRows"`
+	Items     []struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"items"`
+}
+
+func TestJalaliFilter(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	// 1402/01/15 23:30 and 1402/01/16 00:15 in Tehran
+	lastId := 0
+	for _, priceAt := range []string{"2023-04-04T20:00:00Z", "2023-04-04T20:45:00Z"} {
+		lastId = create(t, h, "/api/v1/car-model-price-histories/", map[string]any{"carModelYearId": c.carModelYearId,
+			"priceAt": priceAt, "price": 500000000}, token)
+	}
+
+	cases := []struct {
+		name   string
+		filter map[string]any
+		rows   int
+	}{
+		{"equals a day", map[string]any{"type": "equals", "from": "1402/01/15"}, 1},
+		{"persian digits", map[string]any{"type": "equals", "from": "۱۴۰۲/۰۱/۱۶"}, 1},
+		{"equals a minute", map[string]any{"type": "equals", "from": "1402/01/15 23:30"}, 1},
+		{"less than or equal includes the day", map[string]any{"type": "lessThanOrEqual", "from": "1402/01/15"}, 1},
+		{"in range of days", map[string]any{"type": "inRange", "from": "1402/01/15", "to": "1402/01/16"}, 2},
+		{"greater than a day", map[string]any{"type": "greaterThan", "from": "1402/01/15"}, 1},
+		{"invalid date", map[string]any{"type": "equals", "from": "1402/13/01"}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.filter["filterType"] = "jalali"
+			page := pagedNames{}
+			call(t, h, http.MethodPost, "/api/v1/car-model-price-histories/get-by-filter",
+				map[string]any{"pageNumber": 1, "pageSize": 10, "filter": map[string]any{"PriceAt": tc.filter}}, token, http.StatusOK, &page)
+			if page.TotalRows != tc.rows {
+				t.Fatalf("%d row(s), want %d", page.TotalRows, tc.rows)
+			}
+		})
+	}
+
+	price := struct {
+		PriceAtJalali string `json:"priceAtJalali"`
+	}{}
+	call(t, h, http.MethodGet, fmt.Sprintf("/api/v1/car-model-price-histories/%d?calendar=jalali", lastId), nil, token, http.StatusOK, &price)
+	if price.PriceAtJalali != "1402/01/16 00:15:00" {
+		t.Fatalf("priceAtJalali %q, want 1402/01/16 00:15:00", price.PriceAtJalali)
+	}
+}
+
+func TestPersianTextIsNormalized(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	// Arabic kaf and yeh, a tatweel and a zero width non joiner
+	id := newCarModel(t, h, token, c, "كيـا‌سراتو")
+
+	carModel := struct {
+		Name string `json:"name"`
+	}{}
+	call(t, h, http.MethodGet, fmt.Sprintf("/api/v1/car-models/%d", id), nil, token, http.StatusOK, &carModel)
+	if carModel.Name != "کیا سراتو" {
+		t.Fatalf("stored name %q, want کیا سراتو", carModel.Name)
+	}
+
+	page := pagedNames{}
+	call(t, h, http.MethodPost, "/api/v1/car-models/get-by-filter", map[string]any{"pageNumber": 1, "pageSize": 10,
+		"filter": map[string]any{"Name": map[string]any{"type": "contains", "from": "كيا", "filterType": "text"}}}, token, http.StatusOK, &page)
+	if page.TotalRows != 1 || page.Items[0].Id != id {
+		t.Fatalf("filter with Arabic characters found %+v", page)
+	}
+
+	page = pagedNames{}
+	call(t, h, http.MethodGet, "/api/v1/car-models/search?q="+url.QueryEscape("سراتو كيا"), nil, token, http.StatusOK, &page)
+	if page.TotalRows != 1 || page.Items[0].Id != id {
+		t.Fatalf("search with Arabic characters found %+v", page)
+	}
+}
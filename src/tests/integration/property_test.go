@@ -0,0 +1,41 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPropertyTypeIsKeptWhileValuesExist(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	categoryId := create(t, h, "/api/v1/property-categories/", map[string]any{"name": "Engine"}, token)
+	property := func(dataType string, minValue float64, maxValue float64) map[string]any {
+		return map[string]any{"name": "Power", "categoryId": categoryId, "dataType": dataType, "minValue": minValue, "maxValue": maxValue}
+	}
+	propertyId := create(t, h, "/api/v1/properties/", property("int", 0, 1000), token)
+	path := fmt.Sprintf("/api/v1/properties/%d", propertyId)
+
+	// without values the type changes freely
+	call(t, h, http.MethodPut, path, property("decimal", 0, 500), token, http.StatusOK, nil)
+	valueId := create(t, h, "/api/v1/car-model-properties/", map[string]any{"carModelId": c.carModelId, "propertyId": propertyId, "value": "۱۱۰٫۵"}, token)
+
+	cases := []struct {
+		name     string
+		property map[string]any
+		status   int
+	}{
+		{"another data type", property("int", 0, 500), http.StatusConflict},
+		{"a higher min", property("decimal", 100, 500), http.StatusConflict},
+		{"a lower max", property("decimal", 0, 200), http.StatusConflict},
+		{"wider bounds", property("decimal", -10, 1000), http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			call(t, h, http.MethodPut, path, tc.property, token, tc.status, nil)
+		})
+	}
+
+	call(t, h, http.MethodDelete, fmt.Sprintf("/api/v1/car-model-properties/%d", valueId), nil, token, http.StatusOK, nil)
+	call(t, h, http.MethodPut, path, property("int", 0, 500), token, http.StatusOK, nil)
+}
@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type ratingSummary struct {
+	Average      float64 `json:"average"`
+	Count        int     `json:"count"`
+	Distribution []struct {
+		Stars int `json:"stars"`
+		Count int `json:"count"`
+	} `json:"distribution"`
+}
+
+func TestOneRatingPerUser(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	first := newUser(t, h, "firstrater")
+	second := newUser(t, h, "secondrater")
+	rate := func(token string, stars int) int {
+		t.Helper()
+		return create(t, h, "/api/v1/car-model-ratings/", map[string]any{"carModelId": c.carModelId, "stars": stars}, token)
+	}
+	summary := func() ratingSummary {
+		t.Helper()
+		carModel := struct {
+			Rating ratingSummary `json:"rating"`
+		}{}
+		call(t, h, http.MethodGet, fmt.Sprintf("/api/v1/car-models/%d", c.carModelId), nil, token, http.StatusOK, &carModel)
+		return carModel.Rating
+	}
+	check := func(average float64, count int, distribution [5]int) {
+		t.Helper()
+		s := summary()
+		got := [5]int{}
+		for _, item := range s.Distribution {
+			got[item.Stars-1] = item.Count
+		}
+		if s.Average != average || s.Count != count || got != distribution {
+			t.Fatalf("rating is %v %d %v, want %v %d %v", s.Average, s.Count, got, average, count, distribution)
+		}
+	}
+
+	ratingId := rate(first, 5)
+	rate(second, 2)
+	check(3.5, 2, [5]int{0, 1, 0, 0, 1})
+
+	// rating again changes the stars of the same rating
+	if id := rate(first, 4); id != ratingId {
+		t.Fatalf("second rating of a user is %d, want %d", id, ratingId)
+	}
+	check(3, 2, [5]int{0, 1, 0, 1, 0})
+
+	call(t, h, http.MethodPost, "/api/v1/car-model-ratings/", map[string]any{"carModelId": c.carModelId, "stars": 6}, first, http.StatusBadRequest, nil)
+	path := fmt.Sprintf("/api/v1/car-model-ratings/%d", ratingId)
+	call(t, h, http.MethodGet, path, nil, second, http.StatusNotFound, nil)
+	call(t, h, http.MethodDelete, path, nil, second, http.StatusNotFound, nil)
+
+	call(t, h, http.MethodDelete, path, nil, first, http.StatusOK, nil)
+	check(2, 1, [5]int{0, 1, 0, 0, 0})
+}
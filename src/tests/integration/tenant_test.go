@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestTenantCanNotReadOrWriteOtherTenants(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	_, tenantToken := newTenant(t, h, token, "acme")
+
+	path := fmt.Sprintf("/api/v1/car-models/%d", c.carModelId)
+	call(t, h, http.MethodGet, path, nil, tenantToken, http.StatusNotFound, nil)
+	// an update matching no row of the tenant changes nothing, see the name check below
+	call(t, h, http.MethodPut, path, map[string]any{"name": "Hacked"}, tenantToken, http.StatusOK, nil)
+	call(t, h, http.MethodDelete, path, nil, tenantToken, http.StatusNotFound, nil)
+
+	page := struct {
+		TotalRows int `json:"totalRows"`
+	}{}
+	call(t, h, http.MethodPost, "/api/v1/car-models/get-by-filter", map[string]any{"pageNumber": 1, "pageSize": 10}, tenantToken, http.StatusOK, &page)
+	if page.TotalRows != 0 {
+		t.Fatalf("tenant sees %d car model(s) of the platform tenant", page.TotalRows)
+	}
+
+	carModel := struct {
+		Name string `json:"name"`
+	}{}
+	call(t, h, http.MethodGet, path, nil, token, http.StatusOK, &carModel)
+	if carModel.Name != "Pride" {
+		t.Fatalf("car model name is %q after the tenant update", carModel.Name)
+	}
+}
+
+func TestTenantCanNotWriteGlobalData(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	_, tenantToken := newTenant(t, h, token, "acme")
+
+	call(t, h, http.MethodPost, "/api/v1/countries/", map[string]any{"name": "France"}, tenantToken, http.StatusForbidden, nil)
+	call(t, h, http.MethodPut, fmt.Sprintf("/api/v1/countries/%d", c.countryId), map[string]any{"name": "France"}, tenantToken, http.StatusForbidden, nil)
+	call(t, h, http.MethodDelete, fmt.Sprintf("/api/v1/companies/%d", c.companyId), nil, tenantToken, http.StatusForbidden, nil)
+	call(t, h, http.MethodGet, fmt.Sprintf("/api/v1/countries/%d", c.countryId), nil, tenantToken, http.StatusOK, nil)
+}
+
+func TestTenantCanNotReferenceOtherTenants(t *testing.T) {
+	h, token := newHarness(t)
+	c := newCatalog(t, h, token)
+	_, tenantToken := newTenant(t, h, token, "acme")
+
+	// global reference data can be used by every tenant
+	tenantCarModelId := newCarModel(t, h, tenantToken, c, "Tiba")
+
+	call(t, h, http.MethodPost, "/api/v1/car-model-years/", map[string]any{"carModelId": c.carModelId, "persianYearId": c.persianYearId},
+		tenantToken, http.StatusNotFound, nil)
+	tenantYearId := create(t, h, "/api/v1/car-model-years/", map[string]any{"carModelId": tenantCarModelId, "persianYearId": c.persianYearId}, tenantToken)
+	call(t, h, http.MethodPut, fmt.Sprintf("/api/v1/car-model-years/%d", tenantYearId), map[string]any{"carModelId": c.carModelId},
+		tenantToken, http.StatusNotFound, nil)
+}
@@ -0,0 +1,172 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/dependency"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	"github.com/naeemaei/golang-clean-web-api/infra/outbox"
+	"github.com/naeemaei/golang-clean-web-api/pkg/webhook"
+	"github.com/naeemaei/golang-clean-web-api/tests/harness"
+	"github.com/naeemaei/golang-clean-web-api/usecase"
+)
+
+const webhookSecret = "0123456789abcdef"
+
+// enqueueSink hands the outbox events to the webhook usecase like the bus does in main
+type enqueueSink struct {
+	webhooks *usecase.WebhookUsecase
+}
+
+func (s enqueueSink) Name() string {
+	return "enqueue"
+}
+
+func (s enqueueSink) Publish(ctx context.Context, e event.Event) error {
+	return s.webhooks.Enqueue(ctx, e)
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookTarget answers the deliveries with statuses in order, the last one is repeated
+type webhookTarget struct {
+	mu       sync.Mutex
+	statuses []int
+	received []receivedWebhook
+}
+
+func (w *webhookTarget) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status := w.statuses[min(len(w.received), len(w.statuses)-1)]
+	w.received = append(w.received, receivedWebhook{header: req.Header.Clone(), body: body})
+	res.WriteHeader(status)
+}
+
+type webhookDeliveries struct {
+	TotalRows int `json:"totalRows"`
+	Items     []struct {
+		Status         string `json:"status"`
+		Attempts       int    `json:"attempts"`
+		ResponseStatus int    `json:"responseStatus"`
+		EventType      string `json:"eventType"`
+	} `json:"items"`
+}
+
+func newWebhookHarness(t *testing.T, statuses ...int) (*harness.Harness, string, *webhookTarget, *outbox.Dispatcher, *outbox.WebhookDeliverer) {
+	t.Helper()
+	h, token := newHarness(t, func(cfg *config.Config) {
+		cfg.Webhook.AllowPrivateTargets = true
+		cfg.Webhook.RetryDelay = 0
+		cfg.Webhook.MaxAttempts = 3
+	})
+	target := &webhookTarget{statuses: statuses}
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	create(t, h, "/api/v1/webhooks/", map[string]any{"url": server.URL, "eventTypes": []string{string(event.EntityCreated)},
+		"entities": []string{"CarModel"}, "secret": webhookSecret}, token)
+
+	webhooks := usecase.NewWebhookUsecase(h.Cfg, dependency.GetWebhookSubscriptionRepository(h.Cfg), dependency.GetWebhookDeliveryRepository(h.Cfg))
+	dispatcher := outbox.NewDispatcher(h.Cfg, dependency.GetOutboxRepository(h.Cfg), enqueueSink{webhooks: webhooks})
+	deliverer := outbox.NewWebhookDeliverer(h.Cfg, dependency.GetWebhookDeliveryRepository(h.Cfg))
+	return h, token, target, dispatcher, deliverer
+}
+
+func getDeliveries(t *testing.T, h *harness.Harness, token string) webhookDeliveries {
+	t.Helper()
+	deliveries := webhookDeliveries{}
+	call(t, h, http.MethodPost, "/api/v1/webhook-deliveries/get-by-filter", map[string]any{"pageNumber": 1, "pageSize": 10}, token, http.StatusOK, &deliveries)
+	return deliveries
+}
+
+func TestWebhookIsSignedAndRetried(t *testing.T) {
+	h, token, target, dispatcher, deliverer := newWebhookHarness(t, http.StatusInternalServerError, http.StatusOK)
+	newCatalog(t, h, token)
+	ctx := context.Background()
+	for dispatcher.DispatchOnce(ctx) > 0 {
+	}
+
+	deliveries := getDeliveries(t, h, token)
+	if deliveries.TotalRows != 1 || deliveries.Items[0].EventType != string(event.EntityCreated) {
+		t.Fatalf("deliveries %+v, want one of the created car model", deliveries)
+	}
+
+	if n := deliverer.DeliverOnce(ctx); n != 1 {
+		t.Fatalf("first attempt claimed %d deliveries", n)
+	}
+	deliveries = getDeliveries(t, h, token)
+	if item := deliveries.Items[0]; item.Status != "pending" || item.Attempts != 1 || item.ResponseStatus != http.StatusInternalServerError {
+		t.Fatalf("delivery after a failed attempt %+v", item)
+	}
+
+	if n := deliverer.DeliverOnce(ctx); n != 1 {
+		t.Fatalf("retry claimed %d deliveries", n)
+	}
+	deliveries = getDeliveries(t, h, token)
+	if item := deliveries.Items[0]; item.Status != "delivered" || item.Attempts != 2 || item.ResponseStatus != http.StatusOK {
+		t.Fatalf("delivery after the retry %+v", item)
+	}
+	if n := deliverer.DeliverOnce(ctx); n != 0 {
+		t.Fatalf("a delivered webhook is claimed again")
+	}
+
+	if len(target.received) != 2 {
+		t.Fatalf("target received %d requests, want 2", len(target.received))
+	}
+	for i, received := range target.received {
+		if err := webhook.Verify(webhookSecret, received.header.Get(webhook.SignatureHeader), received.body, time.Minute); err != nil {
+			t.Errorf("attempt %d: %v", i+1, err)
+		}
+		if attempt := received.header.Get(webhook.AttemptHeader); attempt != []string{"1", "2"}[i] {
+			t.Errorf("attempt %d has the attempt header %q", i+1, attempt)
+		}
+		if received.header.Get(webhook.EventIdHeader) != target.received[0].header.Get(webhook.EventIdHeader) {
+			t.Errorf("attempt %d has another event id", i+1)
+		}
+	}
+	tampered := append([]byte{}, target.received[0].body...)
+	tampered[len(tampered)-1] = ' '
+	if err := webhook.Verify(webhookSecret, target.received[0].header.Get(webhook.SignatureHeader), tampered, time.Minute); err == nil {
+		t.Fatal("the signature of a tampered body is valid")
+	}
+	if err := webhook.Verify("another secret value", target.received[0].header.Get(webhook.SignatureHeader), target.received[0].body, time.Minute); err == nil {
+		t.Fatal("the signature is valid with another secret")
+	}
+}
+
+func TestWebhookIsDeadLetteredAfterMaxAttempts(t *testing.T) {
+	h, token, target, dispatcher, deliverer := newWebhookHarness(t, http.StatusServiceUnavailable)
+	newCatalog(t, h, token)
+	ctx := context.Background()
+	for dispatcher.DispatchOnce(ctx) > 0 {
+	}
+
+	for deliverer.DeliverOnce(ctx) > 0 {
+	}
+	deliveries := getDeliveries(t, h, token)
+	if item := deliveries.Items[0]; item.Status != "dead" || item.Attempts != h.Cfg.Webhook.MaxAttempts {
+		t.Fatalf("delivery %+v, want dead after %d attempts", item, h.Cfg.Webhook.MaxAttempts)
+	}
+	if len(target.received) != h.Cfg.Webhook.MaxAttempts {
+		t.Fatalf("target received %d requests, want %d", len(target.received), h.Cfg.Webhook.MaxAttempts)
+	}
+}
+
+func TestWebhookRejectsPrivateTargets(t *testing.T) {
+	h, token := newHarness(t, func(cfg *config.Config) { cfg.Webhook.AllowPrivateTargets = false })
+	for _, url := range []string{"http://127.0.0.1:9100/hook", "http://10.0.0.1/hook", "http://169.254.169.254/latest"} {
+		call(t, h, http.MethodPost, "/api/v1/webhooks/", map[string]any{"url": url, "eventTypes": []string{"*"}}, token, http.StatusBadRequest, nil)
+	}
+}
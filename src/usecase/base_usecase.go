@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/naeemaei/golang-clean-web-api/common"
 	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	"github.com/naeemaei/golang-clean-web-api/domain/repository"
 	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
@@ -72,3 +74,37 @@ func (u *BaseUsecase[TEntity, TCreate, TUpdate, TResponse]) GetByFilter(ctx cont
 
 	return filter.Paginate[TEntity, TResponse](count, entities, req.PageNumber, int64(req.PageSize))
 }
+
+// currentUserId returns the id of the authenticated user, 0 when there is none
+func currentUserId(ctx context.Context) int {
+	userId, _ := ctx.Value(constant.UserIdKey).(float64)
+	return int(userId)
+}
+
+// hasRole reports whether the authenticated user has role
+func hasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value(constant.RolesKey).([]interface{})
+	for _, item := range roles {
+		if item == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdmin reports whether the authenticated user is an admin of the platform or of its tenant,
+// the data of other tenants is out of reach of both by the tenant scope of the repositories
+func isAdmin(ctx context.Context) bool {
+	return hasRole(ctx, constant.AdminRoleName) || hasRole(ctx, constant.TenantAdminRoleName)
+}
+
+// userFilter limits req to the rows of the current user
+func userFilter(ctx context.Context, req filter.PaginationInputWithFilter) filter.PaginationInputWithFilter {
+	filters := map[string]filter.Filter{}
+	for name, f := range req.Filter {
+		filters[name] = f
+	}
+	filters["UserId"] = filter.Filter{Type: "equals", From: strconv.Itoa(currentUserId(ctx)), FilterType: "number"}
+	req.Filter = filters
+	return req
+}
@@ -2,45 +2,177 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"slices"
+	"time"
 
+	"github.com/naeemaei/golang-clean-web-api/common"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
 	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
+// CommentReportThreshold is the number of reports that sends an approved comment back to moderation
+const CommentReportThreshold = 3
+
 type CarModelCommentUsecase struct {
-	base *BaseUsecase[model.CarModelComment, dto.CreateCarModelComment, dto.UpdateCarModelComment, dto.CarModelComment]
+	base       *BaseUsecase[model.CarModelComment, dto.CreateCarModelComment, dto.UpdateCarModelComment, dto.CarModelComment]
+	repository repository.CarModelCommentRepository
 }
 
 func NewCarModelCommentUsecase(cfg *config.Config, repository repository.CarModelCommentRepository) *CarModelCommentUsecase {
 	return &CarModelCommentUsecase{
-		base: NewBaseUsecase[model.CarModelComment, dto.CreateCarModelComment, dto.UpdateCarModelComment, dto.CarModelComment](cfg, repository),
+		base:       NewBaseUsecase[model.CarModelComment, dto.CreateCarModelComment, dto.UpdateCarModelComment, dto.CarModelComment](cfg, repository),
+		repository: repository,
 	}
 }
 
-// Create
+// Create a comment of the current user, it is public once a moderator approves it.
+// A reply names an approved comment of the same car model as its parent
 func (u *CarModelCommentUsecase) Create(ctx context.Context, req dto.CreateCarModelComment) (dto.CarModelComment, error) {
+	if req.ParentId != nil {
+		parent, err := u.GetById(ctx, *req.ParentId)
+		if err != nil || parent.Status != model.CommentApproved || parent.CarModelId != req.CarModelId {
+			return dto.CarModelComment{}, &service_errors.ServiceError{EndUserMessage: service_errors.CommentParentInvalid}
+		}
+	}
+	req.UserId = currentUserId(ctx)
+	req.Status = model.CommentPending
 	return u.base.Create(ctx, req)
 }
 
-// Update
+// Update a comment of the current user, the edited comment is moderated again
 func (s *CarModelCommentUsecase) Update(ctx context.Context, id int, req dto.UpdateCarModelComment) (dto.CarModelComment, error) {
+	if _, err := s.own(ctx, id); err != nil {
+		return dto.CarModelComment{}, err
+	}
+	req.Status = model.CommentPending
 	return s.base.Update(ctx, id, req)
 }
 
-// Delete
+// Delete a comment of the current user
 func (s *CarModelCommentUsecase) Delete(ctx context.Context, id int) error {
+	if _, err := s.own(ctx, id); err != nil {
+		return err
+	}
 	return s.base.Delete(ctx, id)
 }
 
-// Get By Id
+// Get By Id, users get approved comments and their own ones
 func (s *CarModelCommentUsecase) GetById(ctx context.Context, id int) (dto.CarModelComment, error) {
-	return s.base.GetById(ctx, id)
+	comment, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return dto.CarModelComment{}, err
+	}
+	if comment.Status != model.CommentApproved && comment.UserId != currentUserId(ctx) && !isAdmin(ctx) {
+		return dto.CarModelComment{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return common.TypeConverter[dto.CarModelComment](comment)
 }
 
-// Get By Filter
+// Get By Filter, users get approved comments only
 func (s *CarModelCommentUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModelComment], error) {
+	if !isAdmin(ctx) {
+		req = statusFilter(req, model.CommentApproved)
+	}
+	return s.base.GetByFilter(ctx, req)
+}
+
+// Get the comments of the current user in any status
+func (s *CarModelCommentUsecase) GetMine(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModelComment], error) {
+	return s.base.GetByFilter(ctx, userFilter(ctx, req))
+}
+
+// GetModerationQueue returns the pending comments, the most reported first, unless the filter selects a Status
+func (s *CarModelCommentUsecase) GetModerationQueue(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModelComment], error) {
+	if _, ok := req.Filter["Status"]; !ok {
+		req = statusFilter(req, model.CommentPending)
+	}
+	if req.Sort == nil || len(*req.Sort) == 0 {
+		req.Sort = &[]filter.Sort{{ColId: "ReportCount", Sort: "desc"}, {ColId: "Id", Sort: "asc"}}
+	}
 	return s.base.GetByFilter(ctx, req)
 }
+
+// Moderate sets the status of a comment, the reports counted so far are cleared
+func (s *CarModelCommentUsecase) Moderate(ctx context.Context, id int, req dto.ModerateCarModelComment) (dto.CarModelComment, error) {
+	if !slices.Contains(model.CommentStatuses, req.Status) {
+		return dto.CarModelComment{}, &service_errors.ServiceError{EndUserMessage: service_errors.CommentStatusInvalid}
+	}
+	if _, err := s.repository.GetById(ctx, id); err != nil {
+		return dto.CarModelComment{}, err
+	}
+	_, err := s.repository.Update(ctx, id, map[string]interface{}{
+		"Status":         req.Status,
+		"ModerationNote": req.ModerationNote,
+		"ModeratedAt":    sql.NullTime{Valid: true, Time: time.Now().UTC()},
+		"ReportCount":    0,
+	})
+	if err != nil {
+		return dto.CarModelComment{}, err
+	}
+	return s.GetById(ctx, id)
+}
+
+// Report an approved comment of another user as abusive
+func (s *CarModelCommentUsecase) Report(ctx context.Context, id int, req dto.CreateCarModelCommentReport) error {
+	comment, err := s.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if comment.UserId == currentUserId(ctx) {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.CommentReportInvalid}
+	}
+	return s.repository.AddReport(ctx, model.CarModelCommentReport{
+		CarModelCommentId: id,
+		UserId:            currentUserId(ctx),
+		Reason:            req.Reason,
+	}, CommentReportThreshold)
+}
+
+// Vote whether an approved comment of another user was helpful, voting again changes the vote
+func (s *CarModelCommentUsecase) Vote(ctx context.Context, id int, req dto.CreateCarModelCommentVote) error {
+	comment, err := s.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if comment.UserId == currentUserId(ctx) {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.CommentVoteInvalid}
+	}
+	return s.repository.Vote(ctx, model.CarModelCommentVote{
+		CarModelCommentId: id,
+		UserId:            currentUserId(ctx),
+		Helpful:           req.Helpful,
+	})
+}
+
+// RemoveVote removes the vote of the current user on a comment
+func (s *CarModelCommentUsecase) RemoveVote(ctx context.Context, id int) error {
+	return s.repository.RemoveVote(ctx, id, currentUserId(ctx))
+}
+
+// own returns a comment of the current user, comments of others are not found
+func (s *CarModelCommentUsecase) own(ctx context.Context, id int) (model.CarModelComment, error) {
+	comment, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return comment, err
+	}
+	if comment.UserId != currentUserId(ctx) {
+		return model.CarModelComment{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return comment, nil
+}
+
+// statusFilter limits req to the comments of status
+func statusFilter(req filter.PaginationInputWithFilter, status string) filter.PaginationInputWithFilter {
+	filters := map[string]filter.Filter{}
+	for name, f := range req.Filter {
+		filters[name] = f
+	}
+	filters["Status"] = filter.Filter{Type: "equals", From: status, FilterType: "text"}
+	req.Filter = filters
+	return req
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+// FacetPriceField filters the car models by the latest prices of their years
+const FacetPriceField = "Price"
+
+// the number of price ranges aimed at, the nice step may give one or two more
+const facetPriceRanges = 8
+
+// FacetedSearch filters the car models by the facet fields, Price and the other fields of CarModel and counts
+// the car models of every facet value, the counts of a field apply the selections of all the other fields
+func (s *CarModelUsecase) FacetedSearch(ctx context.Context, req filter.PaginationInputWithFilter) (dto.FacetedCarModels, error) {
+	query, err := facetQuery(req.Filter)
+	if err != nil {
+		return dto.FacetedCarModels{}, err
+	}
+	count, rows, err := s.repository.FacetCarModels(ctx, query, req.Sort, req.GetOffset(), req.GetPageSize())
+	if err != nil {
+		return dto.FacetedCarModels{}, err
+	}
+	items := []dto.CarModelFacetItem{}
+	for _, row := range rows {
+		items = append(items, dto.CarModelFacetItem(row))
+	}
+
+	facets := []dto.Facet{}
+	for _, field := range model.FacetFields {
+		selected := query.Selections[field]
+		counts, err := s.repository.FacetCounts(ctx, query.Without(field), field, selected)
+		if err != nil {
+			return dto.FacetedCarModels{}, err
+		}
+		facet := dto.Facet{Field: field, Values: []dto.FacetValue{}}
+		for _, value := range counts {
+			facet.Values = append(facet.Values, dto.FacetValue{Id: value.Id, Name: value.Name, Count: value.Count, Selected: slices.Contains(selected, value.Id)})
+		}
+		facets = append(facets, facet)
+	}
+	priceRanges, err := s.countPriceRanges(ctx, query.WithoutPrice())
+	if err != nil {
+		return dto.FacetedCarModels{}, err
+	}
+	return dto.FacetedCarModels{
+		Items:       filter.NewPagedList(&items, count, req.GetPageNumber(), int64(req.GetPageSize())),
+		Facets:      facets,
+		PriceRanges: priceRanges,
+		Currency:    model.DefaultCurrency,
+	}, nil
+}
+
+// facetQuery splits the filters into the selections of the facet fields, Price and the filters on the other
+// fields of CarModel. A facet field takes an in filter with comma separated ids or an equals filter with one id
+func facetQuery(filters map[string]filter.Filter) (model.CarModelFacetQuery, error) {
+	query := model.CarModelFacetQuery{Selections: map[string][]int{}, Filter: map[string]filter.Filter{}}
+	invalid := &service_errors.ServiceError{EndUserMessage: service_errors.FacetFilterInvalid}
+	for name, f := range filters {
+		switch {
+		case slices.Contains(model.FacetFields, name):
+			ids, err := facetIds(f)
+			if err != nil {
+				return query, invalid
+			}
+			query.Selections[name] = ids
+		case name == FacetPriceField:
+			price, err := priceSelection(f)
+			if err != nil {
+				return query, invalid
+			}
+			query.Price = &price
+		case isCarModelFilterField(name):
+			query.Filter[name] = f
+		default:
+			return query, invalid
+		}
+	}
+	return query, nil
+}
+
+func facetIds(f filter.Filter) ([]int, error) {
+	items := []string{f.From}
+	switch f.Type {
+	case filter.InFilterType:
+		items = strings.Split(f.From, ",")
+	case "equals":
+	default:
+		return nil, &service_errors.ServiceError{EndUserMessage: service_errors.FacetFilterInvalid}
+	}
+	ids := []int{}
+	for _, item := range items {
+		id, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// isCarModelFilterField tells whether name is a column of CarModel the dynamic filters compare
+func isCarModelFilterField(name string) bool {
+	field, ok := reflect.TypeOf(model.CarModel{}).FieldByName(name)
+	if !ok {
+		return false
+	}
+	switch field.Type.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return true
+	}
+	return field.Type == reflect.TypeOf(time.Time{}) || field.Type == reflect.TypeOf(sql.NullTime{})
+}
+
+func priceSelection(f filter.Filter) (model.PriceSelection, error) {
+	from, err := strconv.ParseFloat(strings.TrimSpace(f.From), 64)
+	if err != nil {
+		return model.PriceSelection{}, err
+	}
+	price := model.PriceSelection{Type: f.Type, From: from}
+	switch f.Type {
+	case "equals", "lessThan", "lessThanOrEqual", "greaterThan", "greaterThanOrEqual":
+	case "inRange":
+		if price.To, err = strconv.ParseFloat(strings.TrimSpace(f.To), 64); err != nil {
+			return price, err
+		}
+	default:
+		return price, &service_errors.ServiceError{EndUserMessage: service_errors.FacetFilterInvalid}
+	}
+	return price, nil
+}
+
+// countPriceRanges splits the prices of the car models matching query into ranges of a round width,
+// a car model counts once in every range one of its prices falls in
+func (s *CarModelUsecase) countPriceRanges(ctx context.Context, query model.CarModelFacetQuery) ([]dto.PriceRangeFacet, error) {
+	ranges := []dto.PriceRangeFacet{}
+	low, high, ok, err := s.repository.FacetPriceBounds(ctx, query)
+	if err != nil || !ok {
+		return ranges, err
+	}
+	step := nicePriceStep((high - low) / facetPriceRanges)
+	if step == 0 {
+		step = nicePriceStep(math.Max(high, 1) / facetPriceRanges)
+	}
+	start := math.Floor(low/step) * step
+	counts, err := s.repository.FacetPriceCounts(ctx, query, start, step)
+	if err != nil {
+		return ranges, err
+	}
+	for i := 0; start+float64(i)*step <= high; i++ {
+		from := start + float64(i)*step
+		ranges = append(ranges, dto.PriceRangeFacet{From: from, To: from + step, Count: counts[i]})
+	}
+	return ranges, nil
+}
+
+// nicePriceStep rounds width up to 1, 2, 2.5 or 5 times a power of ten
+func nicePriceStep(width float64) float64 {
+	if width <= 0 {
+		return 0
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(width)))
+	for _, factor := range []float64{1, 2, 2.5, 5} {
+		if width <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
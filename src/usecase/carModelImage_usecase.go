@@ -7,16 +7,19 @@ import (
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
 	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
 type CarModelImageUsecase struct {
-	base *BaseUsecase[model.CarModelImage, dto.CreateCarModelImage, dto.UpdateCarModelImage, dto.CarModelImage]
+	base       *BaseUsecase[model.CarModelImage, dto.CreateCarModelImage, dto.UpdateCarModelImage, dto.CarModelImage]
+	repository repository.CarModelImageRepository
 }
 
 func NewCarModelImageUsecase(cfg *config.Config, repository repository.CarModelImageRepository) *CarModelImageUsecase {
 	return &CarModelImageUsecase{
-		base: NewBaseUsecase[model.CarModelImage, dto.CreateCarModelImage, dto.UpdateCarModelImage, dto.CarModelImage](cfg, repository),
+		base:       NewBaseUsecase[model.CarModelImage, dto.CreateCarModelImage, dto.UpdateCarModelImage, dto.CarModelImage](cfg, repository),
+		repository: repository,
 	}
 }
 
@@ -25,9 +28,32 @@ func (u *CarModelImageUsecase) Create(ctx context.Context, req dto.CreateCarMode
 	return u.base.Create(ctx, req)
 }
 
-// Update
+// Update makes the image main, the main image can't be unset, another image must be made main instead
 func (s *CarModelImageUsecase) Update(ctx context.Context, id int, req dto.UpdateCarModelImage) (dto.CarModelImage, error) {
-	return s.base.Update(ctx, id, req)
+	if req.IsMainImage {
+		return s.SetMain(ctx, id)
+	}
+	image, err := s.base.GetById(ctx, id)
+	if err != nil {
+		return dto.CarModelImage{}, err
+	}
+	if image.IsMainImage {
+		return dto.CarModelImage{}, &service_errors.ServiceError{EndUserMessage: service_errors.CarModelImageMainRequired}
+	}
+	return image, nil
+}
+
+// SetMain makes the image the main image of its car model, the previous main image is demoted
+func (s *CarModelImageUsecase) SetMain(ctx context.Context, id int) (dto.CarModelImage, error) {
+	if err := s.repository.SetMain(ctx, id); err != nil {
+		return dto.CarModelImage{}, err
+	}
+	return s.base.GetById(ctx, id)
+}
+
+// Reorder sets the gallery order of a car model, ImageIds are all of its car model image ids
+func (s *CarModelImageUsecase) Reorder(ctx context.Context, req dto.ReorderCarModelImages) error {
+	return s.repository.Reorder(ctx, req.CarModelId, req.ImageIds)
 }
 
 // Delete
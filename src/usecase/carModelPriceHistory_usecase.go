@@ -2,32 +2,64 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
 
+	"github.com/naeemaei/golang-clean-web-api/common"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
 	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
+// Price analytics buckets, buckets are in Iran time and weeks start on Saturday
+const (
+	PriceBucketDay          = "day"
+	PriceBucketWeek         = "week"
+	PriceBucketMonth        = "month"
+	PriceBucketPersianMonth = "persian_month"
+)
+
+var PriceBuckets = []string{PriceBucketDay, PriceBucketWeek, PriceBucketMonth, PriceBucketPersianMonth}
+
+// days of the price changes of the analytics summary
+var priceChangePeriods = []int{30, 90, 365}
+
 type CarModelPriceHistoryUsecase struct {
-	base *BaseUsecase[model.CarModelPriceHistory, dto.CreateCarModelPriceHistory, dto.UpdateCarModelPriceHistory, dto.CarModelPriceHistory]
+	base           *BaseUsecase[model.CarModelPriceHistory, dto.CreateCarModelPriceHistory, dto.UpdateCarModelPriceHistory, dto.CarModelPriceHistory]
+	repository     repository.CarModelPriceHistoryRepository
+	rateRepository repository.ExchangeRateRepository
 }
 
-func NewCarModelPriceHistoryUsecase(cfg *config.Config, repository repository.CarModelPriceHistoryRepository) *CarModelPriceHistoryUsecase {
+func NewCarModelPriceHistoryUsecase(cfg *config.Config, repository repository.CarModelPriceHistoryRepository, rateRepository repository.ExchangeRateRepository) *CarModelPriceHistoryUsecase {
 	return &CarModelPriceHistoryUsecase{
-		base: NewBaseUsecase[model.CarModelPriceHistory, dto.CreateCarModelPriceHistory, dto.UpdateCarModelPriceHistory, dto.CarModelPriceHistory](cfg, repository),
+		base:           NewBaseUsecase[model.CarModelPriceHistory, dto.CreateCarModelPriceHistory, dto.UpdateCarModelPriceHistory, dto.CarModelPriceHistory](cfg, repository),
+		repository:     repository,
+		rateRepository: rateRepository,
 	}
 }
 
 // Create
 func (u *CarModelPriceHistoryUsecase) Create(ctx context.Context, req dto.CreateCarModelPriceHistory) (dto.CarModelPriceHistory, error) {
+	req.Currency = priceCurrency(req.Currency)
 	return u.base.Create(ctx, req)
 }
 
 // Update
 func (s *CarModelPriceHistoryUsecase) Update(ctx context.Context, id int, req dto.UpdateCarModelPriceHistory) (dto.CarModelPriceHistory, error) {
-	return s.base.Update(ctx, id, req)
+	updateMap := map[string]interface{}{"PriceAt": req.PriceAt, "Price": req.Price}
+	if req.Currency != "" {
+		updateMap["Currency"] = req.Currency
+	}
+	entity, err := s.repository.Update(ctx, id, updateMap)
+	if err != nil {
+		return dto.CarModelPriceHistory{}, err
+	}
+	return common.TypeConverter[dto.CarModelPriceHistory](entity)
 }
 
 // Delete
@@ -35,12 +67,205 @@ func (s *CarModelPriceHistoryUsecase) Delete(ctx context.Context, id int) error
 	return s.base.Delete(ctx, id)
 }
 
-// Get By Id
-func (s *CarModelPriceHistoryUsecase) GetById(ctx context.Context, id int) (dto.CarModelPriceHistory, error) {
-	return s.base.GetById(ctx, id)
+// Get By Id, the price is converted to currency when it is not empty
+func (s *CarModelPriceHistoryUsecase) GetById(ctx context.Context, id int, currency string) (dto.CarModelPriceHistory, error) {
+	price, err := s.base.GetById(ctx, id)
+	if err != nil || currency == "" {
+		return price, err
+	}
+	prices := []dto.CarModelPriceHistory{price}
+	err = convertPriceResponses(ctx, s.rateRepository, prices, currency)
+	return prices[0], err
+}
+
+// Get By Filter, the prices are converted to currency when it is not empty
+func (s *CarModelPriceHistoryUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter, currency string) (*filter.PagedList[dto.CarModelPriceHistory], error) {
+	result, err := s.base.GetByFilter(ctx, req)
+	if err != nil || currency == "" || result.Items == nil {
+		return result, err
+	}
+	return result, convertPriceResponses(ctx, s.rateRepository, *result.Items, currency)
+}
+
+// Get the price analytics of a car model year
+func (s *CarModelPriceHistoryUsecase) GetCarModelYearAnalytics(ctx context.Context, carModelYearId int, req dto.PriceAnalyticsQuery) (dto.PriceAnalytics, error) {
+	if err := validatePriceBucket(&req); err != nil {
+		return dto.PriceAnalytics{}, err
+	}
+	prices, err := s.repository.GetByCarModelYear(ctx, carModelYearId)
+	if err != nil {
+		return dto.PriceAnalytics{}, err
+	}
+	if prices, err = convertPrices(ctx, s.rateRepository, prices, req.Currency); err != nil {
+		return dto.PriceAnalytics{}, err
+	}
+	return priceAnalytics(prices, req, time.Now()), nil
+}
+
+// Get the price analytics of every year of a car model
+func (s *CarModelPriceHistoryUsecase) GetCarModelAnalytics(ctx context.Context, carModelId int, req dto.PriceAnalyticsQuery) (dto.CarModelPriceAnalytics, error) {
+	if err := validatePriceBucket(&req); err != nil {
+		return dto.CarModelPriceAnalytics{}, err
+	}
+	prices, err := s.repository.GetByCarModel(ctx, carModelId)
+	if err != nil {
+		return dto.CarModelPriceAnalytics{}, err
+	}
+	if prices, err = convertPrices(ctx, s.rateRepository, prices, req.Currency); err != nil {
+		return dto.CarModelPriceAnalytics{}, err
+	}
+	return carModelPriceAnalytics(carModelId, prices, req, time.Now()), nil
+}
+
+// validatePriceBucket defaults the bucket to month and the currency to the default currency
+func validatePriceBucket(req *dto.PriceAnalyticsQuery) error {
+	if req.Bucket == "" {
+		req.Bucket = PriceBucketMonth
+	}
+	req.Currency = priceCurrency(req.Currency)
+	if !slices.Contains(PriceBuckets, req.Bucket) {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.PriceBucketInvalid}
+	}
+	return nil
+}
+
+// priceAnalytics buckets the prices of the query range, prices must be ordered by PriceAt.
+// The changes of the summary compare the price at now, or To when it is earlier, with the price
+// the given days before, prices out of the range count for them too.
+func priceAnalytics(prices []model.CarModelPriceHistory, req dto.PriceAnalyticsQuery, now time.Time) dto.PriceAnalytics {
+	result := dto.PriceAnalytics{Bucket: req.Bucket, Currency: req.Currency, From: req.From, To: req.To, Buckets: []dto.PriceBucket{}}
+	summary := &result.Summary
+
+	// last price before the current bucket, the base of its change
+	var previous *float64
+	bases := []*float64{}
+	sum := 0.0
+	for _, price := range prices {
+		if req.To != nil && !price.PriceAt.Before(*req.To) {
+			break
+		}
+		value := price.Price
+		if req.From != nil && price.PriceAt.Before(*req.From) {
+			previous = &value
+			continue
+		}
+		if n := len(result.Buckets); n == 0 || !price.PriceAt.Before(result.Buckets[n-1].EndAt) {
+			if n > 0 {
+				last := result.Buckets[n-1].Last
+				previous = &last
+			}
+			start, end, label := priceBucket(req.Bucket, price.PriceAt)
+			result.Buckets = append(result.Buckets, dto.PriceBucket{Label: label, StartAt: start, EndAt: end, Min: value, Max: value})
+			bases = append(bases, previous)
+		}
+		bucket := &result.Buckets[len(result.Buckets)-1]
+		bucket.Count++
+		bucket.Min = math.Min(bucket.Min, value)
+		bucket.Max = math.Max(bucket.Max, value)
+		bucket.Average += value
+		bucket.Last = value
+
+		if summary.Count == 0 {
+			summary.FirstPrice, summary.MinPrice, summary.MaxPrice = value, value, value
+		}
+		summary.Count++
+		summary.MinPrice = math.Min(summary.MinPrice, value)
+		summary.MaxPrice = math.Max(summary.MaxPrice, value)
+		summary.LastPrice = value
+		priceAt := price.PriceAt
+		summary.LastPriceAt = &priceAt
+		sum += value
+	}
+	for i := range result.Buckets {
+		bucket := &result.Buckets[i]
+		bucket.Average = roundPrice(bucket.Average / float64(bucket.Count))
+		bucket.ChangePercent = changePercent(bases[i], bucket.Last)
+	}
+	if summary.Count > 0 {
+		summary.AveragePrice = roundPrice(sum / float64(summary.Count))
+		summary.ChangePercent = changePercent(&summary.FirstPrice, summary.LastPrice)
+	}
+
+	end := now
+	if req.To != nil && !req.To.After(now) {
+		// To is excluded like in the buckets
+		end = req.To.Add(-time.Nanosecond)
+	}
+	current := priceAt(prices, end)
+	summary.Changes = []dto.PriceChange{}
+	for _, days := range priceChangePeriods {
+		change := dto.PriceChange{Days: days, BasePrice: priceAt(prices, end.AddDate(0, 0, -days))}
+		if current != nil {
+			change.ChangePercent = changePercent(change.BasePrice, *current)
+		}
+		summary.Changes = append(summary.Changes, change)
+	}
+	return result
+}
+
+// carModelPriceAnalytics splits the prices by year, the years of a car model are priced apart and one series
+// of all of them would jump between the years. Prices must be ordered by PriceAt
+func carModelPriceAnalytics(carModelId int, prices []model.CarModelPriceHistory, req dto.PriceAnalyticsQuery, now time.Time) dto.CarModelPriceAnalytics {
+	years := map[int][]model.CarModelPriceHistory{}
+	ids := []int{}
+	for _, price := range prices {
+		if _, ok := years[price.CarModelYearId]; !ok {
+			ids = append(ids, price.CarModelYearId)
+		}
+		years[price.CarModelYearId] = append(years[price.CarModelYearId], price)
+	}
+	slices.Sort(ids)
+	result := dto.CarModelPriceAnalytics{CarModelId: carModelId, Years: []dto.CarModelYearPriceAnalytics{}}
+	for _, id := range ids {
+		result.Years = append(result.Years, dto.CarModelYearPriceAnalytics{CarModelYearId: id, PriceAnalytics: priceAnalytics(years[id], req, now)})
+	}
+	return result
+}
+
+// priceBucket returns the bucket of at
+func priceBucket(bucket string, at time.Time) (start time.Time, end time.Time, label string) {
+	at = at.In(common.IranLocation)
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, common.IranLocation)
+	switch bucket {
+	case PriceBucketDay:
+		return day, day.AddDate(0, 0, 1), day.Format(time.DateOnly)
+	case PriceBucketWeek:
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 1) % 7))
+		return start, start.AddDate(0, 0, 7), start.Format(time.DateOnly)
+	case PriceBucketPersianMonth:
+		year, month, _ := common.ToJalali(day)
+		start = common.FromJalali(year, month, 1, common.IranLocation)
+		return start, common.FromJalali(year, month+1, 1, common.IranLocation), fmt.Sprintf("%04d-%02d", year, month)
+	default:
+		start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, common.IranLocation)
+		return start, start.AddDate(0, 1, 0), start.Format("2006-01")
+	}
+}
+
+// priceAt returns the last price at or before at, prices must be ordered by PriceAt
+func priceAt(prices []model.CarModelPriceHistory, at time.Time) *float64 {
+	var price *float64
+	for i := range prices {
+		if prices[i].PriceAt.After(at) {
+			break
+		}
+		price = &prices[i].Price
+	}
+	if price == nil {
+		return nil
+	}
+	value := *price
+	return &value
+}
+
+func changePercent(base *float64, value float64) *float64 {
+	if base == nil || *base == 0 {
+		return nil
+	}
+	change := roundPrice((value - *base) / *base * 100)
+	return &change
 }
 
-// Get By Filter
-func (s *CarModelPriceHistoryUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModelPriceHistory], error) {
-	return s.base.GetByFilter(ctx, req)
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
 }
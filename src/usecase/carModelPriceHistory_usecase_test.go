@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+func iranTime(year int, month time.Month, day int, hour int, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, common.IranLocation)
+}
+
+func TestPriceBucket(t *testing.T) {
+	cases := []struct {
+		name   string
+		bucket string
+		at     time.Time
+		start  time.Time
+		end    time.Time
+		label  string
+	}{
+		{"day", PriceBucketDay, iranTime(2024, 3, 15, 23, 59), iranTime(2024, 3, 15, 0, 0), iranTime(2024, 3, 16, 0, 0), "2024-03-15"},
+		{"day in iran time", PriceBucketDay, time.Date(2024, 3, 15, 21, 0, 0, 0, time.UTC), iranTime(2024, 3, 16, 0, 0), iranTime(2024, 3, 17, 0, 0), "2024-03-16"},
+		{"week on saturday", PriceBucketWeek, iranTime(2024, 3, 16, 0, 0), iranTime(2024, 3, 16, 0, 0), iranTime(2024, 3, 23, 0, 0), "2024-03-16"},
+		{"week on friday", PriceBucketWeek, iranTime(2024, 3, 22, 23, 59), iranTime(2024, 3, 16, 0, 0), iranTime(2024, 3, 23, 0, 0), "2024-03-16"},
+		{"week on the previous friday", PriceBucketWeek, iranTime(2024, 3, 15, 12, 0), iranTime(2024, 3, 9, 0, 0), iranTime(2024, 3, 16, 0, 0), "2024-03-09"},
+		{"month", PriceBucketMonth, time.Date(2024, 1, 31, 21, 0, 0, 0, time.UTC), iranTime(2024, 2, 1, 0, 0), iranTime(2024, 3, 1, 0, 0), "2024-02"},
+		{"last day of esfand", PriceBucketPersianMonth, iranTime(2024, 3, 19, 23, 30), iranTime(2024, 2, 20, 0, 0), iranTime(2024, 3, 20, 0, 0), "1402-12"},
+		{"nowruz", PriceBucketPersianMonth, iranTime(2024, 3, 20, 0, 0), iranTime(2024, 3, 20, 0, 0), iranTime(2024, 4, 20, 0, 0), "1403-01"},
+		{"esfand 30 of a leap year", PriceBucketPersianMonth, iranTime(2025, 3, 20, 12, 0), iranTime(2025, 2, 19, 0, 0), iranTime(2025, 3, 21, 0, 0), "1403-12"},
+		{"mehr of 30 days", PriceBucketPersianMonth, iranTime(2024, 9, 22, 0, 0), iranTime(2024, 9, 22, 0, 0), iranTime(2024, 10, 22, 0, 0), "1403-07"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, label := priceBucket(tc.bucket, tc.at)
+			if !start.Equal(tc.start) || !end.Equal(tc.end) || label != tc.label {
+				t.Fatalf("bucket of %v is %q [%v, %v), want %q [%v, %v)", tc.at, label, start, end, tc.label, tc.start, tc.end)
+			}
+		})
+	}
+}
+
+func TestPriceAnalytics(t *testing.T) {
+	prices := []model.CarModelPriceHistory{
+		{PriceAt: iranTime(2024, 1, 10, 0, 0), Price: 100},
+		{PriceAt: iranTime(2024, 2, 10, 0, 0), Price: 110},
+		{PriceAt: iranTime(2024, 2, 20, 0, 0), Price: 120},
+		{PriceAt: iranTime(2024, 3, 10, 0, 0), Price: 150},
+	}
+	now := iranTime(2024, 3, 20, 0, 0)
+	at := func(year int, month time.Month, day int) *time.Time {
+		at := iranTime(year, month, day, 0, 0)
+		return &at
+	}
+	type bucket struct {
+		label  string
+		count  int
+		last   float64
+		change *float64
+	}
+	cases := []struct {
+		name    string
+		from    *time.Time
+		to      *time.Time
+		buckets []bucket
+		first   float64
+		last    float64
+		change  *float64
+		// changes of the last 30, 90 and 365 days
+		changes []*float64
+	}{
+		{"all prices", nil, nil, []bucket{{"2024-01", 1, 100, nil}, {"2024-02", 2, 120, ptr(20)}, {"2024-03", 1, 150, ptr(25)}},
+			100, 150, ptr(50), []*float64{ptr(36.36), nil, nil}},
+		{"from is included", at(2024, 2, 10), nil, []bucket{{"2024-02", 2, 120, ptr(20)}, {"2024-03", 1, 150, ptr(25)}},
+			110, 150, ptr(36.36), []*float64{ptr(36.36), nil, nil}},
+		{"to is excluded", nil, at(2024, 3, 10), []bucket{{"2024-01", 1, 100, nil}, {"2024-02", 2, 120, ptr(20)}},
+			100, 120, ptr(20), []*float64{ptr(20), nil, nil}},
+		{"empty range", at(2024, 2, 11), at(2024, 2, 20), []bucket{}, 0, 0, nil, []*float64{ptr(10), nil, nil}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := priceAnalytics(prices, dto.PriceAnalyticsQuery{Bucket: PriceBucketMonth, From: tc.from, To: tc.to}, now)
+			if len(result.Buckets) != len(tc.buckets) {
+				t.Fatalf("%d buckets, want %d", len(result.Buckets), len(tc.buckets))
+			}
+			for i, want := range tc.buckets {
+				got := result.Buckets[i]
+				if got.Label != want.label || got.Count != want.count || got.Last != want.last || !equalPercent(got.ChangePercent, want.change) {
+					t.Errorf("bucket %d is %s with %d prices, last %v changed %v, want %+v", i, got.Label, got.Count, got.Last, fmtPercent(got.ChangePercent), want)
+				}
+			}
+			summary := result.Summary
+			if summary.FirstPrice != tc.first || summary.LastPrice != tc.last || !equalPercent(summary.ChangePercent, tc.change) {
+				t.Errorf("summary from %v to %v changed %s, want from %v to %v changed %s", summary.FirstPrice, summary.LastPrice,
+					fmtPercent(summary.ChangePercent), tc.first, tc.last, fmtPercent(tc.change))
+			}
+			for i, want := range tc.changes {
+				if got := summary.Changes[i]; !equalPercent(got.ChangePercent, want) {
+					t.Errorf("%d day change is %s, want %s", got.Days, fmtPercent(got.ChangePercent), fmtPercent(want))
+				}
+			}
+		})
+	}
+}
+
+func TestCarModelPriceAnalyticsSplitsTheYears(t *testing.T) {
+	prices := []model.CarModelPriceHistory{
+		{CarModelYearId: 2, PriceAt: iranTime(2024, 1, 10, 0, 0), Price: 200},
+		{CarModelYearId: 1, PriceAt: iranTime(2024, 1, 15, 0, 0), Price: 100},
+		{CarModelYearId: 2, PriceAt: iranTime(2024, 2, 10, 0, 0), Price: 220},
+		{CarModelYearId: 1, PriceAt: iranTime(2024, 2, 15, 0, 0), Price: 90},
+	}
+	result := carModelPriceAnalytics(7, prices, dto.PriceAnalyticsQuery{Bucket: PriceBucketMonth}, iranTime(2024, 3, 1, 0, 0))
+	if result.CarModelId != 7 || len(result.Years) != 2 {
+		t.Fatalf("analytics of car model %d has %d years, want 2", result.CarModelId, len(result.Years))
+	}
+	want := []struct {
+		id     int
+		change float64
+	}{{1, -10}, {2, 10}}
+	for i, year := range result.Years {
+		if year.CarModelYearId != want[i].id || year.Summary.Count != 2 || !equalPercent(year.Summary.ChangePercent, &want[i].change) {
+			t.Errorf("year %d has %d prices changed %s, want year %d changed %v", year.CarModelYearId, year.Summary.Count,
+				fmtPercent(year.Summary.ChangePercent), want[i].id, want[i].change)
+		}
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func equalPercent(a *float64, b *float64) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func fmtPercent(v *float64) string {
+	if v == nil {
+		return "nil"
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
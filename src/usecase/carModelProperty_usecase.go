@@ -2,31 +2,73 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/naeemaei/golang-clean-web-api/common"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
 	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
+// bool values by their normalized spellings
+var propertyBoolValues = map[string]bool{
+	"true": true, "yes": true, "1": true, "بله": true, "دارد": true,
+	"false": false, "no": false, "0": false, "خیر": false, "ندارد": false,
+}
+
+// a range value is "from-to" or "from~to", e.g. "1200-1600"
+var propertyRangePattern = regexp.MustCompile(`^(-?[0-9.]+)[-~](-?[0-9.]+)$`)
+
+// Persian decimal separator to a dot, thousands separators and spaces are dropped
+var propertyNumberReplacer = strings.NewReplacer("٫", ".", "٬", "", ",", "", " ", "")
+
 type CarModelPropertyUsecase struct {
-	base *BaseUsecase[model.CarModelProperty, dto.CreateCarModelProperty, dto.UpdateCarModelProperty, dto.CarModelProperty]
+	base               *BaseUsecase[model.CarModelProperty, dto.CreateCarModelProperty, dto.UpdateCarModelProperty, dto.CarModelProperty]
+	repository         repository.CarModelPropertyRepository
+	propertyRepository repository.PropertyRepository
 }
 
-func NewCarModelPropertyUsecase(cfg *config.Config, repository repository.CarModelPropertyRepository) *CarModelPropertyUsecase {
+func NewCarModelPropertyUsecase(cfg *config.Config, repository repository.CarModelPropertyRepository, propertyRepository repository.PropertyRepository) *CarModelPropertyUsecase {
 	return &CarModelPropertyUsecase{
-		base: NewBaseUsecase[model.CarModelProperty, dto.CreateCarModelProperty, dto.UpdateCarModelProperty, dto.CarModelProperty](cfg, repository),
+		base:               NewBaseUsecase[model.CarModelProperty, dto.CreateCarModelProperty, dto.UpdateCarModelProperty, dto.CarModelProperty](cfg, repository),
+		repository:         repository,
+		propertyRepository: propertyRepository,
 	}
 }
 
-// Create
+// Create, the value is validated against the data type of the property
 func (u *CarModelPropertyUsecase) Create(ctx context.Context, req dto.CreateCarModelProperty) (dto.CarModelProperty, error) {
+	property, err := u.propertyRepository.GetById(ctx, req.PropertyId)
+	if err != nil {
+		return dto.CarModelProperty{}, err
+	}
+	value, err := parsePropertyValue(property, req.Value)
+	if err != nil {
+		return dto.CarModelProperty{}, &service_errors.ServiceError{EndUserMessage: service_errors.PropertyValueInvalid, Err: err}
+	}
+	req.Value, req.NumericValue, req.NumericMaxValue = value.Value, value.NumericValue, value.NumericMaxValue
 	return u.base.Create(ctx, req)
 }
 
-// Update
+// Update, the value is validated against the data type of the property
 func (s *CarModelPropertyUsecase) Update(ctx context.Context, id int, req dto.UpdateCarModelProperty) (dto.CarModelProperty, error) {
+	carModelProperty, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return dto.CarModelProperty{}, err
+	}
+	value, err := parsePropertyValue(carModelProperty.Property, req.Value)
+	if err != nil {
+		return dto.CarModelProperty{}, &service_errors.ServiceError{EndUserMessage: service_errors.PropertyValueInvalid, Err: err}
+	}
+	req.Value, req.NumericValue, req.NumericMaxValue = value.Value, value.NumericValue, value.NumericMaxValue
 	return s.base.Update(ctx, id, req)
 }
 
@@ -40,7 +82,91 @@ func (s *CarModelPropertyUsecase) GetById(ctx context.Context, id int) (dto.CarM
 	return s.base.GetById(ctx, id)
 }
 
-// Get By Filter
+// Get By Filter, e.g. engine power over 150 by PropertyId equals and NumericValue greaterThan
 func (s *CarModelPropertyUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModelProperty], error) {
 	return s.base.GetByFilter(ctx, req)
 }
+
+// parsePropertyValue validates value against the data type of property and returns it normalized
+// with its numeric form, the error describes why the value is invalid
+func parsePropertyValue(property model.Property, value string) (model.PropertyValue, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return model.PropertyValue{}, errors.New("value is required")
+	}
+	switch property.DataType {
+	case model.PropertyTypeInt, model.PropertyTypeDecimal:
+		number, err := parsePropertyNumber(property, value)
+		if err != nil {
+			return model.PropertyValue{}, err
+		}
+		return numericPropertyValue(formatPropertyNumber(number), number, number), nil
+	case model.PropertyTypeBool:
+		b, ok := propertyBoolValues[nameKey(value)]
+		if !ok {
+			return model.PropertyValue{}, errors.New("must be true or false")
+		}
+		number := 0.0
+		if b {
+			number = 1
+		}
+		return numericPropertyValue(strconv.FormatBool(b), number, number), nil
+	case model.PropertyTypeEnum:
+		for _, allowed := range strings.Split(property.AllowedValues, ",") {
+			if nameKey(allowed) == nameKey(value) {
+				return model.PropertyValue{Value: allowed}, nil
+			}
+		}
+		return model.PropertyValue{}, fmt.Errorf("must be one of %s", strings.ReplaceAll(property.AllowedValues, ",", ", "))
+	case model.PropertyTypeRange:
+		normalized := normalizePropertyNumber(value)
+		from, to := normalized, normalized
+		if match := propertyRangePattern.FindStringSubmatch(normalized); match != nil {
+			from, to = match[1], match[2]
+		}
+		fromNumber, err := parsePropertyNumber(property, from)
+		if err != nil {
+			return model.PropertyValue{}, err
+		}
+		toNumber, err := parsePropertyNumber(property, to)
+		if err != nil {
+			return model.PropertyValue{}, err
+		}
+		if fromNumber > toNumber {
+			return model.PropertyValue{}, errors.New("range start must not be greater than its end")
+		}
+		return numericPropertyValue(formatPropertyNumber(fromNumber)+"-"+formatPropertyNumber(toNumber), fromNumber, toNumber), nil
+	}
+	return model.PropertyValue{Value: value}, nil
+}
+
+// parsePropertyNumber parses a number of an int, decimal or range property and checks its bounds
+func parsePropertyNumber(property model.Property, value string) (float64, error) {
+	number, err := strconv.ParseFloat(normalizePropertyNumber(value), 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, errors.New("must be a number")
+	}
+	if property.DataType == model.PropertyTypeInt && number != math.Trunc(number) {
+		return 0, errors.New("must be an integer")
+	}
+	if property.MinValue != nil && number < *property.MinValue {
+		return 0, fmt.Errorf("must be at least %s", formatPropertyNumber(*property.MinValue))
+	}
+	if property.MaxValue != nil && number > *property.MaxValue {
+		return 0, fmt.Errorf("must be at most %s", formatPropertyNumber(*property.MaxValue))
+	}
+	return number, nil
+}
+
+// normalizePropertyNumber converts Persian digits and separators, e.g. "۱٬۲۰۰٫۵" to "1200.5"
+func normalizePropertyNumber(value string) string {
+	return propertyNumberReplacer.Replace(strings.TrimSpace(common.NormalizePersian(value)))
+}
+
+func formatPropertyNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+func numericPropertyValue(value string, from float64, to float64) model.PropertyValue {
+	return model.PropertyValue{Value: value, NumericValue: &from, NumericMaxValue: &to}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"testing"
+
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+)
+
+func TestParsePropertyValue(t *testing.T) {
+	bound := func(v float64) *float64 { return &v }
+	intProperty := model.Property{DataType: model.PropertyTypeInt, MinValue: bound(0), MaxValue: bound(10000)}
+	decimalProperty := model.Property{DataType: model.PropertyTypeDecimal, MinValue: bound(0)}
+	boolProperty := model.Property{DataType: model.PropertyTypeBool}
+	enumProperty := model.Property{DataType: model.PropertyTypeEnum, AllowedValues: "Petrol,Diesel,Hybrid"}
+	rangeProperty := model.Property{DataType: model.PropertyTypeRange, MinValue: bound(0), MaxValue: bound(5000)}
+	stringProperty := model.Property{DataType: model.PropertyTypeString}
+
+	cases := []struct {
+		name     string
+		property model.Property
+		value    string
+		want     string
+		from, to float64
+		numeric  bool
+		invalid  bool
+	}{
+		{name: "int", property: intProperty, value: "1600", want: "1600", from: 1600, to: 1600, numeric: true},
+		{name: "int with persian digits and separator", property: intProperty, value: "۱٬۶۰۰", want: "1600", from: 1600, to: 1600, numeric: true},
+		{name: "int with thousands separator", property: intProperty, value: "1,600", want: "1600", from: 1600, to: 1600, numeric: true},
+		{name: "int at min", property: intProperty, value: "0", want: "0", from: 0, to: 0, numeric: true},
+		{name: "int at max", property: intProperty, value: "10000", want: "10000", from: 10000, to: 10000, numeric: true},
+		{name: "int below min", property: intProperty, value: "-1", invalid: true},
+		{name: "int above max", property: intProperty, value: "10001", invalid: true},
+		{name: "int with a fraction", property: intProperty, value: "1600.5", invalid: true},
+		{name: "int not a number", property: intProperty, value: "abc", invalid: true},
+		{name: "decimal", property: decimalProperty, value: "1.50", want: "1.5", from: 1.5, to: 1.5, numeric: true},
+		{name: "decimal with persian separator", property: decimalProperty, value: "۱٫۵", want: "1.5", from: 1.5, to: 1.5, numeric: true},
+		{name: "decimal below min", property: decimalProperty, value: "-0.1", invalid: true},
+		{name: "decimal infinity", property: decimalProperty, value: "Inf", invalid: true},
+		{name: "bool yes", property: boolProperty, value: "Yes", want: "true", from: 1, to: 1, numeric: true},
+		{name: "bool persian", property: boolProperty, value: "ندارد", want: "false", from: 0, to: 0, numeric: true},
+		{name: "bool with arabic yeh", property: boolProperty, value: "خير", want: "false", from: 0, to: 0, numeric: true},
+		{name: "bool invalid", property: boolProperty, value: "maybe", invalid: true},
+		{name: "enum keeps the allowed spelling", property: enumProperty, value: " diesel ", want: "Diesel"},
+		{name: "enum not allowed", property: enumProperty, value: "Electric", invalid: true},
+		{name: "range", property: rangeProperty, value: "1200 - 1600", want: "1200-1600", from: 1200, to: 1600, numeric: true},
+		{name: "range with tilde and persian digits", property: rangeProperty, value: "۱۲۰۰~۱۶۰۰", want: "1200-1600", from: 1200, to: 1600, numeric: true},
+		{name: "range of one number", property: rangeProperty, value: "1400", want: "1400-1400", from: 1400, to: 1400, numeric: true},
+		{name: "range reversed", property: rangeProperty, value: "1600-1200", invalid: true},
+		{name: "range above max", property: rangeProperty, value: "1200-6000", invalid: true},
+		{name: "range below min", property: rangeProperty, value: "-100-100", invalid: true},
+		{name: "string", property: stringProperty, value: " Turbo ", want: "Turbo"},
+		{name: "empty", property: stringProperty, value: "  ", invalid: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := parsePropertyValue(tc.property, tc.value)
+			switch {
+			case tc.invalid:
+				if err == nil {
+					t.Fatalf("%q is valid as %+v", tc.value, value)
+				}
+			case err != nil:
+				t.Fatalf("%q is invalid: %v", tc.value, err)
+			case value.Value != tc.want:
+				t.Fatalf("%q is normalized to %q, want %q", tc.value, value.Value, tc.want)
+			case !tc.numeric && (value.NumericValue != nil || value.NumericMaxValue != nil):
+				t.Fatalf("%q has numeric values %v %v", tc.value, value.NumericValue, value.NumericMaxValue)
+			case tc.numeric && (value.NumericValue == nil || value.NumericMaxValue == nil || *value.NumericValue != tc.from || *value.NumericMaxValue != tc.to):
+				t.Fatalf("%q has numeric values %v %v, want %v %v", tc.value, value.NumericValue, value.NumericMaxValue, tc.from, tc.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+type CarModelRatingUsecase struct {
+	base       *BaseUsecase[model.CarModelRating, dto.CreateCarModelRating, dto.CreateCarModelRating, dto.CarModelRating]
+	repository repository.CarModelRatingRepository
+}
+
+func NewCarModelRatingUsecase(cfg *config.Config, repository repository.CarModelRatingRepository) *CarModelRatingUsecase {
+	return &CarModelRatingUsecase{
+		base:       NewBaseUsecase[model.CarModelRating, dto.CreateCarModelRating, dto.CreateCarModelRating, dto.CarModelRating](cfg, repository),
+		repository: repository,
+	}
+}
+
+// Rate a car model by the current user, rating it again changes the stars
+func (u *CarModelRatingUsecase) Rate(ctx context.Context, req dto.CreateCarModelRating) (dto.CarModelRating, error) {
+	if req.Stars < model.RatingMinStars || req.Stars > model.RatingMaxStars {
+		return dto.CarModelRating{}, &service_errors.ServiceError{EndUserMessage: service_errors.RatingStarsInvalid}
+	}
+	req.UserId = currentUserId(ctx)
+	rating, err := u.repository.Rate(ctx, model.CarModelRating{CarModelId: req.CarModelId, UserId: req.UserId, Stars: req.Stars})
+	if err != nil {
+		return dto.CarModelRating{}, err
+	}
+	return common.TypeConverter[dto.CarModelRating](rating)
+}
+
+// Delete a rating of the current user
+func (u *CarModelRatingUsecase) Delete(ctx context.Context, id int) error {
+	rating, err := u.own(ctx, id)
+	if err != nil {
+		return err
+	}
+	return u.repository.RemoveRating(ctx, rating.CarModelId, rating.UserId)
+}
+
+// Get a rating of the current user
+func (u *CarModelRatingUsecase) GetById(ctx context.Context, id int) (dto.CarModelRating, error) {
+	rating, err := u.own(ctx, id)
+	if err != nil {
+		return dto.CarModelRating{}, err
+	}
+	return common.TypeConverter[dto.CarModelRating](rating)
+}
+
+// Get the ratings of the current user by filter
+func (u *CarModelRatingUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModelRating], error) {
+	return u.base.GetByFilter(ctx, userFilter(ctx, req))
+}
+
+// own returns a rating of the current user, ratings of others are not found
+func (u *CarModelRatingUsecase) own(ctx context.Context, id int) (model.CarModelRating, error) {
+	rating, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return rating, err
+	}
+	if rating.UserId != currentUserId(ctx) {
+		return model.CarModelRating{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return rating, nil
+}
@@ -2,21 +2,33 @@ package usecase
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"strings"
 
+	"github.com/naeemaei/golang-clean-web-api/common"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
 	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
+// CompareMaxCarModels is the most car models compared at once
+const CompareMaxCarModels = 4
+
 type CarModelUsecase struct {
-	base *BaseUsecase[model.CarModel, dto.CreateCarModel, dto.UpdateCarModel, dto.CarModel]
+	base           *BaseUsecase[model.CarModel, dto.CreateCarModel, dto.UpdateCarModel, dto.CarModel]
+	repository     repository.CarModelRepository
+	rateRepository repository.ExchangeRateRepository
 }
 
-func NewCarModelUsecase(cfg *config.Config, repository repository.CarModelRepository) *CarModelUsecase {
+func NewCarModelUsecase(cfg *config.Config, repository repository.CarModelRepository, rateRepository repository.ExchangeRateRepository) *CarModelUsecase {
 	return &CarModelUsecase{
-		base: NewBaseUsecase[model.CarModel, dto.CreateCarModel, dto.UpdateCarModel, dto.CarModel](cfg, repository),
+		base:           NewBaseUsecase[model.CarModel, dto.CreateCarModel, dto.UpdateCarModel, dto.CarModel](cfg, repository),
+		repository:     repository,
+		rateRepository: rateRepository,
 	}
 }
 
@@ -40,7 +52,246 @@ func (s *CarModelUsecase) GetById(ctx context.Context, id int) (dto.CarModel, er
 	return s.base.GetById(ctx, id)
 }
 
+// GetPublicById returns a car model with its approved comments only and its prices converted to the
+// default currency
+func (s *CarModelUsecase) GetPublicById(ctx context.Context, id int) (dto.CarModel, error) {
+	carModel, err := s.base.GetById(ctx, id)
+	if err != nil {
+		return carModel, err
+	}
+	if err = s.convertCatalogPrices(ctx, []dto.CarModel{carModel}); err != nil {
+		return carModel, err
+	}
+	comments := []dto.CarModelComment{}
+	for _, comment := range carModel.CarModelComments {
+		if comment.Status == model.CommentApproved {
+			comments = append(comments, comment)
+		}
+	}
+	carModel.CarModelComments = comments
+	return carModel, nil
+}
+
+// GetPublicByFilter returns car models with their prices converted to the default currency
+func (s *CarModelUsecase) GetPublicByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModel], error) {
+	result, err := s.base.GetByFilter(ctx, req)
+	if err != nil {
+		return result, err
+	}
+	return result, s.convertCatalogPrices(ctx, *result.Items)
+}
+
+// convertCatalogPrices sets the converted prices of the years of the car models in the default currency
+func (s *CarModelUsecase) convertCatalogPrices(ctx context.Context, carModels []dto.CarModel) error {
+	prices := []dto.CarModelPriceHistory{}
+	for _, carModel := range carModels {
+		for _, year := range carModel.CarModelYears {
+			prices = append(prices, year.CarModelPriceHistories...)
+		}
+	}
+	if err := convertPriceResponses(ctx, s.rateRepository, prices, model.DefaultCurrency); err != nil {
+		return err
+	}
+	for _, carModel := range carModels {
+		for _, year := range carModel.CarModelYears {
+			n := copy(year.CarModelPriceHistories, prices)
+			prices = prices[n:]
+		}
+	}
+	return nil
+}
+
 // Get By Filter
 func (s *CarModelUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModel], error) {
 	return s.base.GetByFilter(ctx, req)
 }
+
+// Search full text over company, model, car type, gearbox and property values
+func (s *CarModelUsecase) Search(ctx context.Context, query string, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.CarModelSearchResult], error) {
+	count, items, err := s.repository.Search(ctx, strings.ToLower(common.NormalizePersian(query)), req)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Paginate[model.CarModelSearchResult, dto.CarModelSearchResult](count, items, req.GetPageNumber(), int64(req.GetPageSize()))
+}
+
+// Compare lines up the property values, the latest price of every year and the colors of 2 to
+// CompareMaxCarModels car models
+func (s *CarModelUsecase) Compare(ctx context.Context, ids []int) (dto.CarModelComparison, error) {
+	comparison := dto.CarModelComparison{CarModels: []dto.ComparedCarModel{}}
+	if len(ids) < 2 || len(ids) > CompareMaxCarModels {
+		return comparison, &service_errors.ServiceError{EndUserMessage: service_errors.CompareCarModelsInvalid}
+	}
+	carModels := []model.CarModel{}
+	for i, id := range ids {
+		if slices.Contains(ids[:i], id) {
+			return comparison, &service_errors.ServiceError{EndUserMessage: service_errors.CompareCarModelsInvalid}
+		}
+		carModel, err := s.repository.GetById(ctx, id)
+		if err != nil {
+			return comparison, err
+		}
+		carModels = append(carModels, carModel)
+		comparison.CarModels = append(comparison.CarModels, dto.ComparedCarModel{
+			Id:          carModel.Id,
+			Name:        carModel.Name,
+			CompanyName: carModel.Company.Name,
+			CarTypeName: carModel.CarType.Name,
+			GearboxName: carModel.Gearbox.Name,
+		})
+	}
+	prices, err := s.latestPrices(ctx, carModels)
+	if err != nil {
+		return comparison, err
+	}
+	comparison.Categories = compareProperties(carModels)
+	comparison.Prices = comparePrices(carModels, prices)
+	comparison.Currency = model.DefaultCurrency
+	comparison.Colors = compareColors(carModels)
+	return comparison, nil
+}
+
+// latestPrices returns the latest price of every year of the car models in the default currency by
+// car model year id, the prices without an exchange rate at their time are left out
+func (s *CarModelUsecase) latestPrices(ctx context.Context, carModels []model.CarModel) (map[int]float64, error) {
+	latest := []model.CarModelPriceHistory{}
+	for _, carModel := range carModels {
+		for _, year := range carModel.CarModelYears {
+			if price := latestPrice(year.CarModelPriceHistories); price != nil {
+				latest = append(latest, *price)
+			}
+		}
+	}
+	converted, err := convertPrices(ctx, s.rateRepository, latest, model.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+	prices := map[int]float64{}
+	for _, price := range converted {
+		prices[price.CarModelYearId] = price.Price
+	}
+	return prices, nil
+}
+
+// compareProperties groups the properties by category, both ordered by id
+func compareProperties(carModels []model.CarModel) []dto.PropertyCategoryComparison {
+	properties := []model.Property{}
+	values := map[int][]*string{}
+	for i, carModel := range carModels {
+		for _, item := range carModel.CarModelProperties {
+			if values[item.PropertyId] == nil {
+				properties = append(properties, item.Property)
+				values[item.PropertyId] = make([]*string, len(carModels))
+			}
+			value := item.Value
+			values[item.PropertyId][i] = &value
+		}
+	}
+	sort.Slice(properties, func(i, j int) bool {
+		if properties[i].CategoryId != properties[j].CategoryId {
+			return properties[i].CategoryId < properties[j].CategoryId
+		}
+		return properties[i].Id < properties[j].Id
+	})
+
+	categories := []dto.PropertyCategoryComparison{}
+	for _, property := range properties {
+		if len(categories) == 0 || categories[len(categories)-1].Id != property.CategoryId {
+			categories = append(categories, dto.PropertyCategoryComparison{
+				Id:         property.CategoryId,
+				Name:       property.Category.Name,
+				Icon:       property.Category.Icon,
+				Properties: []dto.PropertyComparison{},
+			})
+		}
+		category := &categories[len(categories)-1]
+		category.Properties = append(category.Properties, dto.PropertyComparison{
+			Id:       property.Id,
+			Name:     property.Name,
+			Icon:     property.Icon,
+			DataType: property.DataType,
+			Unit:     property.Unit,
+			Values:   values[property.Id],
+			Differs:  valuesDiffer(values[property.Id]),
+		})
+	}
+	return categories
+}
+
+// valuesDiffer compares the values by nameKey, a missing value differs from any value
+func valuesDiffer(values []*string) bool {
+	for _, value := range values[1:] {
+		if (value == nil) != (values[0] == nil) {
+			return true
+		}
+		if value != nil && nameKey(*value) != nameKey(*values[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// comparePrices returns a row for every persian year of the car models ordered by year, prices are
+// the latest prices by car model year id
+func comparePrices(carModels []model.CarModel, prices map[int]float64) []dto.YearPriceComparison {
+	years := []dto.YearPriceComparison{}
+	index := map[int]int{}
+	for i, carModel := range carModels {
+		for _, year := range carModel.CarModelYears {
+			row, ok := index[year.PersianYearId]
+			if !ok {
+				row = len(years)
+				index[year.PersianYearId] = row
+				years = append(years, dto.YearPriceComparison{
+					PersianYearId: year.PersianYearId,
+					Year:          year.PersianYear.Year,
+					PersianTitle:  year.PersianYear.PersianTitle,
+					Prices:        make([]*float64, len(carModels)),
+				})
+			}
+			if price, ok := prices[year.Id]; ok {
+				years[row].Prices[i] = &price
+			}
+		}
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i].Year < years[j].Year })
+	return years
+}
+
+func latestPrice(prices []model.CarModelPriceHistory) *model.CarModelPriceHistory {
+	var latest *model.CarModelPriceHistory
+	for i := range prices {
+		if latest == nil || prices[i].PriceAt.After(latest.PriceAt) ||
+			prices[i].PriceAt.Equal(latest.PriceAt) && prices[i].Id > latest.Id {
+			latest = &prices[i]
+		}
+	}
+	return latest
+}
+
+// compareColors returns a row for every color of the car models ordered by id
+func compareColors(carModels []model.CarModel) []dto.ColorComparison {
+	colors := []dto.ColorComparison{}
+	index := map[int]int{}
+	for i, carModel := range carModels {
+		for _, item := range carModel.CarModelColors {
+			row, ok := index[item.ColorId]
+			if !ok {
+				row = len(colors)
+				index[item.ColorId] = row
+				colors = append(colors, dto.ColorComparison{
+					Id:        item.ColorId,
+					Name:      item.Color.Name,
+					HexCode:   item.Color.HexCode,
+					Available: make([]bool, len(carModels)),
+				})
+			}
+			colors[row].Available[i] = true
+		}
+	}
+	for i := range colors {
+		colors[i].Differs = slices.Contains(colors[i].Available, false)
+	}
+	sort.Slice(colors, func(i, j int) bool { return colors[i].Id < colors[j].Id })
+	return colors
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/export"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+)
+
+// Columns of an import file, every other column is the name of a property
+const (
+	importCompanyColumn = "company"
+	importModelColumn   = "model"
+	importCarTypeColumn = "car_type"
+	importGearboxColumn = "gearbox"
+	importColorsColumn  = "colors"
+	importYearsColumn   = "years"
+)
+
+// colors and years cells hold lists, e.g. "white|black" or "1402, 1403"
+const importListSeparators = "|,،;"
+
+// limits of the create car model and car model property requests
+const (
+	importNameMinLength  = 3
+	importNameMaxLength  = 15
+	importValueMaxLength = 100
+)
+
+var importRequiredColumns = []string{importCompanyColumn, importModelColumn, importCarTypeColumn, importGearboxColumn}
+
+type CatalogImportUsecase struct {
+	cfg        *config.Config
+	repository repository.CatalogImportRepository
+}
+
+// catalogIndex is a CatalogLookup keyed by normalized names, so "Peugeot" matches "peugeot"
+// and Arabic characters match their Persian forms
+type catalogIndex struct {
+	companies    map[string]int
+	carTypes     map[string]int
+	gearboxes    map[string]int
+	colors       map[string]int
+	persianYears map[string]int
+	properties   map[string]int
+	// properties by id to validate their values
+	propertyTypes map[int]model.Property
+	// stored names of the car models, the repository matches car models by name
+	carModels   map[string]string
+	carModelIds map[string]int
+}
+
+type importHeader struct {
+	// header cells as they are in the file
+	names []string
+	// columns by index, empty for ignored columns
+	columns []string
+	// property ids by column index
+	properties map[int]int
+}
+
+func NewCatalogImportUsecase(cfg *config.Config, repository repository.CatalogImportRepository) *CatalogImportUsecase {
+	return &CatalogImportUsecase{cfg: cfg, repository: repository}
+}
+
+// Import reads car models with their colors, years and property values from a csv or xlsx file,
+// resolves the names to ids and upserts the valid rows in one transaction. Nothing is written when dryRun is set.
+func (u *CatalogImportUsecase) Import(ctx context.Context, format export.Format, file io.Reader, dryRun bool) (dto.CatalogImportResult, error) {
+	result := dto.CatalogImportResult{DryRun: dryRun, Rows: []dto.CatalogImportRow{}}
+
+	reader, err := export.NewReader(format, file)
+	if err != nil {
+		return result, &service_errors.ServiceError{EndUserMessage: service_errors.ImportFileInvalid, Err: err}
+	}
+	defer reader.Close()
+
+	header, err := reader.Read()
+	if err != nil {
+		return result, &service_errors.ServiceError{EndUserMessage: service_errors.ImportFileInvalid, Err: err}
+	}
+	records, err := u.readRecords(reader)
+	if err != nil {
+		return result, err
+	}
+
+	lookup, err := u.repository.Lookup(ctx)
+	if err != nil {
+		return result, err
+	}
+	index := newCatalogIndex(lookup)
+
+	parsedHeader, headerRow := parseImportHeader(header, index)
+	for _, record := range records {
+		if record != nil {
+			result.TotalRows++
+		}
+	}
+	// rows can not be read without a valid header
+	if len(headerRow.Errors) > 0 {
+		result.InvalidRows = result.TotalRows
+		result.Rows = append(result.Rows, headerRow)
+		return result, nil
+	}
+
+	items := []model.CatalogImportItem{}
+	// index of the report row of every item
+	itemRows := []int{}
+	models := map[string]int{}
+	for i, record := range records {
+		if record == nil {
+			continue
+		}
+		item, row := parsedHeader.parseRow(i+2, record, index, models)
+		if len(row.Errors) > 0 {
+			result.InvalidRows++
+		} else {
+			result.ValidRows++
+			items = append(items, item)
+			itemRows = append(itemRows, len(result.Rows))
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if !dryRun && len(items) > 0 {
+		items, err = u.repository.Import(ctx, items)
+		if err != nil {
+			return result, err
+		}
+	}
+	for i, item := range items {
+		row := &result.Rows[itemRows[i]]
+		row.CarModelId = item.CarModelId
+		if item.Created {
+			row.Action = ImportActionCreate
+			result.Created++
+		} else {
+			row.Action = ImportActionUpdate
+			result.Updated++
+		}
+	}
+	return result, nil
+}
+
+// readRecords reads the data rows, blank rows are kept as nil so row numbers match the file
+func (u *CatalogImportUsecase) readRecords(reader export.Reader) ([][]string, error) {
+	records := [][]string{}
+	count := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, &service_errors.ServiceError{EndUserMessage: service_errors.ImportFileInvalid, Err: err}
+		}
+		if isBlankRecord(record) {
+			records = append(records, nil)
+			continue
+		}
+		count++
+		if u.cfg.Import.MaxRows > 0 && count > u.cfg.Import.MaxRows {
+			return nil, &service_errors.ServiceError{EndUserMessage: service_errors.ImportTooManyRows}
+		}
+		records = append(records, record)
+	}
+}
+
+func newCatalogIndex(lookup model.CatalogLookup) catalogIndex {
+	index := catalogIndex{
+		companies:     normalizeKeys(lookup.Companies),
+		carTypes:      normalizeKeys(lookup.CarTypes),
+		gearboxes:     normalizeKeys(lookup.Gearboxes),
+		colors:        normalizeKeys(lookup.Colors),
+		persianYears:  normalizeKeys(lookup.PersianYears),
+		properties:    normalizeKeys(lookup.Properties),
+		propertyTypes: lookup.PropertyTypes,
+		carModels:     map[string]string{},
+		carModelIds:   lookup.CarModels,
+	}
+	for name := range lookup.CarModels {
+		index.carModels[nameKey(name)] = name
+	}
+	return index
+}
+
+// nameKey matches names regardless of Persian spelling, case and spacing
+func nameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(common.NormalizePersian(name)), " "))
+}
+
+func normalizeKeys(names map[string]int) map[string]int {
+	result := make(map[string]int, len(names))
+	for name, id := range names {
+		result[nameKey(name)] = id
+	}
+	return result
+}
+
+func parseImportHeader(header []string, index catalogIndex) (importHeader, dto.CatalogImportRow) {
+	parsed := importHeader{names: header, columns: make([]string, len(header)), properties: map[int]int{}}
+	row := dto.CatalogImportRow{Row: 1}
+	seen := map[string]bool{}
+	for i, cell := range header {
+		name := strings.TrimSpace(cell)
+		column := strings.ReplaceAll(strings.ToLower(name), " ", "_")
+		switch column {
+		case "":
+			continue
+		case importCompanyColumn, importModelColumn, importCarTypeColumn, importGearboxColumn, importColorsColumn, importYearsColumn:
+		default:
+			id, ok := index.properties[nameKey(name)]
+			if !ok {
+				row.Errors = append(row.Errors, dto.CatalogImportError{Column: name, Message: fmt.Sprintf("property %q not found", name)})
+				continue
+			}
+			parsed.properties[i] = id
+		}
+		if seen[column] {
+			row.Errors = append(row.Errors, dto.CatalogImportError{Column: name, Message: "duplicate column"})
+			continue
+		}
+		seen[column] = true
+		parsed.columns[i] = column
+	}
+	for _, column := range importRequiredColumns {
+		if !seen[column] {
+			row.Errors = append(row.Errors, dto.CatalogImportError{Column: column, Message: "column is required"})
+		}
+	}
+	return parsed, row
+}
+
+// parseRow validates a data row and resolves its names, models holds the row numbers of the
+// models seen so far to report duplicates
+func (h importHeader) parseRow(number int, record []string, index catalogIndex, models map[string]int) (model.CatalogImportItem, dto.CatalogImportRow) {
+	item := model.CatalogImportItem{Row: number, Properties: map[int]model.PropertyValue{}}
+	row := dto.CatalogImportRow{Row: number}
+	for i, column := range h.columns {
+		name := strings.TrimSpace(h.names[i])
+		addError := func(format string, args ...any) {
+			row.Errors = append(row.Errors, dto.CatalogImportError{Column: name, Message: fmt.Sprintf(format, args...)})
+		}
+		resolve := func(ids map[string]int, value string) int {
+			if value == "" {
+				addError("value is required")
+				return 0
+			}
+			id, ok := ids[nameKey(value)]
+			if !ok {
+				addError("%q not found", value)
+			}
+			return id
+		}
+
+		// values are stored normalized like the bound requests
+		value := ""
+		if i < len(record) {
+			value = strings.TrimSpace(common.NormalizePersian(record[i]))
+		}
+		switch column {
+		case importModelColumn:
+			item.Name = value
+			row.Name = value
+			length := utf8.RuneCountInString(value)
+			if length < importNameMinLength || length > importNameMaxLength {
+				addError("length must be between %d and %d", importNameMinLength, importNameMaxLength)
+				continue
+			}
+			key := nameKey(value)
+			if first, ok := models[key]; ok {
+				addError("duplicate of row %d", first)
+				continue
+			}
+			models[key] = number
+			if stored, ok := index.carModels[key]; ok {
+				item.Name, item.CarModelId = stored, index.carModelIds[stored]
+			} else {
+				item.Created = true
+			}
+		case importCompanyColumn:
+			item.CompanyId = resolve(index.companies, value)
+		case importCarTypeColumn:
+			item.CarTypeId = resolve(index.carTypes, value)
+		case importGearboxColumn:
+			item.GearboxId = resolve(index.gearboxes, value)
+		case importColorsColumn:
+			for _, name := range splitImportList(value) {
+				item.ColorIds = append(item.ColorIds, resolve(index.colors, name))
+			}
+		case importYearsColumn:
+			for _, name := range splitImportList(value) {
+				item.PersianYearIds = append(item.PersianYearIds, resolve(index.persianYears, name))
+			}
+		case "":
+		default:
+			if value == "" {
+				continue
+			}
+			if utf8.RuneCountInString(value) > importValueMaxLength {
+				addError("length must be at most %d", importValueMaxLength)
+				continue
+			}
+			parsed, err := parsePropertyValue(index.propertyTypes[h.properties[i]], value)
+			if err != nil {
+				addError("%s", err)
+				continue
+			}
+			item.Properties[h.properties[i]] = parsed
+		}
+	}
+	return item, row
+}
+
+func splitImportList(value string) []string {
+	names := []string{}
+	for _, name := range strings.FieldsFunc(value, func(r rune) bool { return strings.ContainsRune(importListSeparators, r) }) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func isBlankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -79,6 +79,7 @@ type Color struct {
 	HexCode string 
 }
 
+// StartAt and EndAt are set by the usecase
 type CreatePersianYear struct {
 	PersianTitle string
 	Year         int
@@ -86,6 +87,7 @@ type CreatePersianYear struct {
 	EndAt        time.Time
 }
 
+// StartAt and EndAt are set by the usecase
 type UpdatePersianYear struct {
 	PersianTitle string
 	Year         int
@@ -1,6 +1,11 @@
 package dto
 
-import "time"
+import (
+	"database/sql"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+)
 
 type CreateCarModel struct {
 	Name      string
@@ -26,6 +31,23 @@ type CarModel struct {
 	CarModelImages     []CarModelImage
 	CarModelProperties []CarModelProperty
 	CarModelComments   []CarModelComment
+	RatingCount        int
+	RatingAverage      float64
+	OneStarCount       int
+	TwoStarCount       int
+	ThreeStarCount     int
+	FourStarCount      int
+	FiveStarCount      int
+}
+
+type CarModelSearchResult struct {
+	Id          int
+	Name        string
+	CompanyName string
+	CarTypeName string
+	GearboxName string
+	Rank        float64
+	Highlight   string
 }
 
 type CreateCarModelColor struct {
@@ -64,18 +86,179 @@ type CreateCarModelPriceHistory struct {
 	CarModelYearId int
 	PriceAt        time.Time
 	Price          float64
+	Currency       string
 }
 
+// UpdateCarModelPriceHistory an empty Currency keeps the currency of the price
 type UpdateCarModelPriceHistory struct {
-	PriceAt time.Time
-	Price   float64
+	PriceAt  time.Time
+	Price    float64
+	Currency string
 }
 
+// CarModelPriceHistory ConvertedPrice is Price in ConvertedCurrency when a currency is asked, nil when
+// there is no exchange rate at PriceAt
 type CarModelPriceHistory struct {
-	Id             int
+	Id                int
+	CarModelYearId    int
+	PriceAt           time.Time
+	Price             float64
+	Currency          string
+	ConvertedPrice    *float64
+	ConvertedCurrency string
+}
+
+// PriceAnalyticsQuery limits the prices to [From, To), nil bounds are open. The prices are converted to
+// Currency, the default currency when it is empty
+type PriceAnalyticsQuery struct {
+	Bucket   string
+	From     *time.Time
+	To       *time.Time
+	Currency string
+}
+
+type PriceAnalytics struct {
+	Bucket   string
+	Currency string
+	From     *time.Time
+	To       *time.Time
+	Summary  PriceSummary
+	Buckets  []PriceBucket
+}
+
+// CarModelPriceAnalytics holds the analytics of every year of a car model, the years without prices are left out
+type CarModelPriceAnalytics struct {
+	CarModelId int
+	Years      []CarModelYearPriceAnalytics
+}
+
+type CarModelYearPriceAnalytics struct {
 	CarModelYearId int
-	PriceAt        time.Time
-	Price          float64
+	PriceAnalytics
+}
+
+type PriceSummary struct {
+	Count         int
+	FirstPrice    float64
+	LastPrice     float64
+	LastPriceAt   *time.Time
+	MinPrice      float64
+	MaxPrice      float64
+	AveragePrice  float64
+	ChangePercent *float64
+	Changes       []PriceChange
+}
+
+// PriceChange is the change of the price in the last Days days, nil when there was no price back then
+type PriceChange struct {
+	Days          int
+	BasePrice     *float64
+	ChangePercent *float64
+}
+
+// PriceBucket holds the prices of [StartAt, EndAt), ChangePercent is the change of Last from the
+// last price of the previous bucket
+type PriceBucket struct {
+	Label         string
+	StartAt       time.Time
+	EndAt         time.Time
+	Count         int
+	Min           float64
+	Max           float64
+	Average       float64
+	Last          float64
+	ChangePercent *float64
+}
+
+// CarModelComparison lines the compared car models up, the values of every row are in the order of CarModels
+type CarModelComparison struct {
+	CarModels  []ComparedCarModel
+	Categories []PropertyCategoryComparison
+	Prices     []YearPriceComparison
+	// Currency of Prices, the default currency
+	Currency string
+	Colors   []ColorComparison
+}
+
+type ComparedCarModel struct {
+	Id          int
+	Name        string
+	CompanyName string
+	CarTypeName string
+	GearboxName string
+}
+
+type PropertyCategoryComparison struct {
+	Id         int
+	Name       string
+	Icon       string
+	Properties []PropertyComparison
+}
+
+// PropertyComparison values are nil for the car models without the property
+type PropertyComparison struct {
+	Id       int
+	Name     string
+	Icon     string
+	DataType string
+	Unit     string
+	Values   []*string
+	Differs  bool
+}
+
+// YearPriceComparison prices are the latest price of every car model in the year, nil when there is none
+// or it has no exchange rate at its time
+type YearPriceComparison struct {
+	PersianYearId int
+	Year          int
+	PersianTitle  string
+	Prices        []*float64
+}
+
+type ColorComparison struct {
+	Id        int
+	Name      string
+	HexCode   string
+	Available []bool
+	Differs   bool
+}
+
+// FacetedCarModels prices are in Currency, the default currency
+type FacetedCarModels struct {
+	Items       *filter.PagedList[CarModelFacetItem]
+	Facets      []Facet
+	PriceRanges []PriceRangeFacet
+	Currency    string
+}
+
+type CarModelFacetItem struct {
+	Id          int
+	Name        string
+	CompanyName string
+	CountryName string
+	CarTypeName string
+	GearboxName string
+	MinPrice    *float64
+	MaxPrice    *float64
+}
+
+type Facet struct {
+	Field  string
+	Values []FacetValue
+}
+
+type FacetValue struct {
+	Id       int
+	Name     string
+	Count    int
+	Selected bool
+}
+
+// PriceRangeFacet counts the car models with a price in [From, To)
+type PriceRangeFacet struct {
+	From  float64
+	To    float64
+	Count int
 }
 
 type CreateCarModelImage struct {
@@ -88,45 +271,100 @@ type UpdateCarModelImage struct {
 	IsMainImage bool
 }
 
+type ReorderCarModelImages struct {
+	CarModelId int
+	ImageIds   []int
+}
+
 type CarModelImage struct {
 	Id          int
 	CarModelId  int
 	Image       File
 	IsMainImage bool
+	SortOrder   int
 }
 
+// NumericValue and NumericMaxValue are set by the usecase
 type CreateCarModelProperty struct {
-	CarModelId int
-	PropertyId int
-	Value      string
+	CarModelId      int
+	PropertyId      int
+	Value           string
+	NumericValue    *float64
+	NumericMaxValue *float64
 }
 
 type UpdateCarModelProperty struct {
-	Value string
+	Value           string
+	NumericValue    *float64
+	NumericMaxValue *float64
 }
 
 type CarModelProperty struct {
-	Id         int
-	CarModelId int
-	Property   Property
-	Value      string
+	Id              int
+	CarModelId      int
+	Property        Property
+	Value           string
+	NumericValue    *float64
+	NumericMaxValue *float64
 }
 
+// UserId and Status are set by the usecase
 type CreateCarModelComment struct {
 	CarModelId int
+	ParentId   *int
 	UserId     int
 	Message    string
+	Status     string
 }
 
+// Status is set by the usecase, an edited comment is moderated again
 type UpdateCarModelComment struct {
 	Message string
+	Status  string
 }
 
 type CarModelComment struct {
+	Id             int
+	CarModelId     int
+	User           User
+	UserId         int
+	Message        string
+	Status         string
+	ModerationNote string
+	ModeratedAt    sql.NullTime
+	ReportCount    int
+	ParentId       *int
+	HelpfulCount   int
+	UnhelpfulCount int
+}
+
+type ModerateCarModelComment struct {
+	Status         string
+	ModerationNote string
+}
+
+type CreateCarModelCommentReport struct {
+	Reason string
+}
+
+type CreateCarModelCommentVote struct {
+	Helpful bool
+}
+
+// UserId is set by the usecase
+type CreateCarModelRating struct {
+	CarModelId int
+	UserId     int
+	Stars      int
+}
+
+type CarModelRating struct {
 	Id         int
 	CarModelId int
-	User       User
-	Message    string
+	UserId     int
+	Stars      int
+	CreatedAt  time.Time
+	ModifiedAt sql.NullTime
 }
 
 type User struct {
@@ -136,3 +374,27 @@ type User struct {
 	LastName  string
 	Email     string
 }
+
+type CatalogImportResult struct {
+	DryRun      bool
+	TotalRows   int
+	ValidRows   int
+	InvalidRows int
+	Created     int
+	Updated     int
+	Rows        []CatalogImportRow
+}
+
+type CatalogImportRow struct {
+	Row  int
+	Name string
+	// Action is create or update, it is empty for invalid rows
+	Action     string
+	CarModelId int
+	Errors     []CatalogImportError
+}
+
+type CatalogImportError struct {
+	Column  string
+	Message string
+}
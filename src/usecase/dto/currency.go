@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type CreateExchangeRate struct {
+	Currency string
+	Rate     float64
+	RateAt   time.Time
+}
+
+type UpdateExchangeRate struct {
+	Currency string
+	Rate     float64
+	RateAt   time.Time
+}
+
+type ExchangeRate struct {
+	Id       int
+	Currency string
+	Rate     float64
+	RateAt   time.Time
+}
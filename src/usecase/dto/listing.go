@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UserId and Status are set by the usecase
+type CreateListing struct {
+	UserId         int
+	CarModelYearId int
+	CityId         int
+	ColorId        int
+	Mileage        int
+	Price          float64
+	Description    string
+	ContactName    string
+	ContactPhone   string
+	Status         string
+}
+
+// Status is set by the usecase
+type UpdateListing struct {
+	CarModelYearId int
+	CityId         int
+	ColorId        int
+	Mileage        int
+	Price          float64
+	Description    string
+	ContactName    string
+	ContactPhone   string
+	Status         string
+}
+
+type Listing struct {
+	Id             int
+	UserId         int
+	CarModelYear   ListingCarModelYear
+	City           IdName
+	Color          Color
+	Mileage        int
+	Price          float64
+	Description    string
+	ContactName    string
+	ContactPhone   string
+	Status         string
+	ModerationNote string
+	ModeratedAt    sql.NullTime
+	PublishedAt    sql.NullTime
+	ExpiresAt      sql.NullTime
+	SoldAt         sql.NullTime
+	CreatedAt      time.Time
+	// Images is the gallery of a single listing, the main image first
+	Images []ListingImage
+}
+
+type ListingCarModelYear struct {
+	Id          int
+	CarModel    ListingCarModel
+	PersianYear PersianYearWithoutDate
+}
+
+type ListingCarModel struct {
+	IdName
+	Company IdName
+}
+
+type ListingImage struct {
+	Id        int
+	Image     File
+	SortOrder int
+}
+
+// ChangeListingStatus is a status change of a listing by its owner
+type ChangeListingStatus struct {
+	Status string
+}
+
+type ModerateListing struct {
+	Status         string
+	ModerationNote string
+}
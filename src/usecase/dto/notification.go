@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UserId and BasePrice are set by the usecase
+type CreatePriceAlert struct {
+	CarModelYearId int
+	Rule           string
+	Threshold      float64
+	Currency       string
+	Percent        float64
+	Channel        string
+	UserId         int
+	BasePrice      sql.NullFloat64
+}
+
+type UpdatePriceAlert struct {
+	Rule      string
+	Threshold float64
+	Percent   float64
+	Channel   string
+	Enabled   bool
+}
+
+type PriceAlert struct {
+	Id              int
+	CarModelYearId  int
+	Rule            string
+	Threshold       float64
+	Currency        string
+	Percent         float64
+	Channel         string
+	Enabled         bool
+	BasePrice       sql.NullFloat64
+	LastTriggeredAt sql.NullTime
+	CreatedAt       time.Time
+}
+
+type Notification struct {
+	Id        int
+	Channel   string
+	Title     string
+	Body      string
+	Status    string
+	SentAt    sql.NullTime
+	ReadAt    sql.NullTime
+	LastError string
+	CreatedAt time.Time
+}
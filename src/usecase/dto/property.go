@@ -16,13 +16,22 @@ type PropertyCategory struct {
 	Properties []Property
 }
 
+// PropertyType is the data type of a property, MinValue and MaxValue bound numeric values and
+// AllowedValues are the comma separated values of an enum
+type PropertyType struct {
+	DataType      string
+	MinValue      *float64
+	MaxValue      *float64
+	AllowedValues string
+}
+
 type CreateProperty struct {
 	Name        string
 	CategoryId  int
 	Icon        string
 	Description string
-	DataType    string
 	Unit        string
+	PropertyType
 }
 
 type UpdateProperty struct {
@@ -30,15 +39,15 @@ type UpdateProperty struct {
 	CategoryId  int
 	Icon        string
 	Description string
-	DataType    string
 	Unit        string
+	PropertyType
 }
 
 type Property struct {
 	IdName
 	Icon        string
 	Description string
-	DataType    string
 	Unit        string
-	Category    PropertyCategory
+	PropertyType
+	Category PropertyCategory
 }
@@ -15,6 +15,7 @@ type RegisterUserByUsername struct {
 	Username  string
 	Email     string
 	Password  string
+	Language  string
 }
 
 func ToUserModel(from RegisterUserByUsername) model.User {
@@ -22,6 +23,7 @@ func ToUserModel(from RegisterUserByUsername) model.User {
 		FirstName: from.FirstName,
 		LastName:  from.LastName,
 		Email:     from.Email,
+		Language:  from.Language,
 	}
 }
 
@@ -34,3 +36,26 @@ type LoginByUsername struct {
 	Username string
 	Password string
 }
+
+type CreateTenant struct {
+	Name string
+	Slug string
+}
+
+type UpdateTenant struct {
+	Name    string
+	Enabled bool
+}
+
+type Tenant struct {
+	Id      int
+	Name    string
+	Slug    string
+	Enabled bool
+}
+
+type TenantAdmin struct {
+	Id       int
+	TenantId int
+	Username string
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+)
+
+// UserId is set by the usecase
+type CreateWatchlist struct {
+	Name   string
+	UserId int
+}
+
+type UpdateWatchlist struct {
+	Name   string
+	Shared bool
+}
+
+type Watchlist struct {
+	Id         int
+	Name       string
+	ShareToken *string
+	CreatedAt  time.Time
+	Entries    []WatchlistEntry
+}
+
+// CreateWatchlistEntry follows a car model or a car model year, CarModelId may be left out for a year
+type CreateWatchlistEntry struct {
+	CarModelId     int
+	CarModelYearId *int
+}
+
+type WatchlistEntry struct {
+	Id             int
+	CarModelId     int
+	CarModelName   string
+	CompanyName    string
+	CarModelYearId *int
+	// Followers is the number of users following the car model
+	Followers int
+	// LatestPrices has the latest price of every year of the entry, the newest year first
+	LatestPrices []WatchlistPrice
+}
+
+type WatchlistPrice struct {
+	CarModelYearId int
+	PersianTitle   string
+	Year           int
+	Price          float64
+	Currency       string
+	PriceAt        time.Time
+}
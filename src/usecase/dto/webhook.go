@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"database/sql"
+	"time"
+)
+
+// EventTypes and Entities are comma separated like in model.WebhookSubscription
+type CreateWebhookSubscription struct {
+	Url        string
+	EventTypes string
+	Entities   string
+	Secret     string
+}
+
+type UpdateWebhookSubscription struct {
+	Url        string
+	EventTypes string
+	Entities   string
+	Enabled    bool
+}
+
+type WebhookSubscription struct {
+	Id         int
+	Url        string
+	EventTypes string
+	Entities   string
+	Secret     string
+	Enabled    bool
+}
+
+type WebhookDelivery struct {
+	Id             int
+	SubscriptionId int
+	EventId        string
+	EventType      string
+	Payload        string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  sql.NullTime
+	DeliveredAt    sql.NullTime
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+}
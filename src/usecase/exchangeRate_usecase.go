@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+type ExchangeRateUsecase struct {
+	base       *BaseUsecase[model.ExchangeRate, dto.CreateExchangeRate, dto.UpdateExchangeRate, dto.ExchangeRate]
+	repository repository.ExchangeRateRepository
+}
+
+func NewExchangeRateUsecase(cfg *config.Config, repository repository.ExchangeRateRepository) *ExchangeRateUsecase {
+	return &ExchangeRateUsecase{
+		base:       NewBaseUsecase[model.ExchangeRate, dto.CreateExchangeRate, dto.UpdateExchangeRate, dto.ExchangeRate](cfg, repository),
+		repository: repository,
+	}
+}
+
+// Create a rate, a currency has one rate at a time
+func (u *ExchangeRateUsecase) Create(ctx context.Context, req dto.CreateExchangeRate) (dto.ExchangeRate, error) {
+	if err := u.checkUnique(ctx, 0, req.Currency, req.RateAt); err != nil {
+		return dto.ExchangeRate{}, err
+	}
+	return u.base.Create(ctx, req)
+}
+
+// Update a rate, it may not take the time of another rate of the currency
+func (u *ExchangeRateUsecase) Update(ctx context.Context, id int, req dto.UpdateExchangeRate) (dto.ExchangeRate, error) {
+	if err := u.checkUnique(ctx, id, req.Currency, req.RateAt); err != nil {
+		return dto.ExchangeRate{}, err
+	}
+	return u.base.Update(ctx, id, req)
+}
+
+// checkUnique rejects a rate of currency at rateAt when a rate other than id has that time
+func (u *ExchangeRateUsecase) checkUnique(ctx context.Context, id int, currency string, rateAt time.Time) error {
+	rates, err := u.repository.GetByCurrencies(ctx, []string{currency}, rateAt)
+	if err != nil {
+		return err
+	}
+	for _, rate := range rates {
+		if rate.Id != id && rate.RateAt.Equal(rateAt) {
+			return &service_errors.ServiceError{EndUserMessage: service_errors.ExchangeRateExists}
+		}
+	}
+	return nil
+}
+
+// Delete
+func (u *ExchangeRateUsecase) Delete(ctx context.Context, id int) error {
+	return u.base.Delete(ctx, id)
+}
+
+// Get By Id
+func (u *ExchangeRateUsecase) GetById(ctx context.Context, id int) (dto.ExchangeRate, error) {
+	return u.base.GetById(ctx, id)
+}
+
+// Get By Filter
+func (u *ExchangeRateUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.ExchangeRate], error) {
+	return u.base.GetByFilter(ctx, req)
+}
+
+// convertPriceResponses sets the converted prices of prices in currency
+func convertPriceResponses(ctx context.Context, rateRepository repository.ExchangeRateRepository, prices []dto.CarModelPriceHistory, currency string) error {
+	currencies := []string{currency}
+	until := time.Time{}
+	for _, price := range prices {
+		currencies = append(currencies, price.Currency)
+		if price.PriceAt.After(until) {
+			until = price.PriceAt
+		}
+	}
+	converter, err := newCurrencyConverter(ctx, rateRepository, currencies, until)
+	if err != nil {
+		return err
+	}
+	for i := range prices {
+		prices[i].ConvertedCurrency = currency
+		if value, ok := converter.convert(prices[i].Price, prices[i].Currency, currency, prices[i].PriceAt); ok {
+			prices[i].ConvertedPrice = &value
+		}
+	}
+	return nil
+}
+
+// convertPrices returns prices in currency, the prices without an exchange rate at their time are dropped
+func convertPrices(ctx context.Context, rateRepository repository.ExchangeRateRepository, prices []model.CarModelPriceHistory, currency string) ([]model.CarModelPriceHistory, error) {
+	converter, err := newPricesConverter(ctx, rateRepository, prices, currency)
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]model.CarModelPriceHistory, 0, len(prices))
+	for _, price := range prices {
+		value, ok := converter.convert(price.Price, price.Currency, currency, price.PriceAt)
+		if !ok {
+			continue
+		}
+		price.Price, price.Currency = value, currency
+		converted = append(converted, price)
+	}
+	return converted, nil
+}
+
+// newPricesConverter loads the rates that convert prices to currency
+func newPricesConverter(ctx context.Context, rateRepository repository.ExchangeRateRepository, prices []model.CarModelPriceHistory, currency string) (*currencyConverter, error) {
+	currencies := []string{currency}
+	until := time.Time{}
+	for _, price := range prices {
+		currencies = append(currencies, price.Currency)
+		if price.PriceAt.After(until) {
+			until = price.PriceAt
+		}
+	}
+	return newCurrencyConverter(ctx, rateRepository, currencies, until)
+}
+
+// currencyConverter converts prices with the exchange rates valid at their time
+type currencyConverter struct {
+	// rates of every currency ordered by RateAt
+	rates map[string][]model.ExchangeRate
+}
+
+// newCurrencyConverter loads the rates of currencies until until, Rial and Toman need no rates
+func newCurrencyConverter(ctx context.Context, repository repository.ExchangeRateRepository, currencies []string, until time.Time) (*currencyConverter, error) {
+	converter := &currencyConverter{rates: map[string][]model.ExchangeRate{}}
+	currencies = slices.DeleteFunc(slices.Clone(currencies), func(currency string) bool {
+		return currency == model.CurrencyRial || currency == model.CurrencyToman || currency == ""
+	})
+	if len(currencies) == 0 {
+		return converter, nil
+	}
+	rates, err := repository.GetByCurrencies(ctx, currencies, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, rate := range rates {
+		converter.rates[rate.Currency] = append(converter.rates[rate.Currency], rate)
+	}
+	return converter, nil
+}
+
+// convert returns price of currency from in currency to by the rates valid at at, false when a rate is missing
+func (c *currencyConverter) convert(price float64, from string, to string, at time.Time) (float64, bool) {
+	from, to = priceCurrency(from), priceCurrency(to)
+	if from == to {
+		return price, true
+	}
+	fromRate, ok := c.rialRate(from, at)
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := c.rialRate(to, at)
+	if !ok || toRate == 0 {
+		return 0, false
+	}
+	return roundPrice(price * fromRate / toRate), true
+}
+
+// rialRate returns the price of one unit of currency in Rial at at
+func (c *currencyConverter) rialRate(currency string, at time.Time) (float64, bool) {
+	switch currency {
+	case model.CurrencyRial:
+		return 1, true
+	case model.CurrencyToman:
+		return model.RialsPerToman, true
+	}
+	rates := c.rates[currency]
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].RateAt.After(at) })
+	if i == 0 {
+		return 0, false
+	}
+	return rates[i-1].Rate, true
+}
+
+// priceCurrency defaults the currency of the prices recorded before currencies
+func priceCurrency(currency string) string {
+	if currency == "" {
+		return model.DefaultCurrency
+	}
+	return currency
+}
@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+// listingOwnerTransitions are the statuses an owner moves a listing to, by the statuses it may be in:
+// a draft or an expired listing is submitted for review, a listing under review or published is
+// withdrawn to draft, and a published listing is sold
+var listingOwnerTransitions = map[string][]string{
+	model.ListingPendingReview: {model.ListingDraft, model.ListingExpired},
+	model.ListingDraft:         {model.ListingPendingReview, model.ListingPublished},
+	model.ListingSold:          {model.ListingPublished},
+}
+
+// listingModeratorTransitions publish a listing under review, or send it or a published one back to draft
+var listingModeratorTransitions = map[string][]string{
+	model.ListingPublished: {model.ListingPendingReview},
+	model.ListingDraft:     {model.ListingPendingReview, model.ListingPublished},
+}
+
+// listingEditable are the statuses in which the owner may change a listing and its images
+var listingEditable = []string{model.ListingDraft, model.ListingPendingReview, model.ListingPublished}
+
+type ListingUsecase struct {
+	cfg             *config.Config
+	base            *BaseUsecase[model.Listing, dto.CreateListing, dto.UpdateListing, dto.Listing]
+	repository      repository.ListingRepository
+	imageRepository repository.ListingImageRepository
+	fileRepository  repository.FileRepository
+}
+
+func NewListingUsecase(cfg *config.Config, repository repository.ListingRepository, imageRepository repository.ListingImageRepository,
+	fileRepository repository.FileRepository) *ListingUsecase {
+	return &ListingUsecase{
+		cfg:             cfg,
+		base:            NewBaseUsecase[model.Listing, dto.CreateListing, dto.UpdateListing, dto.Listing](cfg, repository),
+		repository:      repository,
+		imageRepository: imageRepository,
+		fileRepository:  fileRepository,
+	}
+}
+
+// Create a draft listing of the current user
+func (u *ListingUsecase) Create(ctx context.Context, req dto.CreateListing) (dto.Listing, error) {
+	req.UserId = currentUserId(ctx)
+	req.Status = model.ListingDraft
+	listing, err := u.base.Create(ctx, req)
+	if err != nil {
+		return listing, err
+	}
+	return u.GetById(ctx, listing.Id)
+}
+
+// Update a listing of the current user, an edited published listing is reviewed again
+func (u *ListingUsecase) Update(ctx context.Context, id int, req dto.UpdateListing) (dto.Listing, error) {
+	listing, err := u.editable(ctx, id)
+	if err != nil {
+		return dto.Listing{}, err
+	}
+	req.Status = listing.Status
+	if listing.Status == model.ListingPublished {
+		req.Status = model.ListingPendingReview
+	}
+	if _, err = u.base.Update(ctx, id, req); err != nil {
+		return dto.Listing{}, err
+	}
+	return u.GetById(ctx, id)
+}
+
+// Delete a listing of the current user
+func (u *ListingUsecase) Delete(ctx context.Context, id int) error {
+	if _, err := u.own(ctx, id); err != nil {
+		return err
+	}
+	return u.base.Delete(ctx, id)
+}
+
+// Get By Id with its images, users get published listings and their own ones
+func (u *ListingUsecase) GetById(ctx context.Context, id int) (dto.Listing, error) {
+	listing, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return dto.Listing{}, err
+	}
+	if listing.Status != model.ListingPublished && listing.UserId != currentUserId(ctx) && !isAdmin(ctx) {
+		return dto.Listing{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return u.withImages(ctx, listing)
+}
+
+// Get By Filter, users get published listings only
+func (u *ListingUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.Listing], error) {
+	if !isAdmin(ctx) {
+		req = statusFilter(req, model.ListingPublished)
+	}
+	return u.base.GetByFilter(ctx, req)
+}
+
+// Get the listings of the current user in any status
+func (u *ListingUsecase) GetMine(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.Listing], error) {
+	return u.base.GetByFilter(ctx, userFilter(ctx, req))
+}
+
+// GetModerationQueue returns the listings under review, the oldest first, unless the filter selects a Status
+func (u *ListingUsecase) GetModerationQueue(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.Listing], error) {
+	if _, ok := req.Filter["Status"]; !ok {
+		req = statusFilter(req, model.ListingPendingReview)
+	}
+	if req.Sort == nil || len(*req.Sort) == 0 {
+		req.Sort = &[]filter.Sort{{ColId: "Id", Sort: "asc"}}
+	}
+	return u.base.GetByFilter(ctx, req)
+}
+
+// ChangeStatus moves a listing of the current user along its lifecycle, see listingOwnerTransitions
+func (u *ListingUsecase) ChangeStatus(ctx context.Context, id int, req dto.ChangeListingStatus) (dto.Listing, error) {
+	from, ok := listingOwnerTransitions[req.Status]
+	if !ok {
+		return dto.Listing{}, &service_errors.ServiceError{EndUserMessage: service_errors.ListingStatusInvalid}
+	}
+	if _, err := u.own(ctx, id); err != nil {
+		return dto.Listing{}, err
+	}
+	values := map[string]interface{}{"Status": req.Status}
+	if req.Status == model.ListingSold {
+		values["SoldAt"] = sql.NullTime{Valid: true, Time: time.Now().UTC()}
+	}
+	if err := u.repository.Transition(ctx, id, from, values); err != nil {
+		return dto.Listing{}, err
+	}
+	return u.GetById(ctx, id)
+}
+
+// Moderate publishes a listing under review for the configured lifetime, or sends it back to draft
+// with a note for the owner
+func (u *ListingUsecase) Moderate(ctx context.Context, id int, req dto.ModerateListing) (dto.Listing, error) {
+	from, ok := listingModeratorTransitions[req.Status]
+	if !ok {
+		return dto.Listing{}, &service_errors.ServiceError{EndUserMessage: service_errors.ListingStatusInvalid}
+	}
+	if _, err := u.repository.GetById(ctx, id); err != nil {
+		return dto.Listing{}, err
+	}
+	now := time.Now().UTC()
+	values := map[string]interface{}{
+		"Status":         req.Status,
+		"ModerationNote": req.ModerationNote,
+		"ModeratedAt":    sql.NullTime{Valid: true, Time: now},
+	}
+	if req.Status == model.ListingPublished {
+		values["PublishedAt"] = sql.NullTime{Valid: true, Time: now}
+		values["ExpiresAt"] = sql.NullTime{Valid: true, Time: now.AddDate(0, 0, u.cfg.Listing.Lifetime)}
+	}
+	if err := u.repository.Transition(ctx, id, from, values); err != nil {
+		return dto.Listing{}, err
+	}
+	return u.GetById(ctx, id)
+}
+
+// AddImage adds an uploaded file to the end of the gallery of a listing of the current user,
+// a published listing is reviewed again
+func (u *ListingUsecase) AddImage(ctx context.Context, id int, req dto.CreateFile) (dto.Listing, error) {
+	listing, err := u.editable(ctx, id)
+	if err != nil {
+		return dto.Listing{}, err
+	}
+	images, err := u.images(ctx, id)
+	if err != nil {
+		return dto.Listing{}, err
+	}
+	if len(images) >= u.cfg.Listing.MaxImages {
+		return dto.Listing{}, &service_errors.ServiceError{EndUserMessage: service_errors.ListingImagesFull}
+	}
+	sortOrder := 1
+	if len(images) > 0 {
+		sortOrder = images[len(images)-1].SortOrder + 1
+	}
+	file, err := common.TypeConverter[model.File](req)
+	if err != nil {
+		return dto.Listing{}, err
+	}
+	if file, err = u.fileRepository.Create(ctx, file); err != nil {
+		return dto.Listing{}, err
+	}
+	if _, err = u.imageRepository.Create(ctx, model.ListingImage{ListingId: id, ImageId: file.Id, SortOrder: sortOrder}); err != nil {
+		return dto.Listing{}, err
+	}
+	if err = u.review(ctx, listing); err != nil {
+		return dto.Listing{}, err
+	}
+	return u.GetById(ctx, id)
+}
+
+// RemoveImage removes an image from the gallery of a listing of the current user
+func (u *ListingUsecase) RemoveImage(ctx context.Context, id int, imageId int) (dto.Listing, error) {
+	if _, err := u.editable(ctx, id); err != nil {
+		return dto.Listing{}, err
+	}
+	image, err := u.imageRepository.GetById(ctx, imageId)
+	if err != nil {
+		return dto.Listing{}, err
+	}
+	if image.ListingId != id {
+		return dto.Listing{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	if err = u.imageRepository.Delete(ctx, imageId); err != nil {
+		return dto.Listing{}, err
+	}
+	return u.GetById(ctx, id)
+}
+
+// own returns a listing of the current user, listings of others are not found
+func (u *ListingUsecase) own(ctx context.Context, id int) (model.Listing, error) {
+	listing, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return listing, err
+	}
+	if listing.UserId != currentUserId(ctx) {
+		return model.Listing{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return listing, nil
+}
+
+// editable returns a listing of the current user that has not been sold or expired
+func (u *ListingUsecase) editable(ctx context.Context, id int) (model.Listing, error) {
+	listing, err := u.own(ctx, id)
+	if err != nil {
+		return listing, err
+	}
+	if !slices.Contains(listingEditable, listing.Status) {
+		return model.Listing{}, &service_errors.ServiceError{EndUserMessage: service_errors.ListingStatusInvalid}
+	}
+	return listing, nil
+}
+
+// review sends a changed published listing back to review
+func (u *ListingUsecase) review(ctx context.Context, listing model.Listing) error {
+	if listing.Status != model.ListingPublished {
+		return nil
+	}
+	return u.repository.Transition(ctx, listing.Id, []string{model.ListingPublished}, map[string]interface{}{"Status": model.ListingPendingReview})
+}
+
+func (u *ListingUsecase) images(ctx context.Context, listingId int) ([]model.ListingImage, error) {
+	req := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageNumber: 1, PageSize: u.cfg.Listing.MaxImages},
+		DynamicFilter: filter.DynamicFilter{
+			Filter: map[string]filter.Filter{"ListingId": {Type: "equals", From: strconv.Itoa(listingId), FilterType: "number"}},
+			Sort:   &[]filter.Sort{{ColId: "SortOrder", Sort: "asc"}, {ColId: "Id", Sort: "asc"}},
+		},
+	}
+	_, images, err := u.imageRepository.GetByFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *images, nil
+}
+
+func (u *ListingUsecase) withImages(ctx context.Context, listing model.Listing) (dto.Listing, error) {
+	result, err := common.TypeConverter[dto.Listing](listing)
+	if err != nil {
+		return result, err
+	}
+	images, err := u.images(ctx, listing.Id)
+	if err != nil {
+		return result, err
+	}
+	result.Images = []dto.ListingImage{}
+	for _, image := range images {
+		item, err := common.TypeConverter[dto.ListingImage](image)
+		if err != nil {
+			return result, err
+		}
+		result.Images = append(result.Images, item)
+	}
+	return result, nil
+}
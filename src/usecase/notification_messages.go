@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/naeemaei/golang-clean-web-api/constant"
+)
+
+// Keys of the notification messages
+const (
+	PriceAlertTitle  = "PriceAlertTitle"
+	PriceAlertBody   = "PriceAlertBody"
+	PriceAlertAbove  = "PriceAlertAbove"
+	PriceAlertBelow  = "PriceAlertBelow"
+	PriceAlertChange = "PriceAlertChange"
+)
+
+// notificationMessages are the fmt formats of the notification texts of every supported language
+var notificationMessages = map[string]map[string]string{
+	constant.EnglishLanguage: {
+		// Price alert
+		PriceAlertTitle:  "Price alert: %s",
+		PriceAlertBody:   "The price of %s %s, it is %s now.",
+		PriceAlertAbove:  "rose to %s or above",
+		PriceAlertBelow:  "fell to %s or below",
+		PriceAlertChange: "changed %+.2f%% from %s",
+	},
+	constant.PersianLanguage: {
+		// Price alert
+		PriceAlertTitle:  "هشدار قیمت: %s",
+		PriceAlertBody:   "قیمت %s %s و اکنون %s است.",
+		PriceAlertAbove:  "به %s یا بیشتر رسید",
+		PriceAlertBelow:  "به %s یا کمتر رسید",
+		PriceAlertChange: "نسبت به %[2]s %+.2[1]f درصد تغییر کرد",
+	},
+}
+
+// notificationMessage formats the message of key in language, falling back to English for a user
+// without a language preference
+func notificationMessage(key string, language string, args ...any) string {
+	format, ok := notificationMessages[language][key]
+	if !ok {
+		format = notificationMessages[constant.DefaultLanguage][key]
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/infra/notification"
+	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
+	"github.com/naeemaei/golang-clean-web-api/pkg/metrics"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+type NotificationUsecase struct {
+	logger     logging.Logger
+	base       *BaseUsecase[model.Notification, dto.Notification, dto.Notification, dto.Notification]
+	repository repository.NotificationRepository
+	channels   map[string]notification.Channel
+}
+
+func NewNotificationUsecase(cfg *config.Config, repository repository.NotificationRepository) *NotificationUsecase {
+	return &NotificationUsecase{
+		logger:     logging.NewLogger(cfg),
+		base:       NewBaseUsecase[model.Notification, dto.Notification, dto.Notification, dto.Notification](cfg, repository),
+		repository: repository,
+		channels:   notification.NewChannels(cfg),
+	}
+}
+
+// Get a notification of the current user
+func (u *NotificationUsecase) GetById(ctx context.Context, id int) (dto.Notification, error) {
+	n, err := u.own(ctx, id)
+	if err != nil {
+		return dto.Notification{}, err
+	}
+	return common.TypeConverter[dto.Notification](n)
+}
+
+// Get the notifications of the current user by filter
+func (u *NotificationUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.Notification], error) {
+	return u.base.GetByFilter(ctx, userFilter(ctx, req))
+}
+
+// MarkRead marks a notification of the current user as read
+func (u *NotificationUsecase) MarkRead(ctx context.Context, id int) (dto.Notification, error) {
+	if _, err := u.own(ctx, id); err != nil {
+		return dto.Notification{}, err
+	}
+	if err := u.repository.MarkRead(ctx, id); err != nil {
+		return dto.Notification{}, err
+	}
+	return u.GetById(ctx, id)
+}
+
+// Notify creates the notifications whose DeduplicationKey is new and sends them through their channel,
+// a failed send is recorded on the notification and is not retried
+func (u *NotificationUsecase) Notify(ctx context.Context, notifications []model.Notification) error {
+	for i := range notifications {
+		notifications[i].Status = model.NotificationPending
+	}
+	created, err := u.repository.Enqueue(ctx, notifications)
+	if err != nil {
+		return err
+	}
+	for _, n := range created {
+		channel, ok := u.channels[n.Channel]
+		if !ok {
+			err = u.repository.MarkFailed(ctx, n.Id, "unknown channel "+n.Channel)
+		} else if sendErr := channel.Send(ctx, n); sendErr != nil {
+			u.logger.Error(logging.General, logging.Notification, sendErr.Error(), map[logging.ExtraKey]interface{}{logging.Channel: n.Channel})
+			metrics.Notification.WithLabelValues(n.Channel, "Failed").Inc()
+			err = u.repository.MarkFailed(ctx, n.Id, sendErr.Error())
+		} else {
+			metrics.Notification.WithLabelValues(n.Channel, "Success").Inc()
+			err = u.repository.MarkSent(ctx, n.Id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// own returns the notification when it belongs to the current user
+func (u *NotificationUsecase) own(ctx context.Context, id int) (model.Notification, error) {
+	n, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return model.Notification{}, err
+	}
+	if n.UserId != currentUserId(ctx) {
+		return model.Notification{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return n, nil
+}
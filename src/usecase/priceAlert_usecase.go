@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+type PriceAlertUsecase struct {
+	base            *BaseUsecase[model.PriceAlert, dto.CreatePriceAlert, dto.UpdatePriceAlert, dto.PriceAlert]
+	repository      repository.PriceAlertRepository
+	priceRepository repository.CarModelPriceHistoryRepository
+	rateRepository  repository.ExchangeRateRepository
+	notifications   *NotificationUsecase
+}
+
+func NewPriceAlertUsecase(cfg *config.Config, repository repository.PriceAlertRepository, priceRepository repository.CarModelPriceHistoryRepository, rateRepository repository.ExchangeRateRepository, notifications *NotificationUsecase) *PriceAlertUsecase {
+	return &PriceAlertUsecase{
+		base:            NewBaseUsecase[model.PriceAlert, dto.CreatePriceAlert, dto.UpdatePriceAlert, dto.PriceAlert](cfg, repository),
+		repository:      repository,
+		priceRepository: priceRepository,
+		rateRepository:  rateRepository,
+		notifications:   notifications,
+	}
+}
+
+// Create an alert of the current user, the last price of the car model year converted to the currency
+// of the alert is the base of a change rule
+func (u *PriceAlertUsecase) Create(ctx context.Context, req dto.CreatePriceAlert) (dto.PriceAlert, error) {
+	if err := validatePriceAlert(req.Rule, req.Threshold, req.Percent); err != nil {
+		return dto.PriceAlert{}, err
+	}
+	req.Currency = priceCurrency(req.Currency)
+	prices, err := u.priceRepository.GetByCarModelYear(ctx, req.CarModelYearId)
+	if err != nil {
+		return dto.PriceAlert{}, err
+	}
+	if len(prices) > 0 {
+		last := prices[len(prices)-1]
+		converter, err := newCurrencyConverter(ctx, u.rateRepository, []string{last.Currency, req.Currency}, last.PriceAt)
+		if err != nil {
+			return dto.PriceAlert{}, err
+		}
+		if price, ok := converter.convert(last.Price, last.Currency, req.Currency, last.PriceAt); ok {
+			req.BasePrice = sql.NullFloat64{Valid: true, Float64: price}
+		}
+	}
+	req.UserId = currentUserId(ctx)
+	return u.base.Create(ctx, req)
+}
+
+// Update an alert of the current user
+func (u *PriceAlertUsecase) Update(ctx context.Context, id int, req dto.UpdatePriceAlert) (dto.PriceAlert, error) {
+	if err := validatePriceAlert(req.Rule, req.Threshold, req.Percent); err != nil {
+		return dto.PriceAlert{}, err
+	}
+	if _, err := u.own(ctx, id); err != nil {
+		return dto.PriceAlert{}, err
+	}
+	return u.base.Update(ctx, id, req)
+}
+
+// Delete an alert of the current user
+func (u *PriceAlertUsecase) Delete(ctx context.Context, id int) error {
+	if _, err := u.own(ctx, id); err != nil {
+		return err
+	}
+	return u.base.Delete(ctx, id)
+}
+
+// Get an alert of the current user
+func (u *PriceAlertUsecase) GetById(ctx context.Context, id int) (dto.PriceAlert, error) {
+	alert, err := u.own(ctx, id)
+	if err != nil {
+		return dto.PriceAlert{}, err
+	}
+	return common.TypeConverter[dto.PriceAlert](alert)
+}
+
+// Get the alerts of the current user by filter
+func (u *PriceAlertUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.PriceAlert], error) {
+	return u.base.GetByFilter(ctx, userFilter(ctx, req))
+}
+
+// Evaluate notifies the alerts that match a PriceChanged event, it is a handler of the outbox bus.
+// A notification is created once per alert and price, so events published more than once notify once.
+func (u *PriceAlertUsecase) Evaluate(ctx context.Context, e event.Event) error {
+	if e.Type != event.PriceChanged {
+		return nil
+	}
+	payload := event.PriceChangedPayload{}
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return err
+	}
+	alerts, err := u.repository.Watching(ctx, e.TenantId, payload.CarModelYearId)
+	if err != nil || len(alerts) == 0 {
+		return err
+	}
+	currencies := []string{payload.Currency, payload.PreviousCurrency}
+	for _, alert := range alerts {
+		currencies = append(currencies, alert.Currency)
+	}
+	converter, err := newCurrencyConverter(ctx, u.rateRepository, currencies, payload.PriceAt)
+	if err != nil {
+		return err
+	}
+	notifications := []model.Notification{}
+	triggered := []model.PriceAlert{}
+	prices := map[int]float64{}
+	for _, alert := range alerts {
+		price, previous, ok := alertPrices(converter, alert, payload)
+		if !ok {
+			// a price without an exchange rate can not be compared
+			continue
+		}
+		prices[alert.Id] = price
+		reason, ok := matchPriceAlert(alert, price, previous)
+		if ok {
+			notifications = append(notifications, priceAlertNotification(alert, payload, price, reason))
+			triggered = append(triggered, alert)
+		} else if alert.Rule == model.PriceAlertChange && !alert.BasePrice.Valid {
+			// the first price of the car model year is the base of the alert
+			if err := u.repository.UpdateBasePrice(ctx, alert.Id, price, false); err != nil {
+				return err
+			}
+		}
+	}
+	if err := u.notifications.Notify(ctx, notifications); err != nil {
+		return err
+	}
+	for _, alert := range triggered {
+		if err := u.repository.UpdateBasePrice(ctx, alert.Id, prices[alert.Id], true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alertPrices converts the price and the previous price of payload to the currency of the alert by the
+// rates valid at their time, previous is nil when there is no previous price or it can not be converted
+func alertPrices(converter *currencyConverter, alert model.PriceAlert, payload event.PriceChangedPayload) (price float64, previous *float64, ok bool) {
+	price, ok = converter.convert(payload.Price, payload.Currency, alert.Currency, payload.PriceAt)
+	if !ok {
+		return 0, nil, false
+	}
+	if payload.PreviousPrice != nil {
+		at := payload.PriceAt
+		if payload.PreviousPriceAt != nil {
+			at = *payload.PreviousPriceAt
+		}
+		if converted, ok := converter.convert(*payload.PreviousPrice, payload.PreviousCurrency, alert.Currency, at); ok {
+			previous = &converted
+		}
+	}
+	return price, previous, true
+}
+
+// matchPriceAlert returns why the price matches the rule of the alert in the language of its user, threshold
+// rules match when the price crosses the threshold. Prices are in the currency of the alert
+func matchPriceAlert(alert model.PriceAlert, price float64, previous *float64) (string, bool) {
+	switch alert.Rule {
+	case model.PriceAlertAbove:
+		if price >= alert.Threshold && (previous == nil || *previous < alert.Threshold) {
+			return notificationMessage(PriceAlertAbove, alert.User.Language, formatPrice(alert.Threshold, alert.Currency)), true
+		}
+	case model.PriceAlertBelow:
+		if price <= alert.Threshold && (previous == nil || *previous > alert.Threshold) {
+			return notificationMessage(PriceAlertBelow, alert.User.Language, formatPrice(alert.Threshold, alert.Currency)), true
+		}
+	case model.PriceAlertChange:
+		if !alert.BasePrice.Valid || alert.BasePrice.Float64 == 0 {
+			return "", false
+		}
+		change := (price - alert.BasePrice.Float64) / alert.BasePrice.Float64 * 100
+		if math.Abs(change) >= alert.Percent {
+			return notificationMessage(PriceAlertChange, alert.User.Language, change, formatPrice(alert.BasePrice.Float64, alert.Currency)), true
+		}
+	}
+	return "", false
+}
+
+// priceAlertNotification is in the language of the user of the alert
+func priceAlertNotification(alert model.PriceAlert, payload event.PriceChangedPayload, price float64, reason string) model.Notification {
+	carModel := fmt.Sprintf("%s %d", alert.CarModelYear.CarModel.Name, alert.CarModelYear.PersianYear.Year)
+	n := model.Notification{
+		UserId:           alert.UserId,
+		Channel:          alert.Channel,
+		Title:            notificationMessage(PriceAlertTitle, alert.User.Language, carModel),
+		Body:             notificationMessage(PriceAlertBody, alert.User.Language, carModel, reason, formatPrice(price, alert.Currency)),
+		DeduplicationKey: fmt.Sprintf("price-alert:%d:%d", alert.Id, payload.CarModelPriceHistoryId),
+	}
+	n.TenantId = alert.TenantId
+	switch alert.Channel {
+	case model.NotificationSms:
+		n.Recipient = alert.User.MobileNumber
+	case model.NotificationEmail:
+		n.Recipient = alert.User.Email
+	}
+	return n
+}
+
+func validatePriceAlert(rule string, threshold float64, percent float64) error {
+	switch {
+	case (rule == model.PriceAlertAbove || rule == model.PriceAlertBelow) && threshold > 0,
+		rule == model.PriceAlertChange && percent > 0:
+		return nil
+	}
+	return &service_errors.ServiceError{EndUserMessage: service_errors.PriceAlertRuleInvalid}
+}
+
+// own returns the alert when it belongs to the current user
+func (u *PriceAlertUsecase) own(ctx context.Context, id int) (model.PriceAlert, error) {
+	alert, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return model.PriceAlert{}, err
+	}
+	if alert.UserId != currentUserId(ctx) {
+		return model.PriceAlert{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return alert, nil
+}
+
+func formatPrice(price float64, currency string) string {
+	return strconv.FormatFloat(price, 'f', -1, 64) + " " + priceCurrency(currency)
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+)
+
+func TestPriceAlertNotificationLanguage(t *testing.T) {
+	cases := []struct {
+		name     string
+		language string
+		rule     string
+		price    float64
+		title    string
+		body     string
+	}{
+		{"english above", "en", model.PriceAlertAbove, 120, "Price alert: Pride 1402",
+			"The price of Pride 1402 rose to 100 IRT or above, it is 120 IRT now."},
+		{"english change", "en", model.PriceAlertChange, 80, "Price alert: Pride 1402",
+			"The price of Pride 1402 changed -20.00% from 100 IRT, it is 80 IRT now."},
+		{"persian below", "fa", model.PriceAlertBelow, 80, "هشدار قیمت: Pride 1402",
+			"قیمت Pride 1402 به 100 IRT یا کمتر رسید و اکنون 80 IRT است."},
+		{"persian change", "fa", model.PriceAlertChange, 120, "هشدار قیمت: Pride 1402",
+			"قیمت Pride 1402 نسبت به 100 IRT +20.00 درصد تغییر کرد و اکنون 120 IRT است."},
+		{"no preference", "", model.PriceAlertAbove, 120, "Price alert: Pride 1402",
+			"The price of Pride 1402 rose to 100 IRT or above, it is 120 IRT now."},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			alert := model.PriceAlert{Rule: tc.rule, Threshold: 100, Percent: 10, Currency: model.DefaultCurrency,
+				BasePrice: sql.NullFloat64{Valid: true, Float64: 100}}
+			alert.User.Language = tc.language
+			alert.CarModelYear.CarModel.Name = "Pride"
+			alert.CarModelYear.PersianYear.Year = 1402
+			reason, ok := matchPriceAlert(alert, tc.price, nil)
+			if !ok {
+				t.Fatalf("%s alert does not match %v", tc.rule, tc.price)
+			}
+			n := priceAlertNotification(alert, event.PriceChangedPayload{}, tc.price, reason)
+			if n.Title != tc.title || n.Body != tc.body {
+				t.Fatalf("notification is %q %q, want %q %q", n.Title, n.Body, tc.title, tc.body)
+			}
+		})
+	}
+}
@@ -2,31 +2,60 @@ package usecase
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
 	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
 	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
 )
 
 type PropertyUsecase struct {
-	base *BaseUsecase[model.Property, dto.CreateProperty, dto.UpdateProperty, dto.Property]
+	base            *BaseUsecase[model.Property, dto.CreateProperty, dto.UpdateProperty, dto.Property]
+	repository      repository.PropertyRepository
+	valueRepository repository.CarModelPropertyRepository
 }
 
-func NewPropertyUsecase(cfg *config.Config, repository repository.PropertyRepository) *PropertyUsecase {
+func NewPropertyUsecase(cfg *config.Config, repository repository.PropertyRepository, valueRepository repository.CarModelPropertyRepository) *PropertyUsecase {
 	return &PropertyUsecase{
-		base: NewBaseUsecase[model.Property, dto.CreateProperty, dto.UpdateProperty, dto.Property](cfg, repository),
+		base:            NewBaseUsecase[model.Property, dto.CreateProperty, dto.UpdateProperty, dto.Property](cfg, repository),
+		repository:      repository,
+		valueRepository: valueRepository,
 	}
 }
 
 // Create
 func (u *PropertyUsecase) Create(ctx context.Context, req dto.CreateProperty) (dto.Property, error) {
+	var err error
+	if req.PropertyType, err = normalizePropertyType(req.PropertyType); err != nil {
+		return dto.Property{}, err
+	}
 	return u.base.Create(ctx, req)
 }
 
-// Update
+// Update, the data type can only be widened while car models have values of the property, since the
+// values are stored normalized for it
 func (s *PropertyUsecase) Update(ctx context.Context, id int, req dto.UpdateProperty) (dto.Property, error) {
+	var err error
+	if req.PropertyType, err = normalizePropertyType(req.PropertyType); err != nil {
+		return dto.Property{}, err
+	}
+	property, err := s.repository.GetById(ctx, id)
+	if err != nil {
+		return dto.Property{}, err
+	}
+	if narrowsPropertyType(property, req.PropertyType) {
+		count, err := s.valueRepository.CountByProperty(ctx, id)
+		if err != nil {
+			return dto.Property{}, err
+		}
+		if count > 0 {
+			return dto.Property{}, &service_errors.ServiceError{EndUserMessage: service_errors.PropertyTypeInUse}
+		}
+	}
 	return s.base.Update(ctx, id, req)
 }
 
@@ -44,3 +73,53 @@ func (s *PropertyUsecase) GetById(ctx context.Context, id int) (dto.Property, er
 func (s *PropertyUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.Property], error) {
 	return s.base.GetByFilter(ctx, req)
 }
+
+// narrowsPropertyType reports whether values valid for property may be invalid for propertyType: another
+// data type, a tighter bound or a removed allowed value
+func narrowsPropertyType(property model.Property, propertyType dto.PropertyType) bool {
+	if property.DataType != propertyType.DataType {
+		return true
+	}
+	if propertyType.MinValue != nil && (property.MinValue == nil || *propertyType.MinValue > *property.MinValue) {
+		return true
+	}
+	if propertyType.MaxValue != nil && (property.MaxValue == nil || *propertyType.MaxValue < *property.MaxValue) {
+		return true
+	}
+	if property.AllowedValues == "" {
+		return false
+	}
+	allowed := strings.Split(propertyType.AllowedValues, ",")
+	for _, value := range strings.Split(property.AllowedValues, ",") {
+		if !slices.Contains(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePropertyType defaults the data type to string and drops the bounds and allowed values
+// the data type does not use
+func normalizePropertyType(propertyType dto.PropertyType) (dto.PropertyType, error) {
+	invalid := &service_errors.ServiceError{EndUserMessage: service_errors.PropertyTypeInvalid}
+	if propertyType.DataType == "" {
+		propertyType.DataType = model.PropertyTypeString
+	}
+	if !slices.Contains(model.PropertyTypes, propertyType.DataType) {
+		return propertyType, invalid
+	}
+	switch propertyType.DataType {
+	case model.PropertyTypeInt, model.PropertyTypeDecimal, model.PropertyTypeRange:
+		if propertyType.MinValue != nil && propertyType.MaxValue != nil && *propertyType.MinValue > *propertyType.MaxValue {
+			return propertyType, invalid
+		}
+	default:
+		propertyType.MinValue, propertyType.MaxValue = nil, nil
+	}
+	if propertyType.DataType != model.PropertyTypeEnum {
+		propertyType.AllowedValues = ""
+	} else if propertyType.AllowedValues = normalizeList(propertyType.AllowedValues); propertyType.AllowedValues == "" {
+		return propertyType, invalid
+	}
+	return propertyType, nil
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+type TenantUsecase struct {
+	base        *BaseUsecase[model.Tenant, dto.CreateTenant, dto.UpdateTenant, dto.Tenant]
+	userUsecase *UserUsecase
+}
+
+func NewTenantUsecase(cfg *config.Config, repository repository.TenantRepository, userRepository repository.UserRepository) *TenantUsecase {
+	return &TenantUsecase{
+		base:        NewBaseUsecase[model.Tenant, dto.CreateTenant, dto.UpdateTenant, dto.Tenant](cfg, repository),
+		userUsecase: NewUserUsecase(cfg, userRepository),
+	}
+}
+
+// Create
+func (u *TenantUsecase) Create(ctx context.Context, req dto.CreateTenant) (dto.Tenant, error) {
+	count, _, err := u.base.repository.GetByFilter(ctx, filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: 1, PageNumber: 1},
+		DynamicFilter:   filter.DynamicFilter{Filter: map[string]filter.Filter{"Slug": {Type: "equals", From: req.Slug}}},
+	})
+	if err != nil {
+		return dto.Tenant{}, err
+	}
+	if count > 0 {
+		return dto.Tenant{}, &service_errors.ServiceError{EndUserMessage: service_errors.TenantSlugExists}
+	}
+	return u.base.Create(ctx, req)
+}
+
+// Update, the platform tenant can not be disabled because its users could not log in any more
+func (u *TenantUsecase) Update(ctx context.Context, id int, req dto.UpdateTenant) (dto.Tenant, error) {
+	if id == constant.DefaultTenantId && !req.Enabled {
+		return dto.Tenant{}, &service_errors.ServiceError{EndUserMessage: service_errors.DefaultTenantLocked}
+	}
+	return u.base.Update(ctx, id, req)
+}
+
+// Delete, the platform tenant can not be deleted
+func (u *TenantUsecase) Delete(ctx context.Context, id int) error {
+	if id == constant.DefaultTenantId {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.DefaultTenantLocked}
+	}
+	return u.base.Delete(ctx, id)
+}
+
+// Get By Id
+func (u *TenantUsecase) GetById(ctx context.Context, id int) (dto.Tenant, error) {
+	return u.base.GetById(ctx, id)
+}
+
+// Get By Filter
+func (u *TenantUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.Tenant], error) {
+	return u.base.GetByFilter(ctx, req)
+}
+
+// GetEnabledBySlug returns the tenant of slug, disabled tenants are not found
+func (u *TenantUsecase) GetEnabledBySlug(ctx context.Context, slug string) (dto.Tenant, error) {
+	_, tenants, err := u.base.repository.GetByFilter(ctx, filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: 1, PageNumber: 1},
+		DynamicFilter:   filter.DynamicFilter{Filter: map[string]filter.Filter{"Slug": {Type: "equals", From: slug}}},
+	})
+	if err != nil {
+		return dto.Tenant{}, err
+	}
+	if len(*tenants) == 0 || !(*tenants)[0].Enabled {
+		return dto.Tenant{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return common.TypeConverter[dto.Tenant]((*tenants)[0])
+}
+
+// Create an admin user of the tenant, it has the tenant-admin role that is limited to the data of the tenant
+func (u *TenantUsecase) CreateAdmin(ctx context.Context, tenantId int, req dto.RegisterUserByUsername) (dto.TenantAdmin, error) {
+	if _, err := u.base.GetById(ctx, tenantId); err != nil {
+		return dto.TenantAdmin{}, err
+	}
+	user, err := u.userUsecase.RegisterTenantAdmin(ctx, tenantId, req)
+	if err != nil {
+		return dto.TenantAdmin{}, err
+	}
+	return dto.TenantAdmin{Id: user.Id, TenantId: user.TenantId, Username: user.Username}, nil
+}
@@ -24,6 +24,8 @@ type tokenDto struct {
 	MobileNumber string
 	Email        string
 	Roles        []string
+	TenantId     int
+	Language     string
 }
 
 func NewTokenUsecase(cfg *config.Config) *TokenUsecase {
@@ -48,6 +50,8 @@ func (s *TokenUsecase) GenerateToken(token tokenDto) (*dto.TokenDetail, error) {
 	atc[constant.EmailKey] = token.Email
 	atc[constant.MobileNumberKey] = token.MobileNumber
 	atc[constant.RolesKey] = token.Roles
+	atc[constant.TenantIdKey] = token.TenantId
+	atc[constant.LanguageKey] = token.Language
 	atc[constant.ExpireTimeKey] = td.AccessTokenExpireTime
 
 	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atc)
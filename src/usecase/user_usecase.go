@@ -5,6 +5,7 @@ import (
 
 	"github.com/naeemaei/golang-clean-web-api/common"
 	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/constant"
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
 	"github.com/naeemaei/golang-clean-web-api/domain/repository"
 	"github.com/naeemaei/golang-clean-web-api/pkg/logging"
@@ -40,7 +41,7 @@ func (u *UserUsecase) LoginByUsername(ctx context.Context, username string, pass
 		return nil, err
 	}
 	tokenDto := tokenDto{UserId: user.Id, FirstName: user.FirstName, LastName: user.LastName,
-		Email: user.Email, MobileNumber: user.MobileNumber}
+		Email: user.Email, MobileNumber: user.MobileNumber, TenantId: user.TenantId, Language: user.Language}
 
 	if len(*user.UserRoles) > 0 {
 		for _, ur := range *user.UserRoles {
@@ -59,33 +60,50 @@ func (u *UserUsecase) LoginByUsername(ctx context.Context, username string, pass
 
 // Register by username
 func (u *UserUsecase) RegisterByUsername(ctx context.Context, req dto.RegisterUserByUsername) error {
+	_, err := u.registerByUsername(ctx, req, constant.DefaultTenantId, constant.DefaultRoleName)
+	return err
+}
+
+// Register an admin user of a tenant
+func (u *UserUsecase) RegisterTenantAdmin(ctx context.Context, tenantId int, req dto.RegisterUserByUsername) (model.User, error) {
+	return u.registerByUsername(ctx, req, tenantId, constant.TenantAdminRoleName)
+}
+
+func (u *UserUsecase) registerByUsername(ctx context.Context, req dto.RegisterUserByUsername, tenantId int, roleName string) (model.User, error) {
 	user := dto.ToUserModel(req)
+	user.TenantId = tenantId
 
 	exists, err := u.repository.ExistsEmail(ctx, req.Email)
 	if err != nil {
-		return err
+		return user, err
 	}
 	if exists {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.EmailExists}
+		return user, &service_errors.ServiceError{EndUserMessage: service_errors.EmailExists}
 	}
 	exists, err = u.repository.ExistsUsername(ctx, req.Username)
 	if err != nil {
-		return err
+		return user, err
 	}
 	if exists {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.UsernameExists}
+		return user, &service_errors.ServiceError{EndUserMessage: service_errors.UsernameExists}
 	}
 
 	bp := []byte(req.Password)
 	hp, err := bcrypt.GenerateFromPassword(bp, bcrypt.DefaultCost)
 	if err != nil {
 		u.logger.Error(logging.General, logging.HashPassword, err.Error(), nil)
-		return err
+		return user, err
 	}
 	user.Password = string(hp)
-	_, err = u.repository.CreateUser(ctx, user)
-	return err
+	return u.repository.CreateUserWithRole(ctx, user, roleName)
+
+}
 
+// SetLanguage stores the language of the error messages of the current user, it is used by the tokens
+// issued after the change
+func (u *UserUsecase) SetLanguage(ctx context.Context, language string) error {
+	_, err := u.repository.Update(ctx, currentUserId(ctx), map[string]interface{}{"Language": language})
+	return err
 }
 
 // Register/login by mobile number
@@ -100,6 +118,7 @@ func (u *UserUsecase) RegisterAndLoginByMobileNumber(ctx context.Context, mobile
 	}
 
 	user := model.User{MobileNumber: mobileNumber, Username: mobileNumber}
+	user.TenantId = constant.DefaultTenantId
 
 	if exists {
 		user, err = u.repository.FetchUserInfo(ctx, user.Username, user.Password)
@@ -129,7 +148,7 @@ func (u *UserUsecase) RegisterAndLoginByMobileNumber(ctx context.Context, mobile
 
 func (u *UserUsecase) generateToken(user model.User) (*dto.TokenDetail, error) {
 	tokenDto := tokenDto{UserId: user.Id, FirstName: user.FirstName, LastName: user.LastName,
-		Email: user.Email, MobileNumber: user.MobileNumber}
+		Email: user.Email, MobileNumber: user.MobileNumber, TenantId: user.TenantId, Language: user.Language}
 
 	if len(*user.UserRoles) > 0 {
 		for _, ur := range *user.UserRoles {
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strconv"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	database "github.com/naeemaei/golang-clean-web-api/infra/persistence/database"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+// WatchlistMaxEntries is the most entries a watchlist holds
+const WatchlistMaxEntries = 100
+
+type WatchlistUsecase struct {
+	base            *BaseUsecase[model.Watchlist, dto.CreateWatchlist, dto.UpdateWatchlist, dto.Watchlist]
+	repository      repository.WatchlistRepository
+	entryRepository repository.WatchlistEntryRepository
+	yearRepository  repository.CarModelYearRepository
+	priceRepository repository.CarModelPriceHistoryRepository
+	rateRepository  repository.ExchangeRateRepository
+}
+
+func NewWatchlistUsecase(cfg *config.Config, repository repository.WatchlistRepository, entryRepository repository.WatchlistEntryRepository,
+	yearRepository repository.CarModelYearRepository, priceRepository repository.CarModelPriceHistoryRepository,
+	rateRepository repository.ExchangeRateRepository) *WatchlistUsecase {
+	return &WatchlistUsecase{
+		base:            NewBaseUsecase[model.Watchlist, dto.CreateWatchlist, dto.UpdateWatchlist, dto.Watchlist](cfg, repository),
+		repository:      repository,
+		entryRepository: entryRepository,
+		yearRepository:  yearRepository,
+		priceRepository: priceRepository,
+		rateRepository:  rateRepository,
+	}
+}
+
+// Create a private watchlist of the current user
+func (u *WatchlistUsecase) Create(ctx context.Context, req dto.CreateWatchlist) (dto.Watchlist, error) {
+	req.UserId = currentUserId(ctx)
+	return u.base.Create(ctx, req)
+}
+
+// Update a watchlist of the current user, sharing it creates a share token and making it private again
+// drops the token so the old link stops working
+func (u *WatchlistUsecase) Update(ctx context.Context, id int, req dto.UpdateWatchlist) (dto.Watchlist, error) {
+	watchlist, err := u.own(ctx, id)
+	if err != nil {
+		return dto.Watchlist{}, err
+	}
+	shareToken := watchlist.ShareToken
+	if !req.Shared {
+		shareToken = nil
+	} else if shareToken == nil {
+		token, err := newShareToken()
+		if err != nil {
+			return dto.Watchlist{}, err
+		}
+		shareToken = &token
+	}
+	if _, err = u.repository.Update(ctx, id, map[string]interface{}{"Name": req.Name, "ShareToken": shareToken}); err != nil {
+		return dto.Watchlist{}, err
+	}
+	return u.GetById(ctx, id)
+}
+
+// Delete a watchlist of the current user
+func (u *WatchlistUsecase) Delete(ctx context.Context, id int) error {
+	if _, err := u.own(ctx, id); err != nil {
+		return err
+	}
+	return u.base.Delete(ctx, id)
+}
+
+// Get a watchlist of the current user with its entries
+func (u *WatchlistUsecase) GetById(ctx context.Context, id int) (dto.Watchlist, error) {
+	watchlist, err := u.own(ctx, id)
+	if err != nil {
+		return dto.Watchlist{}, err
+	}
+	return u.withEntries(ctx, watchlist)
+}
+
+// Get the watchlists of the current user by filter, without their entries
+func (u *WatchlistUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.Watchlist], error) {
+	return u.base.GetByFilter(ctx, userFilter(ctx, req))
+}
+
+// GetShared returns a shared watchlist with its entries, it needs no token
+func (u *WatchlistUsecase) GetShared(ctx context.Context, shareToken string) (dto.Watchlist, error) {
+	watchlist, err := u.repository.GetByShareToken(ctx, shareToken)
+	if err != nil {
+		return dto.Watchlist{}, err
+	}
+	// the entries are read in the tenant of the watchlist
+	ctx = database.WithTenant(ctx, watchlist.TenantId)
+	return u.withEntries(ctx, watchlist)
+}
+
+// AddEntry adds a car model or a car model year to a watchlist of the current user
+func (u *WatchlistUsecase) AddEntry(ctx context.Context, id int, req dto.CreateWatchlistEntry) (dto.Watchlist, error) {
+	watchlist, err := u.own(ctx, id)
+	if err != nil {
+		return dto.Watchlist{}, err
+	}
+	invalid := &service_errors.ServiceError{EndUserMessage: service_errors.WatchlistEntryInvalid}
+	if req.CarModelYearId != nil {
+		year, err := u.yearRepository.GetById(ctx, *req.CarModelYearId)
+		if err != nil {
+			return dto.Watchlist{}, invalid
+		}
+		if req.CarModelId != 0 && req.CarModelId != year.CarModelId {
+			return dto.Watchlist{}, invalid
+		}
+		req.CarModelId = year.CarModelId
+	} else if req.CarModelId == 0 {
+		return dto.Watchlist{}, invalid
+	}
+
+	entries, err := u.entries(ctx, id)
+	if err != nil {
+		return dto.Watchlist{}, err
+	}
+	if len(entries) >= WatchlistMaxEntries {
+		return dto.Watchlist{}, &service_errors.ServiceError{EndUserMessage: service_errors.WatchlistFull}
+	}
+	for _, entry := range entries {
+		if entry.CarModelId == req.CarModelId && equalIds(entry.CarModelYearId, req.CarModelYearId) {
+			return dto.Watchlist{}, &service_errors.ServiceError{EndUserMessage: service_errors.WatchlistEntryExists}
+		}
+	}
+	entry := model.WatchlistEntry{WatchlistId: id, CarModelId: req.CarModelId, CarModelYearId: req.CarModelYearId}
+	if _, err = u.entryRepository.Create(ctx, entry); err != nil {
+		return dto.Watchlist{}, err
+	}
+	return u.withEntries(ctx, watchlist)
+}
+
+// RemoveEntry removes an entry from a watchlist of the current user
+func (u *WatchlistUsecase) RemoveEntry(ctx context.Context, id int, entryId int) (dto.Watchlist, error) {
+	watchlist, err := u.own(ctx, id)
+	if err != nil {
+		return dto.Watchlist{}, err
+	}
+	entry, err := u.entryRepository.GetById(ctx, entryId)
+	if err != nil {
+		return dto.Watchlist{}, err
+	}
+	if entry.WatchlistId != id {
+		return dto.Watchlist{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	if err = u.entryRepository.Delete(ctx, entryId); err != nil {
+		return dto.Watchlist{}, err
+	}
+	return u.withEntries(ctx, watchlist)
+}
+
+// own returns a watchlist of the current user, watchlists of others are not found
+func (u *WatchlistUsecase) own(ctx context.Context, id int) (model.Watchlist, error) {
+	watchlist, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return watchlist, err
+	}
+	if watchlist.UserId != currentUserId(ctx) {
+		return model.Watchlist{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+	}
+	return watchlist, nil
+}
+
+func (u *WatchlistUsecase) entries(ctx context.Context, watchlistId int) ([]model.WatchlistEntry, error) {
+	req := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageNumber: 1, PageSize: WatchlistMaxEntries},
+		DynamicFilter: filter.DynamicFilter{
+			Filter: map[string]filter.Filter{"WatchlistId": {Type: "equals", From: strconv.Itoa(watchlistId), FilterType: "number"}},
+			Sort:   &[]filter.Sort{{ColId: "Id", Sort: "asc"}},
+		},
+	}
+	_, entries, err := u.entryRepository.GetByFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *entries, nil
+}
+
+// withEntries adds the entries of watchlist with their followers and latest prices
+func (u *WatchlistUsecase) withEntries(ctx context.Context, watchlist model.Watchlist) (dto.Watchlist, error) {
+	result, err := common.TypeConverter[dto.Watchlist](watchlist)
+	if err != nil {
+		return result, err
+	}
+	entries, err := u.entries(ctx, watchlist.Id)
+	if err != nil {
+		return result, err
+	}
+	carModelIds := []int{}
+	for _, entry := range entries {
+		carModelIds = append(carModelIds, entry.CarModelId)
+	}
+	followers, err := u.repository.Followers(ctx, carModelIds)
+	if err != nil {
+		return result, err
+	}
+	prices := map[int][]dto.WatchlistPrice{}
+	result.Entries = []dto.WatchlistEntry{}
+	for _, entry := range entries {
+		if _, ok := prices[entry.CarModelId]; !ok {
+			if prices[entry.CarModelId], err = u.latestPrices(ctx, entry.CarModel); err != nil {
+				return result, err
+			}
+		}
+		item := dto.WatchlistEntry{
+			Id:             entry.Id,
+			CarModelId:     entry.CarModelId,
+			CarModelName:   entry.CarModel.Name,
+			CompanyName:    entry.CarModel.Company.Name,
+			CarModelYearId: entry.CarModelYearId,
+			Followers:      followers[entry.CarModelId],
+			LatestPrices:   []dto.WatchlistPrice{},
+		}
+		for _, price := range prices[entry.CarModelId] {
+			if entry.CarModelYearId == nil || *entry.CarModelYearId == price.CarModelYearId {
+				item.LatestPrices = append(item.LatestPrices, price)
+			}
+		}
+		result.Entries = append(result.Entries, item)
+	}
+	return result, nil
+}
+
+// latestPrices returns the latest price of every year of a car model in the default currency, the newest
+// year first. A price without an exchange rate at its time keeps its currency
+func (u *WatchlistUsecase) latestPrices(ctx context.Context, carModel model.CarModel) ([]dto.WatchlistPrice, error) {
+	history, err := u.priceRepository.GetByCarModel(ctx, carModel.Id)
+	if err != nil {
+		return nil, err
+	}
+	// the prices are ordered by PriceAt, so the last one of a year wins
+	latest := map[int]model.CarModelPriceHistory{}
+	for _, price := range history {
+		latest[price.CarModelYearId] = price
+	}
+	converter, err := newPricesConverter(ctx, u.rateRepository, history, model.DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+	prices := []dto.WatchlistPrice{}
+	for _, year := range carModel.CarModelYears {
+		if price, ok := latest[year.Id]; ok {
+			if value, ok := converter.convert(price.Price, price.Currency, model.DefaultCurrency, price.PriceAt); ok {
+				price.Price, price.Currency = value, model.DefaultCurrency
+			}
+			prices = append(prices, dto.WatchlistPrice{
+				CarModelYearId: year.Id,
+				PersianTitle:   year.PersianYear.PersianTitle,
+				Year:           year.PersianYear.Year,
+				Price:          price.Price,
+				Currency:       price.Currency,
+				PriceAt:        price.PriceAt,
+			})
+		}
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Year > prices[j].Year })
+	return prices, nil
+}
+
+func equalIds(a *int, b *int) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func newShareToken() (string, error) {
+	token := make([]byte, 24)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
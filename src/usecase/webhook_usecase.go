@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/naeemaei/golang-clean-web-api/common"
+	"github.com/naeemaei/golang-clean-web-api/config"
+	"github.com/naeemaei/golang-clean-web-api/domain/event"
+	"github.com/naeemaei/golang-clean-web-api/domain/filter"
+	model "github.com/naeemaei/golang-clean-web-api/domain/model"
+	"github.com/naeemaei/golang-clean-web-api/domain/repository"
+	"github.com/naeemaei/golang-clean-web-api/pkg/service_errors"
+	"github.com/naeemaei/golang-clean-web-api/pkg/webhook"
+	"github.com/naeemaei/golang-clean-web-api/usecase/dto"
+)
+
+// WebhookAllEvents subscribes to every event type
+const WebhookAllEvents = "*"
+
+const webhookSecretPrefix = "whsec_"
+
+type WebhookUsecase struct {
+	subscriptions          *BaseUsecase[model.WebhookSubscription, dto.CreateWebhookSubscription, dto.UpdateWebhookSubscription, dto.WebhookSubscription]
+	deliveries             *BaseUsecase[model.WebhookDelivery, dto.WebhookDelivery, dto.WebhookDelivery, dto.WebhookDelivery]
+	subscriptionRepository repository.WebhookSubscriptionRepository
+	deliveryRepository     repository.WebhookDeliveryRepository
+	allowPrivateTargets    bool
+}
+
+func NewWebhookUsecase(cfg *config.Config, subscriptionRepository repository.WebhookSubscriptionRepository, deliveryRepository repository.WebhookDeliveryRepository) *WebhookUsecase {
+	return &WebhookUsecase{
+		subscriptions:          NewBaseUsecase[model.WebhookSubscription, dto.CreateWebhookSubscription, dto.UpdateWebhookSubscription, dto.WebhookSubscription](cfg, subscriptionRepository),
+		deliveries:             NewBaseUsecase[model.WebhookDelivery, dto.WebhookDelivery, dto.WebhookDelivery, dto.WebhookDelivery](cfg, deliveryRepository),
+		subscriptionRepository: subscriptionRepository,
+		deliveryRepository:     deliveryRepository,
+		allowPrivateTargets:    cfg.Webhook.AllowPrivateTargets,
+	}
+}
+
+// Create a subscription, a secret is generated when none is given
+func (u *WebhookUsecase) Create(ctx context.Context, req dto.CreateWebhookSubscription) (dto.WebhookSubscription, error) {
+	var err error
+	if req.EventTypes, err = normalizeEventTypes(req.EventTypes); err != nil {
+		return dto.WebhookSubscription{}, err
+	}
+	req.Entities = normalizeList(req.Entities)
+	if err = u.checkTarget(ctx, req.Url); err != nil {
+		return dto.WebhookSubscription{}, err
+	}
+	if req.Secret == "" {
+		if req.Secret, err = newWebhookSecret(); err != nil {
+			return dto.WebhookSubscription{}, err
+		}
+	}
+	return u.subscriptions.Create(ctx, req)
+}
+
+// Update
+func (u *WebhookUsecase) Update(ctx context.Context, id int, req dto.UpdateWebhookSubscription) (dto.WebhookSubscription, error) {
+	var err error
+	if req.EventTypes, err = normalizeEventTypes(req.EventTypes); err != nil {
+		return dto.WebhookSubscription{}, err
+	}
+	req.Entities = normalizeList(req.Entities)
+	if err = u.checkTarget(ctx, req.Url); err != nil {
+		return dto.WebhookSubscription{}, err
+	}
+	return u.subscriptions.Update(ctx, id, req)
+}
+
+// checkTarget rejects urls that resolve to loopback, private or link local addresses, the deliverer
+// checks the address again when it connects
+func (u *WebhookUsecase) checkTarget(ctx context.Context, url string) error {
+	if u.allowPrivateTargets {
+		return nil
+	}
+	if err := webhook.CheckTarget(ctx, url); err != nil {
+		return &service_errors.ServiceError{EndUserMessage: service_errors.WebhookUrlNotAllowed, Err: err}
+	}
+	return nil
+}
+
+// Delete
+func (u *WebhookUsecase) Delete(ctx context.Context, id int) error {
+	return u.subscriptions.Delete(ctx, id)
+}
+
+// Get By Id
+func (u *WebhookUsecase) GetById(ctx context.Context, id int) (dto.WebhookSubscription, error) {
+	return u.subscriptions.GetById(ctx, id)
+}
+
+// Get By Filter
+func (u *WebhookUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.WebhookSubscription], error) {
+	return u.subscriptions.GetByFilter(ctx, req)
+}
+
+// Get a delivery
+func (u *WebhookUsecase) GetDeliveryById(ctx context.Context, id int) (dto.WebhookDelivery, error) {
+	return u.deliveries.GetById(ctx, id)
+}
+
+// Get deliveries by filter, e.g. the dead letters of a subscription by SubscriptionId and Status
+func (u *WebhookUsecase) GetDeliveriesByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.WebhookDelivery], error) {
+	return u.deliveries.GetByFilter(ctx, req)
+}
+
+// Redeliver sends a delivery again, dead letters get all their attempts back
+func (u *WebhookUsecase) Redeliver(ctx context.Context, id int) (dto.WebhookDelivery, error) {
+	delivery, err := u.deliveryRepository.Redeliver(ctx, id)
+	if err != nil {
+		return dto.WebhookDelivery{}, err
+	}
+	return common.TypeConverter[dto.WebhookDelivery](delivery)
+}
+
+// Enqueue adds a delivery of e for every subscription of its tenant that subscribes to it,
+// it is a handler of the outbox bus
+func (u *WebhookUsecase) Enqueue(ctx context.Context, e event.Event) error {
+	subscriptions, err := u.subscriptionRepository.Subscribers(ctx, e.TenantId)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	deliveries := []model.WebhookDelivery{}
+	for _, subscription := range subscriptions {
+		if !subscribes(subscription, e) {
+			continue
+		}
+		delivery := model.WebhookDelivery{
+			SubscriptionId: subscription.Id,
+			EventId:        e.Id,
+			EventType:      string(e.Type),
+			Payload:        string(payload),
+			Status:         model.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		}
+		delivery.TenantId = subscription.TenantId
+		deliveries = append(deliveries, delivery)
+	}
+	return u.deliveryRepository.Enqueue(ctx, deliveries)
+}
+
+func subscribes(subscription model.WebhookSubscription, e event.Event) bool {
+	eventTypes := strings.Split(subscription.EventTypes, ",")
+	if !slices.Contains(eventTypes, WebhookAllEvents) && !slices.Contains(eventTypes, string(e.Type)) {
+		return false
+	}
+	return subscription.Entities == "" || slices.Contains(strings.Split(subscription.Entities, ","), e.Entity)
+}
+
+func normalizeEventTypes(eventTypes string) (string, error) {
+	eventTypes = normalizeList(eventTypes)
+	if eventTypes == "" {
+		return "", &service_errors.ServiceError{EndUserMessage: service_errors.WebhookEventTypeInvalid}
+	}
+	for _, eventType := range strings.Split(eventTypes, ",") {
+		if eventType != WebhookAllEvents && !slices.Contains(event.Types, event.Type(eventType)) {
+			return "", &service_errors.ServiceError{EndUserMessage: service_errors.WebhookEventTypeInvalid}
+		}
+	}
+	return eventTypes, nil
+}
+
+// normalizeList trims the items of a comma separated list and drops empty and duplicate ones
+func normalizeList(list string) string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" && !slices.Contains(items, item) {
+			items = append(items, item)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(secret), nil
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/naeemaei/golang-clean-web-api/common"
 	"github.com/naeemaei/golang-clean-web-api/config"
 	"github.com/naeemaei/golang-clean-web-api/domain/filter"
 	model "github.com/naeemaei/golang-clean-web-api/domain/model"
@@ -20,13 +21,15 @@ func NewPersianYearUsecase(cfg *config.Config, repository repository.PersianYear
 	}
 }
 
-// Create
+// Create a year, its bounds are the first days of the year and the next year in Iran
 func (u *PersianYearUsecase) Create(ctx context.Context, req dto.CreatePersianYear) (dto.PersianYear, error) {
+	req.StartAt, req.EndAt = common.JalaliYearBounds(req.Year)
 	return u.base.Create(ctx, req)
 }
 
-// Update
+// Update a year, its bounds follow the year
 func (s *PersianYearUsecase) Update(ctx context.Context, id int, req dto.UpdatePersianYear) (dto.PersianYear, error) {
+	req.StartAt, req.EndAt = common.JalaliYearBounds(req.Year)
 	return s.base.Update(ctx, id, req)
 }
 